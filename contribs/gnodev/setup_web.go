@@ -18,6 +18,8 @@ func setupGnoWebServer(logger *slog.Logger, cfg *AppConfig, remoteAddr string) (
 	appcfg.UnsafeHTML = cfg.webHTML
 	appcfg.NodeRemote = remoteAddr
 	appcfg.ChainID = cfg.chainId
+	appcfg.DevMode = true
+	appcfg.SecurityHeaders = nil // the emitter injects inline scripts into pages
 	if cfg.webRemoteHelperAddr != "" {
 		appcfg.RemoteHelp = cfg.webRemoteHelperAddr
 	} else {
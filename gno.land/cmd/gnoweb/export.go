@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type exportCfg struct {
+	height   int64
+	maxPages int
+}
+
+var defaultExportOptions = exportCfg{
+	maxPages: gnoweb.DefaultExportMaxPages,
+}
+
+func newExportCmd(webcfg *webCfg, io commands.IO) *commands.Command {
+	var cfg exportCfg
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "export",
+			ShortUsage: "export [flags] <dir>",
+			ShortHelp:  "exports realms as a static site",
+			LongHelp: `Renders the home page, the aliases and all realms of the node, along with
+the realm pages they link to, at a pinned block height, and writes them with
+their assets to <dir> as a static site, suitable for IPFS pinning or archival
+snapshots. The site must be served from the root of a domain.`,
+		},
+		&cfg,
+		func(ctx context.Context, args []string) error {
+			return execExport(ctx, webcfg, &cfg, args, io)
+		},
+	)
+}
+
+func (c *exportCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.Int64Var(
+		&c.height,
+		"height",
+		defaultExportOptions.height,
+		"block height to render realms at, the latest one if zero",
+	)
+
+	fs.IntVar(
+		&c.maxPages,
+		"max-pages",
+		defaultExportOptions.maxPages,
+		"maximum number of exported pages",
+	)
+}
+
+func execExport(ctx context.Context, webcfg *webCfg, cfg *exportCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+
+	logger, sync := newLogger(webcfg, io)
+	defer sync()
+
+	appcfg, err := newAppConfig(webcfg)
+	if err != nil {
+		return err
+	}
+
+	stats, err := gnoweb.Export(ctx, logger, appcfg, gnoweb.ExportConfig{
+		Dir:      args[0],
+		Height:   cfg.height,
+		MaxPages: cfg.maxPages,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to export: %w", err)
+	}
+
+	io.Printfln("Exported %d pages and %d assets at height %d to %s (%d failed)",
+		stats.Pages, stats.Assets, stats.Height, args[0], stats.Failed)
+	return nil
+}
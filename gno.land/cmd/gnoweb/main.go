@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"maps"
 	"net"
 	"net/http"
@@ -18,30 +19,6 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// Authorized external image host providers.
-var cspImgHost = []string{
-	// Gno-related hosts
-	"https://gnolang.github.io",
-	"https://assets.gnoteam.com",
-	"https://sa.gno.services",
-
-	// Other providers should respect DMCA guidelines.
-	// NOTE: Feel free to open a PR to add more providers here :)
-
-	// imgur
-	"https://imgur.com",
-	"https://*.imgur.com",
-
-	// GitHub
-	"https://*.github.io",
-	"https://github.com",
-	"https://*.githubusercontent.com",
-
-	// IPFS
-	"https://ipfs.io",
-	"https://cloudflare-ipfs.com",
-}
-
 type webCfg struct {
 	chainid          string
 	remote           string
@@ -51,6 +28,8 @@ type webCfg struct {
 	faucetURL        string
 	aliases          string
 	noDefaultAliases bool
+	redirects        string
+	maintenance      bool
 	noCache          bool
 	timeout          time.Duration
 	analytics        bool
@@ -88,6 +67,7 @@ func main() {
 
 			return run()
 		})
+	cmd.AddSubCommands(newExportCmd(&cfg, stdio))
 
 	cmd.Execute(context.Background(), os.Args[1:])
 }
@@ -97,7 +77,7 @@ func (c *webCfg) RegisterFlags(fs *flag.FlagSet) {
 		&c.remote,
 		"remote",
 		defaultWebOptions.remote,
-		"remote gno.land node address",
+		"remote gno.land node address, or comma-separated list of addresses to fail over between",
 	)
 
 	fs.DurationVar(
@@ -128,6 +108,20 @@ func (c *webCfg) RegisterFlags(fs *flag.FlagSet) {
 		"discard default aliases",
 	)
 
+	fs.StringVar(
+		&c.redirects,
+		"redirects",
+		defaultWebOptions.redirects,
+		"JSON file of redirect rules replacing the default redirects, reloaded when modified",
+	)
+
+	fs.BoolVar(
+		&c.maintenance,
+		"maintenance",
+		defaultWebOptions.maintenance,
+		"start in maintenance mode, toggled by sending SIGUSR1 to the process",
+	)
+
 	fs.StringVar(
 		&c.chainid,
 		"help-chainid",
@@ -208,6 +202,54 @@ func (c *webCfg) RegisterFlags(fs *flag.FlagSet) {
 
 func setupWeb(cfg *webCfg, _ []string, io commands.IO) (func() error, error) {
 	// Setup logger
+	logger, sync := newLogger(cfg, io)
+	defer sync()
+
+	// Setup app
+	appcfg, err := newAppConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := gnoweb.NewRouter(logger, appcfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start gnoweb app: %w", err)
+	}
+
+	// Resolve binding address
+	bindaddr, err := net.ResolveTCPAddr("tcp", cfg.bind)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve listener %q: %w", cfg.bind, err)
+	}
+
+	logger.Info("Running", "listener", bindaddr.String())
+
+	// Setup server
+	server := &http.Server{
+		Handler:           app,
+		Addr:              bindaddr.String(),
+		ReadTimeout:       cfg.timeout, // Time to read the request
+		WriteTimeout:      cfg.timeout, // Time to write the entire response
+		IdleTimeout:       cfg.timeout, // Time to keep idle connections open
+		ReadHeaderTimeout: time.Minute, // Time to read request headers
+	}
+
+	return func() error {
+		stop := handleMaintenanceSignal(logger, appcfg.Maintenance)
+		defer stop()
+
+		if err := server.ListenAndServe(); err != nil {
+			logger.Error("HTTP server stopped", "error", err)
+			return commands.ExitCodeError(1)
+		}
+
+		return nil
+	}, nil
+}
+
+// newLogger returns the logger configured by the flags, along with its
+// sync function.
+func newLogger(cfg *webCfg, io commands.IO) (*slog.Logger, func() error) {
 	level := zapcore.InfoLevel
 	if cfg.verbose {
 		level = zapcore.DebugLevel
@@ -218,18 +260,19 @@ func setupWeb(cfg *webCfg, _ []string, io commands.IO) (func() error, error) {
 	} else {
 		zapLogger = log.NewZapConsoleLogger(io.Out(), level)
 	}
-	defer zapLogger.Sync()
 
-	logger := log.ZapLoggerToSlog(zapLogger)
+	return log.ZapLoggerToSlog(zapLogger), zapLogger.Sync
+}
 
-	// Setup app
+// newAppConfig returns the gnoweb configuration set by the flags.
+func newAppConfig(cfg *webCfg) (*gnoweb.AppConfig, error) {
 	appcfg := gnoweb.NewDefaultAppConfig()
 	appcfg.ChainID = cfg.chainid
 	appcfg.NodeRemote = cfg.remote
 	appcfg.NodeRequestTimeout = cfg.remoteTimeout
 	appcfg.RemoteHelp = cfg.remoteHelp
 	if appcfg.RemoteHelp == "" {
-		appcfg.RemoteHelp = appcfg.NodeRemote
+		appcfg.RemoteHelp, _, _ = strings.Cut(appcfg.NodeRemote, ",")
 	}
 	appcfg.Analytics = cfg.analytics
 	appcfg.UnsafeHTML = cfg.html
@@ -248,40 +291,16 @@ func setupWeb(cfg *webCfg, _ []string, io commands.IO) (func() error, error) {
 		maps.Copy(appcfg.Aliases, aliases)
 	}
 
-	app, err := gnoweb.NewRouter(logger, appcfg)
-	if err != nil {
-		return nil, fmt.Errorf("unable to start gnoweb app: %w", err)
-	}
-
-	// Resolve binding address
-	bindaddr, err := net.ResolveTCPAddr("tcp", cfg.bind)
-	if err != nil {
-		return nil, fmt.Errorf("unable to resolve listener %q: %w", cfg.bind, err)
-	}
-
-	logger.Info("Running", "listener", bindaddr.String())
-
-	// Setup security headers
-	secureHandler := SecureHeadersMiddleware(app, !cfg.noStrict)
-
-	// Setup server
-	server := &http.Server{
-		Handler:           secureHandler,
-		Addr:              bindaddr.String(),
-		ReadTimeout:       cfg.timeout, // Time to read the request
-		WriteTimeout:      cfg.timeout, // Time to write the entire response
-		IdleTimeout:       cfg.timeout, // Time to keep idle connections open
-		ReadHeaderTimeout: time.Minute, // Time to read request headers
+	// In strict mode, restrict cross-site resources and enforce https
+	if !cfg.noStrict {
+		*appcfg.SecurityHeaders = gnoweb.StrictSecurityHeaders()
 	}
 
-	return func() error {
-		if err := server.ListenAndServe(); err != nil {
-			logger.Error("HTTP server stopped", "error", err)
-			return commands.ExitCodeError(1)
-		}
+	appcfg.RedirectsFile = cfg.redirects
+	appcfg.Maintenance = &gnoweb.MaintenanceSwitch{}
+	appcfg.Maintenance.Set(cfg.maintenance)
 
-		return nil
-	}, nil
+	return appcfg, nil
 }
 
 // parseAliases parses the given aliases string and return an aliases map.
@@ -318,48 +337,3 @@ func parseAliases(aliasesStr string) (map[string]gnoweb.AliasTarget, error) {
 
 	return aliases, nil
 }
-
-func SecureHeadersMiddleware(next http.Handler, strict bool) http.Handler {
-	// Build img-src CSP directive
-	imgSrc := "'self' data:"
-
-	for _, host := range cspImgHost {
-		imgSrc += " " + host
-	}
-
-	// Define a Content Security Policy (CSP) to restrict the sources of
-	// scripts, styles, images, and other resources. This helps prevent
-	// cross-site scripting (XSS) and other code injection attacks.
-	csp := fmt.Sprintf(
-		"default-src 'self'; script-src 'self' https://sa.gno.services; style-src 'self'; img-src %s; font-src 'self'",
-		imgSrc,
-	)
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Prevent MIME type sniffing by browsers. This ensures that the browser
-		// does not interpret files as a different MIME type than declared.
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-
-		// Prevent the page from being embedded in an iframe. This mitigates
-		// clickjacking attacks by ensuring the page cannot be loaded in a frame.
-		w.Header().Set("X-Frame-Options", "DENY")
-
-		// Control the amount of referrer information sent in the Referer header.
-		// 'no-referrer' ensures that no referrer information is sent, which
-		// enhances privacy and prevents leakage of sensitive URLs.
-		w.Header().Set("Referrer-Policy", "no-referrer")
-
-		// In `strict` mode, prevent cross-site ressources forgery and enforce https
-		if strict {
-			// Set `csp` defined above.
-			w.Header().Set("Content-Security-Policy", csp)
-
-			// Enforce HTTPS by telling browsers to only access the site over HTTPS
-			// for a specified duration (1 year in this case). This also applies to
-			// subdomains and allows preloading into the browser's HSTS list.
-			w.Header().Set("Strict-Transport-Security", "max-age=31536000")
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,8 +36,19 @@ func dummyHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+// secureHeadersHandler returns dummyHandler behind the security headers
+// configured by the given options.
+func secureHeadersHandler(t *testing.T, opts webCfg) http.Handler {
+	t.Helper()
+
+	appcfg, err := newAppConfig(&opts)
+	require.NoError(t, err)
+	require.NotNil(t, appcfg.SecurityHeaders)
+	return gnoweb.SecurityHeadersMiddleware(http.HandlerFunc(dummyHandler), *appcfg.SecurityHeaders, nil, appcfg.CSPByPath)
+}
+
 func TestSecureHeadersMiddlewareStrict(t *testing.T) {
-	handler := SecureHeadersMiddleware(http.HandlerFunc(dummyHandler), true)
+	handler := secureHeadersHandler(t, defaultWebOptions)
 
 	req := httptest.NewRequest("GET", "http://example.com", nil)
 	rec := httptest.NewRecorder()
@@ -72,7 +84,9 @@ func TestSecureHeadersMiddlewareStrict(t *testing.T) {
 }
 
 func TestSecureHeadersMiddlewareNonStrict(t *testing.T) {
-	handler := SecureHeadersMiddleware(http.HandlerFunc(dummyHandler), false)
+	opts := defaultWebOptions
+	opts.noStrict = true
+	handler := secureHeadersHandler(t, opts)
 
 	req := httptest.NewRequest("GET", "http://example.com", nil)
 	rec := httptest.NewRecorder()
@@ -106,6 +120,38 @@ func TestSecureHeadersMiddlewareNonStrict(t *testing.T) {
 	}
 }
 
+func TestSecureHeadersMiddlewareCSPByPath(t *testing.T) {
+	appcfg, err := newAppConfig(&defaultWebOptions)
+	require.NoError(t, err)
+	handler := gnoweb.SecurityHeadersMiddleware(http.HandlerFunc(dummyHandler), *appcfg.SecurityHeaders, nil, map[string][]string{
+		"/r/charts":     {"script-src https://cdn.example.com"},
+		"/r/charts/map": {"script-src https://cdn.example.com", "connect-src https://tiles.example.com"},
+	})
+
+	get := func(path string) string {
+		req := httptest.NewRequest("GET", "http://example.com"+path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Result().Header.Get("Content-Security-Policy")
+	}
+
+	base := get("/r/other")
+	assert.NotContains(t, base, "https://cdn.example.com")
+	assert.Equal(t, base, get("/"))
+	assert.Equal(t, base, get("/r/chartsfoo"))
+
+	// Sources are merged into the existing directive
+	csp := get("/r/charts:home")
+	assert.Contains(t, csp, "script-src 'self' https://sa.gno.services https://cdn.example.com;")
+	assert.Equal(t, strings.Count(base, ";"), strings.Count(csp, ";"))
+	assert.Contains(t, csp, "https://assets.gnoteam.com")
+
+	// The longest prefix wins, and unknown directives are added
+	csp = get("/r/charts/map")
+	assert.Contains(t, csp, "script-src 'self' https://sa.gno.services https://cdn.example.com;")
+	assert.Contains(t, csp, "connect-src https://tiles.example.com")
+}
+
 func TestParseAliases(t *testing.T) {
 	t.Parallel()
 
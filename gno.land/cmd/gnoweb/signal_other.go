@@ -0,0 +1,14 @@
+//go:build !unix
+
+package main
+
+import (
+	"log/slog"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+)
+
+// handleMaintenanceSignal is a no-op, as SIGUSR1 is not available.
+func handleMaintenanceSignal(logger *slog.Logger, m *gnoweb.MaintenanceSwitch) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+)
+
+// handleMaintenanceSignal toggles the maintenance mode on SIGUSR1, until the
+// returned function is called.
+func handleMaintenanceSignal(logger *slog.Logger, m *gnoweb.MaintenanceSwitch) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1)
+	go func() {
+		for range sigs {
+			logger.Info("maintenance mode toggled", "enabled", m.Toggle())
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(sigs)
+	}
+}
@@ -0,0 +1,135 @@
+package gnoweb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// usersRealmPath is the realm of the user names registry.
+	usersRealmPath = "/r/sys/users"
+	// accountTxsLimit bounds the number of recent transactions of profiles.
+	accountTxsLimit = 10
+)
+
+// reEvalAddress matches the first address of an evaluation result.
+var reEvalAddress = regexp.MustCompile(`\bg1[a-z0-9]{38}\b`)
+
+// AccountFunc returns the account of the given address, or nil if it is not
+// known by the chain.
+type AccountFunc func(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error)
+
+// NewRPCAccountFunc returns an AccountFunc querying accounts on the node.
+func NewRPCAccountFunc(logger *slog.Logger, cli *client.RPCClient) AccountFunc {
+	c := &rpcClient{logger: logger, client: cli}
+	return c.account
+}
+
+// account queries the account of the given address.
+func (c *rpcClient) account(ctx context.Context, addr crypto.Address) (*std.BaseAccount, error) {
+	res, err := c.query(ctx, "auth/accounts/"+addr.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query account: %w", err)
+	}
+	if len(res) == 0 || string(res) == "null" {
+		return nil, nil
+	}
+
+	var qret struct{ BaseAccount std.BaseAccount }
+	if err := amino.UnmarshalJSON(res, &qret); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal account: %w", err)
+	}
+	return &qret.BaseAccount, nil
+}
+
+// AccountTx is a transaction of an account, listed on its profile.
+type AccountTx struct {
+	Hash    string
+	Height  int64
+	Time    time.Time
+	Summary string // such as "call gno.land/r/demo/boards.CreateThread"
+	URL     string // optional, such as the transaction on an explorer
+}
+
+// TxIndexer lists the transactions of accounts, which the node does not
+// index, such as from a tx-indexer instance.
+type TxIndexer interface {
+	// AccountTxs returns the most recent transactions sent by the address,
+	// the latest first, up to limit.
+	AccountTxs(ctx context.Context, addr crypto.Address, limit int) ([]AccountTx, error)
+}
+
+// resolveUserAddress returns the address of the given user, which is
+// either an address or a name registered in r/sys/users.
+func (h *HTTPHandler) resolveUserAddress(ctx context.Context, user string) (crypto.Address, bool) {
+	if addr, err := crypto.AddressFromBech32(user); err == nil {
+		return addr, true
+	}
+	if h.UsersEval == nil {
+		return crypto.Address{}, false
+	}
+
+	res, err := h.UsersEval(ctx, usersRealmPath, "ResolveName("+strconv.Quote(user)+")")
+	if err != nil {
+		h.Logger.Debug("unable to resolve user name", "user", user, "error", err)
+		return crypto.Address{}, false
+	}
+	addr, err := crypto.AddressFromBech32(reEvalAddress.FindString(string(res)))
+	if err != nil {
+		return crypto.Address{}, false
+	}
+	return addr, true
+}
+
+// buildAccount returns the on-chain account of the given user, and its
+// recent transactions if a TxIndexer is set, or nil if it cannot be
+// resolved.
+func (h *HTTPHandler) buildAccount(ctx context.Context, user string) *components.UserAccount {
+	addr, ok := h.resolveUserAddress(ctx, user)
+	if !ok {
+		return nil
+	}
+
+	account := &components.UserAccount{Address: addr.String()}
+	acc, err := h.Account(ctx, addr)
+	if err != nil {
+		h.Logger.Warn("unable to fetch account", "address", account.Address, "error", err)
+		return nil
+	}
+	if acc != nil {
+		account.Balance = acc.Coins.String()
+		account.AccountNumber = acc.AccountNumber
+		account.Sequence = acc.Sequence
+	}
+
+	if h.TxIndexer != nil {
+		txs, err := h.TxIndexer.AccountTxs(ctx, addr, accountTxsLimit)
+		if err != nil {
+			h.Logger.Warn("unable to fetch account transactions", "address", account.Address, "error", err)
+		}
+		for _, tx := range txs {
+			utx := components.UserTransaction{
+				Hash:    tx.Hash,
+				Height:  tx.Height,
+				Summary: tx.Summary,
+				URL:     tx.URL,
+			}
+			if !tx.Time.IsZero() {
+				utx.Date = &tx.Time
+			}
+			account.Transactions = append(account.Transactions, utx)
+		}
+	}
+
+	return account
+}
@@ -0,0 +1,137 @@
+package gnoweb
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// APIPath is the prefix of the JSON version of realms, such as
+// `/api/r/demo/boards:page`.
+const APIPath = "/api/"
+
+// apiRealm is the JSON representation of a rendered realm.
+type apiRealm struct {
+	Path        string         `json:"path"`
+	Args        string         `json:"args"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Frontmatter md.Frontmatter `json:"frontmatter"`
+	Headings    []apiHeading   `json:"headings"`
+	HTML        string         `json:"html"`
+	Package     apiPackage     `json:"package"`
+}
+
+// apiHeading is a heading of a rendered realm, in document order.
+type apiHeading struct {
+	Level int    `json:"level"`
+	Title string `json:"title"`
+	ID    string `json:"id"`
+}
+
+// apiPackage holds the metadata of the package of a realm.
+type apiPackage struct {
+	Path  string   `json:"path"`
+	Doc   string   `json:"doc,omitempty"`
+	Files []string `json:"files,omitempty"`
+}
+
+// handlerAPI serves the rendered content of realms as JSON, along with
+// their title, headings, frontmatter and package metadata.
+func handlerAPI(h *HTTPHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		u := *r.URL
+		u.Path = strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(APIPath, "/"))
+		u.RawPath = strings.TrimPrefix(r.URL.RawPath, strings.TrimSuffix(APIPath, "/"))
+
+		gnourl, err := weburl.ParseFromURL(&u)
+		if err != nil || !gnourl.IsRealm() || gnourl.IsFile() || !onlyContentWarningQuery(gnourl) {
+			writeAPIError(w, http.StatusNotFound, "invalid path")
+			return
+		}
+		if h.contentWarningRequired(r, gnourl) {
+			writeAPIError(w, http.StatusForbidden, "flagged content, accept its content warning with the `$proceed` web query")
+			return
+		}
+
+		ctx := r.Context()
+		raw, err := h.Client.Realm(ctx, gnourl.Path, gnourl.EncodeArgs())
+		if err != nil {
+			h.Logger.Debug("unable to fetch api realm", "error", err, "path", gnourl.EncodeURL())
+			status, _ := GetClientErrorStatusPage(gnourl, err)
+			writeAPIError(w, status, http.StatusText(status))
+			return
+		}
+
+		var content bytes.Buffer
+		meta, err := h.Renderer.RenderRealm(&content, gnourl, raw)
+		if err != nil {
+			h.Logger.Error("unable to render api realm", "error", err, "path", gnourl.EncodeURL())
+			writeAPIError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		res := apiRealm{
+			Path:        gnourl.Path,
+			Args:        gnourl.Args,
+			Title:       sanitizeTitle(meta.Title, "", h.MaxTitleLength),
+			Description: sanitizeTitle(meta.Frontmatter.Description, "", maxDescriptionLength),
+			Frontmatter: meta.Frontmatter,
+			Headings:    apiHeadings(nil, meta.Toc.Items, 2),
+			HTML:        content.String(),
+			Package:     apiPackage{Path: gnourl.Path},
+		}
+		if meta.Frontmatter.Title != "" {
+			res.Title = sanitizeTitle(meta.Frontmatter.Title, gnourl.Path, h.MaxTitleLength)
+		}
+
+		// Package metadata is best effort, the render is what matters
+		if files, err := h.Client.ListFiles(ctx, gnourl.Path); err == nil {
+			res.Package.Files = files
+		}
+		if jdoc, err := h.Client.Doc(ctx, gnourl.Path); err == nil {
+			res.Package.Doc = jdoc.PackageDoc
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			h.Logger.Error("unable to write api realm", "error", err)
+		}
+	})
+}
+
+// apiHeadings appends the flattened items of the table of contents, of the
+// given level, to headings. Blank items only hold deeper headings.
+func apiHeadings(headings []apiHeading, items []*md.TocItem, level int) []apiHeading {
+	if headings == nil {
+		headings = []apiHeading{}
+	}
+	for _, item := range items {
+		if len(item.Title) > 0 {
+			headings = append(headings, apiHeading{
+				Level: level,
+				Title: string(item.Title),
+				ID:    string(item.ID),
+			})
+		}
+		headings = apiHeadings(headings, item.Items, level+1)
+	}
+	return headings
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,126 @@
+package gnoweb
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerAPI(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{realms: map[string]string{
+		"/r/demo/boards": "# Boards\n\nA simple **forum**.\n\n## Threads\n\n### Latest\n\n## About\n",
+		"/r/demo/blog":   "---\ntitle: Gno Blog\ndescription: Posts about Gno\n---\n\n# Home\n",
+		"/r/demo/markup": "---\ntitle: Gno <script>alert(1)</script><b>Boards</b>\ndescription: \"A <i>simple</i>\\nforum\"\n---\n",
+		"/r/flagged/x":   "# Sensitive stuff\n",
+	}}
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land"},
+		WarnPaths:     []string{"/r/flagged"},
+	})
+	require.NoError(t, err)
+	handler := handlerAPI(h)
+
+	t.Run("realm", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/r/demo/boards:page", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+		var res apiRealm
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "/r/demo/boards", res.Path)
+		assert.Equal(t, "page", res.Args)
+		assert.Equal(t, "Boards", res.Title)
+		assert.Contains(t, res.HTML, "<strong>forum</strong>")
+		assert.Equal(t, []apiHeading{
+			{Level: 2, Title: "Threads", ID: "threads"},
+			{Level: 3, Title: "Latest", ID: "latest"},
+			{Level: 2, Title: "About", ID: "about"},
+		}, res.Headings)
+		assert.Equal(t, "/r/demo/boards", res.Package.Path)
+	})
+
+	t.Run("frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/r/demo/blog", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var res apiRealm
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "Gno Blog", res.Title)
+		assert.Equal(t, "Posts about Gno", res.Description)
+		assert.Equal(t, "Gno Blog", res.Frontmatter.Title)
+		assert.Empty(t, res.Headings)
+	})
+
+	t.Run("markup frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/r/demo/markup", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var res apiRealm
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "Gno alert(1)Boards", res.Title)
+		assert.Equal(t, "A simple forum", res.Description)
+	})
+
+	t.Run("content warning", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/r/flagged/x", nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "Sensitive stuff")
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/r/flagged/x$proceed", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Sensitive stuff")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		for path, status := range map[string]int{
+			"/api/p/demo/avl":               http.StatusNotFound,
+			"/api/r/demo/boards$source":     http.StatusNotFound,
+			"/api/r/demo/boards/render.gno": http.StatusNotFound,
+			"/api/r/demo/norender":          http.StatusInternalServerError,
+		} {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, status, rr.Code, path)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"), path)
+
+			var res map[string]string
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res), path)
+			assert.NotEmpty(t, res["error"], path)
+		}
+	})
+
+	t.Run("method", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/r/demo/boards", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
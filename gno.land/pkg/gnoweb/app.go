@@ -2,18 +2,26 @@ package gnoweb
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
 	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/version"
 	"github.com/yuin/goldmark"
 	mdhtml "github.com/yuin/goldmark/renderer/html"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var DefaultAliases = map[string]AliasTarget{
@@ -36,16 +44,56 @@ type AppConfig struct {
 	UnsafeHTML bool
 	// Analytics enables SimpleAnalytics.
 	Analytics bool
-	// NodeRemote is the remote address of the gno.land node.
+	// NodeRemote is the remote address of the gno.land node. It may be a
+	// comma-separated list of remotes, in which case queries are load
+	// balanced over the healthy ones and fail over to the next remote on
+	// errors and timeouts.
 	NodeRemote string
 	// NodeRequestTimeout define how much time a request to the remote node should live before timeout.
 	NodeRequestTimeout time.Duration
+	// NodeRetries is the number of times a failing request to the remote node is retried,
+	// within its NodeRequestTimeout.
+	NodeRetries int
+	// NodeRetryBackoff is the delay before the first retry, doubled on each subsequent retry.
+	NodeRetryBackoff time.Duration
+	// NodeBreakerThreshold, if specified, is the number of consecutive failures
+	// after which requests to the remote node fail fast for NodeBreakerCooldown.
+	NodeBreakerThreshold int
+	// NodeBreakerCooldown is how long the circuit breaker stays open.
+	NodeBreakerCooldown time.Duration
+	// NodeFailoverTimeout bounds each query to a single remote when several
+	// are configured, so that a hanging node fails over to the next one.
+	NodeFailoverTimeout time.Duration
+	// NodeHealthInterval is how often a failing remote is health-checked
+	// until it answers again, when several are configured.
+	NodeHealthInterval time.Duration
 	// RemoteHelp is the remote of the gno.land node, as used in the help page.
 	RemoteHelp string
 	// AssetsPath is the base path to the gnoweb assets.
 	AssetsPath string
-	// NoAssetsCache disables assets caching.
+	// NoAssetsCache disables assets caching. Otherwise, pages reference the
+	// embedded assets by content-hashed names, such as `main.<hash>.css`,
+	// served with immutable cache headers.
 	NoAssetsCache bool
+	// Compression, if enabled, compresses HTML, CSS, JavaScript and other
+	// text responses with brotli or gzip, as accepted by the client.
+	Compression bool
+	// Theme, if set, is the name of a theme registered with RegisterTheme,
+	// or the path of a theme directory, holding a `theme.css` stylesheet
+	// and a `templates` directory of template overrides. The theme
+	// stylesheet is served as `theme.css` under AssetsPath. Template
+	// overrides apply process-wide.
+	Theme string
+	// DefaultLocale is the locale of the page chrome, such as the
+	// navigation, the help page and the error pages. It defaults to "en".
+	DefaultLocale string
+	// UILocales lists the other locales the page chrome is translated to,
+	// negotiated with the `Accept-Language` header of requests. Each
+	// locale must have a message catalog, embedded or from CatalogDir.
+	UILocales []string
+	// CatalogDir, if set, is a directory of `<locale>.json` message
+	// catalogs, adding to the embedded ones. Catalogs apply process-wide.
+	CatalogDir string
 	// ChainID is the chain id, used for constructing the help page.
 	ChainID string
 	// FaucetURL, if specified, will be the URL to which `/faucet` redirects.
@@ -54,8 +102,343 @@ type AppConfig struct {
 	Domain string
 	// Aliases is a map of aliases pointing to another path or a static file.
 	Aliases map[string]AliasTarget
+	// Redirects are the exact, prefix or regexp rules redirecting requests
+	// to another URL, or aliasing them to another page.
+	Redirects []RedirectRule
+	// RedirectsFile, if set, is a JSON file of redirect rules replacing
+	// Redirects, reloaded without restarting when the file is modified.
+	RedirectsFile string
 	// RenderConfig defines the default configuration for rendering realms and source files.
 	RenderConfig RenderConfig
+	// SourceRefBase, if specified, is the repository base URL used to link
+	// issue references (`#123`) and commit hashes found in realm content.
+	SourceRefBase string
+	// ProgressiveRender, if enabled, flushes pages incrementally so browsers
+	// can start painting the page before it is fully written.
+	ProgressiveRender bool
+	// ReportURLTemplate, if specified, renders a "report this content" link
+	// on realm pages. `{path}` is replaced by the escaped realm path.
+	ReportURLTemplate string
+	// MathServerRender, if enabled, renders math expressions to MathML on
+	// the server so they display without JavaScript.
+	MathServerRender bool
+	// MathKaTeXURL, if set, is the base URL of the KaTeX runtime rendering
+	// math expressions on the client, such as md.DefaultKaTeXURL, allowed
+	// by the CSP of SecurityHeaders. It is unused with MathServerRender.
+	// Math expressions are left as plain text unless either is set.
+	MathKaTeXURL string
+	// NormalizeWhitespace, if enabled, collapses runs of blank lines in
+	// realm markdown before rendering, outside of code blocks.
+	NormalizeWhitespace bool
+	// CSPByPath maps realm paths to additional CSP sources, each
+	// given as "<directive> <source>" (e.g. "script-src https://cdn.example.com").
+	// They are merged into the base policy of the realm pages and their
+	// sub-paths only.
+	CSPByPath map[string][]string
+	// SecurityHeaders, if set, configures the security headers of all
+	// responses, such as the Content-Security-Policy, which allows the CDN
+	// hosts of the enabled markdown extensions. It defaults to
+	// DefaultSecurityHeaders: public gateways should opt in to
+	// StrictSecurityHeaders.
+	SecurityHeaders *SecurityHeaders
+	// CodeLineAnchors, if enabled, makes code block line number anchors
+	// unique across the page by prefixing them with the block index.
+	CodeLineAnchors bool
+	// AssetPlaceholders, if enabled, serves a transparent placeholder for
+	// missing image assets instead of a 404. Placeholders are not cached.
+	AssetPlaceholders bool
+	// LiveReload, if enabled, injects a script into pages reloading them
+	// when the node block height changes. Meant for local development only.
+	LiveReload bool
+	// Realtime, if enabled, serves a `/ws` WebSocket endpoint notifying
+	// realm pages when a new block changed their render, and injects a
+	// script into them refreshing their content in place. The block
+	// height is polled every RealtimeInterval while clients are connected.
+	Realtime         bool
+	RealtimeInterval time.Duration
+	// ServeMarkdownSource, if enabled, serves the raw realm markdown as
+	// `text/markdown` at `/r/foo.md`, `/r/foo?format=md` or `?format=raw`,
+	// and its plain text conversion at `/r/foo?format=txt`.
+	ServeMarkdownSource bool
+	// RateLimit, if set, limits the rate of page requests per client IP. It
+	// also covers the generated discovery endpoints, such as the sitemap,
+	// and the other rendering endpoints, a client having a single budget
+	// shared by all of them. Clients are identified by their remote address:
+	// behind a reverse proxy, have the proxy restore it, such as with the
+	// PROXY protocol, or limit clients at the proxy instead.
+	RateLimit RateLimit
+	// PathRateLimits maps path prefixes to a rate limit shared by all
+	// clients, protecting expensive realms regardless of who is calling.
+	PathRateLimits map[string]RateLimit
+	// RateLimitBypass lists the CIDR ranges, or single IPs, of the clients
+	// exempt from all rate limits, such as monitoring or internal services.
+	RateLimitBypass []string
+	// ShowBuildInfo, if enabled, exposes the gnoweb build version in the
+	// page footer and the `X-Gnoweb-Version` response header.
+	ShowBuildInfo bool
+	// DotDiagrams, if enabled, renders ```dot fenced blocks as graphs.
+	DotDiagrams bool
+	// DotRuntimeURL is the viz.js runtime used to render dot graphs on the
+	// client. It must be allowed by the page CSP. If empty, the graph source
+	// is displayed instead.
+	DotRuntimeURL string
+	// MermaidDiagrams, if enabled, renders ```mermaid fenced blocks as
+	// diagrams.
+	MermaidDiagrams bool
+	// MermaidRuntimeURL is the mermaid runtime used to render diagrams on
+	// the client. It must be allowed by the page CSP. If empty, the diagram
+	// source is displayed instead.
+	MermaidRuntimeURL string
+	// PflowDiagrams, if enabled, renders ```pflow fenced blocks holding
+	// Petri net models as inline SVG images.
+	PflowDiagrams bool
+	// PflowCDN is the base URL of the pflow viewer assets, `pflow.js` and
+	// `pflow.css`, making Petri nets interactive. It must be allowed by the
+	// page CSP. If empty, nets are static images.
+	PflowCDN string
+	// PflowJSIntegrity and PflowCSSIntegrity are the subresource integrity
+	// hashes of the pflow viewer assets, such as `sha384-...`. Copies of
+	// the assets under `public/pflow/`, if any, are loaded when the CDN is
+	// unreachable.
+	PflowJSIntegrity  string
+	PflowCSSIntegrity string
+	// PflowSimulate, if enabled, serves the simulation of Petri nets at
+	// `/_pflow/simulate`, and lets readers fire the transitions of static
+	// nets by clicking them.
+	PflowSimulate bool
+	// MarkdownExtensions lists the names of the extensions registered with
+	// RegisterMarkdownExtension to enable, in order, after the built-in
+	// ones. An unknown name is an error.
+	MarkdownExtensions []string
+	// BlockCacheSize is the number of rendered pflow nets and server
+	// rendered math expressions kept in an LRU cache, keyed by the hash
+	// of their source. Zero disables the cache.
+	BlockCacheSize int
+	// ListingSort is the default sort order of path listings, by name or
+	// deploy height, overridable with the `sort` query parameter. Deploy
+	// heights are fetched in the background, packages not fetched yet being
+	// sorted as genesis ones. Listings can also be filtered with the `prefix` query
+	// parameter.
+	ListingSort ListingSort
+	// ListingPerPage is the default number of paths per listing page,
+	// overridable with the `per_page` query parameter. Zero disables
+	// pagination.
+	ListingPerPage int
+	// OpenSearch, if enabled, serves an OpenSearch description at
+	// `/opensearch.xml` so browsers can add gnoweb as a search engine.
+	OpenSearch bool
+	// JSONTreeViewer, if enabled, displays JSON realm output as an
+	// interactive collapsible tree. The highlighted JSON remains available
+	// as a raw view and when JavaScript is disabled.
+	JSONTreeViewer bool
+	// Locales lists the locales pages are available in, the first one being
+	// the default locale served at unprefixed paths, and the others under a
+	// `/<locale>` prefix. If set, pages emit `hreflang` alternate links.
+	Locales []string
+	// PageCacheSize, if positive, is the number of rendered pages cached
+	// in memory until the block height advances. Cached pages are served
+	// with ETag and Last-Modified validators for conditional requests.
+	// Pages are not cached if ProgressiveRender is enabled, as cached pages
+	// are buffered before being served.
+	PageCacheSize int
+	// SnapshotDir, if set, is a directory of pre-rendered realm pages
+	// served instead of live rendering when present, laid out as
+	// `<height>/<realm path>[:<args>].html` for the latest block height, or
+	// the one requested with `?height=<N>` if HistoricalRender is enabled.
+	SnapshotDir string
+	// MaxTitleLength is the maximum length of the realm part of page
+	// titles, longer titles are truncated.
+	MaxTitleLength int
+	// WarnPaths lists path prefixes of flagged realms, displayed behind a
+	// "sensitive content" interstitial until the visitor clicks through.
+	WarnPaths []string
+	// StaticSearchIndex, if enabled, serves a JSON index of the realms
+	// paths, titles and excerpts at `/search-index.json`, for client-side
+	// search. It is rebuilt once older than SearchIndexTTL.
+	StaticSearchIndex bool
+	SearchIndexTTL    time.Duration
+	// FullTextSearch, if enabled, serves a full-text search of the realms
+	// renders and the packages sources at `/search?q=<query>`. The index
+	// is built in memory, and rebuilt once older than FullTextSearchTTL.
+	FullTextSearch    bool
+	FullTextSearchTTL time.Duration
+	// EmbedAllowedAncestors, if set, serves a minimal embeddable version of
+	// realms at `/embed/<realm path>`, which may only be framed by these
+	// origins (e.g. "https://example.com").
+	EmbedAllowedAncestors []string
+	// JSONAPI, if enabled, serves realms as JSON at `/api/<realm path>`,
+	// with their rendered HTML, title, headings, frontmatter and package
+	// metadata.
+	JSONAPI bool
+	// OGImageDir, if set, serves social preview images of realms at
+	// `/_og/<realm path>.svg`, cached in this directory, and references
+	// them as `og:image` from realm pages.
+	OGImageDir string
+	// Feeds, if enabled, serves the Atom feed of realms at
+	// `/r/<realm>:feed.xml`, made of the level 2 sections of their
+	// `RenderFeed()` output, or of their `Render("")` output.
+	Feeds bool
+	// HistoricalRender, if enabled, renders realms requested with the
+	// `?height=<N>` query as of that past block height, using height
+	// pinned node queries. The node must still hold the state of the
+	// requested height.
+	HistoricalRender bool
+	// SourceDiff, if enabled, serves a unified or side-by-side diff of a
+	// package's files at `<pkg>$diff`, against the `base` package and
+	// the `from` and `to` block heights, with syntax highlighting.
+	SourceDiff bool
+	// DependencyGraph, if enabled, serves the import graph of a package at
+	// `<pkg>$graph`, following its on-chain imports, as an SVG diagram.
+	DependencyGraph bool
+	// StripHTMLComments, if enabled, removes HTML comments from realm
+	// output. Code blocks are left untouched.
+	StripHTMLComments bool
+	// Tracing, if enabled, continues or starts a W3C `traceparent` trace
+	// for each request, propagates it to the node RPC calls and logs
+	// requests with their trace ID.
+	Tracing bool
+	// TracerProvider, if set, emits OpenTelemetry spans for each request,
+	// and for the node RPC queries made while serving it.
+	TracerProvider trace.TracerProvider
+	// DevMode, if enabled, renders authoring hints meant for development,
+	// such as a visible warning next to images without alt text.
+	DevMode bool
+	// HumansText is the content served at `/humans.txt`, crediting the
+	// people behind the site. If empty, the path responds with a 404.
+	HumansText string
+	// DeprecatedPaths maps deprecated realm path prefixes to the URL of
+	// their replacement (e.g. "/r/demo/boards2"), or to a message,
+	// displayed as a banner above the realm content.
+	DeprecatedPaths map[string]string
+	// AssetCasePolicy defines how asset requests whose casing does not
+	// match the served file are handled, they are forwarded as is by
+	// default.
+	AssetCasePolicy AssetCasePolicy
+	// CopyAsCurl, if enabled, renders a "copy as curl" button below
+	// ```http request blocks.
+	CopyAsCurl bool
+	// CheckInternalLinks, if enabled along with DevMode, checks that the
+	// packages and realms targeted by links exist, and renders a warning
+	// next to broken ones.
+	CheckInternalLinks bool
+	// SourceEmbeds, if enabled, renders ```gno-embed{path=... file=...}
+	// blocks as the highlighted lines of the referenced on-chain file.
+	SourceEmbeds bool
+	// CallLinks, if enabled, renders `gno:call?pkg=...&func=...` links as
+	// buttons to the help page of the function, with prefilled arguments.
+	CallLinks bool
+	// ImageProxy, if enabled, serves remote markdown images through
+	// `/_proxy/img`, so readers do not fetch them from their origin. Image
+	// URLs are signed with ImageProxyKey, so that only the images of
+	// rendered pages are served. Instances serving the same pages, such as
+	// behind a load balancer, must share the key, a random one being
+	// generated if unset.
+	ImageProxy    bool
+	ImageProxyKey string
+	// ImageProxyMaxSize is the maximum size of proxied images, in bytes.
+	ImageProxyMaxSize int64
+	// AccountProfiles, if enabled, displays the balance, sequence and
+	// account number of users on their `/u/<address-or-name>` profile,
+	// resolving names with r/sys/users. TxIndexer, if set, lists their
+	// recent transactions.
+	AccountProfiles bool
+	TxIndexer       TxIndexer
+	// TxExport, if enabled, serves the unsigned transactions of calls built
+	// from the help pages of realms at `/_tx/<realm path>`, as JSON to be
+	// signed offline with `gnokey sign`.
+	TxExport bool
+	// StatusBadge, if enabled, serves an SVG badge displaying whether the
+	// node is online at `/badge.svg`, for embedding in READMEs.
+	StatusBadge bool
+	// ParagraphAnchors, if enabled, sets the `id` of paragraphs ending with
+	// a `{#id}` marker, so they can be linked to.
+	ParagraphAnchors bool
+	// EmojiShortcodes, if enabled, replaces GitHub emoji shortcodes such
+	// as `:rocket:` by their emoji.
+	EmojiShortcodes bool
+	// AdminPassword is the password of the `admin` user, required by the
+	// admin endpoints. If empty, admin endpoints are disabled.
+	AdminPassword string
+	// ErrorPages maps HTTP statuses, such as 404, 500 or 503, to custom
+	// pages replacing their default error view.
+	ErrorPages map[int]ErrorPage
+	// Maintenance, if set, serves the maintenance page with a 503 status
+	// instead of pages while enabled, such as while the node resyncs. It
+	// can be toggled at runtime, and from the admin `/debug/maintenance`
+	// endpoint if AdminPassword is set.
+	Maintenance *MaintenanceSwitch
+	// DebugConfig, if enabled, serves the effective configuration, with
+	// secrets redacted, at the admin `/debug/config.json` endpoint.
+	DebugConfig bool
+	// Metrics, if enabled, serves Prometheus metrics at `/metrics`: the
+	// request counts and latencies by route class, the node RPC call
+	// durations and the page cache lookups. The endpoint requires the
+	// admin credentials if AdminPassword is set.
+	Metrics bool
+	// HomeGroups lists the namespaces, such as `/r/gnoland`, whose realms
+	// are featured on the home page, grouped by namespace in this order.
+	HomeGroups []string
+	// HomeGroupsOther, if enabled, lists the realms outside of HomeGroups
+	// in a last "other" group. Otherwise, they are hidden.
+	HomeGroupsOther bool
+	// Sitemap, if enabled, serves the sitemap of the realms and pure
+	// packages at `/sitemap.xml`. It is regenerated once older than
+	// SitemapTTL.
+	Sitemap    bool
+	SitemapTTL time.Duration
+	// Robots is the crawler policy served at `/robots.txt`, which also
+	// references the sitemap if enabled. It allows crawling everything by
+	// default.
+	Robots RobotsPolicy
+	// Preconnect, if enabled, adds preconnect and dns-prefetch hints for
+	// the CDN origins used by each page, such as the dot runtime one.
+	Preconnect bool
+	// ServeStaleOnOutage, if enabled, serves the last render of realms,
+	// with a warning banner, while the node is unreachable.
+	ServeStaleOnOutage bool
+	// ValidateHost, if enabled, rejects requests whose `Host` is neither
+	// Domain nor one of AllowedHosts, such as `gno.land:8888` or
+	// `*.gno.land`, protecting the absolute URLs generated from it.
+	ValidateHost bool
+	AllowedHosts []string
+	// RPCGateway, if enabled, serves a CORS enabled, read-only JSON-RPC
+	// gateway to the node at `/rpc`, limited to RPCGatewayMethods, or to
+	// all of the gateway methods if unset. Results are cached until the
+	// block height advances. Each client IP is limited by
+	// RPCGatewayRateLimit, sharing its budget with the pages if equal to
+	// RateLimit.
+	RPCGateway          bool
+	RPCGatewayMethods   []string
+	RPCGatewayRateLimit RateLimit
+	// HeadingSpamGuard, if enabled, detects pages with more headings per
+	// word of body text than HeadingSpamRatio, or md.DefaultHeadingSpamRatio
+	// if zero. They are flagged in DevMode, and have their headings
+	// collapsed otherwise.
+	HeadingSpamGuard bool
+	HeadingSpamRatio float64
+	// ContentFlags are the deployment flags, such as `testnet`, enabling
+	// the content of `:::if <flag>` blocks. Blocks with any other flag are
+	// omitted from rendered pages.
+	ContentFlags []string
+	// RenderErrorHistory, if enabled, keeps the recent render errors of
+	// each realm path in memory, served at the admin
+	// `/debug/render-errors?path=<path>` endpoint.
+	RenderErrorHistory bool
+	// HardLineBreaks, if enabled, renders single newlines of realm
+	// markdown as line breaks, as in chat messages and comments.
+	HardLineBreaks bool
+	// TextMode, if enabled, serves realms requested with `?view=text` as
+	// plain semantic HTML, without styles, scripts, images nor widgets.
+	TextMode bool
+	// AutoToc, if enabled, inserts a table of contents before the first
+	// section of realm pages with enough headings. Pages may always place
+	// one with a `[[toc]]` paragraph.
+	AutoToc bool
+	// PageViews, if set, counts the views of each page as a first-party
+	// alternative to Analytics, without recording visitors, served at the
+	// admin `/stats` endpoint. It is owned by the caller, which must close
+	// it once the server stopped so that the last counts are persisted.
+	PageViews *PageViews
 }
 
 // NewDefaultAppConfig returns a new default AppConfig. The default sets
@@ -63,29 +446,68 @@ type AppConfig struct {
 // to be served on /public/.
 func NewDefaultAppConfig() *AppConfig {
 	const localRemote = "127.0.0.1:26657"
+	securityHeaders := DefaultSecurityHeaders()
 	return &AppConfig{
-		NodeRemote:         localRemote, // local first
-		RemoteHelp:         localRemote, // local first
-		NodeRequestTimeout: time.Minute,
-		AssetsPath:         "/public/",
-		Domain:             "gno.land",
-		Aliases:            DefaultAliases,
-		RenderConfig:       NewDefaultRenderConfig(),
+		NodeRemote:          localRemote, // local first
+		RemoteHelp:          localRemote, // local first
+		NodeRequestTimeout:  time.Minute,
+		NodeRetries:         2,
+		NodeRetryBackoff:    100 * time.Millisecond,
+		NodeBreakerCooldown: 10 * time.Second,
+		NodeFailoverTimeout: 10 * time.Second,
+		NodeHealthInterval:  10 * time.Second,
+		AssetsPath:          "/public/",
+		Domain:              "gno.land",
+		Aliases:             DefaultAliases,
+		Redirects:           DefaultRedirectRules(),
+		SecurityHeaders:     &securityHeaders,
+		RenderConfig:        NewDefaultRenderConfig(),
+		DefaultLocale:       components.DefaultLocale,
+		DotRuntimeURL:       md.DefaultDotRuntimeURL,
+		MermaidRuntimeURL:   md.DefaultMermaidRuntimeURL,
+		BlockCacheSize:      md.DefaultBlockCacheSize,
+		ListingSort:         ListingSortName,
+		ListingPerPage:      DefaultListingPerPage,
+		MaxTitleLength:      DefaultMaxTitleLength,
+		SearchIndexTTL:      DefaultSearchIndexTTL,
+		FullTextSearchTTL:   DefaultSearchTTL,
+		SitemapTTL:          DefaultSitemapTTL,
+		PageCacheSize:       DefaultPageCacheSize,
+		RealtimeInterval:    DefaultRealtimeInterval,
+		ImageProxyMaxSize:   DefaultImageProxyMaxSize,
 	}
 }
 
-// NewRouter initializes the gnoweb router with the specified logger and configuration.
-// It sets up all routes, static asset handling, and middleware.
-func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
-	assetsBase := "/" + strings.Trim(cfg.AssetsPath, "/") + "/" // sanitize
+// newNodeClient returns the RPC client of the node, failing over between
+// remotes if several are given.
+func newNodeClient(logger *slog.Logger, cfg *AppConfig) (*client.RPCClient, error) {
+	if remotes := splitRemotes(cfg.NodeRemote); len(remotes) > 1 {
+		pool, err := newNodePool(logger, remotes, cfg.NodeFailoverTimeout, cfg.NodeHealthInterval)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create HTTP client: %w", err)
+		}
+		return client.NewRPCClient(pool, client.WithRequestTimeout(cfg.NodeRequestTimeout)), nil
+	}
 
-	// Initialize RPC Client.
 	rpcclient, err := client.NewHTTPClient(cfg.NodeRemote,
 		client.WithRequestTimeout(cfg.NodeRequestTimeout),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create HTTP client: %w", err)
 	}
+	return rpcclient, nil
+}
+
+// NewRouter initializes the gnoweb router with the specified logger and configuration.
+// It sets up all routes, static asset handling, and middleware.
+func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
+	assetsBase := "/" + strings.Trim(cfg.AssetsPath, "/") + "/" // sanitize
+
+	// Initialize RPC Client
+	rpcclient, err := newNodeClient(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	if cfg.ChainID == "" {
 		cfg.ChainID, err = getChainID(context.Background(), rpcclient)
@@ -95,8 +517,27 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 		}
 	}
 
-	// Setup client adapter
-	adpcli := NewRPCClientAdapter(logger, rpcclient, cfg.Domain)
+	// height returns the latest block height of the node
+	height := func(ctx context.Context) (int64, error) {
+		status, err := rpcclient.Status(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		return status.SyncInfo.LatestBlockHeight, nil
+	}
+
+	var metrics *Metrics
+	if cfg.Metrics {
+		metrics = NewMetrics()
+	}
+
+	// Setup client adapter with its resilience stack
+	adpcli := ChainClient(NewRPCClientAdapter(logger, rpcclient, cfg.Domain),
+		WithCircuitBreaker(cfg.NodeBreakerThreshold, cfg.NodeBreakerCooldown),
+		WithTimeout(cfg.NodeRequestTimeout),
+		WithRetry(cfg.NodeRetries, cfg.NodeRetryBackoff),
+		WithMetrics(metrics),
+	)
 
 	// Setup StaticMetadata
 	chromaStylePath := path.Join(assetsBase, "_chroma", "style.css")
@@ -112,26 +553,278 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 		ChainId:    cfg.ChainID,
 		Analytics:  cfg.Analytics,
 		BuildTime:  buildTime,
+
+		ReportURLTemplate: cfg.ReportURLTemplate,
+		LiveReload:        cfg.LiveReload,
+		Realtime:          cfg.Realtime,
+		OpenSearch:        cfg.OpenSearch,
+		Locales:           cfg.Locales,
+		DeprecatedPaths:   cfg.DeprecatedPaths,
+		Preconnect:        cfg.Preconnect,
+		OGImages:          cfg.OGImageDir != "",
+		TxExport:          cfg.TxExport,
+	}
+	if cfg.ShowBuildInfo {
+		staticMeta.BuildVersion = version.Version
+	}
+	if !cfg.NoAssetsCache {
+		staticMeta.AssetNames = assetNames
+	}
+
+	// Setup theme
+	var themeHandler http.Handler
+	themePath := assetsBase + ThemeFile
+	if cfg.Theme != "" {
+		theme, err := lookupTheme(cfg.Theme)
+		if err != nil {
+			return nil, err
+		}
+		css, err := theme.Stylesheet()
+		if err != nil {
+			return nil, err
+		}
+		if err := components.OverrideTemplates(theme.Templates); err != nil {
+			return nil, err
+		}
+
+		var themeHash string
+		themeHandler, themeHash = newThemeCSSHandler(css)
+		if cfg.NoAssetsCache {
+			themeHandler = NoCacheHandler(themeHandler)
+		} else {
+			themeHandler = CacheHandler(strconv.Quote(themeHash), themeHandler)
+		}
+		staticMeta.ThemePath = themePath + "?v=" + themeHash[:10]
+	}
+
+	// Setup page chrome translations
+	if cfg.CatalogDir != "" {
+		if err := LoadCatalogDir(cfg.CatalogDir); err != nil {
+			return nil, err
+		}
+	}
+	locales, err := uiLocales(cfg.DefaultLocale, cfg.UILocales)
+	if err != nil {
+		return nil, err
 	}
 
 	// Configure Markdown renderer
 	rcfg := cfg.RenderConfig
+	rcfg.NormalizeWhitespace = rcfg.NormalizeWhitespace || cfg.NormalizeWhitespace
+	rcfg.CodeLineAnchors = rcfg.CodeLineAnchors || cfg.CodeLineAnchors
+	rcfg.JSONTreeViewer = rcfg.JSONTreeViewer || cfg.JSONTreeViewer
+	rcfg.HardLineBreaks = rcfg.HardLineBreaks || cfg.HardLineBreaks
 	if cfg.UnsafeHTML {
 		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithRendererOptions(
 			mdhtml.WithXHTML(), mdhtml.WithUnsafe(),
 		))
 	}
+	var blockCache *md.BlockCache
+	if cfg.BlockCacheSize > 0 {
+		if blockCache, err = md.NewBlockCache(cfg.BlockCacheSize); err != nil {
+			return nil, fmt.Errorf("unable to create block cache: %w", err)
+		}
+	}
+	if cfg.MathServerRender || cfg.MathKaTeXURL != "" {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewMathExtension(
+				md.WithMathServerRender(cfg.MathServerRender),
+				md.WithMathKaTeX(cfg.MathKaTeXURL),
+				md.WithMathCache(blockCache),
+			),
+		))
+		if origin := cdnOrigin(cfg.MathKaTeXURL); origin != "" && !cfg.MathServerRender {
+			staticMeta.CDNOrigins = append(staticMeta.CDNOrigins, origin)
+		}
+	}
+	if cfg.DotDiagrams {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewDotExtension(md.WithDotRuntimeURL(cfg.DotRuntimeURL)),
+		))
+		if origin := cdnOrigin(cfg.DotRuntimeURL); origin != "" {
+			staticMeta.CDNOrigins = append(staticMeta.CDNOrigins, origin)
+		}
+	}
+	if cfg.MermaidDiagrams {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewMermaidExtension(md.WithMermaidRuntimeURL(cfg.MermaidRuntimeURL)),
+		))
+		if origin := cdnOrigin(cfg.MermaidRuntimeURL); origin != "" {
+			staticMeta.CDNOrigins = append(staticMeta.CDNOrigins, origin)
+		}
+	}
+	if cfg.PflowDiagrams {
+		if !validIntegrity(cfg.PflowJSIntegrity) || !validIntegrity(cfg.PflowCSSIntegrity) {
+			return nil, errors.New("invalid pflow assets integrity")
+		}
+		pflowOpts := []md.PflowOption{
+			md.WithPflowCDN(cfg.PflowCDN),
+			md.WithPflowIntegrity(cfg.PflowJSIntegrity, cfg.PflowCSSIntegrity),
+			md.WithPflowCache(blockCache),
+		}
+		if hasPflowAssets(assetFS()) {
+			pflowOpts = append(pflowOpts, md.WithPflowFallback(assetsBase+pflowAssetsDir))
+		}
+		if cfg.PflowSimulate {
+			pflowOpts = append(pflowOpts, md.WithPflowSimulate(PflowSimulatePath))
+		}
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewPflowExtension(pflowOpts...),
+		))
+		if origin := cdnOrigin(cfg.PflowCDN); origin != "" {
+			staticMeta.CDNOrigins = append(staticMeta.CDNOrigins, origin)
+		}
+	}
+	if cfg.EmojiShortcodes {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewEmojiExtension(),
+		))
+	}
+	if cfg.ParagraphAnchors {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewAnchorsExtension(),
+		))
+	}
+	if cfg.CopyAsCurl {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewHTTPCurlExtension(),
+		))
+	}
+	if cfg.StripHTMLComments {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewStripCommentsExtension(),
+		))
+	}
+	if cfg.DevMode {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewAltTextWarningExtension(),
+		))
+	}
+	rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+		md.NewConditionalExtension(cfg.ContentFlags...),
+	))
+	var tocOpts []md.TocOption
+	if cfg.AutoToc {
+		tocOpts = append(tocOpts, md.WithTocAuto())
+	}
+	rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+		md.NewTocExtension(tocOpts...),
+	))
+	if cfg.HeadingSpamGuard {
+		opts := []md.HeadingSpamOption{md.WithHeadingSpamRatio(cfg.HeadingSpamRatio)}
+		if !cfg.DevMode {
+			opts = append(opts, md.WithHeadingSpamCollapse())
+		}
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewHeadingSpamGuardExtension(opts...),
+		))
+	}
+	if cfg.DevMode && cfg.CheckInternalLinks {
+		checker := newLinkChecker(adpcli, cfg.Domain)
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewBrokenLinksExtension(checker.Exists),
+		))
+	}
+	var imageProxyKey []byte
+	if cfg.ImageProxy {
+		imageProxyKey = []byte(cfg.ImageProxyKey)
+		if len(imageProxyKey) == 0 {
+			imageProxyKey = make([]byte, 32)
+			rand.Read(imageProxyKey)
+		}
+		sign := func(url string) string { return signImageProxyURL(imageProxyKey, url) }
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewImageProxyExtension(ImageProxyPath, md.WithImageProxySigner(sign)),
+		))
+	}
+	if cfg.CallLinks {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewCallLinkExtension(),
+		))
+	}
+	if cfg.SourceEmbeds {
+		fetcher := newSourceFetcher(adpcli)
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewSourceEmbedExtension(fetcher.Fetch, md.WithSourceEmbedChroma(rcfg.ChromaStyle, rcfg.ChromaOptions...)),
+		))
+	}
+	if cfg.SourceRefBase != "" {
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(
+			md.NewSourceRefsExtension(cfg.SourceRefBase),
+		))
+	}
+	if len(cfg.MarkdownExtensions) > 0 {
+		exts, err := lookupMarkdownExtensions(cfg.MarkdownExtensions)
+		if err != nil {
+			return nil, err
+		}
+		rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(exts...))
+	}
 	renderer := NewHTMLRenderer(logger, rcfg)
 
+	var textRenderer Renderer
+	if cfg.TextMode {
+		tcfg := rcfg
+		tcfg.JSONTreeViewer = false
+		tcfg.GoldmarkOptions = append(slices.Clip(rcfg.GoldmarkOptions), goldmark.WithExtensions(
+			md.NewTextModeExtension(),
+		))
+		textRenderer = NewHTMLRenderer(logger, tcfg)
+	}
+
 	// Configure HTTPHandler
 	if cfg.Aliases == nil {
 		cfg.Aliases = make(map[string]AliasTarget) // Sanitize Aliases cfg
 	}
+
+	var renderErrors *RenderErrorLog
+	if cfg.RenderErrorHistory {
+		if cfg.AdminPassword == "" {
+			return nil, errors.New("render error history requires an admin password")
+		}
+		renderErrors = NewRenderErrorLog(height)
+	}
+
+	var search *SearchEngine
+	if cfg.FullTextSearch {
+		search = NewSearchEngine(logger, adpcli, cfg.Domain, cfg.FullTextSearchTTL)
+	}
+
+	var (
+		account   AccountFunc
+		usersEval EvalFunc
+	)
+	if cfg.AccountProfiles {
+		account = NewRPCAccountFunc(logger, rpcclient)
+		usersEval = NewRPCEvalFunc(logger, rpcclient, cfg.Domain)
+	}
+
 	httphandler, err := NewHTTPHandler(logger, &HTTPHandlerConfig{
 		ClientAdapter: adpcli,
 		Meta:          staticMeta,
 		Renderer:      renderer,
 		Aliases:       cfg.Aliases,
+
+		ProgressiveRender:   cfg.ProgressiveRender,
+		ServeMarkdownSource: cfg.ServeMarkdownSource,
+		ListingSort:         cfg.ListingSort,
+		ListingPerPage:      cfg.ListingPerPage,
+		MaxTitleLength:      cfg.MaxTitleLength,
+		WarnPaths:           cfg.WarnPaths,
+		HomeGroups:          cfg.HomeGroups,
+		HomeGroupsOther:     cfg.HomeGroupsOther,
+		ServeStaleOnOutage:  cfg.ServeStaleOnOutage,
+		RenderErrors:        renderErrors,
+		TextRenderer:        textRenderer,
+		Feeds:               cfg.Feeds,
+		FeedEval:            NewRPCEvalFunc(logger, rpcclient, cfg.Domain),
+		Search:              search,
+		HistoricalRender:    cfg.HistoricalRender,
+		SourceDiff:          cfg.SourceDiff,
+		DependencyGraph:     cfg.DependencyGraph,
+		Account:             account,
+		UsersEval:           usersEval,
+		TxIndexer:           cfg.TxIndexer,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create web handler: %w", err)
@@ -140,8 +833,82 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 	// Setup HTTP muxer
 	mux := http.NewServeMux()
 
+	// Rate limited routes, all limited by a single limiter wrapping the mux
+	routeLimits := make(map[string]RateLimit)
+	limitRoute := func(pattern string, h http.Handler, limit RateLimit) {
+		mux.Handle(pattern, h)
+		routeLimits[pattern] = limit
+	}
+
 	// Handle web handler with redirect middleware
-	mux.Handle("/", RedirectMiddleware(httphandler, cfg.Analytics))
+	var pagehandler http.Handler = httphandler
+	if cfg.SnapshotDir != "" {
+		pagehandler = snapshotMiddleware(logger, pagehandler, cfg.SnapshotDir, height, cfg.HistoricalRender, httphandler.contentWarningRequired)
+	}
+	if cfg.PageCacheSize > 0 && !cfg.ProgressiveRender {
+		if pagehandler, err = pageCacheMiddleware(logger, pagehandler, height, cfg.PageCacheSize); err != nil {
+			return nil, fmt.Errorf("unable to create page cache: %w", err)
+		}
+	}
+
+	if cfg.Maintenance != nil {
+		pagehandler = maintenanceMiddleware(pagehandler, cfg.Maintenance, httphandler)
+	}
+
+	if len(locales) > 1 || locales[0] != components.DefaultLocale {
+		pagehandler = LocaleMiddleware(pagehandler, locales)
+	}
+
+	if len(cfg.ErrorPages) > 0 {
+		errorpages, err := parseErrorPages(cfg.ErrorPages)
+		if err != nil {
+			return nil, err
+		}
+		pagehandler = errorPagesMiddleware(logger, pagehandler, errorpages)
+	}
+
+	if cfg.PageViews != nil {
+		if cfg.AdminPassword == "" {
+			return nil, errors.New("page views require an admin password")
+		}
+
+		pagehandler = pageViewsMiddleware(pagehandler, cfg.PageViews)
+		mux.Handle(StatsPath, AdminAuthHandler(cfg.AdminPassword, handlerStats(cfg.PageViews)))
+	}
+
+	var redirects *RedirectTable
+	if cfg.RedirectsFile != "" {
+		redirects, err = LoadRedirectTable(logger, cfg.RedirectsFile)
+	} else {
+		redirects, err = NewRedirectTable(cfg.Redirects)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to load redirects: %w", err)
+	}
+
+	limitRoute("/", RedirectMiddleware(pagehandler, redirects, cfg.Analytics), cfg.RateLimit)
+
+	// Handle embeddable realms
+	if len(cfg.EmbedAllowedAncestors) > 0 {
+		embedhandler := handlerEmbed(httphandler, cfg.EmbedAllowedAncestors)
+		limitRoute(EmbedPath, embedhandler, cfg.RateLimit)
+	}
+
+	// Handle the JSON version of realms
+	if cfg.JSONAPI {
+		limitRoute(APIPath, handlerAPI(httphandler), cfg.RateLimit)
+	}
+
+	// Handle the social preview images of realms
+	if cfg.OGImageDir != "" {
+		oghandler := handlerOGImage(httphandler, cfg.OGImageDir)
+		limitRoute(OGImagePath, oghandler, cfg.RateLimit)
+	}
+
+	// Handle unsigned transactions export
+	if cfg.TxExport {
+		limitRoute(TxExportPath, handlerTxExport(httphandler), cfg.RateLimit)
+	}
 
 	// Register faucet URL to `/faucet` if specified
 	if cfg.FaucetURL != "" {
@@ -159,21 +926,137 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 		cacheAssetHandler = NoCacheHandler
 	}
 
-	// Handle Chroma CSS requests
-	// XXX: probably move this elsewhere
-	chromaStyleHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/css")
-		if err := renderer.WriteChromaCSS(w); err != nil {
-			logger.Error("unable to write CSS", "err", err)
-			http.NotFound(w, r)
-		}
-	})
-	mux.Handle(chromaStylePath, cacheAssetHandler(chromaStyleHandler))
+	// Handle Chroma CSS requests, generated once for all
+	chromaStyleHandler, chromaStyleHash, err := newChromaCSSHandler(renderer)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.NoAssetsCache {
+		mux.Handle(chromaStylePath, NoCacheHandler(chromaStyleHandler))
+	} else {
+		mux.Handle(chromaStylePath, CacheHandler(chromaStyleHash, chromaStyleHandler))
+	}
+
+	// Handle theme CSS requests
+	if themeHandler != nil {
+		mux.Handle(themePath, themeHandler)
+	}
 
 	// Handle assets path
-	assetsHandler := cacheAssetHandler(AssetHandler())
+	rawAssetsHandler := missingAssetHandler(logger, AssetHandler(), cfg.AssetPlaceholders)
+	assetsHandler := cacheAssetHandler(rawAssetsHandler)
+	if len(staticMeta.AssetNames) > 0 {
+		assetsHandler = hashedAssetsHandler(rawAssetsHandler, staticMeta.AssetNames, assetsHandler)
+	}
 	mux.Handle(assetsBase, http.StripPrefix(assetsBase, assetsHandler))
 
+	// Handle live reload dev endpoints
+	if cfg.LiveReload {
+		mux.Handle(LiveReloadPath, handlerLiveReload(logger, height, time.Second))
+		mux.Handle(LiveReloadScriptPath, handlerLiveReloadScript())
+	}
+
+	// Handle realtime notifications of realm pages
+	if cfg.Realtime {
+		hub := newRealtimeHub(logger, adpcli, height, cfg.RealtimeInterval)
+		limitRoute(RealtimePath, hub, cfg.RateLimit)
+		mux.Handle(RealtimeScriptPath, handlerRealtimeScript())
+	}
+
+	// Handle OpenSearch description
+	if cfg.OpenSearch {
+		mux.Handle(OpenSearchPath, handlerOpenSearch(cfg.Domain, assetsBase, cfg.FullTextSearch))
+	}
+
+	status := func(ctx context.Context) (*ctypes.ResultStatus, error) {
+		return rpcclient.Status(ctx, nil)
+	}
+
+	// Handle status badge
+	if cfg.StatusBadge {
+		mux.Handle(StatusBadgePath, handlerStatusBadge(logger, cfg.Domain, status))
+	}
+
+	// Handle image proxy
+	if cfg.ImageProxy {
+		proxyhandler := handlerImageProxy(logger, imageProxyKey, cfg.ImageProxyMaxSize)
+		limitRoute(ImageProxyPath, proxyhandler, cfg.RateLimit)
+	}
+
+	// Handle pflow simulation
+	if cfg.PflowSimulate {
+		simhandler := handlerPflowSimulate(logger)
+		limitRoute(PflowSimulatePath, simhandler, cfg.RateLimit)
+	}
+
+	// Handle read-only RPC gateway
+	if cfg.RPCGateway {
+		methods := cfg.RPCGatewayMethods
+		if methods == nil {
+			methods = RPCGatewayMethods
+		}
+
+		gatewayheight := cachedHeight(height, pageCacheHeightTTL)
+		gatewayhandler, err := handlerRPCGateway(logger, adpcli, status, gatewayheight, methods, cfg.WarnPaths)
+		if err != nil {
+			return nil, err
+		}
+		limitRoute(RPCGatewayPath, gatewayhandler, cfg.RPCGatewayRateLimit)
+	}
+
+	// Handle effective config for admins
+	if cfg.DebugConfig {
+		if cfg.AdminPassword == "" {
+			return nil, errors.New("debug config requires an admin password")
+		}
+
+		confighandler, err := handlerDebugConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle(DebugConfigPath, AdminAuthHandler(cfg.AdminPassword, confighandler))
+	}
+
+	// Handle maintenance mode toggle for admins
+	if cfg.Maintenance != nil && cfg.AdminPassword != "" {
+		mux.Handle(MaintenancePath, AdminAuthHandler(cfg.AdminPassword, handlerMaintenance(cfg.Maintenance)))
+	}
+
+	// Handle render errors history for admins
+	if renderErrors != nil {
+		mux.Handle(RenderErrorsPath, AdminAuthHandler(cfg.AdminPassword, handlerRenderErrors(renderErrors)))
+	}
+
+	// Handle Prometheus metrics
+	if metrics != nil {
+		var metricshandler http.Handler = metrics.Handler()
+		if cfg.AdminPassword != "" {
+			metricshandler = AdminAuthHandler(cfg.AdminPassword, metricshandler)
+		}
+		mux.Handle(MetricsPath, metricshandler)
+	}
+
+	// Handle humans.txt
+	mux.Handle(HumansTextPath, handlerHumansText(cfg.HumansText))
+
+	// Handle robots.txt
+	if err := cfg.Robots.validate(); err != nil {
+		return nil, err
+	}
+	mux.Handle(RobotsPath, handlerRobots(cfg.Robots, cfg.Sitemap))
+
+	// Handle static search index
+	if cfg.StaticSearchIndex {
+		searchhandler := handlerSearchIndex(logger, adpcli, cfg.Domain, cfg.SearchIndexTTL, cfg.WarnPaths)
+		limitRoute(SearchIndexPath, searchhandler, cfg.RateLimit)
+	}
+
+	// Handle sitemap
+	if cfg.Sitemap {
+		sitemaphandler := handlerSitemap(logger, adpcli, cfg.Domain, cfg.SitemapTTL)
+		limitRoute(SitemapPath, sitemaphandler, cfg.RateLimit)
+	}
+
 	// Handle status page
 	mux.Handle("/status.json", handlerStatusJSON(logger, rpcclient))
 
@@ -183,5 +1066,103 @@ func NewRouter(logger *slog.Logger, cfg *AppConfig) (http.Handler, error) {
 	// Handle readiness check - service can communicate with RPC node and serve clients
 	mux.Handle("/ready", handlerReadyJSON(logger, rpcclient, cfg.Domain))
 
-	return mux, nil
+	handler := assetCaseMiddleware(RateLimitMiddleware(mux, routeLimits, cfg.PathRateLimits), assetsBase, assetFS(), cfg.AssetCasePolicy)
+	if cfg.Compression {
+		handler = CompressionMiddleware(handler)
+	}
+	if metrics != nil {
+		handler = metrics.Middleware(handler, assetsBase)
+	}
+
+	if cfg.ValidateHost {
+		allowed := append([]string{cfg.Domain}, cfg.AllowedHosts...)
+		handler = HostValidationMiddleware(handler, allowed)
+	}
+
+	if len(cfg.RateLimitBypass) > 0 {
+		if handler, err = RateLimitBypassMiddleware(handler, cfg.RateLimitBypass); err != nil {
+			return nil, fmt.Errorf("unable to create rate limit bypass: %w", err)
+		}
+	}
+
+	if cfg.SecurityHeaders != nil {
+		handler = SecurityHeadersMiddleware(handler, *cfg.SecurityHeaders, staticMeta.CDNOrigins, cfg.CSPByPath)
+	}
+
+	handler = RequestLogMiddleware(logger, handler)
+	if cfg.Tracing {
+		handler = traceContextMiddleware(handler)
+	}
+	if cfg.TracerProvider != nil {
+		handler = SpanMiddleware(cfg.TracerProvider, handler)
+	}
+
+	return handler, nil
+}
+
+// assetPlaceholder is a transparent 1x1 GIF served in place of missing images.
+var assetPlaceholder = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\x00\x00\x00!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+// missingAssetHandler logs a warning, once per path, for assets not found by
+// the given handler. If `placeholder` is set, missing images are replaced by
+// a transparent placeholder.
+func missingAssetHandler(logger *slog.Logger, next http.Handler, placeholder bool) http.Handler {
+	const maxWarned = 1024 // bound memory usage on arbitrary paths
+
+	var (
+		mu     sync.Mutex
+		warned = make(map[string]struct{})
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mw := &missingAssetWriter{ResponseWriter: w}
+		next.ServeHTTP(mw, r)
+		if !mw.missing {
+			return
+		}
+
+		mu.Lock()
+		_, seen := warned[r.URL.Path]
+		if !seen && len(warned) < maxWarned {
+			warned[r.URL.Path] = struct{}{}
+			logger.Warn("embedded asset is missing", "path", r.URL.Path)
+		}
+		mu.Unlock()
+
+		switch path.Ext(r.URL.Path) {
+		case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".ico":
+			if placeholder {
+				// Don't let the placeholder outlive the missing asset
+				w.Header().Set("Content-Type", "image/gif")
+				w.Header().Set("Cache-Control", "no-store")
+				w.WriteHeader(http.StatusOK)
+				w.Write(assetPlaceholder)
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+// missingAssetWriter discards the response of a not found asset so that it
+// can be replaced.
+type missingAssetWriter struct {
+	http.ResponseWriter
+	missing bool
+}
+
+func (w *missingAssetWriter) WriteHeader(code int) {
+	if code == http.StatusNotFound {
+		w.missing = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *missingAssetWriter) Write(b []byte) (int, error) {
+	if w.missing {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
 }
@@ -1,11 +1,16 @@
 package gnoweb
 
 import (
+	"bytes"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/rs/xid"
 
@@ -116,6 +121,42 @@ func TestRoutes(t *testing.T) {
 	}
 }
 
+// flushRecorder is an httptest.ResponseRecorder counting flushes.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestProgressiveRenderWithPageCache(t *testing.T) {
+	logger := log.NewTestingLogger(t)
+	rootdir := gnoenv.RootDir()
+	genesis := integration.LoadDefaultGenesisTXsFile(t, "tendermint_test", rootdir)
+	config, _ := integration.TestingNodeConfig(t, rootdir, genesis...)
+	node, remoteAddr := integration.TestingInMemoryNode(t, logger, config)
+	defer node.Stop()
+
+	cfg := NewDefaultAppConfig()
+	cfg.NodeRemote = remoteAddr
+	cfg.ProgressiveRender = true
+	require.Positive(t, cfg.PageCacheSize)
+
+	router, err := NewRouter(logger, cfg)
+	require.NoError(t, err)
+
+	for range 2 {
+		response := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/r/gnoland/users/v1", nil))
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.GreaterOrEqual(t, response.flushes, 2)
+		assert.Empty(t, response.Header().Get("X-Gnoweb-Cache"))
+	}
+}
+
 func TestAnalytics(t *testing.T) {
 	routes := []string{
 		// Special realms
@@ -252,3 +293,96 @@ func TestHealthEndpoints(t *testing.T) {
 		})
 	})
 }
+
+func TestMissingAssetHandler(t *testing.T) {
+	t.Parallel()
+
+	assets := http.FileServer(http.FS(fstest.MapFS{
+		"main.css": &fstest.MapFile{Data: []byte("body{}")},
+	}))
+
+	serve := func(h http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("warn once", func(t *testing.T) {
+		t.Parallel()
+
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&logs, nil))
+		handler := missingAssetHandler(logger, assets, false)
+
+		rec := serve(handler, "/main.css")
+		assert.Equal(t, ok, rec.Code)
+		assert.Empty(t, logs.String())
+
+		for range 2 {
+			rec = serve(handler, "/missing.js")
+			assert.Equal(t, notFound, rec.Code)
+		}
+		assert.Equal(t, 1, strings.Count(logs.String(), "embedded asset is missing"))
+		assert.Contains(t, logs.String(), "path=/missing.js")
+	})
+
+	t.Run("image placeholder", func(t *testing.T) {
+		t.Parallel()
+
+		cached := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			assets.ServeHTTP(w, r)
+		})
+		handler := missingAssetHandler(log.NewNoopLogger(), cached, true)
+
+		rec := serve(handler, "/img/logo.png")
+		assert.Equal(t, ok, rec.Code)
+		assert.Equal(t, "image/gif", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, assetPlaceholder, rec.Body.Bytes())
+
+		rec = serve(handler, "/missing.js")
+		assert.Equal(t, notFound, rec.Code)
+	})
+}
+
+func TestAssetHandlerRanges(t *testing.T) {
+	t.Parallel()
+
+	const font = "fonts/intervar/Intervar.woff2"
+	content, err := fs.ReadFile(assetFS(), font)
+	require.NoError(t, err)
+
+	handler := DefaultCacheAssetsHandler(AssetHandler())
+	get := func(header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/"+font, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("Range", "bytes=10-19")
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, fmt.Sprintf("bytes 10-19/%d", len(content)), rec.Header().Get("Content-Range"))
+	assert.Equal(t, content[10:20], rec.Body.Bytes())
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Skip("assets are not cached, If-Range cannot be validated")
+	}
+
+	// A matching validator resumes the download
+	rec = get("Range", "bytes=10-19", "If-Range", etag)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, content[10:20], rec.Body.Bytes())
+
+	// A stale validator downloads the whole file
+	rec = get("Range", "bytes=10-19", "If-Range", `"stale"`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, content, rec.Body.Bytes())
+}
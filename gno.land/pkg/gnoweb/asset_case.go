@@ -0,0 +1,66 @@
+package gnoweb
+
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+// AssetCasePolicy defines how asset requests whose casing differs from the
+// one of the served file, such as `/Public/Main.css`, are handled.
+type AssetCasePolicy string
+
+const (
+	// AssetCaseAsIs forwards such requests to the file system as is, which
+	// may or may not find the file depending on its case sensitivity.
+	AssetCaseAsIs AssetCasePolicy = ""
+	// AssetCaseNotFound responds to such requests with a 404.
+	AssetCaseNotFound AssetCasePolicy = "notfound"
+	// AssetCaseRedirect permanently redirects such requests to the
+	// canonical path of the file.
+	AssetCaseRedirect AssetCasePolicy = "redirect"
+)
+
+// assetCaseMiddleware applies the given policy to requests of assets served
+// under base from files, whose path only matches a file case-insensitively.
+// Other requests are forwarded to next.
+func assetCaseMiddleware(next http.Handler, base string, files fs.FS, policy AssetCasePolicy) http.Handler {
+	if policy == AssetCaseAsIs {
+		return next
+	}
+
+	// Index the canonical paths of files by their lowercase path
+	canonical := make(map[string]string)
+	fs.WalkDir(files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		canonical[strings.ToLower(p)] = base + p
+		return nil
+	})
+
+	lbase := strings.ToLower(base)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lpath := strings.ToLower(r.URL.Path)
+		if !strings.HasPrefix(lpath, lbase) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target, ok := canonical[lpath[len(lbase):]]
+		if !ok || target == r.URL.Path {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch policy {
+		case AssetCaseRedirect:
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetCaseMiddleware(t *testing.T) {
+	t.Parallel()
+
+	files := fstest.MapFS{
+		"main.css":                     {Data: []byte("body{}")},
+		"fonts/intervar/Intervar.woff": {Data: []byte("font")},
+	}
+	next := http.StripPrefix("/public/", http.FileServer(http.FS(files)))
+
+	cases := []struct {
+		policy   AssetCasePolicy
+		path     string
+		status   int
+		location string
+	}{
+		{AssetCaseNotFound, "/public/main.css", http.StatusOK, ""},
+		{AssetCaseNotFound, "/public/fonts/intervar/Intervar.woff", http.StatusOK, ""},
+		{AssetCaseNotFound, "/Public/Main.css", http.StatusNotFound, ""},
+		{AssetCaseNotFound, "/public/fonts/intervar/intervar.woff", http.StatusNotFound, ""},
+		{AssetCaseRedirect, "/public/main.css", http.StatusOK, ""},
+		{AssetCaseRedirect, "/Public/Main.css", http.StatusMovedPermanently, "/public/main.css"},
+		{AssetCaseRedirect, "/public/MAIN.css?v=1", http.StatusMovedPermanently, "/public/main.css?v=1"},
+		{AssetCaseRedirect, "/public/fonts/intervar/intervar.woff", http.StatusMovedPermanently, "/public/fonts/intervar/Intervar.woff"},
+		{AssetCaseRedirect, "/public/missing.css", http.StatusNotFound, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.policy)+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			handler := assetCaseMiddleware(next, "/public/", files, tc.policy)
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, tc.location, rr.Header().Get("Location"))
+		})
+	}
+
+	t.Run("as is", func(t *testing.T) {
+		t.Parallel()
+
+		// Without policy, requests reach the file system unchanged
+		rr := httptest.NewRecorder()
+		assetCaseMiddleware(next, "/public/", files, AssetCaseAsIs).
+			ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/public/Main.css", nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package gnoweb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+// StatusBadgePath is the path of the node status badge.
+const StatusBadgePath = "/badge.svg"
+
+// statusBadgeTimeout bounds the node status query of the badge.
+const statusBadgeTimeout = 3 * time.Second
+
+// nodeStatusFunc returns the status of the node.
+type nodeStatusFunc func(ctx context.Context) (*ctypes.ResultStatus, error)
+
+// badgeState is the node state displayed by the status badge, along with
+// its color.
+type badgeState struct {
+	text, color string
+}
+
+var (
+	badgeOnline  = badgeState{"online", "#3fb950"}
+	badgeSyncing = badgeState{"syncing", "#d29922"}
+	badgeOffline = badgeState{"offline", "#e5534b"}
+)
+
+// handlerStatusBadge serves an SVG badge labeled with the given name,
+// displaying whether the node is online, syncing or offline.
+func handlerStatusBadge(logger *slog.Logger, label string, status nodeStatusFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), statusBadgeTimeout)
+		defer cancel()
+
+		state := badgeOnline
+		switch res, err := status(ctx); {
+		case err != nil:
+			logger.Debug("unable to query node status for badge", "error", err)
+			state = badgeOffline
+		case res.SyncInfo.CatchingUp:
+			state = badgeSyncing
+		}
+
+		svg := renderStatusBadge(label, state)
+		sum := sha256.Sum256([]byte(svg))
+		etag := strconv.Quote(hex.EncodeToString(sum[:8]))
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write([]byte(svg))
+	})
+}
+
+// renderStatusBadge renders a flat badge, sized after its texts.
+func renderStatusBadge(label string, state badgeState) string {
+	const charWidth, padding = 7, 10
+
+	lw := len([]rune(label))*charWidth + padding
+	sw := len(state.text)*charWidth + padding
+	label = html.EscapeString(label)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<rect width="%[2]d" height="20" fill="#555"/>`+
+		`<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="14">%[4]s</text><text x="%[8]d" y="14">%[5]s</text></g></svg>`,
+		lw+sw, lw, sw, label, state.text, state.color, lw/2, lw+sw/2)
+}
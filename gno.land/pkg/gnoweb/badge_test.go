@@ -0,0 +1,62 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerStatusBadge(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	get := func(status nodeStatusFunc, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, StatusBadgePath, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rr := httptest.NewRecorder()
+		handlerStatusBadge(logger, "gno.land", status).ServeHTTP(rr, req)
+		return rr
+	}
+
+	online := func(ctx context.Context) (*ctypes.ResultStatus, error) {
+		return &ctypes.ResultStatus{}, nil
+	}
+	syncing := func(ctx context.Context) (*ctypes.ResultStatus, error) {
+		return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{CatchingUp: true}}, nil
+	}
+	offline := func(ctx context.Context) (*ctypes.ResultStatus, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	rr := get(online, "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/svg+xml", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), `aria-label="gno.land: online"`)
+	assert.Contains(t, rr.Body.String(), badgeOnline.color)
+	onlineETag := rr.Header().Get("ETag")
+	require.NotEmpty(t, onlineETag)
+
+	// Unchanged status is revalidated
+	rr = get(online, onlineETag)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = get(syncing, onlineETag)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `aria-label="gno.land: syncing"`)
+
+	rr = get(offline, onlineETag)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `aria-label="gno.land: offline"`)
+	assert.Contains(t, rr.Body.String(), badgeOffline.color)
+	assert.NotEqual(t, onlineETag, rr.Header().Get("ETag"))
+}
@@ -1,6 +1,14 @@
 package gnoweb
 
-import "net/http"
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // CacheHandler adds ETag and Cache-Control headers to all asset responses for caching.
 func CacheHandler(hash string, next http.Handler) http.Handler {
@@ -19,6 +27,37 @@ func CacheHandler(hash string, next http.Handler) http.Handler {
 	})
 }
 
+// hashedAssetName returns the given asset name with the given content hash
+// inserted before its extension, such as `main.0123456789.css`.
+func hashedAssetName(name string, sum []byte) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:5]) + ext
+}
+
+// hashedAssetsHandler serves the requests of assets by their content-hashed
+// name, as mapped by `names`, from `assets` with immutable cache headers,
+// since their content never changes under that name. Other requests are
+// forwarded to next.
+func hashedAssetsHandler(assets http.Handler, names map[string]string, next http.Handler) http.Handler {
+	originals := make(map[string]string, len(names))
+	for name, hashed := range names {
+		originals[hashed] = name
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := originals[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.Clone(r.Context())
+		r.URL.Path, r.URL.RawPath = name, ""
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		assets.ServeHTTP(w, r)
+	})
+}
+
 // NoCacheHandler always invalidates cache for all responses.
 func NoCacheHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,3 +65,52 @@ func NoCacheHandler(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// PrivateCacheHandler forbids any caching of the responses, overriding the
+// Cache-Control header set by `next`. It is meant for sensitive responses,
+// such as the ones of authenticated endpoints, which must never be stored
+// by shared caches.
+func PrivateCacheHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&privateCacheWriter{ResponseWriter: w}, r)
+	})
+}
+
+// privateCacheWriter enforces the private Cache-Control header right before
+// the response headers are sent.
+type privateCacheWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *privateCacheWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Del("Expires")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *privateCacheWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *privateCacheWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// serveGenerated serves content generated at modtime and regenerated once
+// older than ttl, letting clients and shared caches keep it for the
+// remaining duration. Conditional requests are answered by
+// http.ServeContent, based on the `Last-Modified` header.
+func serveGenerated(w http.ResponseWriter, r *http.Request, contentType string, data []byte, modtime time.Time, ttl time.Duration) {
+	maxAge := max(ttl-time.Since(modtime), 0)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	http.ServeContent(w, r, "", modtime, bytes.NewReader(data))
+}
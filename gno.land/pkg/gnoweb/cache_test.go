@@ -0,0 +1,41 @@
+package gnoweb_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateCacheHandler(t *testing.T) {
+	t.Parallel()
+
+	for name, next := range map[string]http.Handler{
+		"no policy": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("secret"))
+		}),
+		"public policy": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+			w.Header().Set("Expires", "Wed, 21 Oct 2099 07:28:00 GMT")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("secret"))
+		}),
+		"cached assets": gnoweb.CacheHandler("hash", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("secret"))
+		})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			gnoweb.PrivateCacheHandler(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "private, no-store", rr.Header().Get("Cache-Control"))
+			assert.Empty(t, rr.Header().Get("Expires"))
+			assert.Equal(t, "secret", rr.Body.String())
+		})
+	}
+}
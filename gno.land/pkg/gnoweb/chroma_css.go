@@ -0,0 +1,39 @@
+package gnoweb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// chromaCSSWriter writes the syntax highlighting stylesheet.
+type chromaCSSWriter interface {
+	WriteChromaCSS(w io.Writer) error
+}
+
+// newChromaCSSHandler generates the syntax highlighting stylesheet once,
+// and returns a handler serving it along with its ETag. The stylesheet
+// never changes during the process lifetime, so it is not worth
+// regenerating on each request.
+func newChromaCSSHandler(cw chromaCSSWriter) (http.Handler, string, error) {
+	var css bytes.Buffer
+	if err := cw.WriteChromaCSS(&css); err != nil {
+		return nil, "", fmt.Errorf("unable to generate chroma CSS: %w", err)
+	}
+
+	sum := sha256.Sum256(css.Bytes())
+	etag := strconv.Quote(hex.EncodeToString(sum[:]))
+
+	content := css.Bytes()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		http.ServeContent(w, r, "style.css", time.Time{}, bytes.NewReader(content))
+	})
+
+	return handler, etag, nil
+}
@@ -0,0 +1,56 @@
+package gnoweb
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCSSWriter struct {
+	calls int
+}
+
+func (c *countingCSSWriter) WriteChromaCSS(w io.Writer) error {
+	c.calls++
+	_, err := io.WriteString(w, ".chroma-kd { color: red }")
+	return err
+}
+
+func TestChromaCSSHandler(t *testing.T) {
+	t.Parallel()
+
+	cw := &countingCSSWriter{}
+	handler, etag, err := newChromaCSSHandler(cw)
+	require.NoError(t, err)
+	require.NotEmpty(t, etag)
+	handler = CacheHandler(etag, handler)
+
+	for range 3 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_chroma/style.css", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/css", rr.Header().Get("Content-Type"))
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+		assert.Equal(t, ".chroma-kd { color: red }", rr.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/_chroma/style.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/_chroma/style.css", nil)
+	req.Header.Set("Range", "bytes=0-7")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, ".chroma-", rr.Body.String())
+
+	// The stylesheet was only generated at construction
+	assert.Equal(t, 1, cw.calls)
+}
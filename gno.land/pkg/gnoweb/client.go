@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	gopath "path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/doc"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"go.opentelemetry.io/otel/codes"
 )
 
 var (
@@ -21,6 +23,7 @@ var (
 	ErrClientRenderNotDeclared = errors.New("render function not declared")
 	ErrClientBadRequest        = errors.New("bad request")
 	ErrClientTimeout           = errors.New("RPC node request timeout")
+	ErrClientTransport         = errors.New("RPC node transport error")
 	ErrClientResponse          = errors.New("RPC node response error")
 )
 
@@ -134,7 +137,10 @@ func (c *rpcClient) ListFiles(ctx context.Context, path string) ([]string, error
 // Sources lists all source files available in a specified
 // package path by querying the RPC client.
 func (c *rpcClient) ListPaths(ctx context.Context, prefix string, limit int) ([]string, error) {
-	const qpath = "vm/qpaths"
+	qpath := "vm/qpaths"
+	if limit > 0 {
+		qpath += "?limit=" + strconv.Itoa(limit)
+	}
 
 	// XXX: Consider moving this into gnoclient
 	res, err := c.query(ctx, qpath, []byte(prefix))
@@ -171,17 +177,49 @@ func (c *rpcClient) Doc(ctx context.Context, pkgPath string) (*doc.JSONDocumenta
 	return jdoc, nil
 }
 
+// NewRPCEvalFunc returns an EvalFunc evaluating expressions on the node.
+func NewRPCEvalFunc(logger *slog.Logger, cli *client.RPCClient, domain string) EvalFunc {
+	c := &rpcClient{logger: logger, domain: domain, client: cli}
+	return c.eval
+}
+
+// eval evaluates the expression within the realm at the given path.
+func (c *rpcClient) eval(ctx context.Context, path, expr string) ([]byte, error) {
+	const qpath = "vm/qeval"
+
+	data := fmt.Sprintf("%s/%s.%s", c.domain, strings.Trim(path, "/"), expr)
+	return c.query(ctx, qpath, []byte(data))
+}
+
 // query sends a query to the RPC client and returns the response
 // data.
 func (c *rpcClient) query(ctx context.Context, qpath string, data []byte) ([]byte, error) {
-	c.logger.Info("querying node", "path", qpath, "data", string(data))
+	logger := c.logger
+	if traceID := traceIDFromContext(ctx); traceID != "" {
+		logger = logger.With("trace_id", traceID)
+	}
+	if requestID := requestIDFromContext(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+
+	opts := client.DefaultABCIQueryOptions
+	if height := queryHeightFromContext(ctx); height > 0 {
+		opts.Height = height
+		logger = logger.With("height", height)
+	}
+
+	logger.Info("querying node", "path", qpath, "data", string(data))
+
+	ctx, span := startQuerySpan(ctx, qpath, opts.Height)
+	defer span.End()
 
 	start := time.Now()
-	qres, err := c.client.ABCIQuery(ctx, qpath, data)
+	qres, err := c.client.ABCIQueryWithOptions(ctx, qpath, data, opts)
 	took := time.Since(start)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		// Unexpected error from the RPC client itself
-		c.logger.Error("query request failed",
+		logger.Error("query request failed",
 			"path", qpath,
 			"data", string(data),
 			"error", err,
@@ -192,11 +230,11 @@ func (c *rpcClient) query(ctx context.Context, qpath string, data []byte) ([]byt
 			return nil, fmt.Errorf("%w: %s", ErrClientTimeout, err.Error())
 		}
 
-		return nil, fmt.Errorf("%w: %s", ErrClientBadRequest, err.Error())
+		return nil, fmt.Errorf("%w: %s", ErrClientTransport, err.Error())
 	}
 
 	// Log the response at debug level for detailed tracing
-	c.logger.Debug("query response received",
+	logger.Debug("query response received",
 		"path", qpath,
 		"data", string(data),
 		"response_error", qres.Response.Error,
@@ -211,21 +249,21 @@ func (c *rpcClient) query(ctx context.Context, qpath string, data []byte) ([]byt
 	// Handle and log known error types
 	switch {
 	case errors.Is(qerr, vm.InvalidPkgPathError{}), errors.Is(qerr, vm.InvalidPackageError{}):
-		c.logger.Warn("package not found",
+		logger.Warn("package not found",
 			"path", qpath,
 			"data", string(data),
 			"error", qres.Response.Error,
 		)
 		return nil, ErrClientPackageNotFound
 	case errors.Is(qerr, vm.InvalidFileError{}):
-		c.logger.Warn("file not found",
+		logger.Warn("file not found",
 			"path", qpath,
 			"data", string(data),
 			"error", qres.Response.Error,
 		)
 		return nil, ErrClientFileNotFound
 	case errors.Is(qerr, vm.NoRenderDeclError{}):
-		c.logger.Warn("render function not declared",
+		logger.Warn("render function not declared",
 			"path", qpath,
 			"data", string(data),
 			"error", qres.Response.Error,
@@ -235,7 +273,8 @@ func (c *rpcClient) query(ctx context.Context, qpath string, data []byte) ([]byt
 	}
 
 	// fallback on general error
-	c.logger.Error("node response error",
+	span.SetStatus(codes.Error, qerr.Error())
+	logger.Error("node response error",
 		"path", qpath,
 		"data", string(data),
 		"error", qres.Response.Error,
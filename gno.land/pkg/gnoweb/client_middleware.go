@@ -0,0 +1,200 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+)
+
+// ErrClientUnavailable is returned when the circuit breaker rejects a request.
+var ErrClientUnavailable = errors.New("RPC node unavailable")
+
+// ClientMiddleware decorates a ClientAdapter with additional behavior.
+type ClientMiddleware func(next ClientAdapter) ClientAdapter
+
+// ChainClient wraps the given client with the given middlewares. The first
+// middleware is the outermost one.
+func ChainClient(cli ClientAdapter, mws ...ClientMiddleware) ClientAdapter {
+	for i := len(mws) - 1; i >= 0; i-- {
+		cli = mws[i](cli)
+	}
+	return cli
+}
+
+// IsRetryableClientError reports whether the given error is a transient
+// error worth retrying, such as a timeout or a transport failure.
+func IsRetryableClientError(err error) bool {
+	return errors.Is(err, ErrClientTimeout) || errors.Is(err, ErrClientTransport)
+}
+
+// WithTimeout bounds each call to the underlying client with the given
+// timeout. A zero timeout disables the middleware.
+func WithTimeout(timeout time.Duration) ClientMiddleware {
+	return func(next ClientAdapter) ClientAdapter {
+		if timeout <= 0 {
+			return next
+		}
+
+		return newInterceptedClient(next, func(ctx context.Context, call clientCall) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := call(ctx)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrClientTimeout) {
+				return fmt.Errorf("%w: %w", ErrClientTimeout, err)
+			}
+			return err
+		})
+	}
+}
+
+// WithRetry retries failing calls up to `retries` times on retryable
+// errors, waiting `backoff` before the first retry and doubling the delay
+// on each subsequent one. Zero retries disables the middleware.
+func WithRetry(retries int, backoff time.Duration) ClientMiddleware {
+	return func(next ClientAdapter) ClientAdapter {
+		if retries <= 0 {
+			return next
+		}
+
+		return newInterceptedClient(next, func(ctx context.Context, call clientCall) error {
+			delay := backoff
+			for attempt := 0; ; attempt++ {
+				err := call(ctx)
+				if err == nil || attempt >= retries || !IsRetryableClientError(err) {
+					return err
+				}
+
+				select {
+				case <-ctx.Done():
+					return err
+				case <-time.After(delay):
+				}
+				delay *= 2
+			}
+		})
+	}
+}
+
+// WithCircuitBreaker stops forwarding calls for `cooldown` after `threshold`
+// consecutive retryable failures, failing fast with ErrClientUnavailable.
+// After the cooldown a single trial call is let through; its success closes
+// the circuit again. A zero threshold disables the middleware.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientMiddleware {
+	return func(next ClientAdapter) ClientAdapter {
+		if threshold <= 0 {
+			return next
+		}
+
+		cb := &circuitBreaker{threshold: threshold, cooldown: cooldown}
+		return newInterceptedClient(next, cb.intercept)
+	}
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool // a trial call is in flight
+}
+
+func (cb *circuitBreaker) intercept(ctx context.Context, call clientCall) error {
+	var probe bool
+	cb.mu.Lock()
+	if cb.failures >= cb.threshold {
+		if time.Now().Before(cb.openUntil) || cb.trial {
+			cb.mu.Unlock()
+			return ErrClientUnavailable
+		}
+		cb.trial, probe = true, true // half-open: let a single call through
+	}
+	cb.mu.Unlock()
+
+	err := call(ctx)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	// Calls made before the circuit opened don't end the trial
+	if probe {
+		cb.trial = false
+	}
+	switch {
+	case err == nil, !IsRetryableClientError(err):
+		// The node answered: close the circuit
+		cb.failures = 0
+	default:
+		cb.failures++
+		if cb.failures >= cb.threshold {
+			cb.openUntil = time.Now().Add(cb.cooldown)
+		}
+	}
+
+	return err
+}
+
+// clientCall is a single call to an underlying client.
+type clientCall func(ctx context.Context) error
+
+// clientInterceptor is invoked around each call made to an underlying client.
+type clientInterceptor func(ctx context.Context, call clientCall) error
+
+// interceptedClient implements ClientAdapter by routing every call through
+// an interceptor.
+type interceptedClient struct {
+	next      ClientAdapter
+	intercept clientInterceptor
+}
+
+var _ ClientAdapter = (*interceptedClient)(nil)
+
+func newInterceptedClient(next ClientAdapter, intercept clientInterceptor) ClientAdapter {
+	return &interceptedClient{next: next, intercept: intercept}
+}
+
+func (c *interceptedClient) Realm(ctx context.Context, path, args string) (out []byte, err error) {
+	err = c.intercept(ctx, func(ctx context.Context) (err error) {
+		out, err = c.next.Realm(ctx, path, args)
+		return err
+	})
+	return out, err
+}
+
+func (c *interceptedClient) File(ctx context.Context, path, filename string) (out []byte, meta FileMeta, err error) {
+	err = c.intercept(ctx, func(ctx context.Context) (err error) {
+		out, meta, err = c.next.File(ctx, path, filename)
+		return err
+	})
+	return out, meta, err
+}
+
+func (c *interceptedClient) ListFiles(ctx context.Context, path string) (files []string, err error) {
+	err = c.intercept(ctx, func(ctx context.Context) (err error) {
+		files, err = c.next.ListFiles(ctx, path)
+		return err
+	})
+	return files, err
+}
+
+func (c *interceptedClient) ListPaths(ctx context.Context, prefix string, limit int) (paths []string, err error) {
+	err = c.intercept(ctx, func(ctx context.Context) (err error) {
+		paths, err = c.next.ListPaths(ctx, prefix, limit)
+		return err
+	})
+	return paths, err
+}
+
+func (c *interceptedClient) Doc(ctx context.Context, path string) (jdoc *doc.JSONDocumentation, err error) {
+	err = c.intercept(ctx, func(ctx context.Context) (err error) {
+		jdoc, err = c.next.Doc(ctx, path)
+		return err
+	})
+	return jdoc, err
+}
@@ -0,0 +1,215 @@
+package gnoweb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyClient returns a stub client whose Realm call fails with the given
+// errors in order, then succeeds.
+func flakyClient(calls *int, errs ...error) *stubClient {
+	return &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			*calls++
+			if *calls <= len(errs) {
+				return nil, errs[*calls-1]
+			}
+			return []byte("ok"), nil
+		},
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retry transient errors", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		cli := gnoweb.WithRetry(2, time.Millisecond)(flakyClient(&calls, gnoweb.ErrClientTimeout, gnoweb.ErrClientTransport))
+
+		res, err := cli.Realm(context.Background(), "/r/test", "")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", string(res))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("give up after max retries", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		cli := gnoweb.WithRetry(1, time.Millisecond)(flakyClient(&calls, gnoweb.ErrClientTimeout, gnoweb.ErrClientTimeout))
+
+		_, err := cli.Realm(context.Background(), "/r/test", "")
+		require.ErrorIs(t, err, gnoweb.ErrClientTimeout)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("do not retry permanent errors", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		cli := gnoweb.WithRetry(3, time.Millisecond)(flakyClient(&calls, gnoweb.ErrClientPackageNotFound))
+
+		_, err := cli.Realm(context.Background(), "/r/test", "")
+		require.ErrorIs(t, err, gnoweb.ErrClientPackageNotFound)
+		assert.Equal(t, 1, calls)
+
+		// Bad requests fail the same way when retried
+		calls = 0
+		cli = gnoweb.WithRetry(3, time.Millisecond)(flakyClient(&calls, gnoweb.ErrClientBadRequest))
+
+		_, err = cli.Realm(context.Background(), "/r/test", "")
+		require.ErrorIs(t, err, gnoweb.ErrClientBadRequest)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	cli := gnoweb.WithTimeout(10 * time.Millisecond)(&stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	_, err := cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, gnoweb.ErrClientTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Retries are bounded by the timeout of the whole call
+	var calls int
+	hung := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			calls++
+			<-ctx.Done()
+			return nil, gnoweb.ErrClientTimeout
+		},
+	}
+	cli = gnoweb.ChainClient(hung,
+		gnoweb.WithTimeout(10*time.Millisecond),
+		gnoweb.WithRetry(3, time.Minute),
+	)
+
+	start := time.Now()
+	_, err = cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, gnoweb.ErrClientTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	failure := gnoweb.ErrClientTransport
+	cli := gnoweb.WithCircuitBreaker(2, 50*time.Millisecond)(flakyClient(&calls, failure, failure, failure))
+
+	// Open the circuit
+	for range 2 {
+		_, err := cli.Realm(context.Background(), "/r/test", "")
+		require.ErrorIs(t, err, failure)
+	}
+
+	// Circuit open: fail fast without reaching the client
+	_, err := cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, gnoweb.ErrClientUnavailable)
+	assert.Equal(t, 2, calls)
+
+	// After cooldown, the failing trial call reopens the circuit
+	time.Sleep(60 * time.Millisecond)
+	_, err = cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, failure)
+	_, err = cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, gnoweb.ErrClientUnavailable)
+	assert.Equal(t, 3, calls)
+
+	// After another cooldown, the successful trial call closes the circuit
+	time.Sleep(60 * time.Millisecond)
+	for range 2 {
+		res, err := cli.Realm(context.Background(), "/r/test", "")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", string(res))
+	}
+	assert.Equal(t, 5, calls)
+}
+
+func TestWithCircuitBreaker_Trial(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startedA, releaseA = make(chan struct{}), make(chan struct{})
+		startedC, releaseC = make(chan struct{}), make(chan struct{})
+	)
+	cli := gnoweb.WithCircuitBreaker(1, 0)(&stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			switch path {
+			case "/r/a":
+				close(startedA)
+				<-releaseA
+				return nil, gnoweb.ErrClientTransport
+			case "/r/b":
+				return nil, gnoweb.ErrClientTransport
+			case "/r/c":
+				close(startedC)
+				<-releaseC
+			}
+			return []byte("ok"), nil
+		},
+	})
+	realm := func(path string) error {
+		_, err := cli.Realm(context.Background(), path, "")
+		return err
+	}
+
+	// A call is in flight when another one opens the circuit
+	doneA := make(chan error)
+	go func() { doneA <- realm("/r/a") }()
+	<-startedA
+	require.ErrorIs(t, realm("/r/b"), gnoweb.ErrClientTransport)
+
+	// The trial call is let through
+	doneC := make(chan error)
+	go func() { doneC <- realm("/r/c") }()
+	<-startedC
+
+	// The completion of the earlier call doesn't end the trial
+	close(releaseA)
+	require.ErrorIs(t, <-doneA, gnoweb.ErrClientTransport)
+	require.ErrorIs(t, realm("/r/d"), gnoweb.ErrClientUnavailable)
+
+	// The successful trial call closes the circuit
+	close(releaseC)
+	require.NoError(t, <-doneC)
+	require.NoError(t, realm("/r/d"))
+}
+
+func TestChainClient(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	failure := errors.Join(gnoweb.ErrClientTimeout, errors.New("connection refused"))
+	cli := gnoweb.ChainClient(flakyClient(&calls, failure, failure, failure, failure),
+		gnoweb.WithCircuitBreaker(1, time.Minute),
+		gnoweb.WithTimeout(time.Second),
+		gnoweb.WithRetry(1, time.Millisecond),
+	)
+
+	// The retry middleware absorbs the first failure but the second one
+	// reaches the circuit breaker, which opens.
+	_, err := cli.Realm(context.Background(), "/r/test", "")
+	require.ErrorIs(t, err, gnoweb.ErrClientTimeout)
+	assert.Equal(t, 2, calls)
+
+	_, err = cli.ListFiles(context.Background(), "/r/test")
+	require.ErrorIs(t, err, gnoweb.ErrClientUnavailable)
+	assert.Equal(t, 2, calls)
+}
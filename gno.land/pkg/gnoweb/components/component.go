@@ -13,10 +13,17 @@ type Component interface {
 type TemplateComponent struct {
 	name string
 	data any
+
+	// locale, if set, overrides the locale carried by the writer.
+	locale string
 }
 
 func (c *TemplateComponent) Render(w io.Writer) error {
-	return tmpl.ExecuteTemplate(w, c.name, c.data)
+	locale := c.locale
+	if locale == "" {
+		locale = writerLocale(w)
+	}
+	return localeTemplate(locale).ExecuteTemplate(w, c.name, c.data)
 }
 
 func NewTemplateComponent(name string, data any) Component {
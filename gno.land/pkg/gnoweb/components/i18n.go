@@ -0,0 +1,156 @@
+package components
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of the messages of the embedded templates.
+const DefaultLocale = "en"
+
+// Catalog maps the English messages of the components, such as "Source" or
+// "Powered by gnoweb %s", to their translation.
+type Catalog map[string]string
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+var (
+	localesMu sync.Mutex
+	catalogs  = map[string]Catalog{}
+	// srcTmpl holds the templates, overrides included. It is never
+	// executed, so that it can be cloned for each locale.
+	srcTmpl *template.Template
+	// localized caches the templates of each locale.
+	localized = map[string]*template.Template{}
+)
+
+func init() {
+	files, err := fs.Glob(localesFS, "locales/*.json")
+	if err != nil {
+		panic("unable to list embed catalogs: " + err.Error())
+	}
+	for _, file := range files {
+		data, err := localesFS.ReadFile(file)
+		if err != nil {
+			panic("unable to read embed catalog: " + err.Error())
+		}
+		var cat Catalog
+		if err := json.Unmarshal(data, &cat); err != nil {
+			panic("unable to parse embed catalog " + file + ": " + err.Error())
+		}
+		catalogs[strings.TrimSuffix(path.Base(file), ".json")] = cat
+	}
+}
+
+// RegisterCatalog adds the given messages to the catalog of the locale, such
+// as "fr" or "pt-BR", overriding the existing translations of the same
+// messages.
+func RegisterCatalog(locale string, cat Catalog) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	merged := maps.Clone(catalogs[locale])
+	if merged == nil {
+		merged = Catalog{}
+	}
+	maps.Copy(merged, cat)
+	catalogs[locale] = merged
+	delete(localized, locale)
+}
+
+// Catalogs returns the sorted locales having a catalog, the default locale
+// included.
+func Catalogs() []string {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	locales := slices.Collect(maps.Keys(catalogs))
+	if _, ok := catalogs[DefaultLocale]; !ok {
+		locales = append(locales, DefaultLocale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// translate returns the translation of the message in the catalog, or the
+// message itself, formatted with the given arguments, if any.
+func translate(cat Catalog, msg string, args ...any) string {
+	if s, ok := cat[msg]; ok && s != "" {
+		msg = s
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// localeFuncs returns the template functions translating messages to the
+// given locale.
+func localeFuncs(locale string, cat Catalog) template.FuncMap {
+	return template.FuncMap{
+		"T": func(msg string, args ...any) string {
+			return translate(cat, msg, args...)
+		},
+		"lang": func() string {
+			return locale
+		},
+		"render": renderFunc(locale),
+	}
+}
+
+// localeTemplate returns the templates of the given locale, or the default
+// ones if the locale has no catalog.
+func localeTemplate(locale string) *template.Template {
+	if locale == "" || locale == DefaultLocale {
+		return tmpl.Load()
+	}
+
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	if t, ok := localized[locale]; ok {
+		return t
+	}
+	cat, ok := catalogs[locale]
+	if !ok {
+		return tmpl.Load()
+	}
+
+	t := template.Must(srcTmpl.Clone()).Funcs(localeFuncs(locale, cat))
+	localized[locale] = t
+	return t
+}
+
+// setTemplates replaces the templates of all locales.
+func setTemplates(t *template.Template) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	srcTmpl = t
+	clear(localized)
+	tmpl.Store(template.Must(t.Clone()))
+}
+
+// localeWriter carries the locale of the components rendered to it.
+type localeWriter struct {
+	io.Writer
+	locale string
+}
+
+// writerLocale returns the locale carried by the writer, if any.
+func writerLocale(w io.Writer) string {
+	if lw, ok := w.(*localeWriter); ok {
+		return lw.locale
+	}
+	return ""
+}
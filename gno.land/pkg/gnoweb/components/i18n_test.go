@@ -0,0 +1,56 @@
+package components
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	cat := Catalog{"Source": "Code", "Powered by gnoweb %s": "Servi par gnoweb %s"}
+
+	assert.Equal(t, "Code", translate(cat, "Source"))
+	assert.Equal(t, "Servi par gnoweb v1", translate(cat, "Powered by gnoweb %s", "v1"))
+	assert.Equal(t, "Actions", translate(cat, "Actions"))
+	assert.Equal(t, "Actions", translate(nil, "Actions"))
+}
+
+func TestLocalizedIndexLayout(t *testing.T) {
+	assert.Contains(t, Catalogs(), "fr")
+	assert.Contains(t, Catalogs(), DefaultLocale)
+
+	render := func(locale string) string {
+		var buf bytes.Buffer
+		require.NoError(t, IndexLayout(IndexData{
+			Mode:     ViewModeRealm,
+			Locale:   locale,
+			BodyView: StatusErrorComponent("invalid path"),
+		}).Render(&buf))
+		return buf.String()
+	}
+
+	en := render("")
+	assert.Contains(t, en, `<html lang="en">`)
+	assert.Contains(t, en, "Error: invalid path")
+	assert.Contains(t, en, "Go Back Home")
+
+	// Nested components are translated as well
+	fr := render("fr")
+	assert.Contains(t, fr, `<html lang="fr">`)
+	assert.Contains(t, fr, "Erreur : chemin invalide")
+	assert.Contains(t, fr, "Retour à l&#39;accueil")
+	assert.Contains(t, fr, "Contenu")
+
+	// Registered messages override the embedded catalogs
+	RegisterCatalog("fr", Catalog{"Go Back Home": "Accueil"})
+	t.Cleanup(func() {
+		RegisterCatalog("fr", Catalog{"Go Back Home": "Retour à l'accueil"})
+	})
+	assert.Contains(t, render("fr"), "Accueil")
+	assert.Contains(t, render("fr"), "Erreur : chemin invalide")
+
+	// Unknown locales fall back to the default messages
+	assert.Contains(t, render("xx"), "Go Back Home")
+}
@@ -0,0 +1,22 @@
+package components
+
+// EmbedData holds the data of the minimal embeddable layout, which renders
+// the content without the site chrome (header, navigation and footer).
+type EmbedData struct {
+	HeadData
+	Content Component
+}
+
+// EmbedLayout returns the minimal layout used to embed content in an iframe.
+func EmbedLayout(data EmbedData) Component {
+	return NewTemplateComponent("embed", data)
+}
+
+// EmbedRealmContent returns the component of a realm rendered content, to
+// be used within EmbedLayout.
+func EmbedRealmContent(content Component) Component {
+	return NewTemplateComponent("layout/article", ArticleData{
+		ComponentContent: content,
+		Classes:          "c-realm-view",
+	})
+}
@@ -4,7 +4,21 @@ type FooterData struct {
 	Analytics  bool
 	AssetsPath string
 	BuildTime  string
+	ReportURL  string
 	Sections   []FooterSection
+
+	// AssetNames maps asset names to their content-hashed name, if any.
+	AssetNames map[string]string
+
+	// BuildVersion, if set, is displayed as build information.
+	BuildVersion string
+
+	// LiveReloadScript, if set, is the path of the dev live reload script.
+	LiveReloadScript string
+
+	// RealtimeScript, if set, is the path of the script refreshing the
+	// page content when it changes on chain.
+	RealtimeScript string
 }
 
 type FooterLink struct {
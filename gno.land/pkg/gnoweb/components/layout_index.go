@@ -41,6 +41,53 @@ type HeadData struct {
 	Remote      string
 	ChainId     string
 	BuildTime   string
+
+	// AssetNames maps asset names to their content-hashed name, if any.
+	AssetNames map[string]string
+
+	// ThemePath, if set, is the path of the theme stylesheet, overriding
+	// the base one.
+	ThemePath string
+
+	// OpenSearchPath, if set, is the path of the OpenSearch description
+	// of the site named SiteName.
+	OpenSearchPath string
+	SiteName       string
+
+	// FeedURL, if set, is the URL of the Atom feed of the page.
+	FeedURL string
+
+	// Alternates lists the localized variants of the page.
+	Alternates []Alternate
+
+	// Preconnect lists the third party origins used by the page.
+	Preconnect []string
+}
+
+// Alternate is a localized variant of a page, referenced with `hreflang`.
+type Alternate struct {
+	Lang string
+	URL  string
+}
+
+// DeprecationData describes a deprecated realm, and either the URL of its
+// replacement or a free-form message.
+type DeprecationData struct {
+	URL     string
+	Message string
+}
+
+// HistoryData describes a page rendered as of a past block height, and the
+// URL of its latest version.
+type HistoryData struct {
+	Height    int64
+	LatestURL string
+}
+
+// Flusher is implemented by writers able to send buffered data to the
+// client, such as `http.Flusher`.
+type Flusher interface {
+	Flush()
 }
 
 type IndexData struct {
@@ -49,31 +96,63 @@ type IndexData struct {
 	FooterData
 	BodyView *View
 	Mode     ViewMode
+
+	// Deprecation, if set, is displayed as a banner above the content.
+	Deprecation *DeprecationData
+
+	// HomeGroups, if set, is displayed below the home page content.
+	HomeGroups Component
+
+	// Stale, if set, warns that the content is the last known version of
+	// the page, served while the node is unreachable.
+	Stale bool
+
+	// History, if set, warns that the content is rendered as of a past
+	// block height.
+	History *HistoryData
+
+	// Locale, if set, is the locale the page chrome is translated to, such
+	// as "fr". It defaults to DefaultLocale.
+	Locale string
+
+	// Flusher, if set, is flushed at the layout's natural boundaries (after
+	// the head, the header and the main content) so the client can start
+	// painting the page before it is fully written.
+	Flusher Flusher
 }
 
 type indexLayoutParams struct {
-	IndexData
+	*IndexData
 
 	// Additional data
-	IsDevmodView bool
-	ViewType     string
 	JSController string
 }
 
+// FlushPoint flushes the underlying writer, if any. It always returns an
+// empty string so it can be called from the templates.
+func (p indexLayoutParams) FlushPoint() string {
+	if p.Flusher != nil {
+		p.Flusher.Flush()
+	}
+	return ""
+}
+
+// IsDevmodView reports whether the main view is a developer view.
+func (p indexLayoutParams) IsDevmodView() bool {
+	switch p.BodyView.Type {
+	case HelpViewType, SourceViewType, DirectoryViewType, StatusViewType:
+		return true
+	}
+	return false
+}
+
 func IndexLayout(data IndexData) Component {
 	data.FooterData = EnrichFooterData(data.FooterData)
 	data.HeaderData = EnrichHeaderData(data.HeaderData, data.Mode)
 
 	dataLayout := indexLayoutParams{
-		IndexData: data,
-		ViewType:  data.BodyView.String(),
-	}
-
-	// Set dev mode based on view type and mode
-	switch data.BodyView.Type {
-	case HelpViewType, SourceViewType, DirectoryViewType, StatusViewType:
-		dataLayout.IsDevmodView = true
+		IndexData: &data,
 	}
 
-	return NewTemplateComponent("index", dataLayout)
+	return &TemplateComponent{name: "index", data: dataLayout, locale: data.Locale}
 }
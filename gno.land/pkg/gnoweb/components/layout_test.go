@@ -1,12 +1,15 @@
 package components
 
 import (
+	"bytes"
 	"net/url"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIndexLayout(t *testing.T) {
@@ -72,6 +75,36 @@ func TestIndexLayout(t *testing.T) {
 	}
 }
 
+func TestIndexLayoutLandmarks(t *testing.T) {
+	data := IndexData{
+		HeadData: HeadData{Title: "Test Title"},
+		HeaderData: HeaderData{
+			RealmURL: weburl.GnoURL{WebQuery: map[string][]string{}},
+		},
+		Mode: ViewModeRealm,
+		BodyView: &View{
+			Type:      "test-view",
+			Component: NewReaderComponent(strings.NewReader("testdata")),
+		},
+	}
+
+	var buf strings.Builder
+	require.NoError(t, IndexLayout(data).Render(&buf))
+	out := buf.String()
+
+	// The skip link must be the first focusable element of the body
+	skip := `<a class="b-skip-link" href="#main-content">Skip to main content</a>`
+	body := out[strings.Index(out, "<body>"):]
+	assert.True(t, strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(body, "<body>")), skip))
+
+	assert.Contains(t, out, `<main id="main-content" tabindex="-1"`)
+	for _, landmark := range []string{"<header", "<nav", "<footer"} {
+		assert.Contains(t, out, landmark)
+	}
+	assert.Less(t, strings.Index(out, `<header class="b-header">`), strings.Index(out, "<main"))
+	assert.Less(t, strings.Index(out, "</main>"), strings.Index(out, `<footer class="b-footer">`))
+}
+
 func TestEnrichFooterData(t *testing.T) {
 	data := FooterData{
 		Analytics:  true,
@@ -287,3 +320,33 @@ func TestViewModePredicates(t *testing.T) {
 		})
 	}
 }
+
+func TestAssetURL(t *testing.T) {
+	names := map[string]string{"main.css": "main.0123456789.css"}
+	assert.Equal(t, "/public/main.0123456789.css", assetURL("/public/", names, "main.css", "42"))
+	assert.Equal(t, "/public/js/index.js?v=42", assetURL("/public/", names, "js/index.js", "42"))
+	assert.Equal(t, "/public/main.css?v=42", assetURL("/public/", nil, "main.css", "42"))
+}
+
+func TestOverrideTemplates(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, OverrideTemplates(nil)) })
+
+	render := func() string {
+		var buf bytes.Buffer
+		require.NoError(t, NewTemplateComponent("layouts/footer", EnrichFooterData(FooterData{AssetsPath: "/public/"})).Render(&buf))
+		return buf.String()
+	}
+	assert.Contains(t, render(), `<footer class="b-footer">`)
+
+	require.NoError(t, OverrideTemplates(fstest.MapFS{
+		"layouts/footer.html": {Data: []byte(`{{ define "layouts/footer" }}<footer>custom</footer>{{ end }}`)},
+	}))
+	assert.Equal(t, "<footer>custom</footer>", render())
+
+	require.NoError(t, OverrideTemplates(nil))
+	assert.Contains(t, render(), `<footer class="b-footer">`)
+
+	assert.Error(t, OverrideTemplates(fstest.MapFS{
+		"broken.html": {Data: []byte(`{{ define "layouts/footer" }}`)},
+	}))
+}
@@ -0,0 +1,13 @@
+package components
+
+// TextData holds the data of the text layout, which renders the content as
+// plain semantic HTML, without styles nor scripts.
+type TextData struct {
+	Title   string
+	Content Component
+}
+
+// TextLayout returns the layout used to render content in text mode.
+func TextLayout(data TextData) Component {
+	return NewTemplateComponent("text", data)
+}
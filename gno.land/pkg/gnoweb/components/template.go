@@ -5,7 +5,10 @@ import (
 	"embed"
 	"fmt"
 	"html/template"
+	"io"
+	"io/fs"
 	"net/url"
+	"sync/atomic"
 )
 
 //go:embed ui/*.html views/*.html layouts/*.html
@@ -13,7 +16,12 @@ var html embed.FS
 
 var funcMap = template.FuncMap{}
 
-var tmpl = template.New("web")
+// baseTmpl holds the embedded templates. It is never executed, so that it
+// can be cloned to apply template overrides.
+var baseTmpl = template.New("web")
+
+// tmpl holds the templates rendered by components.
+var tmpl atomic.Pointer[template.Template]
 
 func registerCommonFuncs(funcs template.FuncMap) {
 	// NOTE: this method does NOT escape HTML, use with caution
@@ -26,13 +34,13 @@ func registerCommonFuncs(funcs template.FuncMap) {
 	}
 	// NOTE: this method does NOT escape HTML, use with caution
 	// Render Component element into raw html element
-	funcs["render"] = func(comp Component) (template.HTML, error) {
-		var buf bytes.Buffer
-		if err := comp.Render(&buf); err != nil {
-			return "", fmt.Errorf("unable to render component: %w", err)
-		}
-
-		return template.HTML(buf.String()), nil //nolint:gosec
+	funcs["render"] = renderFunc("")
+	// Messages are translated by the templates of each locale
+	funcs["T"] = func(msg string, args ...any) string {
+		return translate(nil, msg, args...)
+	}
+	funcs["lang"] = func() string {
+		return DefaultLocale
 	}
 	funcs["queryHas"] = func(vals url.Values, key string) bool {
 		if vals == nil {
@@ -42,18 +50,78 @@ func registerCommonFuncs(funcs template.FuncMap) {
 		return vals.Has(key)
 	}
 	funcs["FormatRelativeTime"] = FormatRelativeTimeSince
+	funcs["assetURL"] = assetURL
+}
+
+// renderFunc returns a template function rendering components into raw
+// html, in the given locale.
+func renderFunc(locale string) func(comp Component) (template.HTML, error) {
+	return func(comp Component) (template.HTML, error) {
+		var buf bytes.Buffer
+		var w io.Writer = &buf
+		if locale != "" {
+			w = &localeWriter{Writer: &buf, locale: locale}
+		}
+		if err := comp.Render(w); err != nil {
+			return "", fmt.Errorf("unable to render component: %w", err)
+		}
+
+		return template.HTML(buf.String()), nil //nolint:gosec
+	}
+}
+
+// assetURL returns the URL of the named asset under the assets path, using
+// its content-hashed name if known, or busting caches with the build time.
+func assetURL(assetsPath string, names map[string]string, name, buildTime string) string {
+	if hashed, ok := names[name]; ok {
+		return assetsPath + hashed
+	}
+	return assetsPath + name + "?v=" + buildTime
 }
 
 func init() {
 	// Register templates functions
 	registerCommonFuncs(funcMap)
 	registerHelpFuncs(funcMap)
-	tmpl.Funcs(funcMap)
+	baseTmpl.Funcs(funcMap)
 
 	// Parse templates
 	var err error
-	tmpl, err = tmpl.ParseFS(html, "layouts/*.html", "ui/*.html", "views/*.html")
+	baseTmpl, err = baseTmpl.ParseFS(html, "layouts/*.html", "ui/*.html", "views/*.html")
 	if err != nil {
 		panic("unable to parse embed tempalates: " + err.Error())
 	}
+	setTemplates(template.Must(baseTmpl.Clone()))
+}
+
+// OverrideTemplates replaces the templates defined by the `*.html` files of
+// the given file system, and of its direct subdirectories, such as
+// `{{ define "layouts/footer" }}`, in all components. Overrides apply
+// process-wide and replace any previous ones; a nil file system restores
+// the embedded templates.
+func OverrideTemplates(fsys fs.FS) error {
+	t, err := baseTmpl.Clone()
+	if err != nil {
+		return err
+	}
+
+	if fsys != nil {
+		var files []string
+		for _, pattern := range []string{"*.html", "*/*.html"} {
+			matches, err := fs.Glob(fsys, pattern)
+			if err != nil {
+				return err
+			}
+			files = append(files, matches...)
+		}
+
+		if len(files) > 0 {
+			if t, err = t.ParseFS(fsys, files...); err != nil {
+				return fmt.Errorf("unable to parse template overrides: %w", err)
+			}
+		}
+	}
+
+	setTemplates(t)
+	return nil
 }
@@ -21,5 +21,5 @@ type BreadcrumbData struct {
 }
 
 func RenderBreadcrumpComponent(w io.Writer, data BreadcrumbData) error {
-	return tmpl.ExecuteTemplate(w, "Breadcrumb", data)
+	return localeTemplate(writerLocale(w)).ExecuteTemplate(w, "Breadcrumb", data)
 }
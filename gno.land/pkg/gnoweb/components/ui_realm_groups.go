@@ -0,0 +1,13 @@
+package components
+
+// RealmGroup lists the realms of a namespace, such as `/r/demo`.
+type RealmGroup struct {
+	Name   string
+	Realms []string
+}
+
+// RealmGroupsComponent returns a component listing realms grouped by
+// namespace, with the number of realms of each group.
+func RealmGroupsComponent(groups []RealmGroup) Component {
+	return NewTemplateComponent("ui/realm_groups", groups)
+}
@@ -24,6 +24,10 @@ type HelpData struct {
 	PkgFullPath string
 	Doc         string
 	Domain      string
+
+	// TxPath, if set, is the path unsigned call transactions are exported
+	// from, as JSON to be signed with gnokey.
+	TxPath string
 }
 
 type HelpTocData struct {
@@ -51,6 +55,8 @@ func registerHelpFuncs(funcs template.FuncMap) {
 		return data.SelectedArgs[param.Name], nil
 	}
 
+	funcs["helpInputAttrs"] = helpInputAttrs
+
 	funcs["buildHelpURL"] = func(data HelpData, fn *doc.JSONFunc) string {
 		pkgPath := strings.TrimPrefix(data.PkgPath, data.Domain)
 		url := data.Domain + pkgPath + "$help&func=" + fn.Name
@@ -70,6 +76,23 @@ func registerHelpFuncs(funcs template.FuncMap) {
 	}
 }
 
+// helpInputAttrs returns the attributes of the input of a parameter of the
+// given type, restricting it to numbers or suggesting boolean values.
+func helpInputAttrs(typ string) template.HTMLAttr {
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64":
+		return `type="number" step="1"`
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		return `type="number" step="1" min="0"`
+	case "float32", "float64":
+		return `type="number" step="any"`
+	case "bool":
+		return `type="text" list="help-bool-values"`
+	default:
+		return `type="text"`
+	}
+}
+
 func HelpView(data HelpData) *View {
 	tocData := HelpTocData{
 		Icon:  "code",
@@ -0,0 +1,70 @@
+package components
+
+import "html/template"
+
+const DiffViewType ViewType = "diff-view"
+
+// DiffLineKind is the kind of a diff line.
+type DiffLineKind string
+
+const (
+	DiffLineContext DiffLineKind = "context"
+	DiffLineAdded   DiffLineKind = "added"
+	DiffLineRemoved DiffLineKind = "removed"
+)
+
+// DiffLine is a line of a diff hunk. Line numbers are zero on the side the
+// line is absent from.
+type DiffLine struct {
+	Kind   DiffLineKind
+	OldNum int
+	NewNum int
+	// HTML is the highlighted content of the line.
+	HTML template.HTML
+}
+
+// DiffRow is a row of a side-by-side diff, either side may be nil.
+type DiffRow struct {
+	Old *DiffLine
+	New *DiffLine
+}
+
+// DiffHunk is a group of changed lines, along with their context.
+type DiffHunk struct {
+	Header string // `@@ -l,s +l,s @@`
+	Lines  []DiffLine
+	Rows   []DiffRow
+}
+
+// DiffFile is the diff of a file between the two versions of a package.
+type DiffFile struct {
+	Name      string
+	Status    string // added, removed or modified
+	Additions int
+	Deletions int
+	Hunks     []DiffHunk
+}
+
+// DiffData holds the dynamic fields for the "diff" template.
+type DiffData struct {
+	PkgPath  string
+	BasePath string
+	// FromHeight and ToHeight are the block heights of the base and the
+	// package versions, zero for the latest.
+	FromHeight int64
+	ToHeight   int64
+
+	Split      bool
+	UnifiedURL string
+	SplitURL   string
+
+	Files     []DiffFile
+	Unchanged int
+	Additions int
+	Deletions int
+}
+
+// DiffView returns a view of the diff of a package between two versions.
+func DiffView(data DiffData) *View {
+	return NewTemplateView(DiffViewType, "diff", data)
+}
@@ -9,6 +9,37 @@ type DirData struct {
 	FilesLinks  FilesLinks
 	Mode        ViewMode
 	Readme      Component
+	Pagination  *Pagination
+	// Sorts, Sort and Filter are the sort links, the current sort order and
+	// the prefix filter of path listings.
+	Sorts  []ListingSortLink
+	Sort   string
+	Filter string
+}
+
+// Listing holds the controls of a paginated path listing.
+type Listing struct {
+	Pagination *Pagination
+	Sorts      []ListingSortLink
+	Sort       string
+	Filter     string
+}
+
+// ListingSortLink is a link sorting a listing, toggling the direction of
+// the order if it is the active one.
+type ListingSortLink struct {
+	Label  string
+	URL    string
+	Active bool
+	Desc   bool
+}
+
+// Pagination describes the current page of a paginated listing.
+type Pagination struct {
+	Page    int
+	Pages   int
+	PrevURL string
+	NextURL string
 }
 
 type DirLinkType int
@@ -59,3 +90,20 @@ func DirectoryView(pkgPath string, files []string, fileCounter int, linkType Dir
 	}
 	return NewTemplateView(DirectoryViewType, "renderDir", viewData)
 }
+
+// PaginatedDirectoryView returns a directory view displaying a single page of
+// files, `fileCounter` being the total number of files.
+func PaginatedDirectoryView(pkgPath string, files []string, fileCounter int, linkType DirLinkType, mode ViewMode, listing Listing) *View {
+	viewData := DirData{
+		PkgPath:     pkgPath,
+		Files:       files,
+		FilesLinks:  GetFullLinks(files, linkType, pkgPath),
+		FileCounter: fileCounter,
+		Mode:        mode,
+		Pagination:  listing.Pagination,
+		Sorts:       listing.Sorts,
+		Sort:        listing.Sort,
+		Filter:      listing.Filter,
+	}
+	return NewTemplateView(DirectoryViewType, "renderDir", viewData)
+}
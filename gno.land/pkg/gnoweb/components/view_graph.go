@@ -0,0 +1,24 @@
+package components
+
+import "html/template"
+
+const GraphViewType ViewType = "graph-view"
+
+// GraphData holds the dynamic fields for the "graph" template.
+type GraphData struct {
+	PkgPath   string
+	SourceURL string
+	// SVG is the rendered dependency graph.
+	SVG template.HTML
+	// Packages and Stdlibs are the numbers of on-chain packages and of
+	// standard libraries the package depends on.
+	Packages int
+	Stdlibs  int
+	// Truncated is set when the imports of some packages were not followed.
+	Truncated bool
+}
+
+// GraphView returns a view of the dependency graph of a package.
+func GraphView(data GraphData) *View {
+	return NewTemplateView(GraphViewType, "graph", data)
+}
@@ -0,0 +1,25 @@
+package components
+
+const SearchViewType ViewType = "search-view"
+
+// SearchResult is a package matching a search query.
+type SearchResult struct {
+	Path    string
+	Title   string
+	Excerpt string
+}
+
+// SearchData holds the dynamic fields for the "search" template.
+type SearchData struct {
+	Query   string
+	Results []SearchResult
+	Total   int
+	// Indexing is set while the first index of the packages is built.
+	Indexing   bool
+	Pagination *Pagination
+}
+
+// SearchView returns a view listing the results of a search query.
+func SearchView(data SearchData) *View {
+	return NewTemplateView(SearchViewType, "search", data)
+}
@@ -8,6 +8,10 @@ type StatusData struct {
 	Body       string
 	ButtonURL  string
 	ButtonText string
+
+	// Error, if set, is the error message of the title, translated on its
+	// own.
+	Error string
 }
 
 // StatusErrorComponent returns a view for error scenarios with a message.
@@ -17,6 +21,7 @@ func StatusErrorComponent(message string) *View {
 		"status",
 		StatusData{
 			Title:      "Error: " + message,
+			Error:      message,
 			Body:       "Something went wrong.",
 			ButtonURL:  "/",
 			ButtonText: "Go Back Home",
@@ -24,6 +29,18 @@ func StatusErrorComponent(message string) *View {
 	)
 }
 
+// StatusMaintenanceComponent returns a view for the maintenance mode.
+func StatusMaintenanceComponent() *View {
+	return NewTemplateView(
+		StatusViewType,
+		"status",
+		StatusData{
+			Title: "Under maintenance",
+			Body:  "This site is under maintenance, please come back in a few minutes.",
+		},
+	)
+}
+
 // StatusNoRenderComponent returns a view for non-error notifications when Render() is not implemented.
 func StatusNoRenderComponent(pkgPath string) *View {
 	return NewTemplateView(
@@ -37,3 +54,18 @@ func StatusNoRenderComponent(pkgPath string) *View {
 		},
 	)
 }
+
+// StatusContentWarningComponent returns an interstitial view warning about
+// potentially sensitive content, linking to the content itself.
+func StatusContentWarningComponent(proceedURL string) *View {
+	return NewTemplateView(
+		StatusViewType,
+		"status",
+		StatusData{
+			Title:      "Sensitive content",
+			Body:       "This content has been flagged as potentially sensitive.",
+			ButtonURL:  proceedURL,
+			ButtonText: "View Content",
+		},
+	)
+}
@@ -61,6 +61,27 @@ type UserData struct {
 	RealmCount    int
 	PureCount     int
 	Content       Component
+
+	// Account, if set, is the on-chain account of the user.
+	Account *UserAccount
+}
+
+// UserAccount holds the on-chain state of a user account.
+type UserAccount struct {
+	Address       string
+	Balance       string
+	AccountNumber uint64
+	Sequence      uint64
+	Transactions  []UserTransaction
+}
+
+// UserTransaction is a recent transaction of a user account.
+type UserTransaction struct {
+	Hash    string
+	URL     string
+	Summary string
+	Height  int64
+	Date    *time.Time
 }
 
 // enrichLinks sets the Title of link-type entries to their hostname.
@@ -0,0 +1,175 @@
+package gnoweb
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressibleTypes lists the media types of the responses compressed by
+// CompressionMiddleware.
+var compressibleTypes = map[string]bool{
+	"text/html":              true,
+	"text/css":               true,
+	"text/javascript":        true,
+	"text/plain":             true,
+	"text/markdown":          true,
+	"text/xml":               true,
+	"application/javascript": true,
+	"application/json":       true,
+	"application/xml":        true,
+	"application/atom+xml":   true,
+	"image/svg+xml":          true,
+}
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}}
+	brotliWriters = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	}}
+)
+
+// CompressionMiddleware compresses the HTML, CSS, JavaScript and other text
+// responses with brotli or gzip, preferring brotli, when the client accepts
+// it. Partial responses, already encoded ones, and connection upgrades are
+// left untouched.
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Header.Get("Upgrade") != "" || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the preferred encoding accepted by the given
+// `Accept-Encoding` header, or an empty string.
+func negotiateEncoding(accept string) string {
+	var gz bool
+	for _, part := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		if _, q, ok := strings.Cut(params, "q="); ok {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(q), 64); err == nil && v == 0 {
+				continue // explicitly refused
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "br":
+			return "br"
+		case "gzip":
+			gz = true
+		}
+	}
+
+	if gz {
+		return "gzip"
+	}
+	return ""
+}
+
+// compressWriter compresses the response written to it, if its status and
+// content type allow it, as decided once the header is written.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	wroteHeader bool
+	enc         io.WriteCloser // nil if the response is not compressed
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	mediatype, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if code >= http.StatusOK && code != http.StatusNoContent &&
+		code != http.StatusNotModified && code != http.StatusPartialContent &&
+		h.Get("Content-Encoding") == "" && compressibleTypes[mediatype] {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.encoding)
+
+		switch w.encoding {
+		case "br":
+			bw := brotliWriters.Get().(*brotli.Writer)
+			bw.Reset(w.ResponseWriter)
+			w.enc = bw
+		default:
+			gw := gzipWriters.Get().(*gzip.Writer)
+			gw.Reset(w.ResponseWriter)
+			w.enc = gw
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.enc == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.enc.Write(b)
+}
+
+// Flush flushes the compressed data written so far to the client, so that
+// progressive rendering still works.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	switch enc := w.enc.(type) {
+	case *gzip.Writer:
+		enc.Flush()
+	case *brotli.Writer:
+		enc.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close terminates the compressed stream, if any, and releases its writer.
+func (w *compressWriter) Close() error {
+	if w.enc == nil {
+		return nil
+	}
+
+	err := w.enc.Close()
+	switch enc := w.enc.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(enc)
+	case *brotli.Writer:
+		brotliWriters.Put(enc)
+	}
+	w.enc = nil
+	return err
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,88 @@
+package gnoweb_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("<p>hello gno.land</p>", 100)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/image.png" {
+			w.Header().Set("Content-Type", "image/png")
+		} else {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		}
+		w.Write([]byte(body[:len(body)/2]))
+		http.NewResponseController(w).Flush()
+		w.Write([]byte(body[len(body)/2:]))
+	})
+	h := gnoweb.CompressionMiddleware(next)
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	decode := map[string]func(io.Reader) (io.Reader, error){
+		"br": func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"gzip": func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+	}
+
+	for accept, encoding := range map[string]string{
+		"gzip, deflate, br":  "br",
+		"gzip;q=1.0, br;q=0": "gzip",
+		"GZIP":               "gzip",
+	} {
+		t.Run(accept, func(t *testing.T) {
+			t.Parallel()
+
+			rr := get("/r/demo", accept)
+			assert.Equal(t, encoding, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			assert.Less(t, rr.Body.Len(), len(body))
+
+			r, err := decode[encoding](rr.Body)
+			require.NoError(t, err)
+			out, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(out))
+		})
+	}
+
+	t.Run("not accepted", func(t *testing.T) {
+		t.Parallel()
+
+		for _, accept := range []string{"", "deflate", "gzip;q=0"} {
+			rr := get("/r/demo", accept)
+			assert.Empty(t, rr.Header().Get("Content-Encoding"), accept)
+			assert.Equal(t, body, rr.Body.String(), accept)
+		}
+	})
+
+	t.Run("not compressible", func(t *testing.T) {
+		t.Parallel()
+
+		rr := get("/image.png", "gzip")
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rr.Body.String())
+	})
+}
@@ -0,0 +1,229 @@
+package gnoweb
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DebugConfigPath is the path of the effective configuration endpoint.
+const DebugConfigPath = "/debug/config.json"
+
+// adminUser is the basic auth user of admin endpoints.
+const adminUser = "admin"
+
+// redacted replaces secrets in served configurations.
+const redacted = "[redacted]"
+
+// AdminAuthHandler requires the admin basic auth credentials to reach next.
+// Responses are never cached.
+func AdminAuthHandler(password string, next http.Handler) http.Handler {
+	return PrivateCacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || password == "" ||
+			subtle.ConstantTimeCompare([]byte(user), []byte(adminUser)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(pass), []byte(password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gnoweb admin", charset="UTF-8"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// debugAppConfig is the served view of an AppConfig. Fields are listed
+// explicitly, so that new AppConfig fields are not served until reviewed:
+// secrets are redacted, and the fields holding content or objects are
+// summarized.
+type debugAppConfig struct {
+	UnsafeHTML           bool
+	Analytics            bool
+	NodeRemote           string
+	NodeRequestTimeout   time.Duration
+	NodeRetries          int
+	NodeRetryBackoff     time.Duration
+	NodeBreakerThreshold int
+	NodeBreakerCooldown  time.Duration
+	NodeFailoverTimeout  time.Duration
+	NodeHealthInterval   time.Duration
+	RemoteHelp           string
+	AssetsPath           string
+	NoAssetsCache        bool
+	Compression          bool
+	Theme                string
+	DefaultLocale        string
+	UILocales            []string
+	ChainID              string
+	FaucetURL            string
+	Domain               string
+	Aliases              []string // aliased paths
+	Redirects            int      // number of rules
+	RedirectsFile        bool
+	RenderConfig         struct {
+		ChromaStyle         string
+		NormalizeWhitespace bool
+		CodeLineAnchors     bool
+		JSONTreeViewer      bool
+		HardLineBreaks      bool
+	}
+	SourceRefBase         string
+	ProgressiveRender     bool
+	MathServerRender      bool
+	MathKaTeXURL          string
+	CSPByPath             map[string][]string
+	SecurityHeaders       *SecurityHeaders
+	LiveReload            bool
+	Realtime              bool
+	RealtimeInterval      time.Duration
+	ServeMarkdownSource   bool
+	RateLimit             RateLimit
+	PathRateLimits        map[string]RateLimit
+	RateLimitBypass       int // number of ranges
+	MarkdownExtensions    []string
+	BlockCacheSize        int
+	ListingSort           ListingSort
+	ListingPerPage        int
+	PageCacheSize         int
+	SnapshotDir           bool
+	WarnPaths             []string
+	EmbedAllowedAncestors []string
+	JSONAPI               bool
+	Feeds                 bool
+	HistoricalRender      bool
+	Tracing               bool
+	TracerProvider        bool
+	DevMode               bool
+	ImageProxy            bool
+	TxIndexer             bool
+	AdminPassword         string `json:",omitempty"`
+	ErrorPages            []int  // statuses
+	Maintenance           bool
+	DebugConfig           bool
+	Metrics               bool
+	Sitemap               bool
+	ServeStaleOnOutage    bool
+	ValidateHost          bool
+	AllowedHosts          []string
+	RPCGateway            bool
+	RPCGatewayMethods     []string
+	RPCGatewayRateLimit   RateLimit
+	PageViews             bool
+}
+
+// handlerDebugConfig serves the given configuration as JSON, with secrets
+// redacted.
+func handlerDebugConfig(cfg *AppConfig) (http.Handler, error) {
+	view := debugAppConfig{
+		UnsafeHTML:           cfg.UnsafeHTML,
+		Analytics:            cfg.Analytics,
+		NodeRemote:           redactRemotes(cfg.NodeRemote),
+		NodeRequestTimeout:   cfg.NodeRequestTimeout,
+		NodeRetries:          cfg.NodeRetries,
+		NodeRetryBackoff:     cfg.NodeRetryBackoff,
+		NodeBreakerThreshold: cfg.NodeBreakerThreshold,
+		NodeBreakerCooldown:  cfg.NodeBreakerCooldown,
+		NodeFailoverTimeout:  cfg.NodeFailoverTimeout,
+		NodeHealthInterval:   cfg.NodeHealthInterval,
+		RemoteHelp:           redactRemotes(cfg.RemoteHelp),
+		AssetsPath:           cfg.AssetsPath,
+		NoAssetsCache:        cfg.NoAssetsCache,
+		Compression:          cfg.Compression,
+		Theme:                cfg.Theme,
+		DefaultLocale:        cfg.DefaultLocale,
+		UILocales:            cfg.UILocales,
+		ChainID:              cfg.ChainID,
+		FaucetURL:            redactURL(cfg.FaucetURL),
+		Domain:               cfg.Domain,
+		Aliases:              slices.Sorted(maps.Keys(cfg.Aliases)),
+		Redirects:            len(cfg.Redirects),
+		RedirectsFile:        cfg.RedirectsFile != "",
+
+		SourceRefBase:         cfg.SourceRefBase,
+		ProgressiveRender:     cfg.ProgressiveRender,
+		MathServerRender:      cfg.MathServerRender,
+		MathKaTeXURL:          cfg.MathKaTeXURL,
+		CSPByPath:             cfg.CSPByPath,
+		SecurityHeaders:       cfg.SecurityHeaders,
+		LiveReload:            cfg.LiveReload,
+		Realtime:              cfg.Realtime,
+		RealtimeInterval:      cfg.RealtimeInterval,
+		ServeMarkdownSource:   cfg.ServeMarkdownSource,
+		RateLimit:             cfg.RateLimit,
+		PathRateLimits:        cfg.PathRateLimits,
+		RateLimitBypass:       len(cfg.RateLimitBypass),
+		MarkdownExtensions:    cfg.MarkdownExtensions,
+		BlockCacheSize:        cfg.BlockCacheSize,
+		ListingSort:           cfg.ListingSort,
+		ListingPerPage:        cfg.ListingPerPage,
+		PageCacheSize:         cfg.PageCacheSize,
+		SnapshotDir:           cfg.SnapshotDir != "",
+		WarnPaths:             cfg.WarnPaths,
+		EmbedAllowedAncestors: cfg.EmbedAllowedAncestors,
+		JSONAPI:               cfg.JSONAPI,
+		Feeds:                 cfg.Feeds,
+		HistoricalRender:      cfg.HistoricalRender,
+		Tracing:               cfg.Tracing,
+		TracerProvider:        cfg.TracerProvider != nil,
+		DevMode:               cfg.DevMode,
+		ImageProxy:            cfg.ImageProxy,
+		TxIndexer:             cfg.TxIndexer != nil,
+		ErrorPages:            slices.Sorted(maps.Keys(cfg.ErrorPages)),
+		Maintenance:           cfg.Maintenance != nil,
+		DebugConfig:           cfg.DebugConfig,
+		Metrics:               cfg.Metrics,
+		Sitemap:               cfg.Sitemap,
+		ServeStaleOnOutage:    cfg.ServeStaleOnOutage,
+		ValidateHost:          cfg.ValidateHost,
+		AllowedHosts:          cfg.AllowedHosts,
+		RPCGateway:            cfg.RPCGateway,
+		RPCGatewayMethods:     cfg.RPCGatewayMethods,
+		RPCGatewayRateLimit:   cfg.RPCGatewayRateLimit,
+		PageViews:             cfg.PageViews != nil,
+	}
+	if cfg.AdminPassword != "" {
+		view.AdminPassword = redacted
+	}
+	if cfg.RenderConfig.ChromaStyle != nil {
+		view.RenderConfig.ChromaStyle = cfg.RenderConfig.ChromaStyle.Name
+	}
+	view.RenderConfig.NormalizeWhitespace = cfg.RenderConfig.NormalizeWhitespace
+	view.RenderConfig.CodeLineAnchors = cfg.RenderConfig.CodeLineAnchors
+	view.RenderConfig.JSONTreeViewer = cfg.RenderConfig.JSONTreeViewer
+	view.RenderConfig.HardLineBreaks = cfg.RenderConfig.HardLineBreaks
+
+	out, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(out)
+	}), nil
+}
+
+// redactRemotes redacts the passwords of the given comma-separated list of
+// remotes.
+func redactRemotes(remotes string) string {
+	list := splitRemotes(remotes)
+	for i, remote := range list {
+		list[i] = redactURL(remote)
+	}
+	return strings.Join(list, ",")
+}
+
+// redactURL redacts the password of the given URL, if any.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,32 @@
+package gnoweb
+
+import (
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+)
+
+// deprecationFor returns the deprecation of the longest prefix of paths
+// matching path, or nil if path is not deprecated. Values starting with `/`
+// or an http(s) scheme are replacement URLs, others are messages.
+func deprecationFor(paths map[string]string, path string) *components.DeprecationData {
+	var prefix string
+	for p := range paths {
+		if strings.HasPrefix(path, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	if prefix == "" {
+		return nil
+	}
+
+	value := paths[prefix]
+	switch {
+	case strings.HasPrefix(value, "/"),
+		strings.HasPrefix(value, "https://"),
+		strings.HasPrefix(value, "http://"):
+		return &components.DeprecationData{URL: value}
+	default:
+		return &components.DeprecationData{Message: value}
+	}
+}
@@ -0,0 +1,252 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// diffContextLines is the number of unchanged lines around changes.
+	diffContextLines = 3
+	// diffMaxFiles caps the number of files compared per package.
+	diffMaxFiles = 100
+)
+
+// sourceLineRenderer is implemented by renderers able to highlight source
+// files line by line, such as HTMLRenderer.
+type sourceLineRenderer interface {
+	RenderSourceLines(name string, src []byte) ([]template.HTML, error)
+}
+
+// GetDiffView returns the view of the diff of a package, requested with the
+// `$diff` web query, against a base version. The `base` web query is the
+// path of the base package, this package by default, and the `from` and
+// `to` web queries are the block heights of the base and of the package,
+// the latest by default. The `split` web query displays the diff side by
+// side.
+func (h *HTTPHandler) GetDiffView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
+	basePath := gnourl.Path
+	if base := gnourl.WebQuery.Get("base"); base != "" {
+		baseurl, err := weburl.Parse(base)
+		if err != nil || baseurl.Args != "" || len(baseurl.Query) > 0 || !(baseurl.IsRealm() || baseurl.IsPure()) {
+			return http.StatusBadRequest, components.StatusErrorComponent("invalid base package")
+		}
+		basePath = baseurl.Path
+	}
+
+	from, err := parseHeight(gnourl.WebQuery.Get("from"))
+	if err != nil {
+		return http.StatusBadRequest, components.StatusErrorComponent(err.Error())
+	}
+	to, err := parseHeight(gnourl.WebQuery.Get("to"))
+	if err != nil {
+		return http.StatusBadRequest, components.StatusErrorComponent(err.Error())
+	}
+
+	newFiles, err := h.fetchPackageFiles(ctx, gnourl.Path, to)
+	if err != nil {
+		h.Logger.Warn("unable to fetch package files", "path", gnourl.Path, "height", to, "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+	// A missing base shows the package as added
+	oldFiles, err := h.fetchPackageFiles(ctx, basePath, from)
+	if err != nil && !errors.Is(err, ErrClientPackageNotFound) {
+		h.Logger.Warn("unable to fetch package files", "path", basePath, "height", from, "error", err)
+		return GetClientErrorStatusPage(gnourl, err)
+	}
+
+	data := components.DiffData{
+		PkgPath:    gnourl.Path,
+		BasePath:   basePath,
+		FromHeight: from,
+		ToHeight:   to,
+		Split:      gnourl.WebQuery.Has("split"),
+	}
+
+	u := *gnourl
+	u.WebQuery = maps.Clone(gnourl.WebQuery)
+	u.WebQuery.Del("split")
+	data.UnifiedURL = u.EncodeWebURL()
+	u.WebQuery.Set("split", "")
+	data.SplitURL = u.EncodeWebURL()
+
+	names := make([]string, 0, len(oldFiles)+len(newFiles))
+	for name := range oldFiles {
+		names = append(names, name)
+	}
+	for name := range newFiles {
+		if _, ok := oldFiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		oldSrc, inOld := oldFiles[name]
+		newSrc, inNew := newFiles[name]
+
+		file := components.DiffFile{Name: name, Status: "modified"}
+		switch {
+		case !inOld:
+			file.Status = "added"
+		case !inNew:
+			file.Status = "removed"
+		case string(oldSrc) == string(newSrc):
+			data.Unchanged++
+			continue
+		}
+
+		file.Hunks = diffHunks(h.sourceLines(name, oldSrc), h.sourceLines(name, newSrc))
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.Lines {
+				switch line.Kind {
+				case components.DiffLineAdded:
+					file.Additions++
+				case components.DiffLineRemoved:
+					file.Deletions++
+				}
+			}
+		}
+		data.Additions += file.Additions
+		data.Deletions += file.Deletions
+		data.Files = append(data.Files, file)
+	}
+
+	return http.StatusOK, components.DiffView(data)
+}
+
+// fetchPackageFiles returns the content of the files of the package, at the
+// given block height or the latest one if zero.
+func (h *HTTPHandler) fetchPackageFiles(ctx context.Context, path string, height int64) (map[string][]byte, error) {
+	if height > 0 {
+		ctx = withQueryHeight(ctx, height)
+	}
+
+	names, err := h.Client.ListFiles(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > diffMaxFiles {
+		return nil, fmt.Errorf("%w: too many files to compare", ErrClientBadRequest)
+	}
+
+	files := make(map[string][]byte, len(names))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		content, _, err := h.Client.File(ctx, path, name)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = content
+	}
+	return files, nil
+}
+
+// diffSource is a file split in lines, along with their highlighted HTML.
+type diffSource struct {
+	lines []string
+	html  []template.HTML
+}
+
+// sourceLines splits the source in lines, highlighted by the renderer if
+// supported, or escaped otherwise.
+func (h *HTTPHandler) sourceLines(name string, src []byte) diffSource {
+	var ds diffSource
+	if len(src) == 0 {
+		return ds
+	}
+	ds.lines = strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+
+	if lr, ok := h.Renderer.(sourceLineRenderer); ok {
+		html, err := lr.RenderSourceLines(name, src)
+		if err == nil && len(html) >= len(ds.lines) {
+			ds.html = html[:len(ds.lines)]
+			return ds
+		}
+		h.Logger.Warn("unable to highlight source lines", "file", name, "error", err)
+	}
+
+	ds.html = make([]template.HTML, len(ds.lines))
+	for i, line := range ds.lines {
+		ds.html[i] = template.HTML(template.HTMLEscapeString(line))
+	}
+	return ds
+}
+
+// diffHunks returns the hunks of changes between the two sources, with
+// diffContextLines lines of context, both as unified lines and as
+// side-by-side rows.
+func diffHunks(a, b diffSource) []components.DiffHunk {
+	matcher := difflib.NewMatcher(a.lines, b.lines)
+
+	var hunks []components.DiffHunk
+	for _, group := range matcher.GetGroupedOpCodes(diffContextLines) {
+		first, last := group[0], group[len(group)-1]
+		hunk := components.DiffHunk{
+			Header: fmt.Sprintf("@@ -%s +%s @@",
+				hunkRange(first.I1, last.I2), hunkRange(first.J1, last.J2)),
+		}
+
+		for _, op := range group {
+			var removed, added []components.DiffLine
+			for i := op.I1; i < op.I2 && op.Tag != 'e'; i++ {
+				removed = append(removed, components.DiffLine{
+					Kind: components.DiffLineRemoved, OldNum: i + 1, HTML: a.html[i],
+				})
+			}
+			for j := op.J1; j < op.J2; j++ {
+				line := components.DiffLine{Kind: components.DiffLineAdded, NewNum: j + 1, HTML: b.html[j]}
+				if op.Tag == 'e' {
+					line.Kind, line.OldNum = components.DiffLineContext, op.I1+j-op.J1+1
+				}
+				added = append(added, line)
+			}
+
+			hunk.Lines = append(hunk.Lines, removed...)
+			hunk.Lines = append(hunk.Lines, added...)
+
+			// Pair the removed and added lines side by side
+			if op.Tag == 'e' {
+				for i := range added {
+					hunk.Rows = append(hunk.Rows, components.DiffRow{Old: &added[i], New: &added[i]})
+				}
+				continue
+			}
+			for i := range max(len(removed), len(added)) {
+				var row components.DiffRow
+				if i < len(removed) {
+					row.Old = &removed[i]
+				}
+				if i < len(added) {
+					row.New = &added[i]
+				}
+				hunk.Rows = append(hunk.Rows, row)
+			}
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks
+}
+
+// hunkRange formats the `start,length` range of a hunk header, in the
+// unified diff format.
+func hunkRange(start, end int) string {
+	length := end - start
+	if length == 0 {
+		return strconv.Itoa(start) + ",0"
+	}
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(length)
+}
@@ -0,0 +1,129 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffClient is a sourceClient serving the files of packages as of block
+// heights, keyed by `<path>@<height>`; zero is the latest height.
+type diffClient struct {
+	sourceClient
+}
+
+func (c *diffClient) key(ctx context.Context, path string) string {
+	return path + "@" + strconv.FormatInt(queryHeightFromContext(ctx), 10)
+}
+
+func (c *diffClient) ListFiles(ctx context.Context, path string) ([]string, error) {
+	if _, ok := c.files[c.key(ctx, path)]; !ok {
+		return nil, ErrClientPackageNotFound
+	}
+	return c.sourceClient.ListFiles(ctx, c.key(ctx, path))
+}
+
+func (c *diffClient) File(ctx context.Context, path, filename string) ([]byte, FileMeta, error) {
+	return c.sourceClient.File(ctx, c.key(ctx, path), filename)
+}
+
+func TestHTTPHandler_Diff(t *testing.T) {
+	t.Parallel()
+
+	cli := &diffClient{sourceClient{files: map[string]map[string]string{
+		"/p/demo/avl@0": {
+			"gnomod.toml": "module = \"gno.land/p/demo/avl\"\n",
+			"tree.gno":    "package avl\n\n// Tree is a tree.\ntype Tree struct {\n\tsize int\n}\n",
+			"node.gno":    "package avl\n\ntype Node struct{}\n",
+		},
+		"/p/demo/avl@10": {
+			"gnomod.toml": "module = \"gno.land/p/demo/avl\"\n",
+			"tree.gno":    "package avl\n\n// Tree is a binary tree.\ntype Tree struct {\n\tsize int\n}\n",
+			"old.gno":     "package avl\n",
+		},
+		"/p/demo/avl/v2@0": {
+			"tree.gno": "package avl\n\n// Tree is a tree.\ntype Tree struct {\n\tsize int\n\troot *Node\n}\n",
+		},
+	}}}
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land"},
+		SourceDiff:    true,
+	})
+	require.NoError(t, err)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	t.Run("heights", func(t *testing.T) {
+		t.Parallel()
+
+		rr := get("/p/demo/avl$diff&from=10")
+		require.Equal(t, http.StatusOK, rr.Code)
+		body := rr.Body.String()
+
+		assert.Contains(t, body, "3 files changed")
+		assert.Contains(t, body, "at block 10")
+		assert.NotContains(t, body, "gnomod.toml")
+
+		assert.Contains(t, body, "<h2>node.gno</h2>")
+		assert.Contains(t, body, `<span class="b-diff_status">added</span>`)
+		assert.Contains(t, body, `<span class="b-diff_status">removed</span>`)
+		assert.Contains(t, body, "@@ -1,6 &#43;1,6 @@")
+		assert.Contains(t, body, `<tr class="is-removed">`)
+		assert.Contains(t, body, `<tr class="is-added">`)
+		// Lines are highlighted
+		assert.Contains(t, body, `<span class="chroma-c1">// Tree is a binary tree.</span>`)
+		assert.Contains(t, body, `href="/p/demo/avl$diff&amp;from=10&amp;split"`)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		t.Parallel()
+
+		rr := get("/p/demo/avl/v2$diff&base=/p/demo/avl&split")
+		require.Equal(t, http.StatusOK, rr.Code)
+		body := rr.Body.String()
+
+		assert.Contains(t, body, "<code>/p/demo/avl</code>")
+		assert.Contains(t, body, `class="b-diff_table is-split"`)
+		assert.Contains(t, body, `<td class="b-diff_code is-added">`)
+		assert.Contains(t, body, `<td class="b-diff_code is-blank">`)
+		assert.Contains(t, body, "@@ -3,4 &#43;3,5 @@")
+	})
+
+	t.Run("identical", func(t *testing.T) {
+		t.Parallel()
+
+		rr := get("/p/demo/avl$diff")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "No differences in the 3 files.")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, http.StatusBadRequest, get("/p/demo/avl$diff&from=-1").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/p/demo/avl$diff&base=/x/demo").Code)
+		assert.Equal(t, http.StatusNotFound, get("/p/demo/avl$diff&to=3").Code)
+	})
+}
+
+func TestHunkRange(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1,6", hunkRange(0, 6))
+	assert.Equal(t, "4,0", hunkRange(4, 4))
+}
@@ -0,0 +1,97 @@
+package gnoweb
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// EmbedPath is the prefix of the embeddable version of realms, such as
+// `/embed/r/demo/boards`.
+const EmbedPath = "/embed/"
+
+// handlerEmbed serves the rendered content of realms within a minimal
+// layout, suitable for iframes. Only the given ancestors may embed it.
+func handlerEmbed(h *HTTPHandler, ancestors []string) http.Handler {
+	frameAncestors := "frame-ancestors " + strings.Join(ancestors, " ")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Allow framing from the allowlisted ancestors only; this overrides
+		// any `X-Frame-Options` set by an upper middleware.
+		w.Header().Del("X-Frame-Options")
+		if csp := w.Header().Get("Content-Security-Policy"); csp != "" {
+			w.Header().Set("Content-Security-Policy", csp+"; "+frameAncestors)
+		} else {
+			w.Header().Set("Content-Security-Policy", frameAncestors)
+		}
+
+		u := *r.URL
+		u.Path = strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(EmbedPath, "/"))
+		u.RawPath = strings.TrimPrefix(r.URL.RawPath, strings.TrimSuffix(EmbedPath, "/"))
+
+		gnourl, err := weburl.ParseFromURL(&u)
+		if err != nil || !gnourl.IsRealm() || gnourl.IsFile() || !onlyContentWarningQuery(gnourl) {
+			h.renderEmbed(w, gnourl, http.StatusNotFound, components.StatusErrorComponent("invalid path"))
+			return
+		}
+
+		// Frames of other sites may not get the cookie of accepted
+		// warnings, so the interstitial links to the embedded content
+		if h.contentWarningRequired(r, gnourl) {
+			proceed := strings.TrimSuffix(EmbedPath, "/") + contentWarningProceedURL(gnourl)
+			h.renderEmbed(w, gnourl, http.StatusOK, components.StatusContentWarningComponent(proceed))
+			return
+		}
+
+		raw, err := h.Client.Realm(r.Context(), gnourl.Path, gnourl.EncodeArgs())
+		if err != nil {
+			h.Logger.Debug("unable to fetch embedded realm", "error", err, "path", gnourl.EncodeURL())
+			status, view := GetClientErrorStatusPage(gnourl, err)
+			h.renderEmbed(w, gnourl, status, view)
+			return
+		}
+
+		var content bytes.Buffer
+		if _, err := h.Renderer.RenderRealm(&content, gnourl, raw); err != nil {
+			h.Logger.Error("unable to render embedded realm", "error", err, "path", gnourl.EncodeURL())
+			status, view := GetClientErrorStatusPage(gnourl, err)
+			h.renderEmbed(w, gnourl, status, view)
+			return
+		}
+
+		// NOTE: `RenderRealm` should ensure that HTML content is sanitized
+		h.renderEmbed(w, gnourl, http.StatusOK, components.EmbedRealmContent(components.NewReaderComponent(&content)))
+	})
+}
+
+// renderEmbed writes the given content within the embed layout.
+func (h *HTTPHandler) renderEmbed(w http.ResponseWriter, gnourl *weburl.GnoURL, status int, content components.Component) {
+	title := h.Static.Domain
+	if gnourl != nil {
+		title += " - " + sanitizeTitle(gnourl.Path, "/", h.MaxTitleLength)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := components.EmbedLayout(components.EmbedData{
+		HeadData: components.HeadData{
+			Title:      title,
+			AssetsPath: h.Static.AssetsPath,
+			ChromaPath: h.Static.ChromaPath,
+			BuildTime:  h.Static.BuildTime,
+			AssetNames: h.Static.AssetNames,
+			ThemePath:  h.Static.ThemePath,
+		},
+		Content: content,
+	}).Render(w)
+	if err != nil {
+		h.Logger.Error("failed to render embed layout", "error", err)
+	}
+}
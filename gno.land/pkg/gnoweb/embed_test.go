@@ -0,0 +1,96 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerEmbed(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{realms: map[string]string{
+		"/r/demo/leaderboard": "# Leaderboard\n\n1. alice\n2. bob\n",
+		"/r/flagged/realm":    "# Sensitive stuff\n",
+	}}
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land", AssetsPath: "/public/"},
+		WarnPaths:     []string{"/r/flagged"},
+	})
+	require.NoError(t, err)
+
+	ancestors := []string{"https://example.com", "https://*.example.org"}
+	handler := handlerEmbed(h, ancestors)
+
+	// Simulate the secure headers middleware
+	secured := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		handler.ServeHTTP(w, r)
+	})
+
+	t.Run("realm", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		secured.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/embed/r/demo/leaderboard", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		body := rr.Body.String()
+		assert.Contains(t, body, `<md-renderer class="c-realm-view"`)
+		assert.Contains(t, body, "Leaderboard")
+		assert.Contains(t, body, "alice")
+		assert.Contains(t, body, `src="/public/js/embed.js`)
+		assert.NotContains(t, body, `<header`)
+		assert.NotContains(t, body, `<nav`)
+		assert.NotContains(t, body, `<footer`)
+
+		assert.Empty(t, rr.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "default-src 'self'; frame-ancestors https://example.com https://*.example.org",
+			rr.Header().Get("Content-Security-Policy"))
+	})
+
+	t.Run("without csp", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/embed/r/demo/leaderboard", nil))
+		assert.Equal(t, "frame-ancestors https://example.com https://*.example.org", rr.Header().Get("Content-Security-Policy"))
+	})
+
+	t.Run("content warning", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		secured.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/embed/r/flagged/realm", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Sensitive content")
+		assert.Contains(t, rr.Body.String(), `href="/embed/r/flagged/realm$proceed"`)
+		assert.NotContains(t, rr.Body.String(), "Sensitive stuff")
+
+		rr = httptest.NewRecorder()
+		secured.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/embed/r/flagged/realm$proceed", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Sensitive stuff")
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		t.Parallel()
+
+		for _, path := range []string{"/embed/p/demo/avl", "/embed/r/demo/leaderboard$source", "/embed/r/demo/leaderboard/render.gno"} {
+			rr := httptest.NewRecorder()
+			secured.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, rr.Code, path)
+			assert.NotContains(t, rr.Body.String(), "alice", path)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package gnoweb
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+)
+
+// ErrorPage is a custom page served instead of the default error view of an
+// HTTP status, such as 404, 500, or 503 in maintenance mode. It is an
+// html/template executed with an ErrorPageData.
+type ErrorPage struct {
+	// File is the path of the template of the page.
+	File string
+	// Template is the template of the page, if File is empty.
+	Template string
+}
+
+// ErrorPageData holds the dynamic fields of error page templates.
+type ErrorPageData struct {
+	Status     int
+	StatusText string
+	Path       string
+}
+
+// parseErrorPages parses the templates of the given error pages, by status.
+func parseErrorPages(pages map[int]ErrorPage) (map[int]*template.Template, error) {
+	tmpls := make(map[int]*template.Template, len(pages))
+	for status, page := range pages {
+		if http.StatusText(status) == "" || status < http.StatusBadRequest {
+			return nil, fmt.Errorf("invalid error page status %d", status)
+		}
+
+		src := page.Template
+		if page.File != "" {
+			data, err := os.ReadFile(page.File)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read error page %d: %w", status, err)
+			}
+			src = string(data)
+		}
+
+		t, err := template.New(fmt.Sprintf("error-%d", status)).Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse error page %d: %w", status, err)
+		}
+		tmpls[status] = t
+	}
+	return tmpls, nil
+}
+
+// errorPagesMiddleware replaces the HTML pages of next responding with a
+// status having a custom page, by that page.
+func errorPagesMiddleware(logger *slog.Logger, next http.Handler, pages map[int]*template.Template) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&errorPageWriter{ResponseWriter: w, logger: logger, pages: pages, r: r}, r)
+	})
+}
+
+// errorPageWriter writes the custom page of the status of HTML responses,
+// discarding their original body.
+type errorPageWriter struct {
+	http.ResponseWriter
+	logger *slog.Logger
+	pages  map[int]*template.Template
+	r      *http.Request
+
+	wroteHeader bool
+	replaced    bool
+}
+
+func (w *errorPageWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	page, ok := w.pages[code]
+	mediatype, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if !ok || mediatype != "text/html" {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	w.replaced = true
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(code)
+	err := page.Execute(w.ResponseWriter, ErrorPageData{
+		Status:     code,
+		StatusText: http.StatusText(code),
+		Path:       w.r.URL.Path,
+	})
+	if err != nil {
+		w.logger.Error("unable to render error page", "status", code, "error", err)
+	}
+}
+
+func (w *errorPageWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, for progressive rendering.
+func (w *errorPageWriter) Flush() {
+	if !w.replaced {
+		http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *errorPageWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
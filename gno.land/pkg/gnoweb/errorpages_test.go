@@ -0,0 +1,72 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorPagesMiddleware(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "404.html")
+	require.NoError(t, os.WriteFile(file, []byte(`<h1>{{ .Path }} is lost ({{ .Status }})</h1>`), 0o644))
+
+	pages, err := parseErrorPages(map[int]ErrorPage{
+		http.StatusNotFound:           {File: file},
+		http.StatusServiceUnavailable: {Template: `<h1>{{ .StatusText }}</h1>`},
+	})
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "default page")
+		case "/maintenance":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "default page")
+		case "/download":
+			http.Error(w, "not found", http.StatusNotFound)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, "page")
+		}
+	})
+	handler := errorPagesMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil)), next, pages)
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/missing", http.StatusNotFound, "<h1>/missing is lost (404)</h1>"},
+		{"/maintenance", http.StatusServiceUnavailable, "<h1>Service Unavailable</h1>"},
+		{"/download", http.StatusNotFound, "not found\n"}, // not an HTML page
+		{"/r/demo/boards", http.StatusOK, "page"},
+	}
+	for _, tc := range cases {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		assert.Equal(t, tc.status, rr.Code, tc.path)
+		assert.Equal(t, tc.body, rr.Body.String(), tc.path)
+	}
+
+	for _, invalid := range []map[int]ErrorPage{
+		{http.StatusOK: {Template: "ok"}},
+		{http.StatusNotFound: {File: filepath.Join(t.TempDir(), "missing.html")}},
+		{http.StatusNotFound: {Template: "{{ .Path "}},
+	} {
+		_, err := parseErrorPages(invalid)
+		assert.Error(t, err)
+	}
+}
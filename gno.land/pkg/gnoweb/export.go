@@ -0,0 +1,246 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// DefaultExportMaxPages is the default maximum number of pages of an export.
+const DefaultExportMaxPages = 10_000
+
+// ExportConfig configures Export.
+type ExportConfig struct {
+	// Dir is the directory the site is written to.
+	Dir string
+	// Height is the block height realms are rendered at, the latest one if
+	// zero.
+	Height int64
+	// MaxPages bounds the number of exported pages, DefaultExportMaxPages
+	// if zero.
+	MaxPages int
+}
+
+// ExportStats describes a completed export.
+type ExportStats struct {
+	Height int64
+	Pages  int
+	Assets int
+	Failed int // pages and assets which could not be rendered
+}
+
+var (
+	// exportLinkRe matches the links of pages.
+	exportLinkRe = regexp.MustCompile(`(?:href|src)="([^"]+)"`)
+	// exportCSSURLRe matches the URLs referenced by stylesheets.
+	exportCSSURLRe = regexp.MustCompile(`url\(\s*['"]?([^'")]+)`)
+)
+
+// Export renders the home page, the aliases and all realms of the node, as
+// well as the realm pages they link to, at a pinned block height, and writes
+// them along with the assets they use to cfg.Dir as a static site, such as
+// for IPFS pinning or archival. Pages are written as `index.html` files of
+// their path, such as `<dir>/r/demo/boards:gnolang/index.html`, and links are
+// kept absolute, so the site must be served from the root of a domain.
+func Export(ctx context.Context, logger *slog.Logger, appcfg *AppConfig, cfg ExportConfig) (*ExportStats, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("no export directory")
+	}
+	if cfg.MaxPages <= 0 {
+		cfg.MaxPages = DefaultExportMaxPages
+	}
+
+	// Render pages as served to anonymous visitors, without any state
+	// depending on the latest height or the export requests.
+	c := *appcfg
+	c.PageCacheSize = 0
+	c.SnapshotDir = ""
+	c.LiveReload = false
+	c.Maintenance = nil
+	c.PageViews = nil
+	c.RateLimit = RateLimit{}
+	c.PathRateLimits = nil
+	c.ValidateHost = false
+
+	rpcclient, err := newNodeClient(logger, &c)
+	if err != nil {
+		return nil, err
+	}
+	stats := &ExportStats{Height: cfg.Height}
+	if stats.Height <= 0 {
+		status, err := rpcclient.Status(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch latest height: %w", err)
+		}
+		stats.Height = status.SyncInfo.LatestBlockHeight
+	}
+	ctx = withQueryHeight(ctx, stats.Height)
+
+	router, err := NewRouter(logger, &c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create router: %w", err)
+	}
+
+	adpcli := NewRPCClientAdapter(logger, rpcclient, c.Domain)
+	realms, err := adpcli.ListPaths(ctx, c.Domain+"/r/", cfg.MaxPages)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list realms: %w", err)
+	}
+
+	ex := &exporter{
+		logger:     logger,
+		router:     router,
+		domain:     c.Domain,
+		dir:        cfg.Dir,
+		assetsBase: "/" + strings.Trim(c.AssetsPath, "/") + "/",
+		maxPages:   cfg.MaxPages,
+		seen:       make(map[string]bool),
+		stats:      stats,
+	}
+	seeds := append([]string{"/"}, slices.Sorted(maps.Keys(c.Aliases))...)
+	return stats, ex.run(ctx, append(seeds, realms...))
+}
+
+// exporter crawls the pages and assets of a static export.
+type exporter struct {
+	logger     *slog.Logger
+	router     http.Handler
+	domain     string
+	dir        string
+	assetsBase string
+	maxPages   int
+
+	queue []string
+	seen  map[string]bool
+	pages int // queued
+	stats *ExportStats
+}
+
+// run exports the given paths, and the pages and assets they reference.
+func (ex *exporter) run(ctx context.Context, paths []string) error {
+	for _, p := range paths {
+		ex.enqueue(p)
+	}
+
+	for len(ex.queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p := ex.queue[0]
+		ex.queue = ex.queue[1:]
+		if err := ex.export(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue queues the given path if it has not been seen yet, and is either
+// an asset or a realm page within the page limit.
+func (ex *exporter) enqueue(p string) {
+	if ex.seen[p] {
+		return
+	}
+	if !strings.HasPrefix(p, ex.assetsBase) {
+		if ex.pages >= ex.maxPages {
+			return
+		}
+		ex.pages++
+	}
+	ex.seen[p] = true
+	ex.queue = append(ex.queue, p)
+}
+
+// export renders the given path and writes it, queuing the pages and assets
+// it references.
+func (ex *exporter) export(ctx context.Context, p string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+	if err != nil {
+		return err
+	}
+	// Render absolute URLs, such as canonical links, for the domain
+	req.Host = ex.domain
+	req.Header.Set("X-Forwarded-Proto", "https")
+	// Handlers may rewrite the request, such as aliases
+	base := *req.URL
+
+	rec := &pageRecorder{header: http.Header{}}
+	ex.router.ServeHTTP(rec, req)
+	if rec.status != http.StatusOK {
+		ex.logger.Warn("unable to export page", "path", p, "status", rec.status)
+		ex.stats.Failed++
+		return nil
+	}
+
+	isAsset := strings.HasPrefix(p, ex.assetsBase)
+	name := base.Path
+	if !isAsset {
+		name = strings.TrimSuffix(name, "/") + "/index.html"
+	}
+	filename, err := osm.SafeJoin(ex.dir, name)
+	if err != nil {
+		ex.logger.Warn("rejected export path", "path", p, "error", err)
+		ex.stats.Failed++
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, rec.body.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	contentType := rec.header.Get("Content-Type")
+	switch {
+	case isAsset:
+		ex.stats.Assets++
+		if strings.HasPrefix(contentType, "text/css") {
+			ex.enqueueLinks(&base, exportCSSURLRe, rec.body.String())
+		}
+	case strings.HasPrefix(contentType, "text/html"):
+		ex.stats.Pages++
+		ex.enqueueLinks(&base, exportLinkRe, html.UnescapeString(rec.body.String()))
+	default:
+		ex.stats.Pages++
+	}
+	return nil
+}
+
+// enqueueLinks queues the local assets and realm pages linked by the content
+// of the page at the given URL.
+func (ex *exporter) enqueueLinks(base *url.URL, re *regexp.Regexp, content string) {
+	for _, m := range re.FindAllStringSubmatch(content, -1) {
+		ref, err := url.Parse(m[1])
+		if err != nil || ref.Scheme != "" || ref.Host != "" {
+			continue
+		}
+		u := base.ResolveReference(ref)
+
+		if strings.HasPrefix(u.Path, ex.assetsBase) {
+			ex.enqueue(u.EscapedPath())
+			continue
+		}
+
+		if u.RawQuery != "" {
+			continue // paginations, historical renders...
+		}
+		gnourl, err := weburl.ParseFromURL(u)
+		if err != nil || !gnourl.IsRealm() || len(gnourl.WebQuery) > 0 {
+			continue
+		}
+		ex.enqueue(u.EscapedPath())
+	}
+}
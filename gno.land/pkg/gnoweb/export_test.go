@@ -0,0 +1,98 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	serve := func(contentType, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "gno.land", r.Host)
+			assert.Equal(t, int64(42), queryHeightFromContext(r.Context()))
+			w.Header().Set("Content-Type", contentType)
+			w.Write([]byte(body))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/{$}", serve("text/html; charset=utf-8", `<link href="/public/main.css?v=1" rel="stylesheet">`+
+		`<a href="/r/demo/foo">foo</a> <a href="/r/demo/foo:bar/1">bar</a> <a href="/r/demo/foo:missing">missing</a>`+
+		`<a href="/r/demo/foo$source&amp;file=foo.gno">source</a> <a href="/r/demo/foo?page=2">page</a>`+
+		`<a href="/p/demo/avl">avl</a> <a href="https://example.com/r/demo/ext">ext</a> <a href="#top">top</a>`))
+	mux.Handle("/r/demo/foo", serve("text/html; charset=utf-8", `<a href="/">home</a>`))
+	mux.Handle("/r/demo/foo:bar/1", serve("text/html; charset=utf-8", `<a href="/r/demo/foo">foo</a>`))
+	mux.Handle("/r/demo/other", serve("text/html; charset=utf-8", `other`))
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = "/r/gnoland/pages:p/about" // as aliases do
+		serve("text/html; charset=utf-8", `about`)(w, r)
+	})
+	mux.Handle("/public/main.css", serve("text/css; charset=utf-8", `@font-face{src:url(fonts/inter.woff2)}body{background:url('/public/bg.png')}`))
+	mux.Handle("/public/fonts/inter.woff2", serve("font/woff2", "font"))
+	mux.Handle("/public/bg.png", serve("image/png", "png"))
+	mux.Handle("/", http.NotFoundHandler())
+
+	dir := t.TempDir()
+	ex := &exporter{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		router:     mux,
+		domain:     "gno.land",
+		dir:        dir,
+		assetsBase: "/public/",
+		maxPages:   DefaultExportMaxPages,
+		seen:       make(map[string]bool),
+		stats:      &ExportStats{Height: 42},
+	}
+	ctx := withQueryHeight(context.Background(), 42)
+	require.NoError(t, ex.run(ctx, []string{"/", "/about", "/r/demo/other"}))
+
+	assert.Equal(t, &ExportStats{Height: 42, Pages: 5, Assets: 3, Failed: 1}, ex.stats)
+
+	for name, content := range map[string]string{
+		"r/demo/foo/index.html":       `<a href="/">home</a>`,
+		"r/demo/foo:bar/1/index.html": `<a href="/r/demo/foo">foo</a>`,
+		"r/demo/other/index.html":     `other`,
+		"about/index.html":            `about`,
+		"public/fonts/inter.woff2":    "font",
+		"public/bg.png":               "png",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, content, string(data), name)
+	}
+	assert.FileExists(t, filepath.Join(dir, "index.html"))
+	assert.FileExists(t, filepath.Join(dir, "public/main.css"))
+	assert.NoDirExists(t, filepath.Join(dir, "p"))
+}
+
+func TestExporterMaxPages(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<a href="` + r.URL.Path + `/next">next</a>`)) // endless pages
+	})
+
+	ex := &exporter{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		router:     mux,
+		dir:        t.TempDir(),
+		assetsBase: "/public/",
+		maxPages:   3,
+		seen:       make(map[string]bool),
+		stats:      &ExportStats{},
+	}
+	require.NoError(t, ex.run(context.Background(), []string{"/r/demo/a"}))
+	assert.Equal(t, 3, ex.stats.Pages)
+}
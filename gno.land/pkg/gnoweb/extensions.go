@@ -0,0 +1,58 @@
+package gnoweb
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/yuin/goldmark"
+)
+
+var (
+	markdownExtensionsMu sync.RWMutex
+	markdownExtensions   = map[string]goldmark.Extender{}
+)
+
+// RegisterMarkdownExtension makes a goldmark extension available by name to
+// the gnoweb markdown renderer, to be enabled with the MarkdownExtensions
+// allowlist of AppConfig. It is meant to be called from init functions, and
+// panics if the name is empty or already registered, or the extension nil.
+func RegisterMarkdownExtension(name string, ext goldmark.Extender) {
+	markdownExtensionsMu.Lock()
+	defer markdownExtensionsMu.Unlock()
+
+	if name == "" || ext == nil {
+		panic("gnoweb: invalid markdown extension registration")
+	}
+	if _, dup := markdownExtensions[name]; dup {
+		panic("gnoweb: markdown extension registered twice: " + name)
+	}
+	markdownExtensions[name] = ext
+}
+
+// MarkdownExtensions returns the sorted names of the registered markdown
+// extensions.
+func MarkdownExtensions() []string {
+	markdownExtensionsMu.RLock()
+	defer markdownExtensionsMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(markdownExtensions))
+}
+
+// lookupMarkdownExtensions returns the registered extensions of the given
+// names, in the same order.
+func lookupMarkdownExtensions(names []string) ([]goldmark.Extender, error) {
+	markdownExtensionsMu.RLock()
+	defer markdownExtensionsMu.RUnlock()
+
+	exts := make([]goldmark.Extender, 0, len(names))
+	for _, name := range names {
+		ext, ok := markdownExtensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown markdown extension %q", name)
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
@@ -0,0 +1,52 @@
+package gnoweb
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+)
+
+// countingExtension counts the markdown processors it extends.
+type countingExtension struct {
+	extended atomic.Int32
+}
+
+func (e *countingExtension) Extend(goldmark.Markdown) {
+	e.extended.Add(1)
+}
+
+func TestRegisterMarkdownExtension(t *testing.T) {
+	first, second := &countingExtension{}, &countingExtension{}
+	RegisterMarkdownExtension("test-registry-first", first)
+	RegisterMarkdownExtension("test-registry-second", second)
+
+	assert.Subset(t, MarkdownExtensions(), []string{"test-registry-first", "test-registry-second"})
+	assert.Panics(t, func() { RegisterMarkdownExtension("test-registry-first", second) })
+	assert.Panics(t, func() { RegisterMarkdownExtension("", second) })
+	assert.Panics(t, func() { RegisterMarkdownExtension("test-registry-nil", nil) })
+
+	exts, err := lookupMarkdownExtensions([]string{"test-registry-second", "test-registry-first"})
+	require.NoError(t, err)
+	assert.Equal(t, []goldmark.Extender{second, first}, exts)
+
+	_, err = lookupMarkdownExtensions([]string{"test-registry-unknown"})
+	assert.ErrorContains(t, err, `unknown markdown extension "test-registry-unknown"`)
+
+	// Only the allowed extensions are used by the router
+	logger := log.NewTestingLogger(t)
+	cfg := NewDefaultAppConfig()
+	cfg.ChainID = "test"
+	cfg.MarkdownExtensions = []string{"test-registry-first"}
+	_, err = NewRouter(logger, cfg)
+	require.NoError(t, err)
+	assert.Positive(t, first.extended.Load())
+	assert.Zero(t, second.extended.Load())
+
+	cfg.MarkdownExtensions = []string{"test-registry-unknown"}
+	_, err = NewRouter(logger, cfg)
+	assert.Error(t, err)
+}
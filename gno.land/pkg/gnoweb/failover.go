@@ -0,0 +1,219 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client"
+	rpchttp "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client/http"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+)
+
+// splitRemotes splits a comma-separated list of node remotes.
+func splitRemotes(remotes string) []string {
+	var list []string
+	for _, remote := range strings.Split(remotes, ",") {
+		if remote = strings.TrimSpace(remote); remote != "" {
+			list = append(list, remote)
+		}
+	}
+	return list
+}
+
+// nodePool is a JSON-RPC caller load balancing requests over several nodes,
+// in a round-robin fashion. A node failing to answer, or not answering
+// within `timeout`, is marked down and the request fails over to the next
+// node. Down nodes are only used once every other node failed, and are
+// health-checked every `interval` until they answer again.
+type nodePool struct {
+	logger   *slog.Logger
+	nodes    []*poolNode
+	timeout  time.Duration
+	interval time.Duration
+
+	next atomic.Uint64
+}
+
+var _ rpcclient.Client = (*nodePool)(nil)
+
+type poolNode struct {
+	remote string
+	caller rpcclient.Client
+
+	mu        sync.Mutex
+	down      bool
+	nextCheck time.Time
+	checking  bool // a health check is in flight
+}
+
+// newNodePool returns a nodePool over the given HTTP remotes.
+func newNodePool(logger *slog.Logger, remotes []string, timeout, interval time.Duration) (*nodePool, error) {
+	pool := &nodePool{logger: logger, timeout: timeout, interval: interval}
+	for _, remote := range remotes {
+		caller, err := rpchttp.NewClient(remote)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote %q: %w", remote, err)
+		}
+		pool.nodes = append(pool.nodes, &poolNode{remote: remote, caller: caller})
+	}
+	return pool, nil
+}
+
+// SendRequest sends the request to the first node answering it.
+func (p *nodePool) SendRequest(ctx context.Context, req rpctypes.RPCRequest) (res *rpctypes.RPCResponse, err error) {
+	err = p.do(ctx, func(ctx context.Context, caller rpcclient.Client) (err error) {
+		res, err = caller.SendRequest(ctx, req)
+		return err
+	})
+	return res, err
+}
+
+// SendBatch sends the batch to the first node answering it.
+func (p *nodePool) SendBatch(ctx context.Context, reqs rpctypes.RPCRequests) (res rpctypes.RPCResponses, err error) {
+	err = p.do(ctx, func(ctx context.Context, caller rpcclient.Client) (err error) {
+		res, err = caller.SendBatch(ctx, reqs)
+		return err
+	})
+	return res, err
+}
+
+// Close closes the clients of all nodes.
+func (p *nodePool) Close() error {
+	var errs []error
+	for _, node := range p.nodes {
+		errs = append(errs, node.caller.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// do makes the given call on each node in turn, starting with the next
+// healthy one, until one of them succeeds.
+func (p *nodePool) do(ctx context.Context, call func(ctx context.Context, caller rpcclient.Client) error) error {
+	now := time.Now()
+	start := int(p.next.Add(1) - 1)
+
+	order := make([]*poolNode, 0, len(p.nodes))
+	var down []*poolNode
+	for i := range p.nodes {
+		node := p.nodes[(start+i)%len(p.nodes)]
+		if node.isDown() {
+			down = append(down, node)
+			p.maybeCheck(node, now)
+			continue
+		}
+		order = append(order, node)
+	}
+	// Down nodes are a last resort, rather than failing without trying
+	order = append(order, down...)
+
+	var errs []error
+	for _, node := range order {
+		err := p.attempt(ctx, node, call)
+		if err == nil {
+			p.markUp(node)
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			// The caller gave up, the node is not to blame
+			return err
+		}
+
+		p.markDown(node, err, now)
+		errs = append(errs, fmt.Errorf("%s: %w", node.remote, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// attempt makes the given call on a single node, within the pool timeout.
+func (p *nodePool) attempt(ctx context.Context, node *poolNode, call func(ctx context.Context, caller rpcclient.Client) error) error {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	return call(ctx, node.caller)
+}
+
+// maybeCheck health-checks the given down node in the background, if it
+// is due.
+func (p *nodePool) maybeCheck(node *poolNode, now time.Time) {
+	node.mu.Lock()
+	if node.checking || now.Before(node.nextCheck) {
+		node.mu.Unlock()
+		return
+	}
+	node.checking = true
+	node.mu.Unlock()
+
+	go func() {
+		err := p.check(node)
+
+		node.mu.Lock()
+		node.checking = false
+		node.nextCheck = time.Now().Add(p.interval)
+		node.mu.Unlock()
+
+		if err == nil {
+			p.markUp(node)
+		}
+	}()
+}
+
+// check sends a `health` request to the given node.
+func (p *nodePool) check(node *poolNode) error {
+	req, err := rpctypes.MapToRequest(rpctypes.JSONRPCStringID("gnoweb-health"), "health", nil)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	return p.attempt(ctx, node, func(ctx context.Context, caller rpcclient.Client) error {
+		res, err := caller.SendRequest(ctx, req)
+		if err != nil {
+			return err
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		return nil
+	})
+}
+
+func (node *poolNode) isDown() bool {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return node.down
+}
+
+func (p *nodePool) markUp(node *poolNode) {
+	node.mu.Lock()
+	wasDown := node.down
+	node.down = false
+	node.mu.Unlock()
+
+	if wasDown {
+		p.logger.Info("node is back up", "remote", node.remote)
+	}
+}
+
+func (p *nodePool) markDown(node *poolNode, err error, now time.Time) {
+	node.mu.Lock()
+	wasDown := node.down
+	if !wasDown {
+		node.down = true
+		node.nextCheck = now.Add(p.interval)
+	}
+	node.mu.Unlock()
+
+	if !wasDown {
+		p.logger.Warn("node is down, failing over", "remote", node.remote, "error", err)
+	}
+}
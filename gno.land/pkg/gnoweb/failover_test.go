@@ -0,0 +1,133 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCaller is a JSON-RPC caller failing while `fail` is set.
+type stubCaller struct {
+	calls atomic.Int32
+	fail  atomic.Bool
+	hang  bool
+}
+
+func (c *stubCaller) SendRequest(ctx context.Context, req rpctypes.RPCRequest) (*rpctypes.RPCResponse, error) {
+	c.calls.Add(1)
+	if c.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if c.fail.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return &rpctypes.RPCResponse{ID: req.ID}, nil
+}
+
+func (c *stubCaller) SendBatch(ctx context.Context, reqs rpctypes.RPCRequests) (rpctypes.RPCResponses, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *stubCaller) Close() error { return nil }
+
+func newTestNodePool(interval time.Duration, callers ...*stubCaller) *nodePool {
+	pool := &nodePool{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), timeout: 50 * time.Millisecond, interval: interval}
+	for i, caller := range callers {
+		pool.nodes = append(pool.nodes, &poolNode{remote: string(rune('a' + i)), caller: caller})
+	}
+	return pool
+}
+
+func TestSplitRemotes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"127.0.0.1:26657"}, splitRemotes("127.0.0.1:26657"))
+	assert.Equal(t, []string{"http://a:26657", "http://b:26657"}, splitRemotes(" http://a:26657, http://b:26657,"))
+	assert.Empty(t, splitRemotes(""))
+}
+
+func TestNodePool(t *testing.T) {
+	t.Parallel()
+
+	req := rpctypes.RPCRequest{ID: rpctypes.JSONRPCStringID("1"), Method: "status"}
+	send := func(pool *nodePool) error {
+		_, err := pool.SendRequest(context.Background(), req)
+		return err
+	}
+
+	t.Run("load balance", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := &stubCaller{}, &stubCaller{}
+		pool := newTestNodePool(time.Hour, a, b)
+		for range 4 {
+			require.NoError(t, send(pool))
+		}
+
+		assert.Equal(t, int32(2), a.calls.Load())
+		assert.Equal(t, int32(2), b.calls.Load())
+	})
+
+	t.Run("fail over", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := &stubCaller{}, &stubCaller{}
+		a.fail.Store(true)
+		pool := newTestNodePool(time.Hour, a, b)
+		for range 4 {
+			require.NoError(t, send(pool))
+		}
+
+		// The failing node is skipped once marked down
+		assert.Equal(t, int32(1), a.calls.Load())
+		assert.Equal(t, int32(4), b.calls.Load())
+	})
+
+	t.Run("fail over on timeout", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := &stubCaller{hang: true}, &stubCaller{}
+		pool := newTestNodePool(time.Hour, a, b)
+		require.NoError(t, send(pool))
+		assert.Equal(t, int32(1), b.calls.Load())
+	})
+
+	t.Run("all down", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := &stubCaller{}, &stubCaller{}
+		a.fail.Store(true)
+		b.fail.Store(true)
+		pool := newTestNodePool(time.Hour, a, b)
+		require.Error(t, send(pool))
+
+		// Down nodes are still tried as a last resort
+		b.fail.Store(false)
+		require.NoError(t, send(pool))
+	})
+
+	t.Run("health check", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := &stubCaller{}, &stubCaller{}
+		a.fail.Store(true)
+		pool := newTestNodePool(time.Millisecond, a, b)
+		require.NoError(t, send(pool))
+		assert.True(t, pool.nodes[0].isDown())
+
+		a.fail.Store(false)
+		assert.Eventually(t, func() bool {
+			send(pool) // triggers the health check of the down node
+			return !pool.nodes[0].isDown()
+		}, time.Second, 5*time.Millisecond)
+	})
+}
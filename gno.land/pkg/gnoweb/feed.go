@@ -0,0 +1,280 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+const (
+	// FeedArgs are the render arguments of realm feeds, such as
+	// `/r/demo/blog:feed.xml`.
+	FeedArgs = "feed.xml"
+	// feedRenderFunc is the conventional function of realms rendering their
+	// feed. It returns markdown, like `Render`.
+	feedRenderFunc = "RenderFeed"
+	// feedMaxEntries bounds the number of entries of a feed.
+	feedMaxEntries = 50
+)
+
+// EvalFunc evaluates the expression, such as `RenderFeed()`, within the realm
+// at the given path, and returns the typed results, such as `("..." string)`.
+type EvalFunc func(ctx context.Context, path, expr string) ([]byte, error)
+
+var (
+	// reFeedHeading matches the level 2 headings starting feed entries, and
+	// the title and destination of their optional link.
+	reFeedHeading = regexp.MustCompile(`^##[ \t]+(?:\[([^\]]+)\]\(([^)\s]+)[^)]*\)|(.+?))[ \t#]*$`)
+	// reFeedDate matches the first date of feed entries.
+	reFeedDate = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2})(?:[T ](\d{2}:\d{2}(?::\d{2})?)(Z|[+-]\d{2}:\d{2})?)?\b`)
+)
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Link    atomLink     `xml:"link"`
+	Updated string       `xml:"updated"`
+	Content *atomContent `xml:"content,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// feedEntry is a level 2 section of a realm feed.
+type feedEntry struct {
+	title   string
+	link    string
+	body    []byte
+	updated time.Time
+}
+
+// ServeRealmFeed serves the Atom feed of the realm, whose entries are the
+// level 2 sections of its `RenderFeed()` output, if it declares one, or of
+// its `Render("")` output otherwise.
+func (h *HTTPHandler) ServeRealmFeed(ctx context.Context, gnourl *weburl.GnoURL, w http.ResponseWriter, r *http.Request) {
+	realm := *gnourl
+	realm.Args, realm.Query, realm.WebQuery = "", nil, nil
+
+	src, err := h.realmFeedSource(ctx, &realm)
+	if err != nil {
+		h.Logger.Debug("unable to fetch realm feed", "error", err, "path", gnourl.Path)
+		status, _ := GetClientErrorStatusPage(gnourl, err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	origin := requestOrigin(r)
+	realmURL := origin + realm.Path
+	now := time.Now().UTC()
+
+	feed := atomFeed{
+		Title: sanitizeTitle(gnourl.Path, "/", h.MaxTitleLength) + " - " + h.Static.Domain,
+		ID:    realmURL,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: realmURL + ":" + FeedArgs},
+			{Rel: "alternate", Type: "text/html", Href: realmURL},
+		},
+		Entries: []atomEntry{},
+	}
+	if meta, err := h.Renderer.RenderRealm(&bytes.Buffer{}, &realm, src); err == nil {
+		title := meta.Frontmatter.Title
+		if title == "" {
+			title = meta.Title
+		}
+		if title = sanitizeTitle(title, "", h.MaxTitleLength); title != "" {
+			feed.Title = title + " - " + h.Static.Domain
+		}
+	}
+
+	var updated time.Time
+	for _, entry := range parseFeedEntries(src) {
+		link := resolveFeedLink(origin, realm.Path, entry.link)
+		if entry.updated.IsZero() {
+			entry.updated = now
+		}
+		if entry.updated.After(updated) {
+			updated = entry.updated
+		}
+
+		e := atomEntry{
+			Title:   sanitizeTitle(entry.title, link, h.MaxTitleLength),
+			ID:      link,
+			Link:    atomLink{Rel: "alternate", Type: "text/html", Href: link},
+			Updated: entry.updated.Format(time.RFC3339),
+		}
+
+		// NOTE: `RenderRealm` should ensure that HTML content is sanitized
+		var content bytes.Buffer
+		if _, err := h.Renderer.RenderRealm(&content, &realm, entry.body); err == nil && content.Len() > 0 {
+			e.Content = &atomContent{Type: "html", Value: content.String()}
+		}
+		feed.Entries = append(feed.Entries, e)
+	}
+	if updated.IsZero() {
+		updated = now
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		h.Logger.Error("unable to write realm feed", "error", err, "path", gnourl.Path)
+	}
+}
+
+// realmFeedSource returns the markdown of the feed of the realm.
+func (h *HTTPHandler) realmFeedSource(ctx context.Context, realm *weburl.GnoURL) ([]byte, error) {
+	if h.FeedEval != nil && h.declaresFeed(ctx, realm.Path) {
+		res, err := h.FeedEval(ctx, realm.Path, feedRenderFunc+"()")
+		if err != nil {
+			return nil, err
+		}
+		return parseEvalString(res)
+	}
+	return h.Client.Realm(ctx, realm.Path, "")
+}
+
+// declaresFeed reports whether the realm declares a `RenderFeed() string`
+// function.
+func (h *HTTPHandler) declaresFeed(ctx context.Context, path string) bool {
+	jdoc, err := h.Client.Doc(ctx, path)
+	if err != nil {
+		return false
+	}
+	for _, fn := range jdoc.Funcs {
+		if fn.Type == "" && fn.Name == feedRenderFunc && len(fn.Params) == 0 &&
+			len(fn.Results) == 1 && fn.Results[0].Type == "string" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEvalString returns the value of a single string result of an
+// evaluation, such as `("hello" string)`.
+func parseEvalString(res []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(res))
+	quoted, ok := strings.CutPrefix(s, "(")
+	if ok {
+		quoted, ok = strings.CutSuffix(quoted, " string)")
+	}
+	if !ok {
+		return nil, errors.New("unexpected evaluation result")
+	}
+
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		return nil, errors.New("unexpected evaluation result")
+	}
+	return []byte(value), nil
+}
+
+// parseFeedEntries returns the level 2 sections of the markdown, up to
+// feedMaxEntries. Headings within fenced code blocks are ignored.
+func parseFeedEntries(src []byte) []feedEntry {
+	var entries []feedEntry
+	var fence string
+	for _, line := range bytes.SplitAfter(src, []byte("\n")) {
+		trimmed := strings.TrimSpace(string(line))
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+		} else if m := reFeedHeading.FindStringSubmatch(trimmed); m != nil {
+			if len(entries) == feedMaxEntries {
+				break
+			}
+			entry := feedEntry{title: m[1], link: m[2]}
+			if entry.title == "" {
+				entry.title = m[3]
+			}
+			entries = append(entries, entry)
+			continue
+		}
+
+		if len(entries) > 0 {
+			last := &entries[len(entries)-1]
+			last.body = append(last.body, line...)
+		}
+	}
+
+	for i := range entries {
+		entries[i].body = bytes.TrimSpace(entries[i].body)
+		entries[i].updated = parseFeedDate(entries[i].body)
+	}
+	return entries
+}
+
+// parseFeedDate returns the first date found in the entry, if any.
+func parseFeedDate(body []byte) time.Time {
+	m := reFeedDate.FindSubmatch(body)
+	if m == nil {
+		return time.Time{}
+	}
+
+	value, layout := string(m[1]), time.DateOnly
+	if len(m[2]) > 0 {
+		clock := string(m[2])
+		if len(clock) == len("15:04") {
+			clock += ":00"
+		}
+		value, layout = value+"T"+clock, "2006-01-02T15:04:05"
+		if len(m[3]) > 0 {
+			value, layout = value+string(m[3]), time.RFC3339
+		}
+	}
+
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// resolveFeedLink returns the absolute URL of an entry link, relative to the
+// realm. Entries without a link point to the realm itself.
+func resolveFeedLink(origin, path, link string) string {
+	switch {
+	case link == "":
+		return origin + path
+	case strings.HasPrefix(link, "https://"), strings.HasPrefix(link, "http://"):
+		return link
+	case strings.HasPrefix(link, "/"):
+		return origin + link
+	case strings.HasPrefix(link, ":"), strings.HasPrefix(link, "?"), strings.HasPrefix(link, "#"):
+		return origin + path + link
+	default:
+		return origin + path + "/" + strings.TrimPrefix(link, "./")
+	}
+}
@@ -0,0 +1,161 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedClient is a catalogClient whose blog realm declares `RenderFeed`.
+type feedClient struct {
+	catalogClient
+}
+
+func (c *feedClient) Doc(ctx context.Context, path string) (*doc.JSONDocumentation, error) {
+	if path != "/r/demo/blog" {
+		return nil, ErrClientPackageNotFound
+	}
+	return &doc.JSONDocumentation{Funcs: []*doc.JSONFunc{
+		{Name: "Render", Params: []*doc.JSONField{{Name: "path", Type: "string"}}, Results: []*doc.JSONField{{Type: "string"}}},
+		{Name: "RenderFeed", Results: []*doc.JSONField{{Type: "string"}}},
+	}}, nil
+}
+
+func TestServeRealmFeed(t *testing.T) {
+	t.Parallel()
+
+	cli := &feedClient{catalogClient{realms: map[string]string{
+		"/r/demo/boards": "# Boards\n\n## [Hello](:board/1)\n\nPosted on 2024-03-01 by alice.\n\n" +
+			"```\n## not an entry\n```\n\n## Untitled\n",
+		"/r/demo/blog": "# Blog\n\nSee the feed.\n",
+	}}}
+
+	feed := "# Gno Blog\n\n## [First post](/r/demo/blog:first)\n\n2024-01-02T10:00:00Z\n\n**Hello** world.\n"
+	eval := func(ctx context.Context, path, expr string) ([]byte, error) {
+		if path != "/r/demo/blog" || expr != "RenderFeed()" {
+			return nil, errors.New("unexpected eval")
+		}
+		return []byte("(" + strconv.Quote(feed) + " string)\n"), nil
+	}
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land"},
+		Feeds:         true,
+		FeedEval:      eval,
+	})
+	require.NoError(t, err)
+
+	get := func(t *testing.T, path string) (*httptest.ResponseRecorder, atomFeed) {
+		t.Helper()
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var feed atomFeed
+		if rr.Code == http.StatusOK {
+			require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &feed))
+		}
+		return rr, feed
+	}
+
+	t.Run("render", func(t *testing.T) {
+		t.Parallel()
+
+		rr, feed := get(t, "/r/demo/boards:feed.xml")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/atom+xml; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		assert.Equal(t, "Boards - gno.land", feed.Title)
+		assert.Equal(t, "http://example.com/r/demo/boards", feed.ID)
+		require.Len(t, feed.Entries, 2)
+
+		hello := feed.Entries[0]
+		assert.Equal(t, "Hello", hello.Title)
+		assert.Equal(t, "http://example.com/r/demo/boards:board/1", hello.Link.Href)
+		assert.Equal(t, "2024-03-01T00:00:00Z", hello.Updated)
+		require.NotNil(t, hello.Content)
+		assert.Contains(t, hello.Content.Value, "by alice.")
+		assert.Contains(t, hello.Content.Value, "not an entry")
+
+		untitled := feed.Entries[1]
+		assert.Equal(t, "Untitled", untitled.Title)
+		assert.Equal(t, "http://example.com/r/demo/boards", untitled.Link.Href)
+		assert.Nil(t, untitled.Content)
+	})
+
+	t.Run("render feed", func(t *testing.T) {
+		t.Parallel()
+
+		rr, feed := get(t, "/r/demo/blog:feed.xml")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, "Gno Blog - gno.land", feed.Title)
+		assert.Equal(t, "2024-01-02T10:00:00Z", feed.Updated)
+		require.Len(t, feed.Entries, 1)
+		assert.Equal(t, "First post", feed.Entries[0].Title)
+		assert.Equal(t, "http://example.com/r/demo/blog:first", feed.Entries[0].ID)
+		assert.Contains(t, feed.Entries[0].Content.Value, "<strong>Hello</strong> world.")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		rr, _ := get(t, "/r/demo/unknown:feed.xml")
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("discovery", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `type="application/atom+xml"`)
+		assert.Contains(t, rr.Body.String(), `href="/r/demo/boards:feed.xml"`)
+	})
+}
+
+func TestParseFeedDate(t *testing.T) {
+	t.Parallel()
+
+	for body, expected := range map[string]string{
+		"Posted 2024-03-01.":              "2024-03-01T00:00:00Z",
+		"At 2024-03-01 12:30 by bob":      "2024-03-01T12:30:00Z",
+		"At 2024-03-01T12:30:15+02:00":    "2024-03-01T10:30:15Z",
+		"Not a date: 2024-13-45, or 2024": "",
+	} {
+		got := parseFeedDate([]byte(body))
+		if expected == "" {
+			assert.True(t, got.IsZero(), body)
+			continue
+		}
+		assert.Equal(t, expected, got.Format(time.RFC3339), body)
+	}
+}
+
+func TestParseEvalString(t *testing.T) {
+	t.Parallel()
+
+	value, err := parseEvalString([]byte("(\"# Hello\\n\\nworld\" string)\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello\n\nworld", string(value))
+
+	for _, res := range []string{"(1 int)", "(\"a\" string)\n(\"b\" string)", "hello"} {
+		_, err := parseEvalString([]byte(res))
+		assert.Error(t, err, res)
+	}
+}
@@ -0,0 +1,45 @@
+import { BaseController } from "./controller.js";
+
+type Viz = { renderSVGElement(src: string): SVGSVGElement };
+
+declare global {
+	interface Window {
+		Viz?: { instance(): Promise<Viz> };
+	}
+}
+
+// load the viz.js runtime once per page
+let runtime: Promise<Viz> | null = null;
+const loadRuntime = (url: string): Promise<Viz> => {
+	if (!runtime) {
+		runtime = new Promise<void>((resolve, reject) => {
+			const script = document.createElement("script");
+			script.src = url;
+			script.onload = () => resolve();
+			script.onerror = () => reject(new Error(`unable to load ${url}`));
+			document.head.appendChild(script);
+		}).then(() => {
+			if (!window.Viz) throw new Error("viz.js runtime not found");
+			return window.Viz.instance();
+		});
+	}
+	return runtime;
+};
+
+export class DotController extends BaseController {
+	protected connect(): void {
+		const url = this.getValue("runtime");
+		const source = this.getTarget("source");
+		if (!url || !source) return;
+
+		loadRuntime(url)
+			.then((viz) => {
+				const svg = viz.renderSVGElement(source.textContent || "");
+				source.replaceWith(svg);
+			})
+			.catch((err) => {
+				// keep displaying the graph source
+				console.error("❌ Unable to render dot graph:", err);
+			});
+	}
+}
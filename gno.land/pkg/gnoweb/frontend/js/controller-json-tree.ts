@@ -0,0 +1,94 @@
+import { BaseController } from "./controller.js";
+
+type JSONValue =
+	| null
+	| boolean
+	| number
+	| string
+	| JSONValue[]
+	| { [key: string]: JSONValue };
+
+// number of entries above which arrays and objects start collapsed
+const DEFAULT_COLLAPSE = 20;
+
+export class JsonTreeController extends BaseController {
+	protected connect(): void {
+		const data = this.getTarget("data");
+		const source = this.getTarget("source");
+		if (!data || !source) return;
+
+		let value: JSONValue;
+		try {
+			value = JSON.parse(data.textContent || "");
+		} catch (err) {
+			// keep displaying the highlighted source
+			console.error("❌ Unable to parse json:", err);
+			return;
+		}
+
+		const collapse = Number(this.getValue("collapse")) || DEFAULT_COLLAPSE;
+
+		const tree = document.createElement("div");
+		tree.className = "gno-json-tree-view";
+		tree.appendChild(this.renderValue(value, null, collapse));
+
+		// toggle between the tree and the raw highlighted source
+		const toggle = document.createElement("button");
+		toggle.type = "button";
+		toggle.className = "gno-json-tree-toggle";
+		toggle.textContent = "Raw";
+		toggle.addEventListener("click", () => {
+			const raw = source.hidden;
+			source.hidden = !raw;
+			tree.hidden = raw;
+			toggle.textContent = raw ? "Tree" : "Raw";
+		});
+
+		source.hidden = true;
+		source.before(toggle, tree);
+	}
+
+	private renderValue(
+		value: JSONValue,
+		key: string | null,
+		collapse: number,
+	): HTMLElement {
+		if (value === null || typeof value !== "object") {
+			const leaf = document.createElement("div");
+			leaf.className = "gno-json-tree-leaf";
+			if (key !== null) leaf.appendChild(this.renderKey(key));
+			const span = document.createElement("span");
+			span.className = `gno-json-tree-${value === null ? "null" : typeof value}`;
+			span.textContent = JSON.stringify(value);
+			leaf.appendChild(span);
+			return leaf;
+		}
+
+		const entries: [string | null, JSONValue][] = Array.isArray(value)
+			? value.map((v) => [null, v])
+			: Object.entries(value);
+		const [open, close] = Array.isArray(value) ? ["[", "]"] : ["{", "}"];
+
+		const node = document.createElement("details");
+		node.className = "gno-json-tree-node";
+		node.open = entries.length <= collapse;
+
+		const summary = document.createElement("summary");
+		if (key !== null) summary.appendChild(this.renderKey(key));
+		const count = `${entries.length} ${entries.length === 1 ? "item" : "items"}`;
+		summary.append(`${open} ${count} ${close}`);
+		node.appendChild(summary);
+
+		for (const [k, v] of entries) {
+			node.appendChild(this.renderValue(v, k, collapse));
+		}
+		return node;
+	}
+
+	private renderKey(key: string): HTMLElement {
+		const span = document.createElement("span");
+		span.className = "gno-json-tree-key";
+		span.textContent = `${JSON.stringify(key)}: `;
+		return span;
+	}
+}
@@ -0,0 +1,53 @@
+import { BaseController } from "./controller.js";
+
+type KaTeX = {
+	render(tex: string, element: HTMLElement, options: Record<string, unknown>): void;
+};
+
+declare global {
+	interface Window {
+		katex?: KaTeX;
+	}
+}
+
+// load the KaTeX runtime once per page
+let runtime: Promise<KaTeX> | null = null;
+const loadRuntime = (base: string): Promise<KaTeX> => {
+	if (!runtime) {
+		const style = document.createElement("link");
+		style.rel = "stylesheet";
+		style.href = `${base}/katex.min.css`;
+		document.head.appendChild(style);
+
+		runtime = new Promise<void>((resolve, reject) => {
+			const script = document.createElement("script");
+			script.src = `${base}/katex.min.js`;
+			script.onload = () => resolve();
+			script.onerror = () => reject(new Error(`unable to load ${script.src}`));
+			document.head.appendChild(script);
+		}).then(() => {
+			if (!window.katex) throw new Error("KaTeX runtime not found");
+			return window.katex;
+		});
+	}
+	return runtime;
+};
+
+export class MathController extends BaseController {
+	protected connect(): void {
+		const base = this.getValue("katex");
+		if (!base) return;
+
+		const tex = this.element.textContent || "";
+		const displayMode = this.element.dataset.mathDisplay === "block";
+		loadRuntime(base)
+			.then((katex) => {
+				// untrusted input: no \href, \url nor html extensions
+				katex.render(tex, this.element, { displayMode, throwOnError: false, trust: false });
+			})
+			.catch((err) => {
+				// keep displaying the expression source
+				console.error("❌ Unable to render math:", err);
+			});
+	}
+}
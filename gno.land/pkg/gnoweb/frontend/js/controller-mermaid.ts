@@ -0,0 +1,55 @@
+import { BaseController } from "./controller.js";
+
+type Mermaid = {
+	initialize(config: Record<string, unknown>): void;
+	render(id: string, src: string): Promise<{ svg: string }>;
+};
+
+declare global {
+	interface Window {
+		mermaid?: Mermaid;
+	}
+}
+
+// load the mermaid runtime once per page
+let runtime: Promise<Mermaid> | null = null;
+const loadRuntime = (url: string): Promise<Mermaid> => {
+	if (!runtime) {
+		runtime = new Promise<void>((resolve, reject) => {
+			const script = document.createElement("script");
+			script.src = url;
+			script.onload = () => resolve();
+			script.onerror = () => reject(new Error(`unable to load ${url}`));
+			document.head.appendChild(script);
+		}).then(() => {
+			if (!window.mermaid) throw new Error("mermaid runtime not found");
+			// strict mode disables scripts and click callbacks in diagrams
+			window.mermaid.initialize({ startOnLoad: false, securityLevel: "strict" });
+			return window.mermaid;
+		});
+	}
+	return runtime;
+};
+
+// unique ids of the rendered diagrams
+let count = 0;
+
+export class MermaidController extends BaseController {
+	protected connect(): void {
+		const url = this.getValue("runtime");
+		const source = this.getTarget("source");
+		if (!url || !source) return;
+
+		loadRuntime(url)
+			.then((mermaid) => mermaid.render(`gno-mermaid-${++count}`, source.textContent || ""))
+			.then(({ svg }) => {
+				const diagram = document.createElement("div");
+				diagram.innerHTML = svg;
+				source.replaceWith(diagram);
+			})
+			.catch((err) => {
+				// keep displaying the diagram source
+				console.error("❌ Unable to render mermaid diagram:", err);
+			});
+	}
+}
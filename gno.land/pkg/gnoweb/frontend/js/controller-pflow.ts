@@ -0,0 +1,145 @@
+import { BaseController } from "./controller.js";
+
+type Pflow = { render(container: HTMLElement, model: unknown): void };
+
+declare global {
+	interface Window {
+		pflow?: Pflow;
+	}
+}
+
+type Integrity = { js: string; css: string };
+
+// load the pflow viewer assets once per page and base URL, checking their
+// integrity if known
+const viewers = new Map<string, Promise<Pflow>>();
+const loadViewer = (base: string, integrity: Integrity): Promise<Pflow> => {
+	let viewer = viewers.get(base);
+	if (!viewer) {
+		const style = document.createElement("link");
+		style.rel = "stylesheet";
+		style.href = `${base}/pflow.css`;
+		if (integrity.css) {
+			style.integrity = integrity.css;
+			style.crossOrigin = "anonymous";
+		}
+		document.head.appendChild(style);
+
+		viewer = new Promise<void>((resolve, reject) => {
+			const script = document.createElement("script");
+			script.src = `${base}/pflow.js`;
+			if (integrity.js) {
+				script.integrity = integrity.js;
+				script.crossOrigin = "anonymous";
+			}
+			script.onload = () => resolve();
+			script.onerror = () => {
+				style.remove();
+				reject(new Error(`unable to load ${script.src}`));
+			};
+			document.head.appendChild(script);
+		}).then(() => {
+			if (!window.pflow) throw new Error("pflow viewer not found");
+			return window.pflow;
+		});
+		viewers.set(base, viewer);
+	}
+	return viewer;
+};
+
+type Marking = Record<string, number>;
+type Simulation = { marking?: Marking; enabled?: string[]; error?: string };
+
+export class PflowController extends BaseController {
+	private marking: Marking | null = null;
+
+	protected connect(): void {
+		const cdn = this.getValue("cdn");
+		const simulate = this.getValue("simulate");
+		const data = this.getTarget("model");
+		if ((!cdn && !simulate) || !data) return;
+
+		let model: unknown;
+		try {
+			model = JSON.parse(data.textContent || "");
+		} catch (err) {
+			// keep displaying the static image
+			console.error("❌ Unable to parse pflow model:", err);
+			return;
+		}
+
+		if (!cdn) {
+			this.simulate(simulate, model);
+			return;
+		}
+
+		const fallback = this.getValue("fallback");
+		const integrity = {
+			js: this.getValue("js-integrity"),
+			css: this.getValue("css-integrity"),
+		};
+		loadViewer(cdn, integrity)
+			.catch((err) => {
+				if (!fallback) throw err;
+				console.warn("⚠️ pflow: falling back to local assets:", err);
+				return loadViewer(fallback, integrity);
+			})
+			.then((pflow) => {
+				// scope the viewer elements to the block id
+				const container = document.createElement("div");
+				container.id = `${this.element.id}-viewer`;
+				this.element.replaceChildren(container);
+				pflow.render(container, model);
+			})
+			.catch((err) => {
+				// keep displaying the static image, animated if possible
+				console.error("❌ Unable to load pflow viewer:", err);
+				if (simulate) this.simulate(simulate, model);
+			});
+	}
+
+	// fire the clicked transitions of the static image through the
+	// simulation endpoint
+	private simulate(endpoint: string, model: unknown): void {
+		const step = (sequence: string[]) =>
+			fetch(endpoint, {
+				method: "POST",
+				headers: { "Content-Type": "application/json" },
+				body: JSON.stringify({ model, marking: this.marking, sequence }),
+			})
+				.then((res) => res.json() as Promise<Simulation>)
+				.then((sim) => {
+					if (sim.error) console.warn("⚠️ pflow:", sim.error);
+					if (sim.marking) this.update(sim.marking, sim.enabled || []);
+				})
+				.catch((err) => {
+					console.error("❌ Unable to simulate pflow:", err);
+				});
+
+		this.element
+			.querySelectorAll<SVGElement>("[data-pflow-transition]")
+			.forEach((el) => {
+				el.addEventListener("click", () => {
+					const id = el.dataset.pflowTransition;
+					if (id && el.classList.contains("is-enabled")) step([id]);
+				});
+			});
+		step([]);
+	}
+
+	private update(marking: Marking, enabled: string[]): void {
+		this.marking = marking;
+		this.element
+			.querySelectorAll<SVGElement>("[data-pflow-place]")
+			.forEach((el) => {
+				const tokens = marking[el.dataset.pflowPlace || ""] || 0;
+				el.textContent = tokens > 0 ? String(tokens) : "";
+			});
+		this.element
+			.querySelectorAll<SVGElement>("[data-pflow-transition]")
+			.forEach((el) => {
+				const id = el.dataset.pflowTransition || "";
+				el.classList.toggle("is-enabled", enabled.includes(id));
+			});
+	}
+}
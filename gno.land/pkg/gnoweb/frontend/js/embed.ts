@@ -0,0 +1,18 @@
+// Report the document height to the embedding page, so that it can resize
+// the iframe to fit the content.
+const EMBED_MESSAGE_TYPE = "gnoweb:embed-height";
+
+const postHeight = (): void => {
+	window.parent.postMessage(
+		{
+			type: EMBED_MESSAGE_TYPE,
+			height: document.documentElement.scrollHeight,
+		},
+		"*",
+	);
+};
+
+if (window.parent !== window) {
+	new ResizeObserver(postHeight).observe(document.body);
+	postHeight();
+}
@@ -0,0 +1,132 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// graphMaxPackages bounds the number of on-chain packages of dependency
+// graphs, the imports of the others are not followed.
+const graphMaxPackages = 50
+
+// GetGraphView returns the view of the dependency graph of a package,
+// requested with the `$graph` web query: the on-chain packages it imports,
+// directly or not, and the standard libraries they use.
+func (h *HTTPHandler) GetGraphView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
+	domain := h.Static.Domain + "/"
+
+	var (
+		graph     md.Graph
+		seen      = map[string]bool{gnourl.Path: true}
+		queue     = []string{gnourl.Path}
+		packages  int
+		stdlibs   int
+		truncated bool
+	)
+	for len(queue) > 0 {
+		pkgPath := queue[0]
+		queue = queue[1:]
+
+		u := weburl.GnoURL{Path: pkgPath, WebQuery: map[string][]string{"graph": {""}}}
+		graph.Nodes = append(graph.Nodes, md.GraphNode{ID: pkgPath, Label: pkgPath, URL: u.EncodeWebURL()})
+		packages++
+		if packages > graphMaxPackages {
+			truncated = true
+			continue
+		}
+
+		imports, err := h.packageImports(ctx, pkgPath)
+		if err != nil {
+			if pkgPath == gnourl.Path {
+				h.Logger.Warn("unable to fetch package imports", "path", pkgPath, "error", err)
+				return GetClientErrorStatusPage(gnourl, err)
+			}
+			h.Logger.Debug("unable to fetch package imports", "path", pkgPath, "error", err)
+			continue
+		}
+
+		for _, imp := range imports {
+			dep, onchain := strings.CutPrefix(imp, domain)
+			if onchain {
+				dep = "/" + dep
+			}
+			graph.Edges = append(graph.Edges, md.GraphEdge{From: pkgPath, To: dep})
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+
+			if onchain {
+				queue = append(queue, dep)
+			} else {
+				graph.Nodes = append(graph.Nodes, md.GraphNode{ID: dep, Label: dep, Muted: true})
+				stdlibs++
+			}
+		}
+	}
+
+	var svg bytes.Buffer
+	packages-- // the package itself
+	desc := fmt.Sprintf("Dependency graph of %s: %d packages and %d standard libraries", gnourl.Path, packages, stdlibs)
+	if err := md.WriteGraphSVG(&svg, "graph", desc, &graph); err != nil {
+		h.Logger.Error("unable to render dependency graph", "path", gnourl.Path, "error", err)
+		return http.StatusInternalServerError, components.StatusErrorComponent("internal error")
+	}
+
+	source := weburl.GnoURL{Path: gnourl.Path, WebQuery: map[string][]string{"source": {""}}}
+	return http.StatusOK, components.GraphView(components.GraphData{
+		PkgPath:   gnourl.Path,
+		SourceURL: source.EncodeWebURL(),
+		SVG:       template.HTML(svg.String()), //nolint:gosec
+		Packages:  packages,
+		Stdlibs:   stdlibs,
+		Truncated: truncated,
+	})
+}
+
+// packageImports returns the sorted import paths of the non-test files of
+// the package.
+func (h *HTTPHandler) packageImports(ctx context.Context, pkgPath string) ([]string, error) {
+	names, err := h.Client.ListFiles(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []string
+	fset := token.NewFileSet()
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".gno") || strings.HasSuffix(name, "_test.gno") || strings.HasSuffix(name, "_filetest.gno") {
+			continue
+		}
+
+		src, _, err := h.Client.File(ctx, pkgPath, name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.ImportsOnly)
+		if err != nil {
+			h.Logger.Debug("unable to parse file imports", "path", pkgPath, "file", name, "error", err)
+			continue
+		}
+		for _, spec := range f.Imports {
+			if imp, err := strconv.Unquote(spec.Path.Value); err == nil && !slices.Contains(imports, imp) {
+				imports = append(imports, imp)
+			}
+		}
+	}
+
+	slices.Sort(imports)
+	return imports, nil
+}
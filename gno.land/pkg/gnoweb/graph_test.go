@@ -0,0 +1,69 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphView(t *testing.T) {
+	t.Parallel()
+
+	cli := NewMockClient(
+		&MockPackage{
+			Path:   "/r/demo/boards",
+			Domain: "gno.land",
+			Files: map[string]string{
+				"boards.gno":      "package boards\n\nimport (\n\t\"strings\"\n\n\t\"gno.land/p/demo/avl\"\n\t\"gno.land/p/demo/ufmt\"\n)\n",
+				"render.gno":      "package boards\n\nimport \"gno.land/p/demo/ufmt\"\n",
+				"boards_test.gno": "package boards\n\nimport \"gno.land/p/demo/testutils\"\n",
+			},
+		},
+		&MockPackage{
+			Path:   "/p/demo/avl",
+			Domain: "gno.land",
+			Files:  map[string]string{"avl.gno": "package avl\n\nimport \"gno.land/p/demo/ufmt\"\n"},
+		},
+		&MockPackage{
+			Path:   "/p/demo/ufmt",
+			Domain: "gno.land",
+			Files:  map[string]string{"ufmt.gno": "package ufmt\n\nimport \"strconv\"\n"},
+		},
+	)
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter:   cli,
+		Renderer:        newTestRenderer(),
+		Aliases:         map[string]AliasTarget{},
+		Meta:            StaticMetadata{Domain: "gno.land"},
+		DependencyGraph: true,
+	})
+	require.NoError(t, err)
+
+	imports, err := h.packageImports(context.Background(), "/r/demo/boards")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gno.land/p/demo/avl", "gno.land/p/demo/ufmt", "strings"}, imports)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards$graph", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "2 packages · 2 standard libraries")
+	assert.Contains(t, body, `<a href="/p/demo/avl$graph">`)
+	assert.Contains(t, body, `<a href="/p/demo/ufmt$graph">`)
+	assert.Contains(t, body, `stroke-dasharray="4 3"/><text x=`)
+	assert.NotContains(t, body, "testutils")
+	// boards → avl, boards → ufmt, avl → ufmt, and the two stdlib edges
+	assert.Equal(t, 5, strings.Count(body, "<line "))
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/missing$graph", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -5,9 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"go/token"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
 	"path"
 	"slices"
 	"sort"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/gnolang/gno/gnovm/pkg/doc"
 	"github.com/gnolang/gno/tm2/pkg/bech32"
@@ -31,6 +33,53 @@ type StaticMetadata struct {
 	ChainId    string
 	Analytics  bool
 	BuildTime  string
+
+	// ReportURLTemplate, if set, is used to build a "report this content"
+	// link on realm pages. Any `{path}` occurrence is replaced by the
+	// escaped realm path.
+	ReportURLTemplate string
+
+	// LiveReload injects the live reload script into pages.
+	LiveReload bool
+
+	// Realtime injects the realtime script into realm pages.
+	Realtime bool
+
+	// BuildVersion, if set, is exposed in the page footer and the
+	// `X-Gnoweb-Version` response header.
+	BuildVersion string
+
+	// OpenSearch references the OpenSearch description from pages.
+	OpenSearch bool
+
+	// Locales, if set, are referenced as `hreflang` alternates from pages.
+	// The first one is the default locale.
+	Locales []string
+
+	// DeprecatedPaths maps deprecated realm path prefixes to the URL of
+	// their replacement, or to a message, displayed as a banner.
+	DeprecatedPaths map[string]string
+
+	// Preconnect, if enabled, hints pages to preconnect to the CDN origins
+	// they use: the analytics one, and those of CDNOrigins referenced by
+	// the rendered content.
+	Preconnect bool
+	CDNOrigins []string
+
+	// OGImages references the generated social preview images of realms
+	// as `og:image`, unless their frontmatter declares one.
+	OGImages bool
+
+	// AssetNames maps asset names to their content-hashed name, referenced
+	// from pages instead of the build time busted name.
+	AssetNames map[string]string
+
+	// ThemePath, if set, is the path of the theme stylesheet.
+	ThemePath string
+
+	// TxExport renders forms exporting unsigned call transactions on the
+	// help pages of realms.
+	TxExport bool
 }
 
 type AliasKind int
@@ -52,6 +101,76 @@ type HTTPHandlerConfig struct {
 	Renderer      Renderer
 	Aliases       map[string]AliasTarget
 	Timeout       time.Duration
+
+	// ProgressiveRender, if enabled, flushes the response at the layout
+	// boundaries so the client can start rendering the page early. The
+	// content is fetched and rendered before anything is flushed, so that
+	// the status and the head of pages still depend on it.
+	ProgressiveRender bool
+
+	// ServeMarkdownSource, if enabled, serves the raw markdown of realms
+	// requested with a `.md` suffix or the `format=md` (or `raw`) query,
+	// and its plain text conversion with the `format=txt` query.
+	ServeMarkdownSource bool
+
+	// ListingSort and ListingPerPage are the default sort order and page
+	// size of path listings. A zero page size disables pagination.
+	ListingSort    ListingSort
+	ListingPerPage int
+
+	// MaxTitleLength is the maximum length of the realm part of page
+	// titles. Zero means DefaultMaxTitleLength.
+	MaxTitleLength int
+
+	// WarnPaths lists path prefixes of flagged content, displayed only
+	// once the visitor clicked through a content warning.
+	WarnPaths []string
+
+	// HomeGroups lists the namespaces, such as `/r/gnoland`, whose realms
+	// are listed on the home page, in display order. Realms outside of
+	// these namespaces are listed last if HomeGroupsOther is enabled.
+	HomeGroups      []string
+	HomeGroupsOther bool
+
+	// ServeStaleOnOutage, if enabled, serves the last render of realms
+	// with a warning banner while the node is unreachable.
+	ServeStaleOnOutage bool
+
+	// RenderErrors, if set, records the render errors of realms.
+	RenderErrors *RenderErrorLog
+
+	// TextRenderer, if set, renders realms requested with `?view=text`,
+	// served within the text layout.
+	TextRenderer Renderer
+
+	// Feeds, if enabled, serves the Atom feed of realms requested with the
+	// `feed.xml` render arguments. FeedEval, if set, is used to render the
+	// feed of realms declaring a `RenderFeed() string` function.
+	Feeds    bool
+	FeedEval EvalFunc
+
+	// Search, if set, serves the full-text search of packages at
+	// `/search?q=<query>`.
+	Search *SearchEngine
+
+	// HistoricalRender, if enabled, renders realms requested with the
+	// `height=<N>` query as of the given past block height.
+	HistoricalRender bool
+
+	// SourceDiff, if enabled, serves the diff of packages requested with
+	// the `$diff` web query against another package or block height.
+	SourceDiff bool
+
+	// DependencyGraph, if enabled, serves the import graph of packages
+	// requested with the `$graph` web query.
+	DependencyGraph bool
+
+	// Account, if set, displays the on-chain account of users on their
+	// profile, resolving their name with UsersEval, and listing their
+	// recent transactions from TxIndexer if set.
+	Account   AccountFunc
+	UsersEval EvalFunc
+	TxIndexer TxIndexer
 }
 
 // validate checks if the HTTPHandlerConfig is valid.
@@ -75,6 +194,28 @@ type HTTPHandler struct {
 	Client   ClientAdapter
 	Renderer Renderer
 	Aliases  map[string]AliasTarget
+
+	ProgressiveRender   bool
+	ServeMarkdownSource bool
+	ListingSort         ListingSort
+	ListingPerPage      int
+	MaxTitleLength      int
+	WarnPaths           []string
+	RenderErrors        *RenderErrorLog
+	TextRenderer        Renderer
+	Feeds               bool
+	FeedEval            EvalFunc
+	Search              *SearchEngine
+	HistoricalRender    bool
+	SourceDiff          bool
+	DependencyGraph     bool
+	Account             AccountFunc
+	UsersEval           EvalFunc
+	TxIndexer           TxIndexer
+
+	homeGroups    *homeGroups
+	stale         *staleRenders
+	deployHeights *deployHeights
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
@@ -83,19 +224,59 @@ func NewHTTPHandler(logger *slog.Logger, cfg *HTTPHandlerConfig) (*HTTPHandler,
 		return nil, fmt.Errorf("config validate error: %w", err)
 	}
 
-	return &HTTPHandler{
+	var hg *homeGroups
+	if len(cfg.HomeGroups) > 0 {
+		hg = &homeGroups{
+			client:     cfg.ClientAdapter,
+			domain:     cfg.Meta.Domain,
+			namespaces: cfg.HomeGroups,
+			other:      cfg.HomeGroupsOther,
+		}
+	}
+
+	h := &HTTPHandler{
 		Client:   cfg.ClientAdapter,
 		Static:   cfg.Meta,
 		Renderer: cfg.Renderer,
 		Aliases:  cfg.Aliases,
 		Logger:   logger,
-	}, nil
+
+		ProgressiveRender:   cfg.ProgressiveRender,
+		ServeMarkdownSource: cfg.ServeMarkdownSource,
+		ListingSort:         cfg.ListingSort,
+		ListingPerPage:      cfg.ListingPerPage,
+		MaxTitleLength:      cfg.MaxTitleLength,
+		WarnPaths:           cfg.WarnPaths,
+		RenderErrors:        cfg.RenderErrors,
+		TextRenderer:        cfg.TextRenderer,
+		Feeds:               cfg.Feeds,
+		FeedEval:            cfg.FeedEval,
+		Search:              cfg.Search,
+		HistoricalRender:    cfg.HistoricalRender,
+		SourceDiff:          cfg.SourceDiff,
+		DependencyGraph:     cfg.DependencyGraph,
+		Account:             cfg.Account,
+		UsersEval:           cfg.UsersEval,
+		TxIndexer:           cfg.TxIndexer,
+
+		homeGroups:    hg,
+		deployHeights: newDeployHeights(cfg.ClientAdapter),
+	}
+	if cfg.ServeStaleOnOutage {
+		h.stale = newStaleRenders()
+	}
+
+	return h, nil
 }
 
 // ServeHTTP handles HTTP requests and only allows GET requests.
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.Logger.Debug("receiving request", "method", r.Method, "path", r.URL.Path)
 
+	if h.Static.BuildVersion != "" {
+		w.Header().Set("X-Gnoweb-Version", h.Static.BuildVersion)
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		w.Header().Add("Content-Type", "text/html; charset=utf-8")
@@ -116,20 +297,7 @@ func (h *HTTPHandler) Get(w http.ResponseWriter, r *http.Request) {
 			"elapsed", time.Since(start).String())
 	}()
 
-	indexData := components.IndexData{
-		HeadData: components.HeadData{
-			AssetsPath: h.Static.AssetsPath,
-			ChromaPath: h.Static.ChromaPath,
-			ChainId:    h.Static.ChainId,
-			Remote:     h.Static.RemoteHelp,
-			BuildTime:  h.Static.BuildTime,
-		},
-		FooterData: components.FooterData{
-			Analytics:  h.Static.Analytics,
-			AssetsPath: h.Static.AssetsPath,
-			BuildTime:  h.Static.BuildTime,
-		},
-	}
+	indexData := h.newIndexData(r)
 
 	// Parse the URL
 	gnourl, err := weburl.ParseFromURL(r.URL)
@@ -145,10 +313,47 @@ func (h *HTTPHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle download request outside of component rendering flow.
-	if gnourl.WebQuery.Has("download") {
-		h.ServeSourceDownload(r.Context(), gnourl, w, r)
-		return
+	// Flagged content is only served, in any format, once the visitor
+	// accepted its content warning.
+	contenturl := h.contentURL(r, gnourl)
+	h.acceptContentWarning(w, contenturl)
+	warned := h.contentWarningRequired(r, contenturl)
+
+	if !warned {
+		// Handle download request outside of component rendering flow.
+		if gnourl.WebQuery.Has("download") {
+			h.ServeSourceDownload(r.Context(), gnourl, w, r)
+			return
+		}
+
+		// Handle markdown source request outside of component rendering flow.
+		if h.ServeMarkdownSource {
+			if mdurl, ok := h.markdownSourceURL(r.Context(), gnourl); ok {
+				h.ServeRealmMarkdown(r.Context(), mdurl, gnourl.Query.Get("format") == "txt", w)
+				return
+			}
+		}
+
+		// Handle text view request outside of component rendering flow.
+		if h.TextRenderer != nil && gnourl.IsRealm() && gnourl.Query.Get("view") == "text" {
+			h.ServeRealmText(r.Context(), gnourl, w)
+			return
+		}
+
+		// Handle feed request outside of component rendering flow.
+		if h.Feeds && gnourl.IsRealm() && gnourl.Args == FeedArgs && len(gnourl.WebQuery) == 0 {
+			h.ServeRealmFeed(r.Context(), gnourl, w, r)
+			return
+		}
+	}
+
+	if h.Feeds && gnourl.IsRealm() {
+		realm := weburl.GnoURL{Path: gnourl.Path, Args: FeedArgs}
+		indexData.HeadData.FeedURL = realm.EncodeURL()
+	}
+
+	if h.Static.OGImages && gnourl.IsRealm() && gnourl.IsValidPath() {
+		indexData.HeadData.Image = ogImageURL(requestOrigin(r), gnourl)
 	}
 
 	// Set the header mode based on the URL type and context
@@ -163,8 +368,36 @@ func (h *HTTPHandler) Get(w http.ResponseWriter, r *http.Request) {
 		indexData.Mode = components.ViewModeRealm
 	}
 
+	if indexData.Mode.IsRealm() {
+		indexData.Deprecation = deprecationFor(h.Static.DeprecatedPaths, gnourl.Path)
+		// Only renders are watched, not the source and help views
+		if h.Static.Realtime && len(gnourl.WebQuery) == 0 && !gnourl.IsFile() {
+			indexData.FooterData.RealtimeScript = RealtimeScriptPath
+		}
+	}
+
+	if indexData.Mode.IsHome() && h.homeGroups != nil {
+		groups, err := h.homeGroups.Get(r.Context())
+		if err != nil {
+			h.Logger.Warn("unable to list home page realms", "error", err)
+		}
+		if len(groups) > 0 {
+			indexData.HomeGroups = components.RealmGroupsComponent(groups)
+		}
+	}
+
 	var status int
-	status, indexData.BodyView = h.prepareIndexBodyView(r, &indexData)
+	status, indexData.BodyView = h.prepareIndexBodyView(r, &indexData, warned)
+
+	// Setup report link for realm pages
+	if indexData.Mode.IsRealm() && h.Static.ReportURLTemplate != "" {
+		indexData.FooterData.ReportURL = buildReportURL(h.Static.ReportURLTemplate, indexData.HeaderData.RealmURL.EncodeURL())
+	}
+
+	// Flush the page progressively if supported by the writer
+	if f, ok := w.(http.Flusher); ok && h.ProgressiveRender {
+		indexData.Flusher = f
+	}
 
 	// Render the final page with the rendered body
 	w.WriteHeader(status)
@@ -173,6 +406,42 @@ func (h *HTTPHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// newIndexData returns the layout data shared by all pages of the request.
+func (h *HTTPHandler) newIndexData(r *http.Request) components.IndexData {
+	indexData := components.IndexData{
+		HeadData: components.HeadData{
+			AssetsPath: h.Static.AssetsPath,
+			ChromaPath: h.Static.ChromaPath,
+			ChainId:    h.Static.ChainId,
+			Remote:     h.Static.RemoteHelp,
+			BuildTime:  h.Static.BuildTime,
+			AssetNames: h.Static.AssetNames,
+			ThemePath:  h.Static.ThemePath,
+		},
+		Locale: requestLocale(r.Context()),
+		FooterData: components.FooterData{
+			Analytics:    h.Static.Analytics,
+			AssetsPath:   h.Static.AssetsPath,
+			BuildTime:    h.Static.BuildTime,
+			BuildVersion: h.Static.BuildVersion,
+			AssetNames:   h.Static.AssetNames,
+		},
+	}
+	if h.Static.LiveReload {
+		indexData.FooterData.LiveReloadScript = LiveReloadScriptPath
+	}
+	if h.Static.OpenSearch {
+		indexData.HeadData.OpenSearchPath = OpenSearchPath
+		indexData.HeadData.SiteName = h.Static.Domain
+	}
+	indexData.HeadData.Alternates = hreflangAlternates(requestOrigin(r), r.URL.Path, h.Static.Locales)
+	if h.Static.Preconnect && h.Static.Analytics {
+		indexData.HeadData.Preconnect = []string{analyticsOrigin}
+	}
+
+	return indexData
+}
+
 // Post processes a POST HTTP request.
 func (h *HTTPHandler) Post(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
@@ -204,8 +473,9 @@ func (h *HTTPHandler) Post(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, gnourl.EncodeWebURL(), http.StatusSeeOther)
 }
 
-// prepareIndexBodyView prepares the data and main view for the index page.
-func (h *HTTPHandler) prepareIndexBodyView(r *http.Request, indexData *components.IndexData) (int, *components.View) {
+// prepareIndexBodyView prepares the data and main view for the index page,
+// or the content warning interstitial if warned is set.
+func (h *HTTPHandler) prepareIndexBodyView(r *http.Request, indexData *components.IndexData, warned bool) (int, *components.View) {
 	ctx := r.Context()
 
 	aliasTarget, aliasExists := h.Aliases[r.URL.Path]
@@ -221,7 +491,18 @@ func (h *HTTPHandler) prepareIndexBodyView(r *http.Request, indexData *component
 		return http.StatusNotFound, components.StatusErrorComponent("invalid path")
 	}
 
-	indexData.HeadData.Title = h.Static.Domain + " - " + gnourl.Path
+	if h.HistoricalRender && gnourl.IsRealm() {
+		var height int64
+		if height, gnourl, err = parseHeightQuery(gnourl); err != nil {
+			return http.StatusBadRequest, components.StatusErrorComponent(err.Error())
+		}
+		if height > 0 {
+			ctx = withQueryHeight(ctx, height)
+			indexData.History = &components.HistoryData{Height: height, LatestURL: gnourl.EncodeWebURL()}
+		}
+	}
+
+	indexData.HeadData.Title = h.Static.Domain + " - " + sanitizeTitle(gnourl.Path, "/", h.MaxTitleLength)
 	indexData.HeaderData = components.HeaderData{
 		Breadcrumb: generateBreadcrumbPaths(gnourl),
 		RealmURL:   *gnourl,
@@ -230,9 +511,15 @@ func (h *HTTPHandler) prepareIndexBodyView(r *http.Request, indexData *component
 		Mode:       indexData.Mode,
 	}
 
+	if warned {
+		return http.StatusOK, components.StatusContentWarningComponent(contentWarningProceedURL(gnourl))
+	}
+
 	switch {
 	case aliasExists && aliasTarget.Kind == StaticMarkdown:
 		return h.GetMarkdownView(gnourl, aliasTarget.Value)
+	case h.Search != nil && gnourl.Path == SearchPath:
+		return h.GetSearchView(gnourl, indexData)
 	case gnourl.IsRealm(), gnourl.IsPure(), gnourl.IsUser():
 		return h.GetPackageView(ctx, gnourl, indexData)
 	default:
@@ -246,14 +533,14 @@ func (h *HTTPHandler) GetMarkdownView(gnourl *weburl.GnoURL, mdContent string) (
 	var content bytes.Buffer
 
 	// Use Goldmark for Markdown parsing
-	toc, err := h.Renderer.RenderRealm(&content, gnourl, []byte(mdContent))
+	meta, err := h.Renderer.RenderRealm(&content, gnourl, []byte(mdContent))
 	if err != nil {
 		h.Logger.Error("unable to render markdown file", "error", err, "path", gnourl.EncodeURL())
 		return GetClientErrorStatusPage(gnourl, err)
 	}
 
 	return http.StatusOK, components.RealmView(components.RealmData{
-		TocItems:         &components.RealmTOCData{Items: toc.Items},
+		TocItems:         &components.RealmTOCData{Items: meta.Toc.Items},
 		ComponentContent: components.NewReaderComponent(&content),
 	})
 }
@@ -265,6 +552,16 @@ func (h *HTTPHandler) GetPackageView(ctx context.Context, gnourl *weburl.GnoURL,
 		return h.GetHelpView(ctx, gnourl)
 	}
 
+	// Handle Diff page
+	if h.SourceDiff && gnourl.WebQuery.Has("diff") {
+		return h.GetDiffView(ctx, gnourl)
+	}
+
+	// Handle Graph page
+	if h.DependencyGraph && gnourl.WebQuery.Has("graph") {
+		return h.GetGraphView(ctx, gnourl)
+	}
+
 	// Handle Source page
 	if gnourl.WebQuery.Has("source") || gnourl.IsFile() {
 		return h.GetSourceView(ctx, gnourl)
@@ -288,8 +585,22 @@ func (h *HTTPHandler) GetPackageView(ctx context.Context, gnourl *weburl.GnoURL,
 func (h *HTTPHandler) GetRealmView(ctx context.Context, gnourl *weburl.GnoURL, indexData *components.IndexData) (int, *components.View) {
 	// First fecth the realm
 	raw, err := h.Client.Realm(ctx, gnourl.Path, gnourl.EncodeArgs())
+	// Only the latest renders are kept for outages
+	latest := queryHeightFromContext(ctx) == 0
 	switch {
 	case err == nil: // ok
+		if h.stale != nil && latest {
+			h.stale.Set(gnourl.Path, gnourl.EncodeArgs(), raw)
+		}
+	case h.stale != nil && latest && isOutageError(err):
+		// Serve the last known render, if any
+		var ok bool
+		if raw, ok = h.stale.Get(gnourl.Path, gnourl.EncodeArgs()); !ok {
+			h.Logger.Error("unable to fetch realm", "error", err, "path", gnourl.EncodeURL())
+			return h.realmErrorPage(ctx, gnourl, err)
+		}
+		h.Logger.Warn("node unreachable, serving stale realm", "error", err, "path", gnourl.EncodeURL())
+		indexData.Stale = true
 	case errors.Is(err, ErrClientRenderNotDeclared):
 		// No Render() declared: fall back to directory view (which will show README.md if present)
 		return h.GetDirectoryView(ctx, gnourl, indexData)
@@ -298,19 +609,26 @@ func (h *HTTPHandler) GetRealmView(ctx context.Context, gnourl *weburl.GnoURL, i
 		return h.GetPathsListView(ctx, gnourl, indexData)
 	default:
 		h.Logger.Error("unable to fetch realm", "error", err, "path", gnourl.EncodeURL())
-		return GetClientErrorStatusPage(gnourl, err)
+		return h.realmErrorPage(ctx, gnourl, err)
 	}
 
 	var content bytes.Buffer
 	meta, err := h.Renderer.RenderRealm(&content, gnourl, raw)
 	if err != nil {
 		h.Logger.Error("unable to render realm", "error", err, "path", gnourl.EncodeURL())
-		return GetClientErrorStatusPage(gnourl, err)
+		return h.realmErrorPage(ctx, gnourl, err)
+	}
+
+	if h.Static.Preconnect {
+		origins := usedOrigins(content.Bytes(), h.Static.CDNOrigins)
+		indexData.HeadData.Preconnect = append(indexData.HeadData.Preconnect, origins...)
 	}
 
+	h.applyFrontmatter(&indexData.HeadData, meta.Frontmatter)
+
 	return http.StatusOK, components.RealmView(components.RealmData{
 		TocItems: &components.RealmTOCData{
-			Items: meta.Items,
+			Items: meta.Toc.Items,
 		},
 		// NOTE: `RenderRealm` should ensure that HTML content is
 		// sanitized before rendering
@@ -318,6 +636,30 @@ func (h *HTTPHandler) GetRealmView(ctx context.Context, gnourl *weburl.GnoURL, i
 	})
 }
 
+// applyFrontmatter sets the page title, description and image declared by
+// the realm frontmatter, if any. Images must be https URLs.
+func (h *HTTPHandler) applyFrontmatter(head *components.HeadData, fm md.Frontmatter) {
+	if title := sanitizeTitle(fm.Title, "", h.MaxTitleLength); title != "" {
+		head.Title = title + " - " + h.Static.Domain
+	}
+	if fm.Description != "" {
+		head.Description = sanitizeTitle(fm.Description, "", maxDescriptionLength)
+	}
+	if u, err := url.Parse(fm.Image); err == nil && u.Scheme == "https" && u.Host != "" {
+		head.Image = u.String()
+	}
+}
+
+// realmErrorPage returns the error page of a realm which failed to render,
+// recording the error if the render errors history is enabled.
+func (h *HTTPHandler) realmErrorPage(ctx context.Context, gnourl *weburl.GnoURL, err error) (int, *components.View) {
+	status, view := GetClientErrorStatusPage(gnourl, err)
+	if h.RenderErrors != nil {
+		h.RenderErrors.Record(ctx, gnourl.Path, status, err)
+	}
+	return status, view
+}
+
 // buildContributions returns the sorted list of contributions (packages and realms) for a user.
 func (h *HTTPHandler) buildContributions(ctx context.Context, username string) ([]components.UserContribution, int, error) {
 	prefix := "@" + username
@@ -371,6 +713,7 @@ func CreateUsernameFromBech32(username string) string {
 // GetUserView returns the user profile view for a given GnoURL.
 func (h *HTTPHandler) GetUserView(ctx context.Context, gnourl *weburl.GnoURL) (int, *components.View) {
 	username := strings.TrimPrefix(gnourl.Path, "/u/")
+	user := username
 
 	var content bytes.Buffer
 
@@ -412,6 +755,9 @@ func (h *HTTPHandler) GetUserView(ctx context.Context, gnourl *weburl.GnoURL) (i
 		Content:       components.NewReaderComponent(&content),
 		// TODO: add bio, pic, links, teams, etc.
 	}
+	if h.Account != nil {
+		data.Account = h.buildAccount(ctx, user)
+	}
 
 	return http.StatusOK, components.UserView(data)
 }
@@ -424,18 +770,7 @@ func (h *HTTPHandler) GetHelpView(ctx context.Context, gnourl *weburl.GnoURL) (i
 	}
 
 	// Get public non-method funcs
-	fsigs := []*doc.JSONFunc{}
-	for _, fun := range jdoc.Funcs {
-		if !(fun.Type == "" && token.IsExported(fun.Name)) {
-			continue
-		}
-
-		if len(fun.Params) >= 1 && fun.Params[0].Type == "realm" {
-			// Don't make an entry field for "cur realm". The signature will still show it.
-			fun.Params = fun.Params[1:]
-		}
-		fsigs = append(fsigs, fun)
-	}
+	fsigs := helpFuncs(jdoc)
 
 	// Get selected function
 	selArgs := make(map[string]string)
@@ -456,6 +791,11 @@ func (h *HTTPHandler) GetHelpView(ctx context.Context, gnourl *weburl.GnoURL) (i
 		}
 	}
 
+	var txPath string
+	if h.Static.TxExport && gnourl.IsRealm() {
+		txPath = txExportURL(gnourl.Path)
+	}
+
 	realmName := path.Base(gnourl.Path)
 	return http.StatusOK, components.HelpView(components.HelpData{
 		SelectedFunc: selFn,
@@ -469,6 +809,7 @@ func (h *HTTPHandler) GetHelpView(ctx context.Context, gnourl *weburl.GnoURL) (i
 		Functions: fsigs,
 		Doc:       jdoc.PackageDoc,
 		Domain:    h.Static.Domain,
+		TxPath:    txPath,
 	})
 }
 
@@ -575,10 +916,10 @@ func (h *HTTPHandler) GetSourceView(ctx context.Context, gnourl *weburl.GnoURL)
 }
 
 func (h *HTTPHandler) GetPathsListView(ctx context.Context, gnourl *weburl.GnoURL, indexData *components.IndexData) (int, *components.View) {
-	const limit = 1_000 // XXX: implement pagination
-
-	prefix := path.Join(h.Static.Domain, gnourl.Path) + "/"
-	paths, qerr := h.Client.ListPaths(ctx, prefix, limit)
+	// XXX: paginate on the node side
+	filter := strings.TrimLeft(gnourl.Query.Get("prefix"), "/")
+	prefix := path.Join(h.Static.Domain, gnourl.Path) + "/" + filter
+	paths, qerr := h.Client.ListPaths(ctx, prefix, maxListingPaths)
 	if qerr != nil {
 		h.Logger.Error("unable to query path", "error", qerr, "path", gnourl.EncodeURL())
 	} else {
@@ -586,7 +927,10 @@ func (h *HTTPHandler) GetPathsListView(ctx context.Context, gnourl *weburl.GnoUR
 	}
 
 	if len(paths) == 0 || paths[0] == "" {
-		return GetClientErrorStatusPage(gnourl, ErrClientPackageNotFound)
+		if filter == "" || qerr != nil {
+			return GetClientErrorStatusPage(gnourl, ErrClientPackageNotFound)
+		}
+		paths = nil // no match, keep the filter form
 	}
 
 	// Always use explorer mode for paths list
@@ -595,12 +939,19 @@ func (h *HTTPHandler) GetPathsListView(ctx context.Context, gnourl *weburl.GnoUR
 	// Update header mode
 	indexData.HeaderData.Mode = indexData.Mode
 
-	return http.StatusOK, components.DirectoryView(
+	page, pagination := h.listingPage(gnourl, paths)
+	return http.StatusOK, components.PaginatedDirectoryView(
 		gnourl.Path,
-		paths,
+		page,
 		len(paths),
 		components.DirLinkTypeFile,
 		indexData.Mode,
+		components.Listing{
+			Pagination: pagination,
+			Sorts:      h.listingSorts(gnourl),
+			Sort:       gnourl.Query.Get("sort"),
+			Filter:     filter,
+		},
 	)
 }
 
@@ -669,6 +1020,69 @@ func (h *HTTPHandler) ServeSourceDownload(ctx context.Context, gnourl *weburl.Gn
 	w.Write(source) // write raw file
 }
 
+// markdownSourceURL returns the URL of the realm whose markdown source is
+// requested, either as `/r/foo/bar.md` or `/r/foo/bar?format=md`. The `raw`
+// and `txt` formats are also accepted.
+func (h *HTTPHandler) markdownSourceURL(ctx context.Context, gnourl *weburl.GnoURL) (*weburl.GnoURL, bool) {
+	if !gnourl.IsFile() {
+		switch gnourl.Query.Get("format") {
+		case "md", "raw", "txt":
+		default:
+			return nil, false
+		}
+		if !gnourl.IsRealm() {
+			return nil, false
+		}
+
+		mdurl := *gnourl
+		mdurl.Query = maps.Clone(gnourl.Query)
+		mdurl.Query.Del("format")
+		return &mdurl, true
+	}
+
+	name, ok := strings.CutSuffix(gnourl.File, ".md")
+	if !ok || name == "" {
+		return nil, false
+	}
+
+	mdurl := *gnourl
+	mdurl.Path = gnourl.Path + "/" + name
+	mdurl.File = ""
+	if !mdurl.IsRealm() || !mdurl.IsValidPath() {
+		return nil, false
+	}
+
+	// A file of the parent package takes precedence, such as `/r/foo/doc.md`
+	if files, err := h.Client.ListFiles(ctx, gnourl.Path); err == nil && slices.Contains(files, gnourl.File) {
+		return nil, false
+	}
+
+	return &mdurl, true
+}
+
+// ServeRealmMarkdown handles serving the raw markdown rendered by a realm,
+// or its plain text conversion if plain is set.
+func (h *HTTPHandler) ServeRealmMarkdown(ctx context.Context, gnourl *weburl.GnoURL, plain bool, w http.ResponseWriter) {
+	raw, err := h.Client.Realm(ctx, gnourl.Path, gnourl.EncodeArgs())
+	if err != nil {
+		h.Logger.Error("unable to fetch realm markdown", "error", err, "path", gnourl.EncodeURL())
+		status, _ := GetClientErrorStatusPage(gnourl, err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	if plain {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(md.PlainText(raw))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(raw)
+}
+
 func GetClientErrorStatusPage(_ *weburl.GnoURL, err error) (int, *components.View) {
 	if err == nil {
 		return http.StatusOK, nil
@@ -677,6 +1091,10 @@ func GetClientErrorStatusPage(_ *weburl.GnoURL, err error) (int, *components.Vie
 	switch {
 	case errors.Is(err, ErrClientTimeout):
 		return http.StatusRequestTimeout, components.StatusErrorComponent(err.Error())
+	case errors.Is(err, ErrClientUnavailable):
+		return http.StatusServiceUnavailable, components.StatusErrorComponent(err.Error())
+	case errors.Is(err, ErrClientTransport):
+		return http.StatusBadGateway, components.StatusErrorComponent("RPC node unreachable")
 	case errors.Is(err, ErrClientPackageNotFound):
 		return http.StatusNotFound, components.StatusErrorComponent(err.Error())
 	case errors.Is(err, ErrClientBadRequest):
@@ -688,6 +1106,11 @@ func GetClientErrorStatusPage(_ *weburl.GnoURL, err error) (int, *components.Vie
 	}
 }
 
+// buildReportURL substitutes `{path}` in the given template with the escaped path.
+func buildReportURL(template, path string) string {
+	return strings.ReplaceAll(template, "{path}", url.QueryEscape(path))
+}
+
 func generateBreadcrumbPaths(url *weburl.GnoURL) components.BreadcrumbData {
 	split := strings.Split(url.Path, "/")
 
@@ -5,12 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	gopath "path"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,8 +21,12 @@ import (
 	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	mdhtml "github.com/yuin/goldmark/renderer/html"
 )
 
 type testingLogger struct {
@@ -78,9 +85,9 @@ func (s *stubClient) ListPaths(ctx context.Context, prefix string, limit int) ([
 
 type rawRenderer struct{}
 
-func (rawRenderer) RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (md.Toc, error) {
+func (rawRenderer) RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (gnoweb.RealmMeta, error) {
 	_, err := w.Write(src)
-	return md.Toc{}, err
+	return gnoweb.RealmMeta{}, err
 }
 
 func (rawRenderer) RenderSource(w io.Writer, name string, src []byte) error {
@@ -621,6 +628,61 @@ func TestHTTPHandler_GetUserView(t *testing.T) {
 	assert.Contains(t, body, "testuser")
 }
 
+// stubTxIndexer is a TxIndexer returning fixed transactions.
+type stubTxIndexer []gnoweb.AccountTx
+
+func (idx stubTxIndexer) AccountTxs(ctx context.Context, addr crypto.Address, limit int) ([]gnoweb.AccountTx, error) {
+	return idx, nil
+}
+
+func TestHTTPHandler_GetUserView_Account(t *testing.T) {
+	t.Parallel()
+
+	addr := crypto.AddressFromPreimage([]byte("testuser"))
+	client := &stubClient{
+		listPathsFunc: func(ctx context.Context, prefix string, limit int) ([]string, error) {
+			return nil, nil
+		},
+		realmFunc: func(ctx context.Context, path string, args string) ([]byte, error) {
+			return nil, gnoweb.ErrClientPackageNotFound
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Account = func(ctx context.Context, a crypto.Address) (*std.BaseAccount, error) {
+		require.Equal(t, addr, a)
+		return &std.BaseAccount{Address: a, Coins: std.MustParseCoins("42ugnot"), AccountNumber: 7, Sequence: 3}, nil
+	}
+	cfg.UsersEval = func(ctx context.Context, path, expr string) ([]byte, error) {
+		if path != "/r/sys/users" || expr != `ResolveName("testuser")` {
+			return nil, fmt.Errorf("unexpected eval %s.%s", path, expr)
+		}
+		return []byte(`(&(struct{("` + addr.String() + `" .uverse.address),("testuser" string),(false bool)} gno.land/r/sys/users.UserData) *gno.land/r/sys/users.UserData)(true bool)`), nil
+	}
+	cfg.TxIndexer = stubTxIndexer{{Hash: "CAFE", Height: 12, Summary: "call gno.land/r/demo/boards.CreateThread"}}
+
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	for _, user := range []string{"testuser", addr.String()} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/u/"+user, nil))
+		require.Equal(t, http.StatusOK, rr.Code, user)
+
+		body := rr.Body.String()
+		assert.Contains(t, body, addr.String(), user)
+		assert.Contains(t, body, "42ugnot", user)
+		assert.Contains(t, body, "CAFE", user)
+		assert.Contains(t, body, "boards.CreateThread", user)
+	}
+
+	// Unknown names have no account section
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/u/unknown", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "42ugnot")
+}
+
 func TestHTTPHandler_GetUserView_QueryPathsError(t *testing.T) {
 	t.Parallel()
 
@@ -1045,3 +1107,905 @@ func TestHTTPHandler_DownloadWithContext(t *testing.T) {
 	assert.True(t, contextReceived)
 	assert.Contains(t, rr.Body.String(), content)
 }
+
+// flushRecorder is an httptest.ResponseRecorder counting flushes, and
+// recording the body written at the first one.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+	flushed string
+}
+
+func (r *flushRecorder) Flush() {
+	if r.flushes == 0 {
+		r.flushed = r.Body.String()
+	}
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestHTTPHandler_ProgressiveRender(t *testing.T) {
+	t.Parallel()
+
+	largeContent := strings.Repeat("| col1 | col2 |\n|------|------|\n| a | b |\n", 1000)
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte(largeContent), nil
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.ProgressiveRender = enabled
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/large", nil)
+			rr := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), largeContent)
+			if enabled {
+				assert.GreaterOrEqual(t, rr.flushes, 2, "expected multiple flushes")
+			} else {
+				assert.Zero(t, rr.flushes)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_ProgressiveRenderHead(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("---\ntitle: Realm title\n---\n# Realm content"), nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(&testingLogger{t}, nil))
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Renderer = gnoweb.NewHTMLRenderer(logger, gnoweb.NewDefaultRenderConfig())
+	cfg.ProgressiveRender = true
+	handler, err := gnoweb.NewHTTPHandler(logger, cfg)
+	require.NoError(t, err)
+
+	// The head is flushed before the content, and still depends on it
+	rr := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/realm", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.flushed, "<title>Realm title")
+	assert.NotContains(t, rr.flushed, "Realm content")
+	assert.Contains(t, rr.Body.String(), "Realm content")
+}
+
+func TestHTTPHandler_ProgressiveRenderStatus(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not found", gnoweb.ErrClientPackageNotFound, http.StatusNotFound},
+		{"render error", gnoweb.ErrClientResponse, http.StatusInternalServerError},
+		{"node down", gnoweb.ErrClientTransport, http.StatusBadGateway},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &stubClient{
+				realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+					return nil, tc.err
+				},
+				listPathsFunc: func(ctx context.Context, prefix string, limit int) ([]string, error) {
+					return nil, nil
+				},
+			}
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.ProgressiveRender = true
+			handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+			require.NoError(t, err)
+
+			rr := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/realm", nil))
+			assert.Equal(t, tc.status, rr.Code)
+			assert.NotZero(t, rr.flushes)
+		})
+	}
+}
+
+func TestHTTPHandler_ReportURL(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("user content"), nil
+		},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"configured", "https://report.example.com/form?path={path}", `href="https://report.example.com/form?path=%2Fr%2Ftest%2Frealm%3Athread%2F1"`},
+		{"unconfigured", "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Meta.ReportURLTemplate = tc.template
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/realm:thread/1", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			if tc.expected == "" {
+				assert.NotContains(t, rr.Body.String(), "Report this content")
+				return
+			}
+
+			assert.Contains(t, rr.Body.String(), tc.expected)
+			assert.Contains(t, rr.Body.String(), "Report this content")
+		})
+	}
+}
+
+func TestHTTPHandler_LiveReload(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Hello"), nil
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Meta.LiveReload = enabled
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/realm", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			script := `<script src="` + gnoweb.LiveReloadScriptPath + `"></script>`
+			if enabled {
+				assert.Contains(t, rr.Body.String(), script)
+			} else {
+				assert.NotContains(t, rr.Body.String(), script)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_Realtime(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Hello"), nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Meta.Realtime = true
+	handler, err := gnoweb.NewHTTPHandler(
+		slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+		cfg,
+	)
+	require.NoError(t, err)
+
+	script := `<script src="` + gnoweb.RealtimeScriptPath + `"></script>`
+	for path, expected := range map[string]bool{
+		"/r/test/realm":        true,
+		"/r/test/realm:args":   true,
+		"/r/test/realm$source": false,
+		"/p/test/pkg":          false,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		if expected {
+			assert.Contains(t, rr.Body.String(), script, path)
+		} else {
+			assert.NotContains(t, rr.Body.String(), script, path)
+		}
+	}
+}
+
+func TestHTTPHandler_ServeMarkdownSource(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			if path != "/r/demo/boards" {
+				return nil, gnoweb.ErrClientPackageNotFound
+			}
+			return []byte("# Boards\nargs: " + args), nil
+		},
+		listFilesFunc: func(ctx context.Context, path string) ([]string, error) {
+			if path != "/r/demo" {
+				return nil, gnoweb.ErrClientPackageNotFound
+			}
+			return []string{"demo.gno", "notes.md"}, nil
+		},
+		fileFunc: func(ctx context.Context, path, filename string) ([]byte, gnoweb.FileMeta, error) {
+			return []byte("file " + filename), gnoweb.FileMeta{}, nil
+		},
+	}
+
+	cases := []struct {
+		name         string
+		path         string
+		enabled      bool
+		expectedType string
+		expectedBody string
+	}{
+		{"suffix", "/r/demo/boards.md", true, "text/markdown; charset=utf-8", "# Boards\nargs: "},
+		{"suffix with args", "/r/demo/boards.md:thread/1", true, "text/markdown; charset=utf-8", "# Boards\nargs: thread/1"},
+		{"query", "/r/demo/boards?format=md", true, "text/markdown; charset=utf-8", "# Boards\nargs: "},
+		{"query with other args", "/r/demo/boards:page?format=md&sort=new", true, "text/markdown; charset=utf-8", "# Boards\nargs: page?sort=new"},
+		{"raw query", "/r/demo/boards:page?format=raw", true, "text/markdown; charset=utf-8", "# Boards\nargs: page"},
+		{"text query", "/r/demo/boards:page?format=txt", true, "text/plain; charset=utf-8", "Boards\n\nargs: page\n"},
+		{"unknown format", "/r/demo/boards?format=pdf", true, "text/html; charset=utf-8", "# Boards"},
+		{"package file", "/r/demo/notes.md", true, "text/html; charset=utf-8", "file notes.md"},
+		{"disabled suffix", "/r/demo/boards.md", false, "text/html; charset=utf-8", ""},
+		{"disabled query", "/r/demo/boards?format=md", false, "text/html; charset=utf-8", "# Boards"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.ServeMarkdownSource = tc.enabled
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedType, rr.Header().Get("Content-Type"))
+			if tc.expectedType != "text/html; charset=utf-8" {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, tc.expectedBody, rr.Body.String())
+				return
+			}
+
+			assert.Contains(t, rr.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestHTTPHandler_BuildVersion(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Hello"), nil
+		},
+	}
+
+	for _, version := range []string{"v1.2.3-abcdef0", ""} {
+		t.Run("version="+version, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Meta.BuildVersion = version
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/realm", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, version, rr.Header().Get("X-Gnoweb-Version"))
+			if version != "" {
+				assert.Contains(t, rr.Body.String(), "Powered by gnoweb "+version)
+			} else {
+				assert.NotContains(t, rr.Body.String(), "Powered by gnoweb")
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_PathsListPagination(t *testing.T) {
+	t.Parallel()
+
+	// Fake catalog, returned in no particular order
+	catalog := []string{
+		"example.com/r/demo/delta", "example.com/r/demo/alpha", "example.com/r/demo/echo",
+		"example.com/r/demo/charlie", "example.com/r/demo/bravo",
+	}
+	client := &stubClient{
+		listFilesFunc: func(ctx context.Context, path string) ([]string, error) {
+			return nil, gnoweb.ErrClientPackageNotFound
+		},
+		listPathsFunc: func(ctx context.Context, prefix string, limit int) ([]string, error) {
+			var paths []string
+			for _, p := range catalog {
+				if strings.HasPrefix(p, prefix) {
+					paths = append(paths, p)
+				}
+			}
+			return paths, nil
+		},
+		fileFunc: func(ctx context.Context, path, filename string) ([]byte, gnoweb.FileMeta, error) {
+			// delta predates gnomod.toml
+			heights := map[string]string{"alpha": "30", "bravo": "10", "charlie": "50", "echo": "20"}
+			height, ok := heights[gopath.Base(path)]
+			if !ok || filename != "gnomod.toml" {
+				return nil, gnoweb.FileMeta{}, gnoweb.ErrClientFileNotFound
+			}
+			return []byte("module = \"" + path + "\"\ngno = \"0.9\"\n\n[addpkg]\n  height = " + height + "\n"), gnoweb.FileMeta{}, nil
+		},
+	}
+
+	cases := []struct {
+		name     string
+		query    string
+		expected []string
+		prev     string
+		next     string
+	}{
+		{"default sort", "", []string{"alpha", "bravo"}, "", "page=2"},
+		{"second page", "?page=2", []string{"charlie", "delta"}, "page=1", "page=3"},
+		{"last page", "?page=3", []string{"echo"}, "page=2", ""},
+		{"page out of range", "?page=42", []string{"echo"}, "page=2", ""},
+		{"reverse sort", "?sort=-name", []string{"echo", "delta"}, "", "page=2&sort=-name"},
+		{"per page", "?per_page=4&page=2", []string{"echo"}, "page=1&per_page=4", ""},
+		{"unknown sort", "?sort=updated", []string{"alpha", "bravo"}, "", "page=2&sort=updated"},
+		{"latest deployed", "?sort=-deployed", []string{"charlie", "alpha"}, "", "page=2&sort=-deployed"},
+		{"oldest deployed", "?sort=deployed&page=3", []string{"charlie"}, "page=2&sort=deployed", ""},
+		{"prefix filter", "?prefix=c", []string{"charlie"}, "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Meta.Domain = "example.com"
+			cfg.ListingSort = gnoweb.ListingSortName
+			cfg.ListingPerPage = 2
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			// Returns the response and the listed paths, in order
+			list := func() (*httptest.ResponseRecorder, []string) {
+				req := httptest.NewRequest(http.MethodGet, "/r/demo/"+tc.query, nil)
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				body := rr.Body.String()
+				var listed []string
+				for _, name := range []string{"alpha", "bravo", "charlie", "delta", "echo"} {
+					if strings.Contains(body, "/r/demo/"+name+"\"") {
+						listed = append(listed, name)
+					}
+				}
+				slices.SortFunc(listed, func(a, b string) int {
+					return strings.Index(body, "/r/demo/"+a+"\"") - strings.Index(body, "/r/demo/"+b+"\"")
+				})
+				return rr, listed
+			}
+
+			rr, listed := list()
+			if strings.Contains(tc.query, "deployed") {
+				// Deploy heights are fetched in the background
+				require.Eventually(t, func() bool {
+					rr, listed = list()
+					return slices.Equal(tc.expected, listed)
+				}, 5*time.Second, 10*time.Millisecond)
+			}
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			body := rr.Body.String()
+			if strings.Contains(tc.query, "prefix=") {
+				assert.Contains(t, body, "1 Packages")
+			} else {
+				assert.Contains(t, body, "5 Packages")
+			}
+			assert.Equal(t, tc.expected, listed)
+
+			if tc.prev != "" {
+				assert.Contains(t, body, `href="/r/demo/?`+html.EscapeString(tc.prev)+`" rel="prev"`)
+			} else {
+				assert.NotContains(t, body, `rel="prev"`)
+			}
+			if tc.next != "" {
+				assert.Contains(t, body, `href="/r/demo/?`+html.EscapeString(tc.next)+`" rel="next"`)
+			} else {
+				assert.NotContains(t, body, `rel="next"`)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_OpenSearchLink(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Hello"), nil
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Meta.Domain = "gno.land"
+			cfg.Meta.OpenSearch = enabled
+			handler, err := gnoweb.NewHTTPHandler(
+				slog.New(slog.NewTextHandler(&testingLogger{t}, nil)),
+				cfg,
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/realm", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			link := `<link rel="search" type="application/opensearchdescription+xml" title="gno.land" href="` + gnoweb.OpenSearchPath + `" />`
+			if enabled {
+				assert.Contains(t, rr.Body.String(), link)
+			} else {
+				assert.NotContains(t, rr.Body.String(), `rel="search"`)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_JSONTreeViewer(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte(`{"name": "</script>", "items": [1, 2, 3]}`), nil
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			logger := slog.New(slog.NewTextHandler(&testingLogger{t}, nil))
+			rcfg := gnoweb.NewDefaultRenderConfig()
+			rcfg.JSONTreeViewer = enabled
+
+			cfg := newTestHandlerConfig(t, client)
+			cfg.Renderer = gnoweb.NewHTMLRenderer(logger, rcfg)
+			handler, err := gnoweb.NewHTTPHandler(logger, cfg)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/r/test/realm", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			body := rr.Body.String()
+
+			// The highlighted JSON is always rendered as the no-JS fallback
+			assert.Contains(t, body, `<pre class="chroma-chroma">`)
+			assert.NotContains(t, body, "</script>\"")
+
+			if enabled {
+				assert.Contains(t, body, `<div class="gno-json-tree" data-controller="json-tree" data-json-tree-collapse-value="20">`)
+				assert.Contains(t, body, `<script type="application/json" data-json-tree-target="data">{"name": "\u003c/script\u003e", "items": [1, 2, 3]}</script>`)
+				assert.Contains(t, body, `<div class="gno-json-tree-source" data-json-tree-target="source">`)
+			} else {
+				assert.NotContains(t, body, `data-controller="json-tree"`)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_HreflangAlternates(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Hello"), nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Meta.Locales = []string{"en", "fr", "ja"}
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/r/test/realm", "/fr/r/test/realm"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			body := rr.Body.String()
+			assert.Contains(t, body, `<link rel="alternate" hreflang="en" href="http://example.com/r/test/realm" />`)
+			assert.Contains(t, body, `<link rel="alternate" hreflang="fr" href="http://example.com/fr/r/test/realm" />`)
+			assert.Contains(t, body, `<link rel="alternate" hreflang="ja" href="http://example.com/ja/r/test/realm" />`)
+			assert.Contains(t, body, `<link rel="alternate" hreflang="x-default" href="http://example.com/r/test/realm" />`)
+		})
+	}
+
+	t.Run("no locales", func(t *testing.T) {
+		cfg := newTestHandlerConfig(t, client)
+		handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/r/test/realm", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.NotContains(t, rr.Body.String(), "hreflang")
+	})
+}
+
+func TestHTTPHandler_WarnPaths(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			calls++
+			return []byte("# Sensitive stuff"), nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.WarnPaths = []string{"/r/flagged"}
+	cfg.ServeMarkdownSource = true
+	cfg.TextRenderer = &rawRenderer{}
+	cfg.Feeds = true
+	cfg.Aliases = map[string]gnoweb.AliasTarget{"/flagged": {Value: "/r/flagged/realm", Kind: gnoweb.GnowebPath}}
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	get := func(path string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// First visit displays the interstitial, without fetching the content
+	rr := get("/r/flagged/realm")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Sensitive content")
+	assert.Contains(t, rr.Body.String(), `href="/r/flagged/realm$proceed"`)
+	assert.NotContains(t, rr.Body.String(), "Sensitive stuff")
+	assert.Equal(t, 0, calls)
+
+	// Other formats and aliases of the content are gated as well
+	for _, path := range []string{
+		"/r/flagged/realm.md",
+		"/r/flagged/realm?format=md",
+		"/r/flagged/realm?format=raw",
+		"/r/flagged/realm?format=txt",
+		"/r/flagged/realm?view=text",
+		"/r/flagged/realm:feed.xml",
+		"/r/flagged/realm$source&download",
+		"/flagged",
+	} {
+		rr := get(path)
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+		assert.Contains(t, rr.Body.String(), "Sensitive content", path)
+		assert.Equal(t, 0, calls, path)
+	}
+
+	// Proceeding renders the content, and remembers the choice
+	rr = get("/r/flagged/realm$proceed")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Sensitive stuff")
+	assert.Equal(t, 1, calls)
+
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "/r/flagged", cookies[0].Path)
+
+	rr = get("/r/flagged/realm:page", cookies...)
+	assert.Contains(t, rr.Body.String(), "Sensitive stuff")
+	assert.Equal(t, 2, calls)
+
+	// Other paths are not affected
+	rr = get("/r/other/realm")
+	assert.Contains(t, rr.Body.String(), "Sensitive stuff")
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func TestHTTPHandler_DeprecatedPaths(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Old realm"), nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Meta.DeprecatedPaths = map[string]string{
+		"/r/demo/boards":      "/r/demo/boards2",
+		"/r/demo/boards/v0":   "Boards v0 are frozen.",
+		"/r/demo/unrelated/x": "/r/demo/y",
+	}
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	get := func(path string) string {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	body := get("/r/demo/boards:thread/1")
+	assert.Contains(t, body, `class="b-deprecation"`)
+	assert.Contains(t, body, `superseded by <a href="/r/demo/boards2">/r/demo/boards2</a>`)
+	assert.Contains(t, body, "Old realm")
+
+	// The longest prefix wins
+	body = get("/r/demo/boards/v0")
+	assert.Contains(t, body, "Boards v0 are frozen.")
+	assert.NotContains(t, body, "superseded by")
+
+	body = get("/r/demo/other")
+	assert.NotContains(t, body, `class="b-deprecation"`)
+	assert.Contains(t, body, "Old realm")
+}
+
+func TestHTTPHandler_HomeGroups(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Home"), nil
+		},
+		listPathsFunc: func(ctx context.Context, prefix string, limit int) ([]string, error) {
+			return []string{"/r/demo/boards", "/r/gnoland/home", "/r/test/foo"}, nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Meta.Domain = "gno.land"
+	cfg.Aliases = map[string]gnoweb.AliasTarget{"/": {Value: "/r/gnoland/home", Kind: gnoweb.GnowebPath}}
+	cfg.HomeGroups = []string{"/r/gnoland", "/r/demo"}
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	get := func(path string) string {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	body := get("/")
+	require.Contains(t, body, `class="b-realm-groups"`)
+	gnoland := strings.Index(body, `<h2>/r/gnoland <span`)
+	demo := strings.Index(body, `<h2>/r/demo <span`)
+	assert.True(t, gnoland >= 0 && demo > gnoland, "groups should be listed in order")
+	assert.Contains(t, body, `<a href="/r/demo/boards">/r/demo/boards</a>`)
+	assert.NotContains(t, body, "/r/test/foo", "realms outside of groups should be hidden")
+
+	// Only the home page lists realms
+	assert.NotContains(t, get("/r/gnoland/home"), `class="b-realm-groups"`)
+}
+
+func TestHTTPHandler_Preconnect(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			if path == "/r/test/graph" {
+				return []byte("```dot\ndigraph { a -> b }\n```"), nil
+			}
+			return []byte("# No graph"), nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(&testingLogger{t}, nil))
+	rcfg := gnoweb.NewDefaultRenderConfig()
+	rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions, goldmark.WithExtensions(md.NewDotExtension()))
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Renderer = gnoweb.NewHTMLRenderer(logger, rcfg)
+	cfg.Meta.Preconnect = true
+	cfg.Meta.CDNOrigins = []string{"https://cdn.jsdelivr.net", "https://cdn.example.com"}
+	handler, err := gnoweb.NewHTTPHandler(logger, cfg)
+	require.NoError(t, err)
+
+	get := func(path string) string {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	body := get("/r/test/graph")
+	assert.Contains(t, body, `<link rel="preconnect" href="https://cdn.jsdelivr.net" />`)
+	assert.Contains(t, body, `<link rel="dns-prefetch" href="https://cdn.jsdelivr.net" />`)
+	assert.NotContains(t, body, `href="https://cdn.example.com"`)
+
+	// Pages using no CDN get no hints
+	assert.NotContains(t, get("/r/test/plain"), `rel="preconnect"`)
+}
+
+func TestHTTPHandler_ServeStaleOnOutage(t *testing.T) {
+	t.Parallel()
+
+	var outage atomic.Bool
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			if outage.Load() {
+				return nil, gnoweb.ErrClientUnavailable
+			}
+			return []byte("# Cached realm"), nil
+		},
+	}
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.ServeStaleOnOutage = true
+	handler, err := gnoweb.NewHTTPHandler(slog.New(slog.NewTextHandler(&testingLogger{t}, nil)), cfg)
+	require.NoError(t, err)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := get("/r/test/cached")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), `class="b-stale"`)
+
+	outage.Store(true)
+
+	// The cached path is served stale, with a banner
+	rr = get("/r/test/cached")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `class="b-stale"`)
+	assert.Contains(t, rr.Body.String(), "Cached realm")
+
+	// Uncached paths show the unavailable page
+	rr = get("/r/test/uncached")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.NotContains(t, rr.Body.String(), `class="b-stale"`)
+	assert.Contains(t, rr.Body.String(), gnoweb.ErrClientUnavailable.Error())
+}
+
+func TestHTTPHandler_TextMode(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			return []byte("# Title\n\n" +
+				"![a gopher](https://example.com/gopher.png)\n\n" +
+				"<script>alert(1)</script>\n\n" +
+				"<style>body { color: red }</style>\n\n" +
+				"```dot\ndigraph { a -> b }\n```\n"), nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(&testingLogger{t}, nil))
+	rcfg := gnoweb.NewDefaultRenderConfig()
+	rcfg.GoldmarkOptions = append(rcfg.GoldmarkOptions,
+		goldmark.WithRendererOptions(mdhtml.WithUnsafe()), // raw HTML is dropped anyway
+		goldmark.WithExtensions(md.NewDotExtension(), md.NewTextModeExtension()),
+	)
+
+	cfg := newTestHandlerConfig(t, client)
+	cfg.TextRenderer = gnoweb.NewHTMLRenderer(logger, rcfg)
+	handler, err := gnoweb.NewHTTPHandler(logger, cfg)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/page?view=text", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "default-src 'none'", rr.Header().Get("Content-Security-Policy"))
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "<h1")
+	assert.NotContains(t, body, "<script")
+	assert.NotContains(t, body, "<style")
+	assert.NotContains(t, body, `rel="stylesheet"`)
+
+	// Images are replaced by their alt text
+	assert.NotContains(t, body, "<img")
+	assert.Contains(t, body, "[Image: a gopher]")
+
+	// Widgets are replaced by a description
+	assert.NotContains(t, body, `data-controller`)
+	assert.Contains(t, body, "<figcaption>Diagram, described by the following graph source:</figcaption>")
+	assert.Contains(t, body, "digraph { a -&gt; b }")
+
+	// Regular views are unaffected
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/page", nil))
+	assert.Contains(t, rr.Body.String(), `rel="stylesheet"`)
+}
+
+func TestHTTPHandler_Frontmatter(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		realmFunc: func(ctx context.Context, path, args string) ([]byte, error) {
+			if path == "/r/test/plain" {
+				return []byte("# Plain"), nil
+			}
+			return []byte("---\n" +
+				"title: Gno <b>Boards</b>\n" +
+				"description: Discussion boards for gnomes\n" +
+				"image: https://example.com/boards.png\n" +
+				"---\n" +
+				"# Boards\n"), nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(&testingLogger{t}, nil))
+	cfg := newTestHandlerConfig(t, client)
+	cfg.Renderer = gnoweb.NewHTMLRenderer(logger, gnoweb.NewDefaultRenderConfig())
+	cfg.Meta.Domain = "gno.land"
+	handler, err := gnoweb.NewHTTPHandler(logger, cfg)
+	require.NoError(t, err)
+
+	get := func(path string) string {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	body := get("/r/test/boards")
+	assert.Contains(t, body, "<title>Gno Boards - gno.land</title>")
+	assert.Contains(t, body, `<meta name="description" content="Discussion boards for gnomes" />`)
+	assert.Contains(t, body, `<meta property="og:title" content="Gno Boards - gno.land" />`)
+	assert.Contains(t, body, `<meta property="og:image" content="https://example.com/boards.png" />`)
+	assert.NotContains(t, body, "title: Gno")
+
+	// Pages without frontmatter keep the default title
+	assert.Contains(t, get("/r/test/plain"), "<title>gno.land - /r/test/plain</title>")
+}
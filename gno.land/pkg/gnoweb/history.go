@@ -0,0 +1,62 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strconv"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// HeightQuery is the query parameter pinning the render of a realm to a
+// past block height.
+const HeightQuery = "height"
+
+var errInvalidHeight = errors.New("invalid height")
+
+type heightKey struct{}
+
+// withQueryHeight returns a context whose node queries are made at the given
+// block height.
+func withQueryHeight(ctx context.Context, height int64) context.Context {
+	return context.WithValue(ctx, heightKey{}, height)
+}
+
+// queryHeightFromContext returns the block height node queries are pinned
+// to, or zero for the latest one.
+func queryHeightFromContext(ctx context.Context) int64 {
+	height, _ := ctx.Value(heightKey{}).(int64)
+	return height
+}
+
+// parseHeightQuery returns the block height requested by the `height` query
+// of the URL, if any, along with the URL without it. The height must be a
+// positive integer.
+func parseHeightQuery(gnourl *weburl.GnoURL) (int64, *weburl.GnoURL, error) {
+	if !gnourl.Query.Has(HeightQuery) {
+		return 0, gnourl, nil
+	}
+
+	height, err := parseHeight(gnourl.Query.Get(HeightQuery))
+	if err != nil || height == 0 {
+		return 0, gnourl, errInvalidHeight
+	}
+
+	u := *gnourl
+	u.Query = maps.Clone(gnourl.Query)
+	u.Query.Del(HeightQuery)
+	return height, &u, nil
+}
+
+// parseHeight parses a positive block height, or zero if empty.
+func parseHeight(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	height, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || height <= 0 {
+		return 0, errInvalidHeight
+	}
+	return height, nil
+}
@@ -0,0 +1,99 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyClient is a catalogClient rendering realms along with the queried
+// block height and render arguments.
+type historyClient struct {
+	catalogClient
+}
+
+func (c *historyClient) Realm(ctx context.Context, path, args string) ([]byte, error) {
+	if path != "/r/demo/boards" {
+		return nil, ErrClientRenderNotDeclared
+	}
+	height := queryHeightFromContext(ctx)
+	return []byte("# Boards\n\nheight " + strconv.FormatInt(height, 10) + ", args `" + args + "`\n"), nil
+}
+
+func TestHTTPHandler_HistoricalRender(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(enabled bool) *HTTPHandler {
+		h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+			ClientAdapter:      &historyClient{},
+			Renderer:           newTestRenderer(),
+			Aliases:            map[string]AliasTarget{},
+			Meta:               StaticMetadata{Domain: "gno.land"},
+			ServeStaleOnOutage: true,
+			HistoricalRender:   enabled,
+		})
+		require.NoError(t, err)
+		return h
+	}
+
+	get := func(h *HTTPHandler, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	h := newHandler(true)
+
+	rr := get(h, "/r/demo/boards:board?height=42&page=2")
+	require.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.Contains(t, body, "height 42, args <code>board?page=2</code>")
+	assert.Contains(t, body, `class="b-history"`)
+	assert.Contains(t, body, "as of block 42")
+	assert.Contains(t, body, `href="/r/demo/boards:board?page=2"`)
+
+	// Historical renders are not kept for outages
+	_, ok := h.stale.Get("/r/demo/boards", "board?page=2")
+	assert.False(t, ok)
+
+	rr = get(h, "/r/demo/boards")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "height 0")
+	assert.NotContains(t, rr.Body.String(), `class="b-history"`)
+
+	for _, height := range []string{"0", "-1", "latest", ""} {
+		rr = get(h, "/r/demo/boards?height="+height)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, height)
+	}
+
+	// Disabled, the query is passed to the realm
+	rr = get(newHandler(false), "/r/demo/boards?height=42")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "height 0, args <code>?height=42</code>")
+}
+
+func TestParseHeightQuery(t *testing.T) {
+	t.Parallel()
+
+	gnourl, err := weburl.Parse("/r/demo/boards:board?height=7&a=b")
+	require.NoError(t, err)
+
+	height, u, err := parseHeightQuery(gnourl)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, height)
+	assert.Equal(t, "/r/demo/boards:board?a=b", u.EncodeURL())
+	assert.True(t, gnourl.Query.Has(HeightQuery), "the original URL is left untouched")
+
+	height, u, err = parseHeightQuery(u)
+	require.NoError(t, err)
+	assert.Zero(t, height)
+	assert.Equal(t, "/r/demo/boards:board?a=b", u.EncodeURL())
+}
@@ -0,0 +1,99 @@
+package gnoweb
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+)
+
+const (
+	// HomeGroupOther is the name of the group of realms outside of the
+	// featured namespaces.
+	HomeGroupOther = "other"
+
+	// homeGroupsTTL is the duration the realms list of the home page is
+	// cached for.
+	homeGroupsTTL = time.Minute
+	// homeGroupsMaxRealms bounds the number of listed realms.
+	homeGroupsMaxRealms = 1_000
+)
+
+// groupRealms groups the given realm paths by the longest matching
+// namespace, in the namespaces order. Duplicated paths are listed once, and
+// realms outside of any namespace are grouped last under HomeGroupOther, if
+// other is true.
+func groupRealms(paths, namespaces []string, other bool) []components.RealmGroup {
+	paths = slices.Clone(paths)
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	groups := make([]components.RealmGroup, len(namespaces))
+	for i, ns := range namespaces {
+		groups[i].Name = strings.TrimSuffix(ns, "/")
+	}
+
+	var others []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		match := -1
+		for i, group := range groups {
+			if strings.HasPrefix(path, group.Name+"/") &&
+				(match < 0 || len(group.Name) > len(groups[match].Name)) {
+				match = i
+			}
+		}
+
+		if match < 0 {
+			others = append(others, path)
+			continue
+		}
+		groups[match].Realms = append(groups[match].Realms, path)
+	}
+
+	if other && len(others) > 0 {
+		groups = append(groups, components.RealmGroup{Name: HomeGroupOther, Realms: others})
+	}
+
+	// Hide empty groups
+	return slices.DeleteFunc(groups, func(g components.RealmGroup) bool {
+		return len(g.Realms) == 0
+	})
+}
+
+// homeGroups caches the grouped realms displayed on the home page.
+type homeGroups struct {
+	client     ClientAdapter
+	domain     string
+	namespaces []string
+	other      bool
+
+	mu      sync.Mutex
+	groups  []components.RealmGroup
+	builtAt time.Time
+}
+
+// Get returns the grouped realms, listing them again once the cache is
+// stale. On failure, the last grouped realms are returned.
+func (hg *homeGroups) Get(ctx context.Context) ([]components.RealmGroup, error) {
+	hg.mu.Lock()
+	defer hg.mu.Unlock()
+
+	if hg.groups != nil && time.Since(hg.builtAt) < homeGroupsTTL {
+		return hg.groups, nil
+	}
+
+	paths, err := hg.client.ListPaths(ctx, hg.domain+"/r/", homeGroupsMaxRealms)
+	if err != nil {
+		return hg.groups, err
+	}
+
+	hg.groups, hg.builtAt = groupRealms(paths, hg.namespaces, hg.other), time.Now()
+	return hg.groups, nil
+}
@@ -0,0 +1,88 @@
+package gnoweb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRealms(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{
+		"/r/demo/boards", "/r/gnoland/home", "/r/demo/blog", "/r/sys/users",
+		"/r/demo/games/chess", "/r/demo/boards", "/r/test/foo", "",
+	}
+
+	cases := []struct {
+		name       string
+		namespaces []string
+		other      bool
+		want       []components.RealmGroup
+	}{
+		{
+			name:       "ordered",
+			namespaces: []string{"/r/gnoland", "/r/demo"},
+			want: []components.RealmGroup{
+				{Name: "/r/gnoland", Realms: []string{"/r/gnoland/home"}},
+				{Name: "/r/demo", Realms: []string{"/r/demo/blog", "/r/demo/boards", "/r/demo/games/chess"}},
+			},
+		},
+		{
+			name:       "other",
+			namespaces: []string{"/r/demo/"},
+			other:      true,
+			want: []components.RealmGroup{
+				{Name: "/r/demo", Realms: []string{"/r/demo/blog", "/r/demo/boards", "/r/demo/games/chess"}},
+				{Name: HomeGroupOther, Realms: []string{"/r/gnoland/home", "/r/sys/users", "/r/test/foo"}},
+			},
+		},
+		{
+			name:       "longest namespace",
+			namespaces: []string{"/r/demo", "/r/demo/games"},
+			want: []components.RealmGroup{
+				{Name: "/r/demo", Realms: []string{"/r/demo/blog", "/r/demo/boards"}},
+				{Name: "/r/demo/games", Realms: []string{"/r/demo/games/chess"}},
+			},
+		},
+		{
+			name:       "empty groups hidden",
+			namespaces: []string{"/r/none", "/r/sys", "/r/dem"},
+			want: []components.RealmGroup{
+				{Name: "/r/sys", Realms: []string{"/r/sys/users"}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, groupRealms(paths, tc.namespaces, tc.other))
+		})
+	}
+}
+
+func TestHomeGroupsCache(t *testing.T) {
+	t.Parallel()
+
+	client := &packagesClient{packages: []string{"/r/demo/boards", "/r/gnoland/home", "/p/demo/avl"}}
+	hg := &homeGroups{
+		client:     client,
+		domain:     "gno.land",
+		namespaces: []string{"/r/gnoland", "/r/demo"},
+	}
+
+	groups, err := hg.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []components.RealmGroup{
+		{Name: "/r/gnoland", Realms: []string{"/r/gnoland/home"}},
+		{Name: "/r/demo", Realms: []string{"/r/demo/boards"}},
+	}, groups)
+
+	_, err = hg.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.calls, "realms should be listed once")
+}
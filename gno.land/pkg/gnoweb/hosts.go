@@ -0,0 +1,64 @@
+package gnoweb
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// normalizeHost returns the lowercase host of the given `host[:port]`,
+// without port nor trailing dot.
+func normalizeHost(hostport string) string {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// HostValidationMiddleware rejects requests whose `Host` header does not
+// match any of the allowed hosts with a 400 response, so that absolute URLs
+// are only ever generated for trusted hosts. Allowed hosts are matched
+// regardless of case and port, and a `*.` prefix matches any subdomain.
+// The `Host` of accepted requests is normalized to its lowercase form.
+func HostValidationMiddleware(next http.Handler, allowed []string) http.Handler {
+	exact := make(map[string]bool, len(allowed))
+	var suffixes []string
+	for _, host := range allowed {
+		host = normalizeHost(host)
+		if suffix, ok := strings.CutPrefix(host, "*"); ok {
+			suffixes = append(suffixes, suffix)
+			continue
+		}
+		exact[host] = true
+	}
+
+	isAllowed := func(host string) bool {
+		if exact[host] {
+			return true
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := normalizeHost(r.Host)
+		if host == "" || !isAllowed(host) {
+			http.Error(w, "invalid host", http.StatusBadRequest)
+			return
+		}
+
+		_, port, err := net.SplitHostPort(r.Host)
+		if err == nil && port != "" {
+			r.Host = net.JoinHostPort(host, port)
+		} else {
+			r.Host = host
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
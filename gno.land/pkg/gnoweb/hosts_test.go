@@ -0,0 +1,50 @@
+package gnoweb_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostValidationMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var gotHost string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := gnoweb.HostValidationMiddleware(next, []string{"gno.land", "*.gno.land", "LOCALHOST"})
+
+	cases := []struct {
+		host     string
+		wantCode int
+		wantHost string
+	}{
+		{host: "gno.land", wantCode: http.StatusOK, wantHost: "gno.land"},
+		{host: "GNO.Land.", wantCode: http.StatusOK, wantHost: "gno.land"},
+		{host: "gno.land:8888", wantCode: http.StatusOK, wantHost: "gno.land:8888"},
+		{host: "test.gno.land", wantCode: http.StatusOK, wantHost: "test.gno.land"},
+		{host: "localhost:8888", wantCode: http.StatusOK, wantHost: "localhost:8888"},
+		{host: "evil.com", wantCode: http.StatusBadRequest},
+		{host: "gno.land.evil.com", wantCode: http.StatusBadRequest},
+		{host: "evilgno.land", wantCode: http.StatusBadRequest},
+		{host: "", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.host, func(t *testing.T) {
+			gotHost = ""
+			req := httptest.NewRequest(http.MethodGet, "/r/demo/boards", nil)
+			req.Host = tc.host
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.wantCode, rr.Code)
+			assert.Equal(t, tc.wantHost, gotHost)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package gnoweb
+
+import (
+	"net/http"
+)
+
+// HumansTextPath is the path of the humans.txt document.
+const HumansTextPath = "/humans.txt"
+
+// handlerHumansText serves the given humans.txt content, crediting the
+// people behind the site. If the content is empty, it responds with a 404.
+func handlerHumansText(text string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if text == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(text))
+	})
+}
@@ -0,0 +1,36 @@
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerHumansText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("configured", func(t *testing.T) {
+		t.Parallel()
+
+		text := "/* TEAM */\nContributor: gnome\n"
+		req := httptest.NewRequest(http.MethodGet, HumansTextPath, nil)
+		rr := httptest.NewRecorder()
+		handlerHumansText(text).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, text, rr.Body.String())
+	})
+
+	t.Run("unconfigured", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, HumansTextPath, nil)
+		rr := httptest.NewRecorder()
+		handlerHumansText("").ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,148 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+)
+
+// localeKey is the context key of the negotiated locale of a request.
+type localeKey struct{}
+
+// requestLocale returns the locale negotiated for the request, if any.
+func requestLocale(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// LocaleMiddleware negotiates the locale of the page chrome among the given
+// locales, from the `Accept-Language` header of requests. The first locale
+// is the default one, used if none of the others is accepted.
+func LocaleMiddleware(next http.Handler, locales []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := negotiateLocale(r.Header.Get("Accept-Language"), locales)
+
+		if len(locales) > 1 {
+			w.Header().Add("Vary", "Accept-Language")
+		}
+		w.Header().Set("Content-Language", locale)
+
+		ctx := context.WithValue(r.Context(), localeKey{}, locale)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// negotiateLocale returns the locale preferred by the given `Accept-Language`
+// header, matching either a locale itself or its primary language, such as
+// "fr" for "fr-CA". It defaults to the first locale.
+func negotiateLocale(accept string, locales []string) string {
+	type langRange struct {
+		tag string
+		q   float64
+	}
+
+	var ranges []langRange
+	for _, part := range strings.Split(accept, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if _, v, ok := strings.Cut(params, "q="); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, langRange{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b langRange) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+
+	primary := func(tag string) string {
+		lang, _, _ := strings.Cut(tag, "-")
+		return lang
+	}
+	for _, r := range ranges {
+		for _, locale := range locales {
+			if strings.EqualFold(r.tag, locale) {
+				return locale
+			}
+		}
+		for _, locale := range locales {
+			if strings.EqualFold(primary(r.tag), primary(locale)) {
+				return locale
+			}
+		}
+	}
+
+	if len(locales) == 0 {
+		return components.DefaultLocale
+	}
+	return locales[0]
+}
+
+// LoadCatalogDir registers the message catalogs of the given directory,
+// named after their locale, such as `fr.json`, each holding a JSON object
+// mapping English messages to their translation.
+func LoadCatalogDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("unable to list catalogs: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("unable to read catalog: %w", err)
+		}
+
+		var cat components.Catalog
+		if err := json.Unmarshal(data, &cat); err != nil {
+			return fmt.Errorf("unable to parse catalog %q: %w", filepath.Base(file), err)
+		}
+		components.RegisterCatalog(strings.TrimSuffix(filepath.Base(file), ".json"), cat)
+	}
+
+	return nil
+}
+
+// uiLocales returns the locales of the page chrome, the default one first,
+// checking they all have a message catalog.
+func uiLocales(defaultLocale string, others []string) ([]string, error) {
+	if defaultLocale == "" {
+		defaultLocale = components.DefaultLocale
+	}
+
+	available := components.Catalogs()
+	var locales []string
+	for _, locale := range append([]string{defaultLocale}, others...) {
+		if slices.Contains(locales, locale) {
+			continue
+		}
+		if !slices.Contains(available, locale) {
+			return nil, fmt.Errorf("no message catalog for locale %q", locale)
+		}
+		locales = append(locales, locale)
+	}
+
+	return locales, nil
+}
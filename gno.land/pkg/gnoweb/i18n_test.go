@@ -0,0 +1,71 @@
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	t.Parallel()
+
+	locales := []string{"en", "fr", "pt-BR"}
+	for accept, want := range map[string]string{
+		"":                       "en",
+		"*":                      "en",
+		"de":                     "en",
+		"fr":                     "fr",
+		"fr-CA,fr;q=0.9":         "fr",
+		"de,fr;q=0.5,en;q=0.8":   "en",
+		"en;q=0.1, FR":           "fr",
+		"pt":                     "pt-BR",
+		"pt-BR":                  "pt-BR",
+		"fr;q=0,de":              "en",
+		"de;q=0.9, fr;q=invalid": "fr",
+	} {
+		assert.Equal(t, want, negotiateLocale(accept, locales), accept)
+	}
+	assert.Equal(t, components.DefaultLocale, negotiateLocale("fr", nil))
+}
+
+func TestLocaleMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(requestLocale(r.Context())))
+	}), []string{"fr", "en"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "fr", rr.Body.String())
+	assert.Equal(t, "fr", rr.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "en", rr.Body.String())
+}
+
+func TestUILocales(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "x-test.json"), []byte(`{"Source": "Quelle"}`), 0o644))
+	require.NoError(t, LoadCatalogDir(dir))
+
+	locales, err := uiLocales("", []string{"fr", "x-test", "fr"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "fr", "x-test"}, locales)
+
+	_, err = uiLocales("de", nil)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`[`), 0o644))
+	assert.Error(t, LoadCatalogDir(dir))
+}
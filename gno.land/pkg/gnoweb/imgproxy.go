@@ -0,0 +1,331 @@
+package gnoweb
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// ImageProxyPath is the path of the image proxy.
+const ImageProxyPath = "/_proxy/img"
+
+const (
+	// DefaultImageProxyMaxSize is the default maximum size of proxied
+	// images.
+	DefaultImageProxyMaxSize = 5 << 20
+	// imageProxyCacheSize bounds the total size of cached images, the least
+	// recently served ones being evicted first.
+	imageProxyCacheSize = 64 << 20
+	// imageProxyCacheEntries bounds the number of cached images.
+	imageProxyCacheEntries = 4096
+	// imageProxyTTL is the duration images are cached for.
+	imageProxyTTL = time.Hour
+	// imageProxyTimeout bounds the duration of an image fetch.
+	imageProxyTimeout = 10 * time.Second
+	// imageProxyMaxRedirects bounds the number of redirects followed.
+	imageProxyMaxRedirects = 3
+)
+
+// imageProxyTypes lists the content types served by the proxy. SVG is not
+// proxied, as it would be served from the gnoweb origin.
+var imageProxyTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// imageProxyDeniedPrefixes are the address ranges the proxy never connects
+// to: private, shared, reserved and documentation ranges, as well as the
+// IPv6 ranges embedding IPv4 addresses, which could reach the former.
+var imageProxyDeniedPrefixes = func() []netip.Prefix {
+	ranges := []string{
+		// IPv4
+		"0.0.0.0/8",          // "this" network
+		"10.0.0.0/8",         // private
+		"100.64.0.0/10",      // shared address space (CGNAT)
+		"127.0.0.0/8",        // loopback
+		"169.254.0.0/16",     // link local
+		"172.16.0.0/12",      // private
+		"192.0.0.0/24",       // IETF protocol assignments
+		"192.0.2.0/24",       // documentation
+		"192.88.99.0/24",     // 6to4 relay anycast
+		"192.168.0.0/16",     // private
+		"198.18.0.0/15",      // benchmarking
+		"198.51.100.0/24",    // documentation
+		"203.0.113.0/24",     // documentation
+		"224.0.0.0/4",        // multicast
+		"240.0.0.0/4",        // reserved
+		"255.255.255.255/32", // broadcast
+
+		// IPv6
+		"::/128",         // unspecified
+		"::1/128",        // loopback
+		"::/96",          // IPv4-compatible
+		"::ffff:0:0/96",  // IPv4-mapped
+		"64:ff9b::/96",   // NAT64
+		"64:ff9b:1::/48", // local NAT64
+		"100::/64",       // discard
+		"2001::/23",      // IETF protocol assignments, including Teredo
+		"2001:db8::/32",  // documentation
+		"2002::/16",      // 6to4
+		"fc00::/7",       // unique local
+		"fe80::/10",      // link local
+		"fec0::/10",      // site local
+		"ff00::/8",       // multicast
+	}
+	prefixes := make([]netip.Prefix, len(ranges))
+	for i, r := range ranges {
+		prefixes[i] = netip.MustParsePrefix(r)
+	}
+	return prefixes
+}()
+
+var (
+	errImageProxyForbidden = errors.New("forbidden image address")
+	errImageProxyTooLarge  = errors.New("image too large")
+	errImageProxyType      = errors.New("unsupported image type")
+)
+
+type proxiedImage struct {
+	contentType string
+	data        []byte
+	fetched     time.Time
+}
+
+// imageProxy fetches remote images on behalf of readers, caching them for a
+// while.
+type imageProxy struct {
+	logger  *slog.Logger
+	client  *http.Client
+	key     []byte
+	maxSize int64
+
+	mu    sync.Mutex
+	cache *lru.Cache[string, *proxiedImage]
+	size  int64 // of the cached images
+}
+
+// handlerImageProxy serves the image of the `url` query parameter, which
+// must be a public http(s) image of at most maxSize bytes, signed with key
+// by the `sig` query parameter.
+func handlerImageProxy(logger *slog.Logger, key []byte, maxSize int64) http.Handler {
+	return newImageProxy(logger, newImageProxyClient(), key, maxSize)
+}
+
+func newImageProxy(logger *slog.Logger, client *http.Client, key []byte, maxSize int64) *imageProxy {
+	if maxSize <= 0 {
+		maxSize = DefaultImageProxyMaxSize
+	}
+	p := &imageProxy{
+		logger:  logger,
+		client:  client,
+		key:     key,
+		maxSize: maxSize,
+	}
+	// Only fails with a non-positive size
+	p.cache, _ = lru.NewWithEvict(imageProxyCacheEntries, func(_ string, img *proxiedImage) {
+		p.size -= int64(len(img.data)) // p.mu is held by the caller
+	})
+	return p
+}
+
+// signImageProxyURL returns the signature of the given image URL.
+func signImageProxyURL(key []byte, target string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(target))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newImageProxyClient returns a client which only connects to public
+// addresses, so the proxy cannot be used to reach internal services.
+func newImageProxyClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: imageProxyTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if addr, err := netip.ParseAddr(host); err != nil || !isPublicIP(addr) {
+				return fmt.Errorf("%w: %s", errImageProxyForbidden, host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   imageProxyTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= imageProxyMaxRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: %s", errImageProxyForbidden, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// isPublicIP reports whether the given address is publicly routable.
+func isPublicIP(addr netip.Addr) bool {
+	addr = addr.WithZone("").Unmap()
+	for _, prefix := range imageProxyDeniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return addr.IsValid()
+}
+
+// parseImageProxyURL returns the image URL of the given proxy query, if it
+// is an absolute http(s) URL.
+func parseImageProxyURL(raw string) (*url.URL, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return nil, false
+	}
+	return u, true
+}
+
+func (p *imageProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	target, ok := parseImageProxyURL(query.Get("url"))
+	if !ok {
+		http.Error(w, "invalid image url", http.StatusBadRequest)
+		return
+	}
+
+	// Only serve the images of rendered pages
+	sig := signImageProxyURL(p.key, query.Get("url"))
+	if !hmac.Equal([]byte(sig), []byte(query.Get("sig"))) {
+		http.Error(w, "invalid image signature", http.StatusForbidden)
+		return
+	}
+
+	img, err := p.get(r.Context(), target.String())
+	switch {
+	case err == nil:
+	case errors.Is(err, errImageProxyTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, errImageProxyType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	default:
+		p.logger.Debug("unable to proxy image", "url", target.String(), "error", err)
+		http.Error(w, "unable to fetch image", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", img.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(img.data)))
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(imageProxyTTL.Seconds())))
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Method == http.MethodGet {
+		w.Write(img.data)
+	}
+}
+
+// get returns the image at the given URL, from the cache if fresh enough.
+func (p *imageProxy) get(ctx context.Context, target string) (*proxiedImage, error) {
+	p.mu.Lock()
+	img, ok := p.cache.Get(target)
+	p.mu.Unlock()
+	if ok && time.Since(img.fetched) < imageProxyTTL {
+		return img, nil
+	}
+
+	img, err := p.fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	p.store(target, img)
+	return img, nil
+}
+
+// store caches the image of the given URL, evicting the least recently
+// served images beyond imageProxyCacheSize.
+func (p *imageProxy) store(target string, img *proxiedImage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cache.Remove(target)
+	p.cache.Add(target, img)
+	p.size += int64(len(img.data))
+	for p.size > imageProxyCacheSize {
+		p.cache.RemoveOldest()
+	}
+}
+
+// fetch downloads the image at the given URL, checking its size and type.
+func (p *imageProxy) fetch(ctx context.Context, target string) (*proxiedImage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", "gnoweb-image-proxy")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", res.Status)
+	}
+	if res.ContentLength > p.maxSize {
+		return nil, errImageProxyTooLarge
+	}
+	if mt, _, err := mime.ParseMediaType(res.Header.Get("Content-Type")); err != nil || !imageProxyTypes[mt] {
+		return nil, errImageProxyType
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, p.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > p.maxSize {
+		return nil, errImageProxyTooLarge
+	}
+
+	// Serve the actual content type, whatever the origin declared
+	contentType := http.DetectContentType(data)
+	if !imageProxyTypes[contentType] {
+		return nil, errImageProxyType
+	}
+
+	return &proxiedImage{contentType: contentType, data: data, fetched: time.Now()}, nil
+}
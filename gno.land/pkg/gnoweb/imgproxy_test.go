@@ -0,0 +1,156 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 32)...)
+
+func TestImageProxy(t *testing.T) {
+	t.Parallel()
+
+	var fetches int
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		switch r.URL.Path {
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(testPNG)
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(append(testPNG, make([]byte, 1024)...))
+		case "/fake.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<script>alert(1)</script>"))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer origin.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	key := []byte("secret")
+	proxy := newImageProxy(logger, origin.Client(), key, 512)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		query := "?url=" + url.QueryEscape(target) + "&sig=" + url.QueryEscape(signImageProxyURL(key, target))
+		proxy.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ImageProxyPath+query, nil))
+		return rr
+	}
+
+	rr := get(origin.URL + "/logo.png")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, testPNG, rr.Body.Bytes())
+
+	// Served from the cache
+	get(origin.URL + "/logo.png")
+	assert.Equal(t, 1, fetches)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, get(origin.URL+"/huge.png").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, get(origin.URL+"/fake.png").Code)
+	assert.Equal(t, http.StatusUnsupportedMediaType, get(origin.URL+"/page.html").Code)
+	assert.Equal(t, http.StatusBadGateway, get(origin.URL+"/missing.png").Code)
+	assert.Equal(t, http.StatusBadRequest, get("file:///etc/passwd").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/public/logo.png").Code)
+
+	// Only signed URLs are served
+	for _, query := range []string{
+		"?url=" + url.QueryEscape(origin.URL+"/logo.png"),
+		"?url=" + url.QueryEscape(origin.URL+"/logo.png") + "&sig=" + signImageProxyURL([]byte("other"), origin.URL+"/logo.png"),
+		"?url=" + url.QueryEscape(origin.URL+"/huge.png") + "&sig=" + signImageProxyURL(key, origin.URL+"/logo.png"),
+	} {
+		rr := httptest.NewRecorder()
+		proxy.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ImageProxyPath+query, nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code, query)
+	}
+}
+
+func TestImageProxy_Cache(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	proxy := newImageProxy(logger, nil, nil, 0)
+	image := func(size int) *proxiedImage {
+		return &proxiedImage{contentType: "image/png", data: make([]byte, size), fetched: time.Now()}
+	}
+	const size = imageProxyCacheSize / 4
+	for _, target := range []string{"a", "b", "c", "d"} {
+		proxy.store(target, image(size))
+	}
+
+	// Serving an image keeps it cached
+	_, err := proxy.get(context.Background(), "a")
+	require.NoError(t, err)
+
+	// The least recently served image is evicted, not the whole cache
+	proxy.store("e", image(size))
+	assert.Equal(t, []string{"c", "d", "a", "e"}, proxy.cache.Keys())
+	assert.Equal(t, int64(imageProxyCacheSize), proxy.size)
+
+	// Replacing an image accounts for its previous size
+	proxy.store("e", image(1))
+	assert.Equal(t, int64(3*size+1), proxy.size)
+}
+
+func TestImageProxy_PrivateAddresses(t *testing.T) {
+	t.Parallel()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(testPNG)
+	}))
+	defer origin.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	key := []byte("secret")
+	handler := handlerImageProxy(logger, key, 0)
+
+	rr := httptest.NewRecorder()
+	query := "?url=" + url.QueryEscape(origin.URL) + "&sig=" + url.QueryEscape(signImageProxyURL(key, origin.URL))
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ImageProxyPath+query, nil))
+	assert.Equal(t, http.StatusBadGateway, rr.Code)
+
+	for ip, public := range map[string]bool{
+		"8.8.8.8":              true,
+		"2606:4700::1111":      true,
+		"127.0.0.1":            false,
+		"10.0.0.1":             false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false, // CGNAT
+		"0.1.2.3":              false,
+		"198.18.0.1":           false, // benchmarking
+		"::1":                  false,
+		"0.0.0.0":              false,
+		"::ffff:127.0.0.1":     false, // IPv4-mapped
+		"::ffff:8.8.8.8":       true,
+		"64:ff9b::a00:1":       false, // NAT64 of 10.0.0.1
+		"2002:a00:1::1":        false, // 6to4 of 10.0.0.1
+		"fd00::1":              false,
+		"fe80::1%eth0":         false,
+		"2001:0:4136:e378::1":  false, // Teredo
+		"2001:db8::1":          false,
+		"ff02::1":              false,
+		"2001:4860:4860::8888": true,
+	} {
+		assert.Equal(t, public, isPublicIP(netip.MustParseAddr(ip)), ip)
+	}
+}
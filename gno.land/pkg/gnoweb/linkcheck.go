@@ -0,0 +1,83 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// linkCheckTTL is the duration link check results are cached for.
+	linkCheckTTL = 30 * time.Second
+	// linkCheckTimeout bounds the duration of a single link check.
+	linkCheckTimeout = 2 * time.Second
+	// maxCheckedLinks bounds the number of cached link check results.
+	maxCheckedLinks = 1024
+)
+
+type linkCheck struct {
+	exists  bool
+	checked time.Time
+}
+
+// linkChecker checks whether the packages and realms targeted by internal
+// links exist, caching the results for a short while.
+type linkChecker struct {
+	client ClientAdapter
+	domain string
+
+	mu    sync.Mutex
+	cache map[string]linkCheck
+}
+
+func newLinkChecker(client ClientAdapter, domain string) *linkChecker {
+	return &linkChecker{
+		client: client,
+		domain: domain,
+		cache:  make(map[string]linkCheck),
+	}
+}
+
+// Exists reports whether the package or realm at path exists, or has sub
+// paths. Paths which cannot be checked, such as when the node is
+// unavailable, are considered as existing.
+func (c *linkChecker) Exists(path string) bool {
+	path = "/" + strings.Trim(path, "/")
+
+	c.mu.Lock()
+	check, ok := c.cache[path]
+	c.mu.Unlock()
+	if ok && time.Since(check.checked) < linkCheckTTL {
+		return check.exists
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), linkCheckTimeout)
+	defer cancel()
+
+	_, err := c.client.ListFiles(ctx, path)
+	switch {
+	case err == nil:
+		check.exists = true
+	case errors.Is(err, ErrClientPackageNotFound):
+		// Not a package, but it may still be a listable namespace
+		paths, err := c.client.ListPaths(ctx, c.domain+path+"/", 1)
+		if err != nil {
+			return true
+		}
+		check.exists = len(paths) > 0
+	default:
+		return true
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= maxCheckedLinks {
+		clear(c.cache)
+	}
+	check.checked = time.Now()
+	c.cache[path] = check
+	c.mu.Unlock()
+
+	return check.exists
+}
@@ -0,0 +1,54 @@
+package gnoweb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packagesClient is a client only knowing the given packages.
+type packagesClient struct {
+	catalogClient
+	packages []string
+	calls    int
+}
+
+func (c *packagesClient) ListFiles(ctx context.Context, path string) ([]string, error) {
+	c.calls++
+	for _, pkg := range c.packages {
+		if pkg == path {
+			return []string{"render.gno"}, nil
+		}
+	}
+	return nil, ErrClientPackageNotFound
+}
+
+func (c *packagesClient) ListPaths(ctx context.Context, prefix string, limit int) ([]string, error) {
+	c.calls++
+	var paths []string
+	for _, pkg := range c.packages {
+		if len(paths) < limit && strings.HasPrefix("gno.land"+pkg, prefix) {
+			paths = append(paths, pkg)
+		}
+	}
+	return paths, nil
+}
+
+func TestLinkChecker(t *testing.T) {
+	t.Parallel()
+
+	client := &packagesClient{packages: []string{"/r/demo/boards"}}
+	checker := newLinkChecker(client, "gno.land")
+
+	assert.True(t, checker.Exists("/r/demo/boards"))
+	assert.True(t, checker.Exists("/r/demo")) // namespace with packages
+	assert.False(t, checker.Exists("/r/demo/gone"))
+
+	// Results are cached
+	calls := client.calls
+	assert.True(t, checker.Exists("/r/demo/boards/"))
+	assert.False(t, checker.Exists("/r/demo/gone"))
+	assert.Equal(t, calls, client.calls)
+}
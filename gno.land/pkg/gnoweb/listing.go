@@ -0,0 +1,245 @@
+package gnoweb
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+)
+
+// ListingSort is the sort order of path listings.
+type ListingSort string
+
+const (
+	ListingSortName         ListingSort = "name"      // alphabetical order
+	ListingSortNameDesc     ListingSort = "-name"     // reverse alphabetical order
+	ListingSortDeployed     ListingSort = "deployed"  // oldest deployments first
+	ListingSortDeployedDesc ListingSort = "-deployed" // latest deployments first
+)
+
+// valid reports whether the sort order is known.
+func (s ListingSort) valid() bool {
+	switch s {
+	case ListingSortName, ListingSortNameDesc, ListingSortDeployed, ListingSortDeployedDesc:
+		return true
+	}
+	return false
+}
+
+const (
+	// DefaultListingPerPage is the default number of paths per listing page.
+	DefaultListingPerPage = 100
+	// maxListingPerPage bounds the `per_page` query parameter.
+	maxListingPerPage = 1_000
+	// maxListingPaths bounds the number of paths of a listing, as the node
+	// does.
+	maxListingPaths = 10_000
+	// deployHeightsWorkers bounds the concurrent queries of deploy heights.
+	deployHeightsWorkers = 8
+	// deployHeightsTimeout bounds the background queries of deploy heights.
+	deployHeightsTimeout = 5 * time.Minute
+)
+
+// listingPage sorts the given paths and returns the requested page, along
+// with its pagination, based on the `sort`, `page` and `per_page` query
+// parameters of the given URL.
+func (h *HTTPHandler) listingPage(gnourl *weburl.GnoURL, paths []string) ([]string, *components.Pagination) {
+	query := gnourl.Query
+
+	sort := h.listingSort(gnourl)
+	slices.Sort(paths)
+	switch sort {
+	case ListingSortNameDesc:
+		slices.Reverse(paths)
+	case ListingSortDeployed, ListingSortDeployedDesc:
+		heights := h.deployHeights.get(paths)
+		slices.SortStableFunc(paths, func(a, b string) int {
+			ha, hb := heights[a], heights[b]
+			if sort == ListingSortDeployedDesc {
+				ha, hb = hb, ha
+			}
+			return cmp.Compare(ha, hb)
+		})
+	}
+
+	perPage := h.ListingPerPage
+	if n, err := strconv.Atoi(query.Get("per_page")); err == nil && n > 0 {
+		perPage = min(n, maxListingPerPage)
+	}
+	if perPage <= 0 {
+		return paths, nil // no pagination
+	}
+
+	pages := max((len(paths)+perPage-1)/perPage, 1)
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	page = min(page, pages)
+
+	pagination := &components.Pagination{Page: page, Pages: pages}
+	if page > 1 {
+		pagination.PrevURL = listingURL(gnourl, "page", strconv.Itoa(page-1))
+	}
+	if page < pages {
+		pagination.NextURL = listingURL(gnourl, "page", strconv.Itoa(page+1))
+	}
+
+	start := (page - 1) * perPage
+	end := min(start+perPage, len(paths))
+	return paths[start:end], pagination
+}
+
+// listingSort returns the sort order requested with the `sort` query
+// parameter, or the default one.
+func (h *HTTPHandler) listingSort(gnourl *weburl.GnoURL) ListingSort {
+	if sort := ListingSort(gnourl.Query.Get("sort")); sort.valid() {
+		return sort
+	}
+	return h.ListingSort
+}
+
+// listingSorts returns the links sorting the listing of the given URL, back
+// to its first page.
+func (h *HTTPHandler) listingSorts(gnourl *weburl.GnoURL) []components.ListingSortLink {
+	current := h.listingSort(gnourl)
+	link := func(label string, sort, desc ListingSort) components.ListingSortLink {
+		l := components.ListingSortLink{Label: label}
+		next := sort
+		switch current {
+		case sort:
+			next = desc // toggle the direction of the current order
+			l.Active, l.Desc = true, strings.HasPrefix(string(sort), "-")
+		case desc:
+			l.Active, l.Desc = true, strings.HasPrefix(string(desc), "-")
+		}
+		l.URL = listingURL(gnourl, "sort", string(next), "page", "")
+		return l
+	}
+	return []components.ListingSortLink{
+		link("Name", ListingSortName, ListingSortNameDesc),
+		link("Deployed", ListingSortDeployedDesc, ListingSortDeployed),
+	}
+}
+
+// listingURL returns the given URL with the given query parameters set, or
+// removed if empty.
+func listingURL(gnourl *weburl.GnoURL, kvs ...string) string {
+	u := *gnourl
+	u.Query = maps.Clone(gnourl.Query)
+	if u.Query == nil {
+		u.Query = url.Values{}
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if kvs[i+1] == "" {
+			u.Query.Del(kvs[i])
+		} else {
+			u.Query.Set(kvs[i], kvs[i+1])
+		}
+	}
+	return u.EncodeWebURL()
+}
+
+// deployHeights caches the deploy height of packages, read from the
+// `addpkg` section of their gnomod.toml. As packages cannot change once
+// deployed, heights are cached until the cache is full. Heights are queried
+// in the background, listings never waiting for them.
+type deployHeights struct {
+	client ClientAdapter
+
+	mu      sync.Mutex
+	heights map[string]int64
+	pending map[string]bool // being queried
+}
+
+func newDeployHeights(client ClientAdapter) *deployHeights {
+	return &deployHeights{
+		client:  client,
+		heights: make(map[string]int64),
+		pending: make(map[string]bool),
+	}
+}
+
+// get returns the cached deploy heights of the given packages, and starts
+// querying the other ones in the background. Packages whose height is
+// unknown, such as genesis packages or packages not queried yet, are at
+// height zero.
+func (d *deployHeights) get(paths []string) map[string]int64 {
+	heights := make(map[string]int64, len(paths))
+	var missing []string
+	d.mu.Lock()
+	for _, path := range paths {
+		if height, ok := d.heights[path]; ok {
+			heights[path] = height
+		} else if !d.pending[path] {
+			d.pending[path] = true
+			missing = append(missing, path)
+		}
+	}
+	d.mu.Unlock()
+
+	if len(missing) > 0 {
+		go d.fetch(missing)
+	}
+	return heights
+}
+
+// fetch queries and caches the deploy heights of the given packages.
+func (d *deployHeights) fetch(paths []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), deployHeightsTimeout)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan string)
+	)
+	for range min(deployHeightsWorkers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				height, ok := d.query(ctx, path)
+
+				d.mu.Lock()
+				delete(d.pending, path)
+				if ok {
+					if len(d.heights) >= maxListingPaths {
+						clear(d.heights)
+					}
+					d.heights[path] = height
+				}
+				d.mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range paths {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// query returns the deploy height of the package, and whether it can be
+// cached.
+func (d *deployHeights) query(ctx context.Context, path string) (int64, bool) {
+	src, _, err := d.client.File(ctx, path, "gnomod.toml")
+	if err != nil {
+		// Packages without gnomod.toml predate it
+		return 0, errors.Is(err, ErrClientPackageNotFound) || errors.Is(err, ErrClientFileNotFound)
+	}
+	gm, err := gnomod.ParseBytes("gnomod.toml", src)
+	if err != nil {
+		return 0, true
+	}
+	return int64(gm.AddPkg.Height), true
+}
@@ -0,0 +1,129 @@
+package gnoweb
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// LiveReloadPath is the dev endpoint notifying pages to reload.
+	LiveReloadPath = "/_dev/livereload"
+	// LiveReloadScriptPath serves the script connecting to LiveReloadPath.
+	LiveReloadScriptPath = "/_dev/livereload.js"
+)
+
+// liveReloadScript reloads the page when notified by the live reload
+// endpoint, and reconnects when the connection is lost.
+const liveReloadScript = `(() => {
+  const url = (location.protocol === "https:" ? "wss://" : "ws://") + location.host + "` + LiveReloadPath + `";
+  const connect = (reconnect) => {
+    const ws = new WebSocket(url);
+    ws.onopen = () => { if (reconnect) location.reload(); };
+    ws.onmessage = (e) => { if (e.data === "reload") location.reload(); };
+    ws.onclose = () => setTimeout(() => connect(true), 1000);
+  };
+  connect(false);
+})();
+`
+
+// heightFunc returns the latest block height of the node.
+type heightFunc func(ctx context.Context) (int64, error)
+
+// cachedHeight returns a heightFunc caching the height returned by `height`
+// for `ttl`, concurrent calls sharing a single query. Errors are not cached.
+func cachedHeight(height heightFunc, ttl time.Duration) heightFunc {
+	var (
+		mu      sync.Mutex
+		latest  int64
+		fetched time.Time
+	)
+	return func(ctx context.Context) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !fetched.IsZero() && time.Since(fetched) < ttl {
+			return latest, nil
+		}
+		h, err := height(ctx)
+		if err != nil {
+			return 0, err
+		}
+		latest, fetched = h, time.Now()
+		return latest, nil
+	}
+}
+
+// handlerLiveReloadScript serves the live reload client script.
+func handlerLiveReloadScript() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte(liveReloadScript))
+	})
+}
+
+// handlerLiveReload upgrades requests to a WebSocket connection and sends a
+// "reload" message once the node block height changes, polling it every
+// `interval`.
+func handlerLiveReload(logger *slog.Logger, height heightFunc, interval time.Duration) http.Handler {
+	upgrader := websocket.Upgrader{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger.Debug("unable to upgrade live reload connection", "error", err)
+			return
+		}
+		defer conn.Close()
+
+		// Detect the client going away
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		// Reload only on changes from a known height, not once the node
+		// first answers
+		last, err := height(ctx)
+		known := err == nil
+		if err != nil {
+			logger.Debug("unable to fetch block height", "error", err)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			h, err := height(ctx)
+			if err != nil {
+				continue
+			}
+			if !known {
+				last, known = h, true
+				continue
+			}
+			if h == last {
+				continue
+			}
+
+			logger.Debug("block height changed, reloading pages", "height", h)
+			conn.WriteMessage(websocket.TextMessage, []byte("reload"))
+			return
+		}
+	})
+}
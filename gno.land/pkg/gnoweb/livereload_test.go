@@ -0,0 +1,90 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerLiveReload(t *testing.T) {
+	t.Parallel()
+
+	var current atomic.Int64
+	current.Store(10)
+	height := func(ctx context.Context) (int64, error) {
+		return current.Load(), nil
+	}
+
+	srv := httptest.NewServer(handlerLiveReload(log.NewNoopLogger(), height, 10*time.Millisecond))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Let a few polls happen without any height change
+	time.Sleep(50 * time.Millisecond)
+	current.Store(11)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "reload", string(msg))
+}
+
+func TestHandlerLiveReload_NodeDown(t *testing.T) {
+	t.Parallel()
+
+	var (
+		down    atomic.Bool
+		current atomic.Int64
+	)
+	down.Store(true)
+	current.Store(10)
+	height := func(ctx context.Context) (int64, error) {
+		if down.Load() {
+			return 0, errors.New("node down")
+		}
+		return current.Load(), nil
+	}
+
+	srv := httptest.NewServer(handlerLiveReload(log.NewNoopLogger(), height, 10*time.Millisecond))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	msgs := make(chan string, 1)
+	go func() {
+		if _, msg, err := conn.ReadMessage(); err == nil {
+			msgs <- string(msg)
+		}
+	}()
+
+	// The first height is recorded as the baseline, without reloading
+	time.Sleep(30 * time.Millisecond)
+	down.Store(false)
+	select {
+	case msg := <-msgs:
+		t.Fatalf("unexpected %q message once the node is back", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	current.Store(11)
+	select {
+	case msg := <-msgs:
+		assert.Equal(t, "reload", msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after a height change")
+	}
+}
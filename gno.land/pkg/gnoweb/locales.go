@@ -0,0 +1,49 @@
+package gnoweb
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+)
+
+// requestOrigin returns the scheme and host the request was made to,
+// honoring `X-Forwarded-Proto` when gnoweb runs behind a TLS proxy.
+func requestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// hreflangAlternates returns the alternate URLs of the page at the given path
+// for each locale. The first locale is the default one, served at unprefixed
+// paths and used as `x-default`; other locales are served under a `/<locale>`
+// path prefix.
+func hreflangAlternates(origin, path string, locales []string) []components.Alternate {
+	if len(locales) == 0 {
+		return nil
+	}
+
+	// Strip the locale prefix, if any, to get the unlocalized path
+	for _, locale := range locales[1:] {
+		prefix := "/" + locale
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	alternates := make([]components.Alternate, 0, len(locales)+1)
+	alternates = append(alternates, components.Alternate{Lang: locales[0], URL: origin + path})
+	for _, locale := range locales[1:] {
+		alternates = append(alternates, components.Alternate{Lang: locale, URL: origin + "/" + locale + path})
+	}
+	alternates = append(alternates, components.Alternate{Lang: "x-default", URL: origin + path})
+
+	return alternates
+}
@@ -0,0 +1,105 @@
+package gnoweb
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+)
+
+// MaintenancePath is the admin endpoint reporting the maintenance mode, and
+// toggling it with POST requests.
+const MaintenancePath = "/debug/maintenance"
+
+// maintenanceRetryAfter is the delay, in seconds, after which clients are
+// told to retry while in maintenance mode.
+const maintenanceRetryAfter = 120
+
+// MaintenanceSwitch toggles the maintenance mode of gnoweb at runtime, in
+// which pages respond with a 503 status instead of querying the node, such
+// as while it resyncs. A nil switch is never enabled.
+type MaintenanceSwitch struct {
+	enabled atomic.Bool
+}
+
+// Enabled reports whether the maintenance mode is enabled.
+func (m *MaintenanceSwitch) Enabled() bool {
+	return m != nil && m.enabled.Load()
+}
+
+// Set enables or disables the maintenance mode.
+func (m *MaintenanceSwitch) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Toggle switches the maintenance mode, and returns whether it is now
+// enabled.
+func (m *MaintenanceSwitch) Toggle() bool {
+	for {
+		enabled := m.enabled.Load()
+		if m.enabled.CompareAndSwap(enabled, !enabled) {
+			return !enabled
+		}
+	}
+}
+
+// maintenanceMiddleware serves the maintenance page while the maintenance
+// mode is enabled, and next otherwise.
+func maintenanceMiddleware(next http.Handler, m *MaintenanceSwitch, h *HTTPHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", strconv.Itoa(maintenanceRetryAfter))
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		h.ServeMaintenance(w, r)
+	})
+}
+
+// ServeMaintenance renders the maintenance page, with a 503 status.
+func (h *HTTPHandler) ServeMaintenance(w http.ResponseWriter, r *http.Request) {
+	indexData := h.newIndexData(r)
+	indexData.HeadData.Title = h.Static.Domain + " - maintenance"
+	indexData.BodyView = components.StatusMaintenanceComponent()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := components.IndexLayout(indexData).Render(w); err != nil {
+		h.Logger.Error("failed to render maintenance view", "error", err)
+	}
+}
+
+// handlerMaintenance reports whether the maintenance mode is enabled, and
+// sets it from the `enabled` form value of POST requests.
+func handlerMaintenance(m *MaintenanceSwitch) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+			if err != nil {
+				http.Error(w, "invalid `enabled` value", http.StatusBadRequest)
+				return
+			}
+			m.Set(enabled)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Enabled bool `json:"enabled"`
+		}{m.Enabled()})
+	})
+}
@@ -0,0 +1,78 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: NewMockClient(),
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land"},
+	})
+	require.NoError(t, err)
+
+	var m MaintenanceSwitch
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "page")
+	})
+	handler := maintenanceMiddleware(next, &m, h)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "page", rr.Body.String())
+
+	assert.True(t, m.Toggle())
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "120", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), "Under maintenance")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/r/demo/boards", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	assert.False(t, m.Toggle())
+	assert.False(t, (*MaintenanceSwitch)(nil).Enabled())
+}
+
+func TestHandlerMaintenance(t *testing.T) {
+	t.Parallel()
+
+	var m MaintenanceSwitch
+	handler := handlerMaintenance(&m)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, MaintenancePath, nil))
+	assert.JSONEq(t, `{"enabled": false}`, rr.Body.String())
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, MaintenancePath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr = post(url.Values{"enabled": {"true"}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"enabled": true}`, rr.Body.String())
+	assert.True(t, m.Enabled())
+
+	assert.Equal(t, http.StatusBadRequest, post(url.Values{"enabled": {"maybe"}}).Code)
+	assert.True(t, m.Enabled())
+}
@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultBlockCacheSize is the default number of rendered blocks cached.
+const DefaultBlockCacheSize = 1024
+
+// BlockCache is an LRU cache of the rendered HTML of expensive blocks, such
+// as pflow nets or server rendered math, keyed by the CID of their source.
+// It is safe for concurrent use, and can be shared by several extensions.
+type BlockCache struct {
+	lru *lru.Cache[string, []byte]
+}
+
+// NewBlockCache returns a cache holding up to size rendered blocks.
+func NewBlockCache(size int) (*BlockCache, error) {
+	c, err := lru.New[string, []byte](size)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockCache{lru: c}, nil
+}
+
+// BlockCID returns the content identifier of a block, made of the hash of
+// its kind, rendering options and source.
+func BlockCID(kind string, parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range append([][]byte{[]byte(kind)}, parts...) {
+		// Length prefixes keep the parts boundaries unambiguous
+		binary.Write(h, binary.BigEndian, uint64(len(part)))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Len returns the number of cached blocks.
+func (c *BlockCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	return c.lru.Len()
+}
+
+// render writes the cached output of the block with the given CID, or writes
+// it with render and caches it if successful. A nil cache always renders.
+func (c *BlockCache) render(w util.BufWriter, cid string, render func(w util.BufWriter) error) error {
+	if c == nil {
+		return render(w)
+	}
+
+	if out, ok := c.lru.Get(cid); ok {
+		_, err := w.Write(out)
+		return err
+	}
+
+	var buf bytes.Buffer
+	bw := bufio.NewWriter(&buf)
+	if err := render(bw); err != nil {
+		return err
+	}
+	bw.Flush()
+
+	c.lru.Add(cid, buf.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
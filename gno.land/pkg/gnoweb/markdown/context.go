@@ -7,9 +7,10 @@ import (
 
 var gUrlContextKey = parser.NewContextKey()
 
-// NewGnoParserContext creates a new parser context with GnoURL
+// NewGnoParserContext creates a new parser context with GnoURL. Element IDs,
+// such as heading anchors, are generated with Slugify.
 func NewGnoParserContext(url *weburl.GnoURL) parser.Context {
-	ctx := parser.NewContext()
+	ctx := parser.NewContext(parser.WithIDs(make(slugIDs)))
 	ctx.Set(gUrlContextKey, *url)
 	return ctx
 }
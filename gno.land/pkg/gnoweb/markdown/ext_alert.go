@@ -117,6 +117,7 @@ const (
 	AlertTypeWarning
 	AlertTypeSuccess
 	AlertTypeInfo
+	AlertTypeImportant
 )
 
 type AlertType int
@@ -133,6 +134,8 @@ func parseAlertType(kind string) (AlertType, string) {
 		return AlertTypeSuccess, "success"
 	case "note":
 		return AlertTypeNote, "note"
+	case "important":
+		return AlertTypeImportant, "important"
 	default:
 		return AlertTypeInfo, "info"
 	}
@@ -0,0 +1,110 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var KindAltTextWarning = ast.NewNodeKind("AltTextWarning")
+
+// AltTextWarning marks an image rendered without alt text.
+type AltTextWarning struct {
+	ast.BaseInline
+	Destination []byte
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *AltTextWarning) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Destination": string(n.Destination)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*AltTextWarning) Kind() ast.NodeKind {
+	return KindAltTextWarning
+}
+
+// hasAltText reports whether the given image has a non blank alt text.
+func hasAltText(img *ast.Image, source []byte) bool {
+	var found bool
+	ast.Walk(img, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || found {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Text:
+			found = len(bytes.TrimSpace(n.Segment.Value(source))) > 0
+		case *ast.String:
+			found = len(bytes.TrimSpace(n.Value)) > 0
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
+
+// altTextTransformer implements ASTTransformer, inserting an AltTextWarning
+// node after each image without alt text.
+type altTextTransformer struct{}
+
+func (t *altTextTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var images []*ast.Image
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if img, ok := node.(*ast.Image); ok && entering && !hasAltText(img, source) {
+			images = append(images, img)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, img := range images {
+		parent := img.Parent()
+		parent.InsertAfter(parent, img, &AltTextWarning{Destination: img.Destination})
+	}
+}
+
+// altTextRenderer implements NodeRenderer.
+type altTextRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *altTextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAltTextWarning, r.renderAltTextWarning)
+}
+
+func (r *altTextRenderer) renderAltTextWarning(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*AltTextWarning)
+		w.WriteString(`<span class="gno-alt-warning" role="note" title="` + HTMLEscapeString(string(n.Destination)) + `">`)
+		w.WriteString("Image without alt text")
+		w.WriteString("</span>")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// altTextExtension is a Goldmark extension flagging images without alt text.
+type altTextExtension struct{}
+
+// NewAltTextWarningExtension returns an extension rendering a visible
+// warning next to each image without alt text. It is meant for development,
+// to help authors notice inaccessible images.
+func NewAltTextWarningExtension() goldmark.Extender {
+	return &altTextExtension{}
+}
+
+// Extend adds the alt text transformer and renderer to the provided Goldmark
+// markdown processor.
+func (e *altTextExtension) Extend(m goldmark.Markdown) {
+	// Run after the image validator, which may erase destinations
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&altTextTransformer{}, 600),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&altTextRenderer{}, 500),
+	))
+}
@@ -0,0 +1,82 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// reAnchorMarker matches a trailing `{#id}` anchor marker.
+var reAnchorMarker = regexp.MustCompile(`[ \t]*\{#([A-Za-z0-9_-]+)\}[ \t]*$`)
+
+// anchorsTransformer implements ASTTransformer, setting the `id` of the
+// paragraphs ending with a `{#id}` marker, which is removed from the text.
+// A marker preceded by a backslash, such as `\{#id}`, is kept as is.
+type anchorsTransformer struct{}
+
+func (t *anchorsTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var paragraphs []*ast.Paragraph
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if p, ok := node.(*ast.Paragraph); ok && entering {
+			paragraphs = append(paragraphs, p)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, p := range paragraphs {
+		last, ok := p.LastChild().(*ast.Text)
+		if !ok {
+			continue
+		}
+
+		value := last.Segment.Value(source)
+		loc := reAnchorMarker.FindSubmatchIndex(value)
+		if loc == nil {
+			continue
+		}
+
+		// Escaped marker: rendered as literal text
+		if brace := bytes.IndexByte(value[loc[0]:], '{') + loc[0]; brace > 0 && value[brace-1] == '\\' {
+			continue
+		}
+
+		// Register the id so it does not collide with other elements
+		id := pc.IDs().Generate(value[loc[2]:loc[3]], ast.KindParagraph)
+		p.SetAttributeString("id", id)
+
+		if loc[0] == 0 {
+			// The marker is on its own line
+			if prev, ok := last.PreviousSibling().(*ast.Text); ok {
+				prev.SetSoftLineBreak(false)
+			}
+			p.RemoveChild(p, last)
+			continue
+		}
+		last.Segment = last.Segment.WithStop(last.Segment.Start + loc[0])
+	}
+}
+
+// anchorsExtension is a Goldmark extension handling `{#id}` paragraph
+// anchors.
+type anchorsExtension struct{}
+
+// NewAnchorsExtension returns an extension setting the `id` of paragraphs
+// ending with a `{#id}` marker, so they can be linked to.
+func NewAnchorsExtension() goldmark.Extender {
+	return &anchorsExtension{}
+}
+
+// Extend adds the anchors transformer to the provided Goldmark markdown
+// processor.
+func (e *anchorsExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&anchorsTransformer{}, 500),
+	))
+}
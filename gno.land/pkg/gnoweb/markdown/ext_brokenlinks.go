@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// LinkExistsFunc reports whether the package or realm at the given path
+// exists.
+type LinkExistsFunc func(path string) bool
+
+var KindBrokenLinkWarning = ast.NewNodeKind("BrokenLinkWarning")
+
+// BrokenLinkWarning marks a link to a package or realm which does not exist.
+type BrokenLinkWarning struct {
+	ast.BaseInline
+	Path string
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *BrokenLinkWarning) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Path": n.Path}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*BrokenLinkWarning) Kind() ast.NodeKind {
+	return KindBrokenLinkWarning
+}
+
+// brokenLinksTransformer implements ASTTransformer, inserting a
+// BrokenLinkWarning node after each link whose target does not exist. It
+// relies on the GnoLink nodes of the links extension.
+type brokenLinksTransformer struct {
+	exists LinkExistsFunc
+}
+
+func (t *brokenLinksTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var broken []*GnoLink
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := node.(*GnoLink)
+		if !ok || !entering || link.GnoURL == nil {
+			return ast.WalkContinue, nil
+		}
+
+		switch link.LinkType {
+		case GnoLinkTypePackage, GnoLinkTypeInternal:
+		default:
+			return ast.WalkContinue, nil
+		}
+
+		target := link.GnoURL
+		if (target.IsPure() || target.IsRealm()) && !t.exists(target.Path) {
+			broken = append(broken, link)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, link := range broken {
+		parent := link.Parent()
+		parent.InsertAfter(parent, link, &BrokenLinkWarning{Path: link.GnoURL.Path})
+	}
+}
+
+// brokenLinksRenderer implements NodeRenderer.
+type brokenLinksRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *brokenLinksRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindBrokenLinkWarning, r.renderBrokenLinkWarning)
+}
+
+func (r *brokenLinksRenderer) renderBrokenLinkWarning(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*BrokenLinkWarning)
+		w.WriteString(`<span class="gno-link-warning" role="note" title="` + HTMLEscapeString(n.Path) + ` does not exist">`)
+		w.WriteString("Broken link")
+		w.WriteString("</span>")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// brokenLinksExtension is a Goldmark extension flagging links to packages
+// and realms which do not exist.
+type brokenLinksExtension struct {
+	exists LinkExistsFunc
+}
+
+// NewBrokenLinksExtension returns an extension rendering a visible warning
+// next to each link to a package or realm for which exists returns false.
+// It is meant for development, as each link target is checked.
+func NewBrokenLinksExtension(exists LinkExistsFunc) goldmark.Extender {
+	return &brokenLinksExtension{exists: exists}
+}
+
+// Extend adds the broken links transformer and renderer to the provided
+// Goldmark markdown processor.
+func (e *brokenLinksExtension) Extend(m goldmark.Markdown) {
+	// Run after the links transformer, which creates GnoLink nodes
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&brokenLinksTransformer{exists: e.exists}, 600),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&brokenLinksRenderer{}, 500),
+	))
+}
@@ -0,0 +1,230 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// CallLinkPrefix starts the destination of links calling a realm function,
+// such as `gno:call?pkg=gno.land/r/gov/dao&func=Vote&arg.id=1`.
+const CallLinkPrefix = "gno:call?"
+
+var ErrCallLinkInvalid = errors.New("invalid call link")
+
+var (
+	// reCallIdent matches function and argument names.
+	reCallIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// reCallRealm matches the web path of realms.
+	reCallRealm = regexp.MustCompile(`^/r/[a-z0-9_][a-z0-9_/]*$`)
+)
+
+// CallArg is an argument of a call link.
+type CallArg struct {
+	Name, Value string
+}
+
+var KindCallLink = ast.NewNodeKind("CallLink")
+
+// CallLink is a link to the help page of a realm function, with its
+// arguments prefilled.
+type CallLink struct {
+	ast.BaseInline
+	PkgPath string // full package path, such as `gno.land/r/gov/dao`
+	Path    string // web path, such as `/r/gov/dao`
+	Func    string
+	Args    []CallArg // in the order of the link
+	Send    string
+	Err     error
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *CallLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"PkgPath": n.PkgPath, "Func": n.Func}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*CallLink) Kind() ast.NodeKind {
+	return KindCallLink
+}
+
+// HelpURL returns the URL of the help page for the call.
+func (n *CallLink) HelpURL() string {
+	var b strings.Builder
+	b.WriteString(n.Path + "$help&func=" + url.QueryEscape(n.Func))
+	for _, arg := range n.Args {
+		b.WriteString("&" + url.QueryEscape(arg.Name) + "=" + url.QueryEscape(arg.Value))
+	}
+	if n.Send != "" {
+		b.WriteString("&.send=" + url.QueryEscape(n.Send))
+	}
+	return b.String()
+}
+
+// Command returns the gnokey command performing the call.
+func (n *CallLink) Command() string {
+	var b strings.Builder
+	b.WriteString("gnokey maketx call -pkgpath " + strconv.Quote(n.PkgPath) + " -func " + strconv.Quote(n.Func))
+	for _, arg := range n.Args {
+		b.WriteString(" -args " + strconv.Quote(arg.Value))
+	}
+	if n.Send != "" {
+		b.WriteString(" -send " + strconv.Quote(n.Send))
+	}
+	return b.String()
+}
+
+// parseCallLink parses the query of a call link. Arguments keep their order,
+// which is the one of the command. The domain is used for packages given
+// without one.
+func parseCallLink(query, domain string) (*CallLink, error) {
+	n := &CallLink{}
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return n, fmt.Errorf("%w: %w", ErrCallLinkInvalid, err)
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return n, fmt.Errorf("%w: %w", ErrCallLinkInvalid, err)
+		}
+
+		switch {
+		case key == "pkg":
+			n.PkgPath = value
+		case key == "func":
+			n.Func = value
+		case key == "send":
+			n.Send = value
+		case strings.HasPrefix(key, "arg."):
+			name := strings.TrimPrefix(key, "arg.")
+			if !reCallIdent.MatchString(name) {
+				return n, fmt.Errorf("%w: invalid argument name %q", ErrCallLinkInvalid, name)
+			}
+			n.Args = append(n.Args, CallArg{Name: name, Value: value})
+		default:
+			return n, fmt.Errorf("%w: unknown parameter %q", ErrCallLinkInvalid, key)
+		}
+	}
+
+	if strings.HasPrefix(n.PkgPath, "/") {
+		n.PkgPath = domain + n.PkgPath
+	}
+	n.Path = strings.TrimPrefix(n.PkgPath, domain)
+	if domain == "" || !reCallRealm.MatchString(n.Path) {
+		return n, fmt.Errorf("%w: %q is not a realm of %s", ErrCallLinkInvalid, n.PkgPath, domain)
+	}
+	if !reCallIdent.MatchString(n.Func) {
+		return n, fmt.Errorf("%w: invalid function %q", ErrCallLinkInvalid, n.Func)
+	}
+
+	return n, nil
+}
+
+// callLinkTransformer implements ASTTransformer, replacing `gno:call` links
+// with CallLink nodes. It runs before the links transformer, which would
+// take them for external links.
+type callLinkTransformer struct{}
+
+func (t *callLinkTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var domain string
+	if u, ok := getUrlFromContext(pc); ok {
+		domain = u.Domain
+	}
+
+	var links []*ast.Link
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := node.(*ast.Link); ok && entering {
+			if strings.HasPrefix(string(link.Destination), CallLinkPrefix) {
+				links = append(links, link)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, link := range links {
+		n, err := parseCallLink(strings.TrimPrefix(string(link.Destination), CallLinkPrefix), domain)
+		n.Err = err
+		for child := link.FirstChild(); child != nil; {
+			next := child.NextSibling()
+			n.AppendChild(n, child)
+			child = next
+		}
+
+		parent := link.Parent()
+		parent.ReplaceChild(parent, link, n)
+	}
+}
+
+// callLinkRenderer implements NodeRenderer.
+type callLinkRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *callLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindCallLink, r.renderCallLink)
+}
+
+func (r *callLinkRenderer) renderCallLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*CallLink)
+	if n.Err != nil {
+		// Only render the link text
+		if entering {
+			w.WriteString(`<!-- ` + HTMLEscapeString(n.Err.Error()) + ` -->`)
+		}
+		return ast.WalkContinue, nil
+	}
+
+	if entering {
+		w.WriteString(`<a href="` + HTMLEscapeString(n.HelpURL()) + `" class="gno-call" title="` + HTMLEscapeString(n.Command()) + `">`)
+		return ast.WalkContinue, nil
+	}
+
+	writeHTMLTag(w, "span", []attr{
+		{"class", classLinkTx + " tooltip"},
+		{"data-tooltip-target", "info"},
+		{"data-tooltip", tooltipTxLink},
+		{"title", tooltipTxLink},
+	})
+	w.WriteString(`<svg class="c-icon"><use href="#` + iconTxLink + `"></use></svg>`)
+	w.WriteString("</span></a>")
+
+	return ast.WalkContinue, nil
+}
+
+// callLinkExtension is a Goldmark extension handling `gno:call` links.
+type callLinkExtension struct{}
+
+// NewCallLinkExtension returns an extension rendering
+// `[Vote](gno:call?pkg=gno.land/r/gov/dao&func=Vote&arg.id=1)` links as
+// buttons to the help page of the function, with its arguments prefilled,
+// and the matching gnokey command as title. A `send` parameter sets the
+// coins sent along the call.
+func NewCallLinkExtension() goldmark.Extender {
+	return &callLinkExtension{}
+}
+
+// Extend adds the call link transformer and renderer to the provided
+// Goldmark markdown processor.
+func (e *callLinkExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&callLinkTransformer{}, 400),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&callLinkRenderer{}, 500),
+	))
+}
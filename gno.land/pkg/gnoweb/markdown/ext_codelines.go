@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"fmt"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// codeBlockIndexAttr is the attribute holding the index of a fenced code
+// block within its document.
+var codeBlockIndexAttr = []byte("gno-block-index")
+
+// codeLinesTransformer implements ASTTransformer, numbering fenced code
+// blocks in document order.
+type codeLinesTransformer struct{}
+
+func (t *codeLinesTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var index int
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		fcb, ok := node.(*ast.FencedCodeBlock)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		// Highlighting ignores info string attributes (e.g. `{hl_lines=[2]}`)
+		// once the node has its own, so copy them first.
+		if fcb.Attributes() == nil && fcb.Info != nil {
+			info := fcb.Info.Segment.Value(source)
+			for i, c := range info {
+				if c != '{' {
+					continue
+				}
+				if attrs, ok := parser.ParseAttributes(text.NewReader(info[i:])); ok {
+					for _, attr := range attrs {
+						fcb.SetAttribute(attr.Name, attr.Value)
+					}
+				}
+				break
+			}
+		}
+
+		index++
+		fcb.SetAttribute(codeBlockIndexAttr, index)
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// codeLinesOptions returns the chroma options prefixing line number IDs
+// with the index of the code block, e.g. `B2-L12`.
+func codeLinesOptions(ctx highlighting.CodeBlockContext) []chromahtml.Option {
+	if ctx.Attributes() == nil {
+		return nil
+	}
+
+	index, ok := ctx.Attributes().Get(codeBlockIndexAttr)
+	if !ok {
+		return nil
+	}
+
+	return []chromahtml.Option{
+		chromahtml.WithLinkableLineNumbers(true, fmt.Sprintf("B%d-L", index)),
+	}
+}
+
+// codeLinesExtension is a Goldmark extension highlighting code blocks with
+// line number anchors that are unique across the page.
+type codeLinesExtension struct {
+	highlighting goldmark.Extender
+}
+
+// NewCodeLineAnchorsExtension returns a highlighting extension configured
+// with the given options, which renders each line number as an anchor whose
+// ID is prefixed by the block index, so that multiple blocks on a page never
+// collide. It replaces the goldmark-highlighting extension.
+func NewCodeLineAnchorsExtension(opts ...highlighting.Option) goldmark.Extender {
+	opts = append(opts, highlighting.WithCodeBlockOptions(codeLinesOptions))
+	return &codeLinesExtension{highlighting: highlighting.NewHighlighting(opts...)}
+}
+
+// Extend adds the code lines transformer and highlighting to the provided
+// Goldmark markdown processor.
+func (e *codeLinesExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&codeLinesTransformer{}, 600),
+	))
+	e.highlighting.Extend(m)
+}
@@ -17,6 +17,18 @@ import (
 var (
 	KindGnoColumn       = ast.NewNodeKind("GnoColumn")
 	GnoColumnsShorthand = []byte("|||") // shorthand for column separator
+
+	// Directive syntax, an alternative to the tags:
+	//
+	//	:::columns
+	//	:::column
+	//	## Left
+	//	:::column
+	//	## Right
+	//	:::
+	GnoColumnsDirectiveOpen  = []byte(":::columns")
+	GnoColumnsDirectiveSep   = []byte(":::column")
+	GnoColumnsDirectiveClose = []byte(":::")
 )
 
 // GnoColumnTag represents the type of tag in a column block.
@@ -46,6 +58,10 @@ type GnoColumnNode struct {
 	Index int          // Index of the column associated with the node.
 	Tag   GnoColumnTag // Current Column Tag for this node.
 
+	// leading is set on a `:::column` directive directly following the
+	// opening one, which starts the first column rather than a new one.
+	leading bool
+
 	ctx *columnsContext
 }
 
@@ -79,6 +95,9 @@ func (n *GnoColumnNode) IsEmptyColumns() bool {
 	}
 
 	next, ok := ctx.OpenTag.NextSibling().(*GnoColumnNode)
+	if ok && next.leading {
+		next, ok = next.NextSibling().(*GnoColumnNode)
+	}
 	if ok && next.Tag == GnoColumnTagClose {
 		return true
 	}
@@ -108,6 +127,16 @@ func parseLineTag(line []byte) GnoColumnTag {
 		return GnoColumnTagSep
 	}
 
+	// Check for the directive syntax
+	switch {
+	case bytes.Equal(line, GnoColumnsDirectiveOpen):
+		return GnoColumnTagOpen
+	case bytes.Equal(line, GnoColumnsDirectiveSep):
+		return GnoColumnTagSep
+	case bytes.Equal(line, GnoColumnsDirectiveClose):
+		return GnoColumnTagClose
+	}
+
 	// Parse the line into HTML tokens
 	toks, err := ParseHTMLTokens(bytes.NewReader(line))
 	if err != nil || len(toks) != 1 {
@@ -139,7 +168,7 @@ type columnsParser struct{}
 
 // Trigger returns the trigger characters for the parser.
 func (*columnsParser) Trigger() []byte {
-	return []byte{'<', '|'}
+	return []byte{'<', '|', ':'}
 }
 
 // Open creates a column node based on the line tag.
@@ -179,6 +208,11 @@ func (p *columnsParser) Open(doc ast.Node, reader text.Reader, pc parser.Context
 
 	case GnoColumnTagClose:
 		if !cctx.IsOpen {
+			if bytes.Equal(line, GnoColumnsDirectiveClose) {
+				// Let other directives, or the paragraph, handle it.
+				return nil, parser.NoChildren
+			}
+
 			node.Tag = GnoColumnTagUndefined
 			return node, parser.NoChildren
 		}
@@ -187,7 +221,7 @@ func (p *columnsParser) Open(doc ast.Node, reader text.Reader, pc parser.Context
 
 	case GnoColumnTagSep:
 		if !cctx.IsOpen {
-			if bytes.Equal(line, GnoColumnsShorthand) {
+			if bytes.Equal(line, GnoColumnsShorthand) || bytes.Equal(line, GnoColumnsDirectiveSep) {
 				// We return nil to let the parser continue here as we
 				// are not in a column context.
 				return nil, parser.NoChildren
@@ -197,6 +231,12 @@ func (p *columnsParser) Open(doc ast.Node, reader text.Reader, pc parser.Context
 			return node, parser.NoChildren
 		}
 
+		if bytes.Equal(line, GnoColumnsDirectiveSep) && doc.LastChild() == cctx.OpenTag {
+			// The first `:::column` opens the column started by `:::columns`.
+			node.leading = true
+			return node, parser.NoChildren
+		}
+
 		cctx.Index++
 		node.Index = cctx.Index
 	}
@@ -272,6 +312,10 @@ func renderGnoColumns(w util.BufWriter, _ []byte, node ast.Node, entering bool)
 		fallthrough // start the first column
 
 	case GnoColumnTagSep:
+		if cnode.leading {
+			return ast.WalkContinue, nil
+		}
+
 		if cnode.Index > 0 {
 			fmt.Fprintln(w, "</div>")
 		}
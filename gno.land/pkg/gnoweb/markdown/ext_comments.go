@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// stripCommentsTransformer implements ASTTransformer, removing the HTML
+// comments found in the source. Comments emitted by renderers, such as the
+// columns markers, are not affected, nor is the content of code blocks and
+// code spans.
+type stripCommentsTransformer struct{}
+
+func (t *stripCommentsTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var comments []ast.Node
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.HTMLBlock:
+			if n.HTMLBlockType == ast.HTMLBlockType2 {
+				comments = append(comments, n)
+			}
+		case *ast.RawHTML:
+			if n.Segments.Len() == 0 {
+				break
+			}
+			if seg := n.Segments.At(0); bytes.HasPrefix(seg.Value(source), []byte("<!--")) {
+				comments = append(comments, n)
+			}
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.CodeSpan:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, node := range comments {
+		node.Parent().RemoveChild(node.Parent(), node)
+	}
+}
+
+// stripCommentsExtension is a Goldmark extension removing HTML comments.
+type stripCommentsExtension struct{}
+
+// NewStripCommentsExtension returns an extension removing HTML comments from
+// the source before rendering.
+func NewStripCommentsExtension() goldmark.Extender {
+	return &stripCommentsExtension{}
+}
+
+// Extend adds the comments transformer to the provided Goldmark markdown
+// processor.
+func (e *stripCommentsExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&stripCommentsTransformer{}, 900),
+	))
+}
@@ -0,0 +1,151 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var KindConditional = ast.NewNodeKind("Conditional")
+
+// Conditional is a `:::if <flag>` block, only rendered if its flag is set.
+type Conditional struct {
+	ast.BaseBlock
+	Flag string
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *Conditional) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Flag": n.Flag}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*Conditional) Kind() ast.NodeKind {
+	return KindConditional
+}
+
+// reConditionalOpen matches the opening `:::if <flag>` line of a block.
+var reConditionalOpen = regexp.MustCompile(`^:::if[ \t]+([A-Za-z0-9_.-]+)[ \t]*$`)
+
+// conditionalParser implements BlockParser, parsing `:::if <flag>` blocks
+// closed by a `:::` line. Conditional blocks cannot be nested.
+type conditionalParser struct{}
+
+var _ parser.BlockParser = (*conditionalParser)(nil)
+
+// Trigger returns the characters that trigger this parser.
+func (*conditionalParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+// advanceLine advances the reader to the end of the current line, before its
+// newline, as goldmark expects from block parsers consuming a whole line.
+func advanceLine(reader text.Reader) {
+	line, segment := reader.PeekLine()
+	newline := 0
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		newline = 1
+	}
+	reader.Advance(segment.Stop - segment.Start - newline + segment.Padding)
+}
+
+// Open opens a Conditional block on a `:::if <flag>` line.
+func (p *conditionalParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	if _, ok := parent.(*ast.Document); !ok {
+		return nil, parser.NoChildren
+	}
+
+	line, _ := reader.PeekLine()
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 {
+		return nil, parser.NoChildren
+	}
+
+	match := reConditionalOpen.FindSubmatch(bytes.TrimRight(line[pos:], "\r\n"))
+	if match == nil {
+		return nil, parser.NoChildren
+	}
+
+	advanceLine(reader)
+	return &Conditional{Flag: string(match[1])}, parser.HasChildren
+}
+
+// Continue closes the block on a `:::` line.
+func (*conditionalParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if string(bytes.TrimSpace(line)) == ":::" {
+		advanceLine(reader)
+		return parser.Close
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+// Close is called when the block ends.
+func (*conditionalParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph indicates if this parser can interrupt a paragraph.
+func (*conditionalParser) CanInterruptParagraph() bool {
+	return true
+}
+
+// CanAcceptIndentedLine indicates if this parser accepts indented lines.
+func (*conditionalParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// conditionalTransformer implements ASTTransformer, removing Conditional
+// blocks whose flag is not set and unwrapping the others, so that omitted
+// content never reaches the renderer.
+type conditionalTransformer struct {
+	flags map[string]bool
+}
+
+func (t *conditionalTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	for c := doc.FirstChild(); c != nil; {
+		next := c.NextSibling()
+
+		if cond, ok := c.(*Conditional); ok {
+			if t.flags[cond.Flag] {
+				for child := cond.FirstChild(); child != nil; {
+					sibling := child.NextSibling()
+					doc.InsertBefore(doc, cond, child)
+					child = sibling
+				}
+			}
+			doc.RemoveChild(doc, cond)
+		}
+
+		c = next
+	}
+}
+
+// conditionalExtension is a Goldmark extension handling `:::if` blocks.
+type conditionalExtension struct {
+	flags map[string]bool
+}
+
+// NewConditionalExtension returns an extension rendering the content of
+// `:::if <flag>` ... `:::` blocks only if their flag is one of the given
+// flags. Blocks with other flags are omitted from the output.
+func NewConditionalExtension(flags ...string) goldmark.Extender {
+	e := conditionalExtension{flags: make(map[string]bool, len(flags))}
+	for _, flag := range flags {
+		e.flags[flag] = true
+	}
+	return &e
+}
+
+// Extend adds the conditional parser and transformer to the provided
+// Goldmark markdown processor.
+func (e *conditionalExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&conditionalParser{}, 500)),
+		// Run first, so other transformers skip omitted content
+		parser.WithASTTransformers(util.Prioritized(&conditionalTransformer{flags: e.flags}, 100)),
+	)
+}
@@ -0,0 +1,214 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultDotRuntimeURL is the default viz.js runtime used to render DOT
+// graphs on the client.
+const DefaultDotRuntimeURL = "https://cdn.jsdelivr.net/npm/@viz-js/viz@3.11.0/lib/viz-standalone.js"
+
+var ErrDotInvalid = errors.New("invalid dot graph")
+
+var KindDotBlock = ast.NewNodeKind("DotBlock")
+
+// DotBlock represents a graph from a ```dot or ```graphviz block.
+type DotBlock struct {
+	ast.BaseBlock
+	Source []byte
+	Err    error
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *DotBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Source": string(n.Source)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*DotBlock) Kind() ast.NodeKind {
+	return KindDotBlock
+}
+
+// dotTransformer implements ASTTransformer, converting ```dot fenced code
+// blocks into DotBlock nodes.
+type dotTransformer struct{}
+
+func (t *dotTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			switch string(fcb.Language(source)) {
+			case "dot", "graphviz":
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, fcb := range blocks {
+		var src bytes.Buffer
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			src.Write(seg.Value(source))
+		}
+
+		db := &DotBlock{Source: bytes.TrimSpace(src.Bytes())}
+		db.Err = validateDot(string(db.Source))
+		parent := fcb.Parent()
+		parent.ReplaceChild(parent, fcb, db)
+	}
+}
+
+// validateDot performs a lightweight syntax check of a DOT graph: header,
+// balanced delimiters, terminated strings and comments, and edge operators
+// matching the graph kind.
+func validateDot(src string) error {
+	fields := strings.Fields(strings.ToLower(src))
+	if len(fields) > 0 && fields[0] == "strict" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "graph") && !strings.HasPrefix(fields[0], "digraph") {
+		return fmt.Errorf("%w: expected `graph` or `digraph`", ErrDotInvalid)
+	}
+	directed := strings.HasPrefix(fields[0], "digraph")
+
+	var (
+		stack  []byte
+		closed bool // the graph body has been closed
+	)
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if closed && !isSpace(c) && c != '\r' && c != '/' && c != '#' {
+			return fmt.Errorf("%w: unexpected content after the graph", ErrDotInvalid)
+		}
+
+		switch {
+		case c == '"':
+			end := i + 1
+			for ; end < len(src) && src[end] != '"'; end++ {
+				if src[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(src) {
+				return fmt.Errorf("%w: unterminated string", ErrDotInvalid)
+			}
+			i = end
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("%w: unterminated comment", ErrDotInvalid)
+			}
+			i += end + 3
+		case c == '/' && strings.HasPrefix(src[i:], "//"), c == '#' && (i == 0 || src[i-1] == '\n'):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '-' && strings.HasPrefix(src[i:], "->") && !directed:
+			return fmt.Errorf("%w: `->` used in an undirected graph", ErrDotInvalid)
+		case c == '-' && strings.HasPrefix(src[i:], "--") && directed:
+			return fmt.Errorf("%w: `--` used in a directed graph", ErrDotInvalid)
+		case c == '{', c == '[', c == '<':
+			stack = append(stack, c)
+		case c == '}', c == ']', c == '>':
+			open := map[byte]byte{'}': '{', ']': '[', '>': '<'}[c]
+			if c == '>' && i > 0 && src[i-1] == '-' {
+				continue // edge operator
+			}
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return fmt.Errorf("%w: unexpected %q", ErrDotInvalid, c)
+			}
+			stack = stack[:len(stack)-1]
+			closed = len(stack) == 0 && c == '}'
+		}
+	}
+
+	if !closed {
+		return fmt.Errorf("%w: unclosed graph body", ErrDotInvalid)
+	}
+
+	return nil
+}
+
+// dotRenderer implements NodeRenderer.
+type dotRenderer struct {
+	runtimeURL string
+}
+
+// RegisterFuncs registers the renderer functions.
+func (r *dotRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindDotBlock, r.renderDotBlock)
+}
+
+func (r *dotRenderer) renderDotBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*DotBlock)
+	src := HTMLEscapeString(string(n.Source))
+	switch {
+	case n.Err != nil:
+		w.WriteString(`<div class="gno-dot gno-dot-error" role="alert">`)
+		w.WriteString(`<p>` + HTMLEscapeString(n.Err.Error()) + `</p>`)
+		w.WriteString(`<pre><code>` + src + `</code></pre></div>` + "\n")
+	case r.runtimeURL == "":
+		// No runtime: display the graph source
+		w.WriteString(`<div class="gno-dot"><pre><code>` + src + `</code></pre></div>` + "\n")
+	default:
+		w.WriteString(`<div class="gno-dot" data-controller="dot" data-dot-runtime-value="` + HTMLEscapeString(r.runtimeURL) + `">`)
+		w.WriteString(`<pre data-dot-target="source"><code>` + src + `</code></pre></div>` + "\n")
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+// DotOption configures the dot extension.
+type DotOption func(e *dotExtension)
+
+// WithDotRuntimeURL sets the URL of the viz.js runtime rendering graphs on
+// the client. An empty URL displays the graph source only.
+func WithDotRuntimeURL(url string) DotOption {
+	return func(e *dotExtension) {
+		e.runtimeURL = url
+	}
+}
+
+// dotExtension is a Goldmark extension handling ```dot graph blocks.
+type dotExtension struct {
+	runtimeURL string
+}
+
+// NewDotExtension returns a new dot extension. Graphs are validated on the
+// server and rendered to SVG on the client.
+func NewDotExtension(opts ...DotOption) goldmark.Extender {
+	e := dotExtension{runtimeURL: DefaultDotRuntimeURL}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the dot transformer and renderer to the provided Goldmark
+// markdown processor.
+func (e *dotExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(util.Prioritized(&dotTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&dotRenderer{runtimeURL: e.runtimeURL}, 500),
+	))
+}
@@ -0,0 +1,301 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// maxSourceEmbeds bounds the number of files embedded in a page, as
+	// each of them is fetched.
+	maxSourceEmbeds = 8
+	// maxSourceEmbedLines bounds the number of lines of an embed.
+	maxSourceEmbedLines = 500
+)
+
+var ErrSourceEmbedInvalid = errors.New("invalid source embed")
+
+// SourceFetchFunc returns the content of the given file of the package at
+// path, such as `/p/demo/avl`.
+type SourceFetchFunc func(path, file string) ([]byte, error)
+
+var (
+	// reSourceEmbedInfo matches the info string of embed blocks, such as
+	// `gno-embed{path=gno.land/p/demo/avl file=avl.gno lines=10-40}`.
+	reSourceEmbedInfo = regexp.MustCompile(`^gno-embed[ \t]*\{([^}]*)\}[ \t]*$`)
+	reSourceEmbedPath = regexp.MustCompile(`^/[a-z]/[a-z0-9_][a-z0-9_/]*$`)
+	reSourceEmbedFile = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+var KindSourceEmbed = ast.NewNodeKind("SourceEmbed")
+
+// SourceEmbed is an excerpt of an on-chain source file, from a
+// ```gno-embed{...} block.
+type SourceEmbed struct {
+	ast.BaseBlock
+	Path  string // package path, such as `/p/demo/avl`
+	File  string
+	Start int // first line, starting at 1
+	End   int // last line, included
+	// Source holds the embedded lines.
+	Source []byte
+	Err    error
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *SourceEmbed) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Path":  n.Path,
+		"File":  n.File,
+		"Lines": fmt.Sprintf("%d-%d", n.Start, n.End),
+	}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*SourceEmbed) Kind() ast.NodeKind {
+	return KindSourceEmbed
+}
+
+// parseSourceEmbed parses the attributes of an embed block. The domain is
+// stripped from the package path.
+func parseSourceEmbed(attrs, domain string) (*SourceEmbed, error) {
+	n := &SourceEmbed{}
+	var lines string
+	for _, attr := range strings.Fields(attrs) {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok {
+			return n, fmt.Errorf("%w: malformed attribute %q", ErrSourceEmbedInvalid, attr)
+		}
+
+		switch key {
+		case "path":
+			value = strings.TrimPrefix(value, domain)
+			n.Path = "/" + strings.Trim(value, "/")
+		case "file":
+			n.File = value
+		case "lines":
+			lines = value
+		default:
+			return n, fmt.Errorf("%w: unknown attribute %q", ErrSourceEmbedInvalid, key)
+		}
+	}
+
+	if !reSourceEmbedPath.MatchString(n.Path) {
+		return n, fmt.Errorf("%w: invalid package path %q", ErrSourceEmbedInvalid, n.Path)
+	}
+	if !reSourceEmbedFile.MatchString(n.File) {
+		return n, fmt.Errorf("%w: invalid file name %q", ErrSourceEmbedInvalid, n.File)
+	}
+
+	if lines == "" {
+		return n, nil
+	}
+
+	first, last, isRange := strings.Cut(lines, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 1 {
+		return n, fmt.Errorf("%w: invalid lines %q", ErrSourceEmbedInvalid, lines)
+	}
+	n.Start, n.End = start, start
+	switch {
+	case !isRange:
+	case last == "":
+		n.End = 0 // up to the end of the file
+	default:
+		end, err := strconv.Atoi(last)
+		if err != nil || end < start {
+			return n, fmt.Errorf("%w: invalid lines %q", ErrSourceEmbedInvalid, lines)
+		}
+		n.End = end
+	}
+
+	return n, nil
+}
+
+// selectLines sets the source of the embed to its lines of the given file.
+func (n *SourceEmbed) selectLines(file []byte) error {
+	lines := bytes.SplitAfter(bytes.TrimSuffix(file, []byte("\n")), []byte("\n"))
+	if n.Start == 0 {
+		n.Start = 1
+	}
+	if n.End == 0 {
+		n.End = len(lines)
+	}
+
+	switch {
+	case n.Start > len(lines) || n.End > len(lines):
+		return fmt.Errorf("%w: %s has %d lines", ErrSourceEmbedInvalid, n.File, len(lines))
+	case n.End-n.Start+1 > maxSourceEmbedLines:
+		return fmt.Errorf("%w: more than %d lines, select some with the lines attribute", ErrSourceEmbedInvalid, maxSourceEmbedLines)
+	}
+
+	n.Source = bytes.Join(lines[n.Start-1:n.End], nil)
+	return nil
+}
+
+// sourceEmbedTransformer implements ASTTransformer, replacing
+// ```gno-embed{...} fenced code blocks with the referenced source.
+type sourceEmbedTransformer struct {
+	fetch SourceFetchFunc
+}
+
+func (t *sourceEmbedTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering && fcb.Info != nil {
+			if bytes.HasPrefix(fcb.Info.Segment.Value(source), []byte("gno-embed")) {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var domain string
+	if u, ok := getUrlFromContext(pc); ok {
+		domain = u.Domain
+	}
+
+	for i, fcb := range blocks {
+		var n *SourceEmbed
+		match := reSourceEmbedInfo.FindSubmatch(fcb.Info.Segment.Value(source))
+		switch {
+		case match == nil:
+			n = &SourceEmbed{Err: fmt.Errorf("%w: expected gno-embed{path=... file=...}", ErrSourceEmbedInvalid)}
+		case i >= maxSourceEmbeds:
+			n = &SourceEmbed{Err: fmt.Errorf("%w: more than %d embeds in the page", ErrSourceEmbedInvalid, maxSourceEmbeds)}
+		default:
+			var err error
+			n, err = parseSourceEmbed(string(match[1]), domain)
+			n.Err = err
+		}
+
+		if n.Err == nil {
+			file, err := t.fetch(n.Path, n.File)
+			if err != nil {
+				n.Err = fmt.Errorf("unable to fetch %s: %w", path.Join(n.Path, n.File), err)
+			} else {
+				n.Err = n.selectLines(file)
+			}
+		}
+
+		parent := fcb.Parent()
+		parent.ReplaceChild(parent, fcb, n)
+	}
+}
+
+// sourceEmbedRenderer implements NodeRenderer.
+type sourceEmbedRenderer struct {
+	style   *chroma.Style
+	options []chromahtml.Option
+}
+
+// RegisterFuncs registers the renderer functions.
+func (r *sourceEmbedRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindSourceEmbed, r.renderSourceEmbed)
+}
+
+func (r *sourceEmbedRenderer) renderSourceEmbed(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*SourceEmbed)
+	if n.Err != nil {
+		w.WriteString(`<div class="gno-embed gno-embed-error" role="alert"><p>` + HTMLEscapeString(n.Err.Error()) + `</p></div>` + "\n")
+		return ast.WalkSkipChildren, nil
+	}
+
+	href := path.Join(n.Path, n.File) + "#L" + strconv.Itoa(n.Start)
+	w.WriteString(`<figure class="gno-embed">` + "\n")
+	w.WriteString(`<figcaption><a href="` + HTMLEscapeString(href) + `">` + HTMLEscapeString(path.Join(n.Path, n.File)) + `</a>`)
+	w.WriteString(fmt.Sprintf(" (lines %d-%d)</figcaption>\n", n.Start, n.End))
+
+	lexer := lexers.Match(n.File)
+	if lexer == nil && path.Ext(n.File) == ".gno" {
+		lexer = lexers.Get("go") // the Gno lexer is registered by gnoweb
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	// Number lines as in the file, without anchors which would clash with
+	// the ones of the page
+	opts := append(slices.Clip(r.options),
+		chromahtml.BaseLineNumber(n.Start),
+		chromahtml.WithLinkableLineNumbers(false, ""),
+	)
+
+	iterator, err := lexer.Tokenise(nil, string(n.Source))
+	if err == nil {
+		err = chromahtml.New(opts...).Format(w, r.style, iterator)
+	}
+	if err != nil {
+		w.WriteString("<pre><code>" + HTMLEscapeString(string(n.Source)) + "</code></pre>\n")
+	}
+
+	w.WriteString("</figure>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+// SourceEmbedOption configures the source embed extension.
+type SourceEmbedOption func(e *sourceEmbedExtension)
+
+// WithSourceEmbedChroma sets the style and options used to highlight the
+// embedded sources.
+func WithSourceEmbedChroma(style *chroma.Style, opts ...chromahtml.Option) SourceEmbedOption {
+	return func(e *sourceEmbedExtension) {
+		e.style = style
+		e.options = opts
+	}
+}
+
+// sourceEmbedExtension is a Goldmark extension embedding on-chain source
+// files.
+type sourceEmbedExtension struct {
+	fetch   SourceFetchFunc
+	style   *chroma.Style
+	options []chromahtml.Option
+}
+
+// NewSourceEmbedExtension returns an extension replacing
+// ```gno-embed{path=gno.land/p/demo/avl file=avl.gno lines=10-40} blocks
+// with the highlighted lines of the given file, fetched with fetch. The
+// lines attribute is optional, and also accepts a single line or an open
+// range such as `10-`.
+func NewSourceEmbedExtension(fetch SourceFetchFunc, opts ...SourceEmbedOption) goldmark.Extender {
+	e := sourceEmbedExtension{fetch: fetch, style: styles.Fallback}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the source embed transformer and renderer to the provided
+// Goldmark markdown processor.
+func (e *sourceEmbedExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&sourceEmbedTransformer{fetch: e.fetch}, 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&sourceEmbedRenderer{style: e.style, options: e.options}, 500),
+	))
+}
@@ -0,0 +1,461 @@
+package markdown
+
+import (
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var KindEmoji = ast.NewNodeKind("Emoji")
+
+// Emoji represents an emoji from a `:name:` shortcode.
+type Emoji struct {
+	ast.BaseInline
+	Name  string
+	Value string
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *Emoji) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Name": n.Name, "Value": n.Value}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*Emoji) Kind() ast.NodeKind {
+	return KindEmoji
+}
+
+// reEmojiShortcode matches an emoji shortcode at the start of a line, such
+// as `:rocket:` or `:+1:`.
+var reEmojiShortcode = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+
+// emojiParser implements InlineParser, parsing the known shortcodes into
+// Emoji nodes. Unknown shortcodes are left as text.
+type emojiParser struct{}
+
+// Trigger returns the bytes that trigger this parser.
+func (p *emojiParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (p *emojiParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	match := reEmojiShortcode.FindSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	name := string(match[1])
+	value, ok := emojiShortcodes[name]
+	if !ok {
+		return nil
+	}
+
+	block.Advance(len(match[0]))
+	return &Emoji{Name: name, Value: value}
+}
+
+// emojiRenderer implements NodeRenderer.
+type emojiRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *emojiRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindEmoji, r.renderEmoji)
+}
+
+func (r *emojiRenderer) renderEmoji(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		w.WriteString(node.(*Emoji).Value)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// emojiExtension is a Goldmark extension handling emoji shortcodes.
+type emojiExtension struct{}
+
+// NewEmojiExtension returns an extension replacing the GitHub emoji
+// shortcodes of the built-in table, such as `:rocket:`, by their emoji.
+func NewEmojiExtension() goldmark.Extender {
+	return &emojiExtension{}
+}
+
+// Extend adds the emoji parser and renderer to the provided Goldmark
+// markdown processor.
+func (e *emojiExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(&emojiParser{}, 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&emojiRenderer{}, 500),
+	))
+}
+
+// emojiShortcodes maps the most used GitHub emoji shortcodes to their emoji.
+var emojiShortcodes = map[string]string{
+	// Smileys and people
+	"smile":                        "😄",
+	"smiley":                       "😃",
+	"grinning":                     "😀",
+	"grin":                         "😁",
+	"laughing":                     "😆",
+	"satisfied":                    "😆",
+	"sweat_smile":                  "😅",
+	"joy":                          "😂",
+	"rofl":                         "🤣",
+	"slightly_smiling_face":        "🙂",
+	"upside_down_face":             "🙃",
+	"wink":                         "😉",
+	"blush":                        "😊",
+	"innocent":                     "😇",
+	"heart_eyes":                   "😍",
+	"star_struck":                  "🤩",
+	"kissing_heart":                "😘",
+	"yum":                          "😋",
+	"stuck_out_tongue":             "😛",
+	"stuck_out_tongue_winking_eye": "😜",
+	"thinking":                     "🤔",
+	"neutral_face":                 "😐",
+	"expressionless":               "😑",
+	"no_mouth":                     "😶",
+	"smirk":                        "😏",
+	"unamused":                     "😒",
+	"roll_eyes":                    "🙄",
+	"grimacing":                    "😬",
+	"relieved":                     "😌",
+	"pensive":                      "😔",
+	"sleepy":                       "😪",
+	"sleeping":                     "😴",
+	"mask":                         "😷",
+	"nerd_face":                    "🤓",
+	"sunglasses":                   "😎",
+	"confused":                     "😕",
+	"worried":                      "😟",
+	"slightly_frowning_face":       "🙁",
+	"open_mouth":                   "😮",
+	"astonished":                   "😲",
+	"flushed":                      "😳",
+	"pleading_face":                "🥺",
+	"cry":                          "😢",
+	"sob":                          "😭",
+	"scream":                       "😱",
+	"disappointed":                 "😞",
+	"sweat":                        "😓",
+	"weary":                        "😩",
+	"triumph":                      "😤",
+	"rage":                         "😡",
+	"angry":                        "😠",
+	"skull":                        "💀",
+	"poop":                         "💩",
+	"hankey":                       "💩",
+	"clown_face":                   "🤡",
+	"ghost":                        "👻",
+	"alien":                        "👽",
+	"robot":                        "🤖",
+	"wave":                         "👋",
+	"raised_hand":                  "✋",
+	"ok_hand":                      "👌",
+	"v":                            "✌️",
+	"crossed_fingers":              "🤞",
+	"point_up":                     "☝️",
+	"point_down":                   "👇",
+	"point_left":                   "👈",
+	"point_right":                  "👉",
+	"+1":                           "👍",
+	"thumbsup":                     "👍",
+	"-1":                           "👎",
+	"thumbsdown":                   "👎",
+	"fist":                         "✊",
+	"clap":                         "👏",
+	"raised_hands":                 "🙌",
+	"open_hands":                   "👐",
+	"handshake":                    "🤝",
+	"pray":                         "🙏",
+	"muscle":                       "💪",
+	"eyes":                         "👀",
+	"brain":                        "🧠",
+	"bow":                          "🙇",
+	"facepalm":                     "🤦",
+	"shrug":                        "🤷",
+
+	// Hearts and symbols
+	"heart":                       "❤️",
+	"orange_heart":                "🧡",
+	"yellow_heart":                "💛",
+	"green_heart":                 "💚",
+	"blue_heart":                  "💙",
+	"purple_heart":                "💜",
+	"black_heart":                 "🖤",
+	"broken_heart":                "💔",
+	"sparkling_heart":             "💖",
+	"100":                         "💯",
+	"boom":                        "💥",
+	"collision":                   "💥",
+	"zap":                         "⚡",
+	"fire":                        "🔥",
+	"sparkles":                    "✨",
+	"star":                        "⭐",
+	"star2":                       "🌟",
+	"dizzy":                       "💫",
+	"zzz":                         "💤",
+	"speech_balloon":              "💬",
+	"thought_balloon":             "💭",
+	"white_check_mark":            "✅",
+	"heavy_check_mark":            "✔️",
+	"ballot_box_with_check":       "☑️",
+	"x":                           "❌",
+	"negative_squared_cross_mark": "❎",
+	"heavy_plus_sign":             "➕",
+	"heavy_minus_sign":            "➖",
+	"question":                    "❓",
+	"grey_question":               "❔",
+	"exclamation":                 "❗",
+	"heavy_exclamation_mark":      "❗",
+	"grey_exclamation":            "❕",
+	"bangbang":                    "‼️",
+	"warning":                     "⚠️",
+	"no_entry":                    "⛔",
+	"no_entry_sign":               "🚫",
+	"stop_sign":                   "🛑",
+	"construction":                "🚧",
+	"rotating_light":              "🚨",
+	"recycle":                     "♻️",
+	"information_source":          "ℹ️",
+	"new":                         "🆕",
+	"free":                        "🆓",
+	"up":                          "🆙",
+	"cool":                        "🆒",
+	"ok":                          "🆗",
+	"sos":                         "🆘",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"arrow_left":                  "⬅️",
+	"arrow_right":                 "➡️",
+	"arrows_counterclockwise":     "🔄",
+	"red_circle":                  "🔴",
+	"large_blue_circle":           "🔵",
+	"green_circle":                "🟢",
+	"yellow_circle":               "🟡",
+	"white_circle":                "⚪",
+	"black_circle":                "⚫",
+
+	// Objects and activities
+	"rocket":                     "🚀",
+	"tada":                       "🎉",
+	"confetti_ball":              "🎊",
+	"balloon":                    "🎈",
+	"gift":                       "🎁",
+	"trophy":                     "🏆",
+	"medal_sports":               "🏅",
+	"1st_place_medal":            "🥇",
+	"dart":                       "🎯",
+	"game_die":                   "🎲",
+	"video_game":                 "🎮",
+	"art":                        "🎨",
+	"musical_note":               "🎵",
+	"notes":                      "🎶",
+	"bell":                       "🔔",
+	"mega":                       "📣",
+	"loudspeaker":                "📢",
+	"bulb":                       "💡",
+	"flashlight":                 "🔦",
+	"book":                       "📖",
+	"books":                      "📚",
+	"memo":                       "📝",
+	"pencil":                     "📝",
+	"pencil2":                    "✏️",
+	"page_facing_up":             "📄",
+	"clipboard":                  "📋",
+	"pushpin":                    "📌",
+	"paperclip":                  "📎",
+	"link":                       "🔗",
+	"scroll":                     "📜",
+	"calendar":                   "📆",
+	"date":                       "📅",
+	"chart_with_upwards_trend":   "📈",
+	"chart_with_downwards_trend": "📉",
+	"bar_chart":                  "📊",
+	"package":                    "📦",
+	"mailbox":                    "📫",
+	"email":                      "📧",
+	"envelope":                   "✉️",
+	"inbox_tray":                 "📥",
+	"outbox_tray":                "📤",
+	"file_folder":                "📁",
+	"open_file_folder":           "📂",
+	"wastebasket":                "🗑️",
+	"lock":                       "🔒",
+	"unlock":                     "🔓",
+	"key":                        "🔑",
+	"old_key":                    "🗝️",
+	"hammer":                     "🔨",
+	"wrench":                     "🔧",
+	"gear":                       "⚙️",
+	"hammer_and_wrench":          "🛠️",
+	"nut_and_bolt":               "🔩",
+	"chains":                     "⛓️",
+	"shield":                     "🛡️",
+	"mag":                        "🔍",
+	"mag_right":                  "🔎",
+	"microscope":                 "🔬",
+	"telescope":                  "🔭",
+	"test_tube":                  "🧪",
+	"dna":                        "🧬",
+	"computer":                   "💻",
+	"desktop_computer":           "🖥️",
+	"keyboard":                   "⌨️",
+	"iphone":                     "📱",
+	"satellite":                  "📡",
+	"battery":                    "🔋",
+	"electric_plug":              "🔌",
+	"floppy_disk":                "💾",
+	"cd":                         "💿",
+	"camera":                     "📷",
+	"movie_camera":               "🎥",
+	"tv":                         "📺",
+	"alarm_clock":                "⏰",
+	"stopwatch":                  "⏱️",
+	"hourglass":                  "⌛",
+	"hourglass_flowing_sand":     "⏳",
+	"moneybag":                   "💰",
+	"dollar":                     "💵",
+	"coin":                       "🪙",
+	"gem":                        "💎",
+	"credit_card":                "💳",
+	"bank":                       "🏦",
+	"scales":                     "⚖️",
+	"ballot_box":                 "🗳️",
+	"label":                      "🏷️",
+	"bookmark":                   "🔖",
+	"triangular_flag_on_post":    "🚩",
+	"checkered_flag":             "🏁",
+	"crown":                      "👑",
+	"bug":                        "🐛",
+	"ant":                        "🐜",
+	"bee":                        "🐝",
+	"honeybee":                   "🐝",
+	"lady_beetle":                "🐞",
+	"beetle":                     "🐞",
+
+	// Nature and places
+	"sunny":                "☀️",
+	"cloud":                "☁️",
+	"partly_sunny":         "⛅",
+	"umbrella":             "☔",
+	"snowflake":            "❄️",
+	"rainbow":              "🌈",
+	"ocean":                "🌊",
+	"droplet":              "💧",
+	"earth_africa":         "🌍",
+	"earth_americas":       "🌎",
+	"earth_asia":           "🌏",
+	"globe_with_meridians": "🌐",
+	"crescent_moon":        "🌙",
+	"full_moon":            "🌕",
+	"new_moon":             "🌑",
+	"seedling":             "🌱",
+	"herb":                 "🌿",
+	"four_leaf_clover":     "🍀",
+	"evergreen_tree":       "🌲",
+	"deciduous_tree":       "🌳",
+	"palm_tree":            "🌴",
+	"cactus":               "🌵",
+	"mushroom":             "🍄",
+	"rose":                 "🌹",
+	"sunflower":            "🌻",
+	"tulip":                "🌷",
+	"cherry_blossom":       "🌸",
+	"fallen_leaf":          "🍂",
+	"maple_leaf":           "🍁",
+	"mountain":             "⛰️",
+	"volcano":              "🌋",
+	"house":                "🏠",
+	"office":               "🏢",
+	"classical_building":   "🏛️",
+	"factory":              "🏭",
+	"construction_worker":  "👷",
+	"airplane":             "✈️",
+	"car":                  "🚗",
+	"bike":                 "🚲",
+	"ship":                 "🚢",
+	"train":                "🚆",
+	"world_map":            "🗺️",
+	"compass":              "🧭",
+
+	// Animals
+	"dog":         "🐶",
+	"cat":         "🐱",
+	"mouse":       "🐭",
+	"rabbit":      "🐰",
+	"fox_face":    "🦊",
+	"bear":        "🐻",
+	"panda_face":  "🐼",
+	"koala":       "🐨",
+	"tiger":       "🐯",
+	"lion":        "🦁",
+	"cow":         "🐮",
+	"pig":         "🐷",
+	"frog":        "🐸",
+	"monkey":      "🐒",
+	"see_no_evil": "🙈",
+	"chicken":     "🐔",
+	"penguin":     "🐧",
+	"bird":        "🐦",
+	"eagle":       "🦅",
+	"owl":         "🦉",
+	"bat":         "🦇",
+	"wolf":        "🐺",
+	"horse":       "🐴",
+	"unicorn":     "🦄",
+	"turtle":      "🐢",
+	"snake":       "🐍",
+	"dragon":      "🐉",
+	"octopus":     "🐙",
+	"whale":       "🐳",
+	"dolphin":     "🐬",
+	"fish":        "🐟",
+	"shark":       "🦈",
+	"crab":        "🦀",
+	"snail":       "🐌",
+	"butterfly":   "🦋",
+	"spider":      "🕷️",
+	"hamster":     "🐹",
+
+	// Food and drink
+	"apple":       "🍎",
+	"green_apple": "🍏",
+	"banana":      "🍌",
+	"cherries":    "🍒",
+	"strawberry":  "🍓",
+	"grapes":      "🍇",
+	"lemon":       "🍋",
+	"watermelon":  "🍉",
+	"peach":       "🍑",
+	"avocado":     "🥑",
+	"carrot":      "🥕",
+	"corn":        "🌽",
+	"bread":       "🍞",
+	"cheese":      "🧀",
+	"pizza":       "🍕",
+	"hamburger":   "🍔",
+	"fries":       "🍟",
+	"taco":        "🌮",
+	"sushi":       "🍣",
+	"ramen":       "🍜",
+	"cookie":      "🍪",
+	"cake":        "🍰",
+	"birthday":    "🎂",
+	"doughnut":    "🍩",
+	"popcorn":     "🍿",
+	"coffee":      "☕",
+	"tea":         "🍵",
+	"beer":        "🍺",
+	"beers":       "🍻",
+	"wine_glass":  "🍷",
+	"champagne":   "🍾",
+	"cocktail":    "🍸",
+}
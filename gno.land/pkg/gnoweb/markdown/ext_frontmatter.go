@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/pelletier/go-toml"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Frontmatter holds the page metadata declared at the top of the markdown,
+// between `---` lines in YAML, or between `+++` lines in TOML.
+type Frontmatter struct {
+	Title       string `yaml:"title" toml:"title"`
+	Description string `yaml:"description" toml:"description"`
+	Image       string `yaml:"image" toml:"image"`
+}
+
+var frontmatterContextKey = parser.NewContextKey()
+
+// GetFrontmatter returns the frontmatter found while parsing with the given
+// context, if any.
+func GetFrontmatter(pc parser.Context) (fm Frontmatter, ok bool) {
+	fm, ok = pc.Get(frontmatterContextKey).(Frontmatter)
+	return
+}
+
+// parseFrontmatter decodes the given frontmatter content. The content must
+// be a mapping, so a page starting with a thematic break is not mistaken
+// for a frontmatter.
+func parseFrontmatter(delim string, src []byte) (Frontmatter, bool) {
+	var fm Frontmatter
+	switch delim {
+	case "---":
+		var node yaml.Node
+		if err := yaml.Unmarshal(src, &node); err != nil || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			return fm, false
+		}
+		if err := node.Decode(&fm); err != nil {
+			return fm, false
+		}
+	case "+++":
+		if err := toml.Unmarshal(src, &fm); err != nil {
+			return fm, false
+		}
+	default:
+		return fm, false
+	}
+	return fm, true
+}
+
+var KindFrontmatterBlock = ast.NewNodeKind("FrontmatterBlock")
+
+// FrontmatterBlock is the frontmatter of the page, which is not rendered.
+type FrontmatterBlock struct {
+	ast.BaseBlock
+	delim string
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *FrontmatterBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// Kind implements Node.Kind.
+func (*FrontmatterBlock) Kind() ast.NodeKind {
+	return KindFrontmatterBlock
+}
+
+// frontmatterParser implements BlockParser.
+type frontmatterParser struct{}
+
+// Trigger returns the bytes that trigger this parser.
+func (p *frontmatterParser) Trigger() []byte {
+	return []byte{'-', '+'}
+}
+
+// Open opens a FrontmatterBlock on a `---` or `+++` first line, if it is
+// closed by the same delimiter and holds a valid frontmatter.
+func (p *frontmatterParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if segment.Start != 0 || parent.Kind() != ast.KindDocument || parent.HasChildren() {
+		return nil, parser.NoChildren
+	}
+
+	delim := string(bytes.TrimRight(line, " \t\r\n"))
+	if delim != "---" && delim != "+++" {
+		return nil, parser.NoChildren
+	}
+
+	// Look ahead for the closing delimiter
+	source := reader.Source()
+	var content []byte
+	closed := false
+	for _, l := range bytes.SplitAfter(source[segment.Stop:], []byte("\n")) {
+		if string(bytes.TrimRight(l, " \t\r\n")) == delim {
+			closed = true
+			break
+		}
+		content = append(content, l...)
+	}
+	if !closed {
+		return nil, parser.NoChildren
+	}
+
+	fm, ok := parseFrontmatter(delim, content)
+	if !ok {
+		return nil, parser.NoChildren
+	}
+	pc.Set(frontmatterContextKey, fm)
+
+	advanceLine(reader)
+	return &FrontmatterBlock{delim: delim}, parser.NoChildren
+}
+
+// Continue consumes the frontmatter up to its closing delimiter.
+func (p *frontmatterParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+
+	delim := node.(*FrontmatterBlock).delim
+	advanceLine(reader)
+	if string(bytes.TrimRight(line, " \t\r\n")) == delim {
+		return parser.Close
+	}
+	return parser.Continue | parser.NoChildren
+}
+
+// Close implements BlockParser.
+func (p *frontmatterParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+// CanInterruptParagraph implements BlockParser.
+func (p *frontmatterParser) CanInterruptParagraph() bool {
+	return false
+}
+
+// CanAcceptIndentedLine implements BlockParser.
+func (p *frontmatterParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// frontmatterRenderer implements NodeRenderer, rendering nothing of the
+// frontmatter.
+type frontmatterRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *frontmatterRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindFrontmatterBlock, r.renderFrontmatter)
+}
+
+func (r *frontmatterRenderer) renderFrontmatter(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+// frontmatterExtension is a Goldmark extension handling page frontmatters.
+type frontmatterExtension struct{}
+
+// NewFrontmatterExtension returns an extension parsing the YAML or TOML
+// frontmatter at the top of pages, which can then be retrieved with
+// GetFrontmatter.
+func NewFrontmatterExtension() goldmark.Extender {
+	return &frontmatterExtension{}
+}
+
+// Extend adds the frontmatter parser and renderer to the provided Goldmark
+// markdown processor.
+func (e *frontmatterExtension) Extend(m goldmark.Markdown) {
+	// Take precedence over thematic breaks
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(&frontmatterParser{}, 0),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&frontmatterRenderer{}, 500),
+	))
+}
@@ -0,0 +1,191 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultHeadingSpamRatio is the default maximum number of headings per
+// word of body text.
+const DefaultHeadingSpamRatio = 0.2
+
+// headingSpamMinHeadings is the number of headings below which a page is
+// never considered as padded with headings.
+const headingSpamMinHeadings = 8
+
+var KindHeadingSpamWarning = ast.NewNodeKind("HeadingSpamWarning")
+
+// HeadingSpamWarning flags a page with too many headings for its body text.
+type HeadingSpamWarning struct {
+	ast.BaseBlock
+	Headings int
+	Words    int
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *HeadingSpamWarning) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Headings": strconv.Itoa(n.Headings),
+		"Words":    strconv.Itoa(n.Words),
+	}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*HeadingSpamWarning) Kind() ast.NodeKind {
+	return KindHeadingSpamWarning
+}
+
+// countWords returns the number of words of the text nodes under n.
+func countWords(n ast.Node, source []byte) int {
+	var words int
+	ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Text:
+			words += len(bytes.Fields(n.Segment.Value(source)))
+		case *ast.String:
+			words += len(bytes.Fields(n.Value))
+		}
+		return ast.WalkContinue, nil
+	})
+	return words
+}
+
+// headingSpamTransformer implements ASTTransformer, detecting pages whose
+// heading to body text ratio exceeds the configured one. Such pages are
+// either flagged with a HeadingSpamWarning node, or have their headings
+// collapsed into plain paragraphs, except the first one.
+type headingSpamTransformer struct {
+	ratio    float64
+	collapse bool
+}
+
+func (t *headingSpamTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var (
+		headings []*ast.Heading
+		words    int
+	)
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Heading:
+			headings = append(headings, n)
+			return ast.WalkSkipChildren, nil
+		case *ast.Text, *ast.String:
+			words += countWords(n, source)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if len(headings) < headingSpamMinHeadings ||
+		float64(len(headings)) <= t.ratio*float64(words) {
+		return
+	}
+
+	if !t.collapse {
+		doc.InsertBefore(doc, doc.FirstChild(), &HeadingSpamWarning{
+			Headings: len(headings),
+			Words:    words,
+		})
+		return
+	}
+
+	for _, h := range headings[1:] {
+		parent := h.Parent()
+		if countWords(h, source) == 0 {
+			parent.RemoveChild(parent, h)
+			continue
+		}
+
+		p := ast.NewParagraph()
+		for c := h.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			p.AppendChild(p, c)
+			c = next
+		}
+		parent.ReplaceChild(parent, h, p)
+	}
+}
+
+// headingSpamRenderer implements NodeRenderer.
+type headingSpamRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *headingSpamRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindHeadingSpamWarning, r.renderHeadingSpamWarning)
+}
+
+func (r *headingSpamRenderer) renderHeadingSpamWarning(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*HeadingSpamWarning)
+		w.WriteString(`<div class="gno-heading-warning" role="note">`)
+		w.WriteString("This page has " + strconv.Itoa(n.Headings) + " headings for " + strconv.Itoa(n.Words) + " words of text.")
+		w.WriteString("</div>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// HeadingSpamOption configures the heading spam guard extension.
+type HeadingSpamOption func(e *headingSpamExtension)
+
+// WithHeadingSpamRatio sets the maximum number of headings per word of body
+// text. A zero ratio means DefaultHeadingSpamRatio.
+func WithHeadingSpamRatio(ratio float64) HeadingSpamOption {
+	return func(e *headingSpamExtension) {
+		if ratio > 0 {
+			e.ratio = ratio
+		}
+	}
+}
+
+// WithHeadingSpamCollapse collapses the headings of offending pages into
+// paragraphs instead of flagging them.
+func WithHeadingSpamCollapse() HeadingSpamOption {
+	return func(e *headingSpamExtension) {
+		e.collapse = true
+	}
+}
+
+// headingSpamExtension is a Goldmark extension guarding against pages padded
+// with headings.
+type headingSpamExtension struct {
+	ratio    float64
+	collapse bool
+}
+
+// NewHeadingSpamGuardExtension returns an extension detecting pages with too
+// many headings for their body text. By default, such pages are flagged
+// with a visible warning, meant for development and moderation.
+func NewHeadingSpamGuardExtension(opts ...HeadingSpamOption) goldmark.Extender {
+	e := headingSpamExtension{ratio: DefaultHeadingSpamRatio}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the heading spam transformer and renderer to the provided
+// Goldmark markdown processor.
+func (e *headingSpamExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&headingSpamTransformer{ratio: e.ratio, collapse: e.collapse}, 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&headingSpamRenderer{}, 500),
+	))
+}
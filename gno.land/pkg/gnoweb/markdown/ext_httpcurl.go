@@ -0,0 +1,196 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// HTTPLanguage is the fenced code block language used for HTTP requests.
+const HTTPLanguage = "http"
+
+var ErrHTTPRequestInvalid = errors.New("invalid http request")
+
+var KindHTTPCurl = ast.NewNodeKind("HTTPCurl")
+
+// HTTPCurl holds the curl command equivalent to the preceding ```http
+// block.
+type HTTPCurl struct {
+	ast.BaseBlock
+	Command string
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *HTTPCurl) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Command": n.Command}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*HTTPCurl) Kind() ast.NodeKind {
+	return KindHTTPCurl
+}
+
+// httpMethods lists the request methods accepted in ```http blocks.
+var httpMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true,
+	"PATCH": true, "DELETE": true, "OPTIONS": true,
+}
+
+// HTTPToCurl converts a simple HTTP request description, made of a request
+// line followed by headers, an empty line and an optional body, into the
+// equivalent curl command. Request targets that are not absolute URLs are
+// resolved against the `Host` header, over https.
+func HTTPToCurl(req string) (string, error) {
+	head, body, _ := strings.Cut(strings.ReplaceAll(req, "\r\n", "\n"), "\n\n")
+
+	lines := strings.Split(strings.TrimSpace(head), "\n")
+	fields := strings.Fields(lines[0])
+	if len(fields) < 2 || len(fields) > 3 {
+		return "", fmt.Errorf("%w: expected `<method> <url> [<version>]`", ErrHTTPRequestInvalid)
+	}
+
+	method, target := fields[0], fields[1]
+	if !httpMethods[method] {
+		return "", fmt.Errorf("%w: unknown method %q", ErrHTTPRequestInvalid, method)
+	}
+	if len(fields) == 3 && !strings.HasPrefix(fields[2], "HTTP/") {
+		return "", fmt.Errorf("%w: unknown version %q", ErrHTTPRequestInvalid, fields[2])
+	}
+
+	var host string
+	headers := make([]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return "", fmt.Errorf("%w: malformed header %q", ErrHTTPRequestInvalid, line)
+		}
+		if strings.EqualFold(name, "Host") {
+			host = value
+			continue
+		}
+		headers = append(headers, name+": "+value)
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrHTTPRequestInvalid, err)
+	}
+	switch {
+	case u.Scheme == "http" || u.Scheme == "https":
+	case u.Scheme == "" && u.Host == "" && strings.HasPrefix(target, "/") && host != "":
+		u.Scheme, u.Host = "https", host
+	default:
+		return "", fmt.Errorf("%w: expected an absolute URL or a `Host` header", ErrHTTPRequestInvalid)
+	}
+
+	args := []string{"curl"}
+	if method != "GET" {
+		args = append(args, "-X "+method)
+	}
+	args = append(args, shellQuote(u.String()))
+	for _, h := range headers {
+		args = append(args, "-H "+shellQuote(h))
+	}
+	if body = strings.TrimRight(body, "\n"); body != "" {
+		args = append(args, "--data-raw "+shellQuote(body))
+	}
+
+	return strings.Join(args, " \\\n  "), nil
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// httpCurlTransformer implements ASTTransformer, appending an HTTPCurl node
+// after each valid ```http fenced code block. The block itself is left
+// untouched, so it is still highlighted.
+type httpCurlTransformer struct{}
+
+func (t *httpCurlTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			if string(fcb.Language(source)) == HTTPLanguage {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, fcb := range blocks {
+		var src bytes.Buffer
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			src.Write(seg.Value(source))
+		}
+
+		cmd, err := HTTPToCurl(src.String())
+		if err != nil {
+			continue // rendered as a plain code block
+		}
+
+		parent := fcb.Parent()
+		parent.InsertAfter(parent, fcb, &HTTPCurl{Command: cmd})
+	}
+}
+
+// httpCurlRenderer implements NodeRenderer.
+type httpCurlRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *httpCurlRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindHTTPCurl, r.renderHTTPCurl)
+}
+
+func (r *httpCurlRenderer) renderHTTPCurl(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*HTTPCurl)
+	w.WriteString(`<div class="gno-http-curl">`)
+	w.WriteString(`<button class="b-inline-btn c-with-icon" data-controller="copy" data-action="click->copy#copy" data-copy-text-value="`)
+	w.WriteString(HTMLEscapeString(n.Command))
+	w.WriteString(`" aria-label="Copy as curl">`)
+	w.WriteString(`<svg class="c-icon"><use href="#ico-copy" data-copy-target="icon"></use>`)
+	w.WriteString(`<use href="#ico-check" class="u-hidden u-color-valid" data-copy-target="icon"></use></svg>`)
+	w.WriteString(`<span>Copy as curl</span></button></div>` + "\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+// httpCurlExtension is a Goldmark extension adding a "copy as curl" button
+// to ```http request blocks.
+type httpCurlExtension struct{}
+
+// NewHTTPCurlExtension returns a new extension rendering a "copy as curl"
+// button below each valid ```http request block.
+func NewHTTPCurlExtension() goldmark.Extender {
+	return &httpCurlExtension{}
+}
+
+// Extend adds the http transformer and renderer to the provided Goldmark
+// markdown processor.
+func (e *httpCurlExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(util.Prioritized(&httpCurlTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&httpCurlRenderer{}, 500),
+	))
+}
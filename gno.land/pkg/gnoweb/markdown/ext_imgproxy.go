@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// imgProxyTransformer implements ASTTransformer, rewriting the destination
+// of remote images through the proxy.
+type imgProxyTransformer struct {
+	proxy string
+	sign  func(url string) string
+}
+
+func (t *imgProxyTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		img, ok := node.(*ast.Image)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		dest := string(img.Destination)
+		if strings.HasPrefix(dest, "https://") || strings.HasPrefix(dest, "http://") {
+			proxied := t.proxy + "?url=" + url.QueryEscape(dest)
+			if t.sign != nil {
+				proxied += "&sig=" + url.QueryEscape(t.sign(dest))
+			}
+			img.Destination = []byte(proxied)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// imgProxyExtension is a Goldmark extension proxying remote images.
+type imgProxyExtension struct {
+	proxy string
+	sign  func(url string) string
+}
+
+// ImageProxyOption configures the image proxy extension.
+type ImageProxyOption func(*imgProxyExtension)
+
+// WithImageProxySigner adds the signature of each image URL returned by sign
+// as `sig` query parameter, so that the proxy only serves the images of
+// rendered pages.
+func WithImageProxySigner(sign func(url string) string) ImageProxyOption {
+	return func(e *imgProxyExtension) {
+		e.sign = sign
+	}
+}
+
+// NewImageProxyExtension returns an extension rewriting the http(s) images
+// to the given proxy path, with their URL as `url` query parameter, so that
+// readers do not fetch them from their origin.
+func NewImageProxyExtension(proxy string, opts ...ImageProxyOption) goldmark.Extender {
+	e := &imgProxyExtension{proxy: proxy}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Extend adds the image proxy transformer to the provided Goldmark markdown
+// processor.
+func (e *imgProxyExtension) Extend(m goldmark.Markdown) {
+	// Run after the image validator, which may erase destinations
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&imgProxyTransformer{proxy: e.proxy, sign: e.sign}, 510),
+	))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/yuin/goldmark"
@@ -113,7 +114,9 @@ func (t *linkTransformer) Transform(doc *ast.Document, reader text.Reader, pc pa
 		parent.RemoveChild(parent, node)
 		parent.InsertBefore(parent, next, gnoLink)
 
-		// Parse destination URL and check for validity.
+		// Resolve links relative to the current package, then parse
+		// destination URL and check for validity.
+		link.Destination = []byte(resolveRelativeDest(string(link.Destination), &orig))
 		dest, err := url.Parse(string(link.Destination))
 		if err != nil {
 			gnoLink.LinkType = GnoLinkTypeInvalid
@@ -127,6 +130,23 @@ func (t *linkTransformer) Transform(doc *ast.Document, reader text.Reader, pc pa
 	})
 }
 
+// resolveRelativeDest resolves the `./` and `../` relative destinations
+// against the package path of the origin, taken as a directory, so that
+// `./subpage` on /r/demo/foo links to /r/demo/foo/subpage and `../other`
+// to /r/demo/other. Other destinations are returned as is.
+func resolveRelativeDest(dest string, orig *weburl.GnoURL) string {
+	if orig.Path == "" || !(strings.HasPrefix(dest, "./") || strings.HasPrefix(dest, "../")) {
+		return dest
+	}
+
+	ref, err := url.Parse(dest)
+	if err != nil {
+		return dest
+	}
+	base := &url.URL{Path: strings.TrimSuffix(orig.Path, "/") + "/"}
+	return base.ResolveReference(ref).String()
+}
+
 // detectLinkType detects the type of link based on the destination
 func detectLinkType(dest *url.URL, orig *weburl.GnoURL) (*weburl.GnoURL, GnoLinkType) {
 	// Attempt to parse the destination as a GnoURL.
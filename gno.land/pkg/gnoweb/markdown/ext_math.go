@@ -0,0 +1,260 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MathLanguage is the fenced code block language used for display math.
+const MathLanguage = "math"
+
+// DefaultKaTeXURL is the default base URL of the KaTeX runtime, holding
+// `katex.min.js` and `katex.min.css`.
+const DefaultKaTeXURL = "https://cdn.jsdelivr.net/npm/katex@0.16.11/dist"
+
+var KindMath = ast.NewNodeKind("Math")
+
+// Math represents an inline (`$...$`) math expression.
+type Math struct {
+	ast.BaseInline
+	Tex     []byte
+	Display bool // `$$...$$`
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Tex": string(n.Tex)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*Math) Kind() ast.NodeKind {
+	return KindMath
+}
+
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+// MathBlock represents a display math expression from a ```math block.
+type MathBlock struct {
+	ast.BaseBlock
+	Tex []byte
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Tex": string(n.Tex)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+// mathParser implements InlineParser for `$...$` and `$$...$$` expressions.
+type mathParser struct{}
+
+// Trigger returns the bytes that trigger this parser.
+func (p *mathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+
+	delim := []byte("$")
+	if bytes.HasPrefix(line, []byte("$$")) {
+		delim = []byte("$$")
+	}
+
+	content := line[len(delim):]
+	end := bytes.Index(content, delim)
+	if end <= 0 {
+		return nil
+	}
+
+	tex := content[:end]
+	if bytes.IndexByte(tex, '`') >= 0 {
+		return nil // do not cross code spans
+	}
+
+	after := content[end+len(delim):]
+	if len(delim) == 1 {
+		// Avoid false positives such as prices: `$5 and $6`
+		if before := block.PrecendingCharacter(); unicode.IsLetter(before) || unicode.IsDigit(before) {
+			return nil
+		}
+		if isSpace(tex[0]) || isSpace(tex[len(tex)-1]) {
+			return nil
+		}
+		if len(after) > 0 && after[0] >= '0' && after[0] <= '9' {
+			return nil
+		}
+	}
+
+	block.Advance(len(delim)*2 + end)
+	return &Math{Tex: bytes.Clone(tex), Display: len(delim) == 2}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// mathTransformer implements ASTTransformer, converting ```math fenced code
+// blocks into MathBlock nodes.
+type mathTransformer struct{}
+
+func (t *mathTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			if string(fcb.Language(source)) == MathLanguage {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, fcb := range blocks {
+		var tex bytes.Buffer
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			tex.Write(seg.Value(source))
+		}
+
+		mb := &MathBlock{Tex: bytes.TrimSpace(tex.Bytes())}
+		parent := fcb.Parent()
+		parent.ReplaceChild(parent, fcb, mb)
+	}
+}
+
+// mathRenderer implements NodeRenderer.
+type mathRenderer struct {
+	serverRender bool
+	katexURL     string
+	cache        *BlockCache
+}
+
+// RegisterFuncs registers the renderer functions.
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *mathRenderer) renderMath(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*Math)
+		r.writeMath(w, n.Tex, n.Display, "span")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*MathBlock)
+		r.writeMath(w, n.Tex, true, "div")
+		w.WriteByte('\n')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// writeMath writes the given expression either as MathML (server-side
+// rendering), or as markup suitable for a client-side math runtime.
+func (r *mathRenderer) writeMath(w util.BufWriter, tex []byte, display bool, tag string) {
+	mode := "inline"
+	if display {
+		mode = "block"
+	}
+
+	if !r.serverRender {
+		w.WriteString("<" + tag + ` class="gno-math" data-math-display="` + mode + `"`)
+		if r.katexURL != "" {
+			w.WriteString(` data-controller="math" data-math-katex-value="` + HTMLEscapeString(r.katexURL) + `"`)
+		}
+		w.WriteString(">")
+		w.WriteString(HTMLEscapeString(string(tex)))
+		w.WriteString("</" + tag + ">")
+		return
+	}
+
+	cid := BlockCID("math", []byte(mode), []byte(tag), tex)
+	r.cache.render(w, cid, func(w util.BufWriter) error {
+		mathml, err := TexToMathML(string(tex), display)
+		if err != nil {
+			// Render the raw source with an error marker
+			w.WriteString("<" + tag + ` class="gno-math gno-math-error" title="` + HTMLEscapeString(err.Error()) + `">`)
+			w.WriteString(HTMLEscapeString(string(tex)))
+			w.WriteString("</" + tag + ">")
+			return nil
+		}
+
+		w.WriteString(mathml)
+		return nil
+	})
+}
+
+// MathOption configures the math extension.
+type MathOption func(e *mathExtension)
+
+// WithMathServerRender renders math expressions to MathML on the server,
+// so they display without JavaScript.
+func WithMathServerRender(enabled bool) MathOption {
+	return func(e *mathExtension) {
+		e.serverRender = enabled
+	}
+}
+
+// WithMathKaTeX sets the base URL of the KaTeX runtime rendering math
+// expressions on the client, when they are not rendered on the server.
+func WithMathKaTeX(url string) MathOption {
+	return func(e *mathExtension) {
+		e.katexURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithMathCache caches the expressions rendered on the server in the given
+// cache.
+func WithMathCache(c *BlockCache) MathOption {
+	return func(e *mathExtension) {
+		e.cache = c
+	}
+}
+
+// mathExtension is a Goldmark extension handling `$...$` inline and ```math
+// block expressions.
+type mathExtension struct {
+	serverRender bool
+	katexURL     string
+	cache        *BlockCache
+}
+
+// NewMathExtension returns a new math extension. By default, expressions are
+// rendered as markup for a client-side math runtime.
+func NewMathExtension(opts ...MathOption) goldmark.Extender {
+	var e mathExtension
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the math parser, transformer and renderer to the provided
+// Goldmark markdown processor.
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&mathParser{}, 500)),
+		parser.WithASTTransformers(util.Prioritized(&mathTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&mathRenderer{serverRender: e.serverRender, katexURL: e.katexURL, cache: e.cache}, 500),
+	))
+}
@@ -0,0 +1,188 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MermaidLanguage is the fenced code block language used for diagrams.
+const MermaidLanguage = "mermaid"
+
+// DefaultMermaidRuntimeURL is the default mermaid runtime used to render
+// diagrams on the client.
+const DefaultMermaidRuntimeURL = "https://cdn.jsdelivr.net/npm/mermaid@11.4.1/dist/mermaid.min.js"
+
+var ErrMermaidInvalid = errors.New("invalid mermaid diagram")
+
+// mermaidDiagramTypes lists the keywords starting mermaid diagrams.
+var mermaidDiagramTypes = map[string]bool{
+	"graph": true, "flowchart": true, "sequenceDiagram": true,
+	"classDiagram": true, "stateDiagram": true, "stateDiagram-v2": true,
+	"erDiagram": true, "journey": true, "gantt": true, "pie": true,
+	"gitGraph": true, "mindmap": true, "timeline": true,
+	"quadrantChart": true, "requirementDiagram": true, "C4Context": true,
+	"sankey-beta": true, "xychart-beta": true, "block-beta": true,
+}
+
+var KindMermaidBlock = ast.NewNodeKind("MermaidBlock")
+
+// MermaidBlock represents a diagram from a ```mermaid block.
+type MermaidBlock struct {
+	ast.BaseBlock
+	Source []byte
+	Err    error
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *MermaidBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Source": string(n.Source)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*MermaidBlock) Kind() ast.NodeKind {
+	return KindMermaidBlock
+}
+
+// mermaidTransformer implements ASTTransformer, converting ```mermaid
+// fenced code blocks into MermaidBlock nodes.
+type mermaidTransformer struct{}
+
+func (t *mermaidTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			if string(fcb.Language(source)) == MermaidLanguage {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, fcb := range blocks {
+		var src bytes.Buffer
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			src.Write(seg.Value(source))
+		}
+
+		mb := &MermaidBlock{Source: bytes.TrimSpace(src.Bytes())}
+		mb.Err = validateMermaid(string(mb.Source))
+		parent := fcb.Parent()
+		parent.ReplaceChild(parent, fcb, mb)
+	}
+}
+
+// validateMermaid checks that the diagram starts with a known diagram type,
+// after the optional front matter, directives and comments.
+func validateMermaid(src string) error {
+	lines := strings.Split(src, "\n")
+
+	// Skip the front matter
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		end := 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "---" {
+			end++
+		}
+		if end == len(lines) {
+			return fmt.Errorf("%w: unterminated front matter", ErrMermaidInvalid)
+		}
+		lines = lines[end+1:]
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue // blank lines, comments and directives
+		}
+
+		if fields := strings.Fields(line); !mermaidDiagramTypes[fields[0]] {
+			return fmt.Errorf("%w: unknown diagram type %q", ErrMermaidInvalid, fields[0])
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: empty diagram", ErrMermaidInvalid)
+}
+
+// mermaidRenderer implements NodeRenderer.
+type mermaidRenderer struct {
+	runtimeURL string
+}
+
+// RegisterFuncs registers the renderer functions.
+func (r *mermaidRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMermaidBlock, r.renderMermaidBlock)
+}
+
+func (r *mermaidRenderer) renderMermaidBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*MermaidBlock)
+	src := HTMLEscapeString(string(n.Source))
+	switch {
+	case n.Err != nil:
+		w.WriteString(`<div class="gno-mermaid gno-mermaid-error" role="alert">`)
+		w.WriteString(`<p>` + HTMLEscapeString(n.Err.Error()) + `</p>`)
+		w.WriteString(`<pre><code>` + src + `</code></pre></div>` + "\n")
+	case r.runtimeURL == "":
+		// No runtime: display the diagram source
+		w.WriteString(`<div class="gno-mermaid"><pre><code>` + src + `</code></pre></div>` + "\n")
+	default:
+		w.WriteString(`<div class="gno-mermaid" data-controller="mermaid" data-mermaid-runtime-value="` + HTMLEscapeString(r.runtimeURL) + `">`)
+		w.WriteString(`<pre data-mermaid-target="source"><code>` + src + `</code></pre></div>` + "\n")
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+// MermaidOption configures the mermaid extension.
+type MermaidOption func(e *mermaidExtension)
+
+// WithMermaidRuntimeURL sets the URL of the mermaid runtime rendering
+// diagrams on the client. An empty URL displays the diagram source only.
+func WithMermaidRuntimeURL(url string) MermaidOption {
+	return func(e *mermaidExtension) {
+		e.runtimeURL = url
+	}
+}
+
+// mermaidExtension is a Goldmark extension handling ```mermaid diagram
+// blocks.
+type mermaidExtension struct {
+	runtimeURL string
+}
+
+// NewMermaidExtension returns a new mermaid extension. Diagrams are checked
+// on the server and rendered to SVG on the client.
+func NewMermaidExtension(opts ...MermaidOption) goldmark.Extender {
+	e := mermaidExtension{runtimeURL: DefaultMermaidRuntimeURL}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the mermaid transformer and renderer to the provided Goldmark
+// markdown processor.
+func (e *mermaidExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(util.Prioritized(&mermaidTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&mermaidRenderer{runtimeURL: e.runtimeURL}, 500),
+	))
+}
@@ -0,0 +1,581 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// PflowLanguage is the fenced code block language used for Petri nets.
+const PflowLanguage = "pflow"
+
+var ErrPflowInvalid = errors.New("invalid pflow model")
+
+// PflowModel is a Petri net, in the JSON format of pflow.xyz.
+type PflowModel struct {
+	ModelType   string                     `json:"modelType"`
+	Version     string                     `json:"version"`
+	Places      map[string]PflowPlace      `json:"places"`
+	Transitions map[string]PflowTransition `json:"transitions"`
+	Arcs        []PflowArc                 `json:"arcs"`
+}
+
+// PflowPlace is a place of a Petri net, holding tokens.
+type PflowPlace struct {
+	Offset   int `json:"offset"`
+	Initial  int `json:"initial"`
+	Capacity int `json:"capacity"` // zero means unbounded
+	X        int `json:"x"`
+	Y        int `json:"y"`
+}
+
+// PflowTransition is a transition of a Petri net, moving tokens between
+// places when fired.
+type PflowTransition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// PflowArc connects a place to a transition, or a transition to a place.
+// Inhibitor arcs disable their transition while the place holds tokens.
+type PflowArc struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Weight  int    `json:"weight"`
+	Inhibit bool   `json:"inhibit"`
+}
+
+const (
+	// maxPflowNodes bounds the number of places and transitions of models.
+	maxPflowNodes = 256
+	// maxPflowArcs bounds the number of arcs of models.
+	maxPflowArcs = 1024
+	// maxPflowCoord bounds the absolute coordinates of nodes.
+	maxPflowCoord = 10000
+	// maxPflowIDLength bounds the length of node ids.
+	maxPflowIDLength = 64
+)
+
+// ParsePflowModel parses and validates the JSON model of a Petri net:
+// unknown fields, dangling or invalid arcs and out of range values are
+// rejected.
+func ParsePflowModel(src []byte) (*PflowModel, error) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.DisallowUnknownFields()
+
+	var model PflowModel
+	if err := dec.Decode(&model); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPflowInvalid, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: unexpected content after the model", ErrPflowInvalid)
+	}
+
+	if err := model.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPflowInvalid, err)
+	}
+
+	return &model, nil
+}
+
+// validate checks the consistency of the model.
+func (m *PflowModel) validate() error {
+	if m.ModelType != "" && m.ModelType != "petriNet" {
+		return fmt.Errorf("unsupported model type %q", m.ModelType)
+	}
+	if n := len(m.Places) + len(m.Transitions); n > maxPflowNodes {
+		return fmt.Errorf("too many places and transitions (%d > %d)", n, maxPflowNodes)
+	}
+	if len(m.Arcs) > maxPflowArcs {
+		return fmt.Errorf("too many arcs (%d > %d)", len(m.Arcs), maxPflowArcs)
+	}
+
+	checkNode := func(kind, id string, x, y int) error {
+		if id == "" || len(id) > maxPflowIDLength {
+			return fmt.Errorf("%s %q: id must be 1 to %d bytes long", kind, id, maxPflowIDLength)
+		}
+		if x < -maxPflowCoord || x > maxPflowCoord || y < -maxPflowCoord || y > maxPflowCoord {
+			return fmt.Errorf("%s %q: coordinates out of range", kind, id)
+		}
+		return nil
+	}
+	for id, p := range m.Places {
+		if err := checkNode("place", id, p.X, p.Y); err != nil {
+			return err
+		}
+		if p.Initial < 0 || p.Capacity < 0 {
+			return fmt.Errorf("place %q: negative tokens or capacity", id)
+		}
+		if p.Capacity > 0 && p.Initial > p.Capacity {
+			return fmt.Errorf("place %q: initial tokens exceed the capacity", id)
+		}
+	}
+	for id, t := range m.Transitions {
+		if _, ok := m.Places[id]; ok {
+			return fmt.Errorf("transition %q: id already used by a place", id)
+		}
+		if err := checkNode("transition", id, t.X, t.Y); err != nil {
+			return err
+		}
+	}
+
+	for i, arc := range m.Arcs {
+		if !m.hasNode(arc.Source) {
+			return fmt.Errorf("arc %d: unknown source %q", i, arc.Source)
+		}
+		if !m.hasNode(arc.Target) {
+			return fmt.Errorf("arc %d: unknown target %q", i, arc.Target)
+		}
+		_, fromPlace := m.Places[arc.Source]
+		_, toPlace := m.Places[arc.Target]
+		if fromPlace == toPlace {
+			return fmt.Errorf("arc %d: must connect a place and a transition", i)
+		}
+		if arc.Weight < 0 {
+			return fmt.Errorf("arc %d: negative weight", i)
+		}
+	}
+
+	return nil
+}
+
+// hasNode reports whether id is a place or a transition of the model.
+func (m *PflowModel) hasNode(id string) bool {
+	_, place := m.Places[id]
+	_, transition := m.Transitions[id]
+	return place || transition
+}
+
+// position returns the coordinates of the given place or transition.
+func (m *PflowModel) position(id string) (x, y int) {
+	if p, ok := m.Places[id]; ok {
+		return p.X, p.Y
+	}
+	t := m.Transitions[id]
+	return t.X, t.Y
+}
+
+var KindPflowBlock = ast.NewNodeKind("PflowBlock")
+
+// PflowBlock represents a Petri net from a ```pflow block, or from a run of
+// adjacent ```pflow compose=<group> blocks of the same group composed into a
+// single net.
+type PflowBlock struct {
+	ast.BaseBlock
+	ID     string // unique within the document, prefixing the ids of the image
+	Group  string // composition group, if any
+	Source []byte
+	Model  *PflowModel
+	Err    error
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *PflowBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"ID": n.ID, "Group": n.Group, "Source": string(n.Source)}, nil)
+}
+
+// Kind implements Node.Kind.
+func (*PflowBlock) Kind() ast.NodeKind {
+	return KindPflowBlock
+}
+
+// pflowComposeOption is the fence option composing adjacent blocks of the
+// same group, such as ```pflow compose=traffic.
+const pflowComposeOption = "compose="
+
+// pflowComposeGroup returns the composition group of the given info string
+// of a ```pflow block, if any.
+func pflowComposeGroup(info []byte) string {
+	for _, field := range strings.Fields(string(info))[1:] {
+		if group, ok := strings.CutPrefix(field, pflowComposeOption); ok {
+			return group
+		}
+	}
+	return ""
+}
+
+// pflowTransformer implements ASTTransformer, converting ```pflow fenced
+// code blocks into PflowBlock nodes. Adjacent blocks of the same
+// composition group are replaced by a single node of their composed net.
+type pflowTransformer struct{}
+
+func (t *pflowTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if fcb, ok := node.(*ast.FencedCodeBlock); ok && entering {
+			if string(fcb.Language(source)) == PflowLanguage {
+				blocks = append(blocks, fcb)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	ids := map[string]int{}
+	var last *PflowBlock
+	var parts [][]byte // sources of the nets composed by last
+	done := func() {
+		if last != nil {
+			last.Model, last.Err = parsePflowParts(parts)
+			last.ID = pflowBlockID(ids, last.Source)
+		}
+	}
+	for _, fcb := range blocks {
+		var src bytes.Buffer
+		lines := fcb.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			src.Write(seg.Value(source))
+		}
+		part := bytes.TrimSpace(src.Bytes())
+		group := pflowComposeGroup(fcb.Info.Segment.Value(source))
+
+		parent := fcb.Parent()
+		if group != "" && last != nil && last.Group == group && fcb.PreviousSibling() == last {
+			last.Source = append(append(last.Source, "\n\n"...), part...)
+			parts = append(parts, part)
+			parent.RemoveChild(parent, fcb)
+			continue
+		}
+
+		done()
+		last = &PflowBlock{Group: group, Source: slices.Clone(part)}
+		parts = [][]byte{part}
+		parent.ReplaceChild(parent, fcb, last)
+	}
+	done()
+}
+
+// parsePflowParts parses the given nets, and composes them if there are
+// several of them.
+func parsePflowParts(parts [][]byte) (*PflowModel, error) {
+	models := make([]*PflowModel, len(parts))
+	for i, part := range parts {
+		m, err := ParsePflowModel(part)
+		if err != nil {
+			if len(parts) > 1 {
+				return nil, fmt.Errorf("net %d: %w", i+1, err)
+			}
+			return nil, err
+		}
+		models[i] = m
+	}
+	if len(models) == 1 {
+		return models[0], nil
+	}
+	return ComposePflowModels(models...)
+}
+
+// pflowBlockID returns an id derived from the block source, so that it is
+// stable across renders, suffixed with its rank among identical blocks of
+// the document.
+func pflowBlockID(ids map[string]int, source []byte) string {
+	id := "pflow-" + BlockCID(PflowLanguage, source)[:12]
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += "-" + strconv.Itoa(n)
+	}
+	return id
+}
+
+const (
+	pflowPlaceRadius    = 16 // radius of places
+	pflowTransitionSize = 30 // side of transitions
+	pflowMargin         = 40 // margin around the net, room for labels
+)
+
+// pflowRenderer implements NodeRenderer.
+type pflowRenderer struct {
+	assets   pflowAssets
+	simulate string
+	cache    *BlockCache
+}
+
+// RegisterFuncs registers the renderer functions.
+func (r *pflowRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindPflowBlock, r.renderPflowBlock)
+}
+
+func (r *pflowRenderer) renderPflowBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*PflowBlock)
+	if n.Err != nil {
+		w.WriteString(`<div class="gno-pflow gno-pflow-error" role="alert">`)
+		w.WriteString(`<p>` + HTMLEscapeString(n.Err.Error()) + `</p>`)
+		w.WriteString(`<pre><code>` + HTMLEscapeString(string(n.Source)) + `</code></pre></div>` + "\n")
+		return ast.WalkSkipChildren, nil
+	}
+
+	a := r.assets
+	cid := BlockCID(PflowLanguage, []byte(a.cdn), []byte(a.fallback), []byte(a.jsIntegrity), []byte(a.cssIntegrity),
+		[]byte(r.simulate), []byte(n.ID), n.Source)
+	if err := r.cache.render(w, cid, func(w util.BufWriter) error {
+		return r.writePflow(w, n.ID, n.Model)
+	}); err != nil {
+		return ast.WalkStop, err
+	}
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writePflow writes the static image of the model, along with the model
+// for the viewer or the simulation if any. All the ids of the block are
+// prefixed with its id.
+func (r *pflowRenderer) writePflow(w util.BufWriter, id string, m *PflowModel) error {
+	if r.assets.cdn == "" && r.simulate == "" {
+		w.WriteString(`<div class="gno-pflow" id="` + HTMLEscapeString(id) + `">`)
+		writePflowSVG(w, id, m, false)
+		w.WriteString("</div>\n")
+		return nil
+	}
+
+	// The static image is replaced by the viewer once loaded, or animated
+	// by the simulation. Marshaling escapes `<`, `>` and `&`, so the model
+	// can't close its script element.
+	model, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("unable to marshal pflow model: %w", err)
+	}
+	w.WriteString(`<div class="gno-pflow" id="` + HTMLEscapeString(id) + `" data-controller="pflow"`)
+	if a := r.assets; a.cdn != "" {
+		w.WriteString(` data-pflow-cdn-value="` + HTMLEscapeString(a.cdn) + `"`)
+		if a.fallback != "" {
+			w.WriteString(` data-pflow-fallback-value="` + HTMLEscapeString(a.fallback) + `"`)
+		}
+		if a.jsIntegrity != "" {
+			w.WriteString(` data-pflow-js-integrity-value="` + HTMLEscapeString(a.jsIntegrity) + `"`)
+		}
+		if a.cssIntegrity != "" {
+			w.WriteString(` data-pflow-css-integrity-value="` + HTMLEscapeString(a.cssIntegrity) + `"`)
+		}
+	}
+	if r.simulate != "" {
+		w.WriteString(` data-pflow-simulate-value="` + HTMLEscapeString(r.simulate) + `"`)
+	}
+	w.WriteString(`>`)
+	w.WriteString(`<script type="application/json" data-pflow-target="model">`)
+	w.Write(model)
+	w.WriteString(`</script>`)
+	writePflowSVG(w, id, m, r.simulate != "")
+	w.WriteString("</div>\n")
+	return nil
+}
+
+// PflowDescription returns a short text description of the model.
+func PflowDescription(m *PflowModel) string {
+	return fmt.Sprintf("Petri net with %d places and %d transitions", len(m.Places), len(m.Transitions))
+}
+
+// writePflowSVG writes the given model as a static SVG image. Elements are
+// written in a stable order, and drawn with the current text color. For
+// interactive images, transitions and token counts are tagged with their
+// node id, and empty places get an empty token count. The markers ids are
+// prefixed with the given block id, so that several nets of a page don't
+// reference each other's markers.
+func writePflowSVG(w util.BufWriter, blockID string, m *PflowModel, interactive bool) {
+	places := slices.Sorted(maps.Keys(m.Places))
+	transitions := slices.Sorted(maps.Keys(m.Transitions))
+
+	// Compute the bounding box of the net
+	minX, minY, maxX, maxY := math.MaxInt, math.MaxInt, math.MinInt, math.MinInt
+	for _, id := range append(slices.Clone(places), transitions...) {
+		x, y := m.position(id)
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+	if minX > maxX {
+		minX, minY, maxX, maxY = 0, 0, 0, 0 // empty net
+	}
+	minX, minY = minX-pflowMargin, minY-pflowMargin
+	width, height := maxX-minX+pflowMargin, maxY-minY+pflowMargin
+
+	desc := HTMLEscapeString(PflowDescription(m))
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%d %d %d %d" width="%d" height="%d" role="img" aria-label="%s">`,
+		minX, minY, width, height, width, height, desc)
+	w.WriteString(`<title>` + desc + `</title>`)
+	w.WriteString(`<defs>`)
+	fmt.Fprintf(w, `<marker id="%s-arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">`, blockID)
+	w.WriteString(`<path d="M0,0 L10,5 L0,10 z" fill="currentColor"/></marker>`)
+	fmt.Fprintf(w, `<marker id="%s-inhibit" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8">`, blockID)
+	w.WriteString(`<circle cx="5" cy="5" r="4" fill="none" stroke="currentColor" stroke-width="1.5"/></marker>`)
+	w.WriteString(`</defs>`)
+
+	// Arcs first, so nodes are drawn over them
+	w.WriteString(`<g stroke="currentColor" stroke-width="1.5">`)
+	for _, arc := range m.Arcs {
+		writePflowArc(w, blockID, m, arc)
+	}
+	w.WriteString(`</g>`)
+
+	w.WriteString(`<g fill="none" stroke="currentColor" stroke-width="2">`)
+	for _, id := range places {
+		p := m.Places[id]
+		fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d"/>`, p.X, p.Y, pflowPlaceRadius)
+	}
+	for _, id := range transitions {
+		t := m.Transitions[id]
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d"`,
+			t.X-pflowTransitionSize/2, t.Y-pflowTransitionSize/2, pflowTransitionSize, pflowTransitionSize)
+		if interactive {
+			w.WriteString(` data-pflow-transition="` + HTMLEscapeString(id) + `"`)
+		}
+		w.WriteString(`/>`)
+	}
+	w.WriteString(`</g>`)
+
+	// Labels and token counts
+	w.WriteString(`<g fill="currentColor" font-family="sans-serif" font-size="12" text-anchor="middle">`)
+	for _, id := range places {
+		p := m.Places[id]
+		switch {
+		case interactive:
+			fmt.Fprintf(w, `<text x="%d" y="%d" dominant-baseline="central" data-pflow-place="%s">`, p.X, p.Y, HTMLEscapeString(id))
+			if p.Initial > 0 {
+				w.WriteString(strconv.Itoa(p.Initial))
+			}
+			w.WriteString(`</text>`)
+		case p.Initial > 0:
+			fmt.Fprintf(w, `<text x="%d" y="%d" dominant-baseline="central">%d</text>`, p.X, p.Y, p.Initial)
+		}
+		fmt.Fprintf(w, `<text x="%d" y="%d">%s</text>`, p.X, p.Y+pflowPlaceRadius+14, HTMLEscapeString(id))
+	}
+	for _, id := range transitions {
+		t := m.Transitions[id]
+		fmt.Fprintf(w, `<text x="%d" y="%d">%s</text>`, t.X, t.Y+pflowTransitionSize/2+14, HTMLEscapeString(id))
+	}
+	w.WriteString(`</g>`)
+
+	w.WriteString(`</svg>`)
+}
+
+// writePflowArc writes an arc as a line between the borders of its nodes,
+// labeled with its weight if greater than one.
+func writePflowArc(w util.BufWriter, blockID string, m *PflowModel, arc PflowArc) {
+	x1, y1 := m.position(arc.Source)
+	x2, y2 := m.position(arc.Target)
+
+	dx, dy := float64(x2-x1), float64(y2-y1)
+	dist := math.Hypot(dx, dy)
+	if dist == 0 {
+		return // overlapping nodes
+	}
+
+	// Approximate both nodes by a circle
+	const gap = pflowPlaceRadius + 2
+	ux, uy := dx/dist, dy/dist
+	marker := blockID + "-arrow"
+	if arc.Inhibit {
+		marker = blockID + "-inhibit"
+	}
+	fmt.Fprintf(w, `<line x1="%s" y1="%s" x2="%s" y2="%s" marker-end="url(#%s)"/>`,
+		pflowCoord(float64(x1)+ux*gap), pflowCoord(float64(y1)+uy*gap),
+		pflowCoord(float64(x2)-ux*gap), pflowCoord(float64(y2)-uy*gap), marker)
+
+	if arc.Weight > 1 {
+		fmt.Fprintf(w, `<text x="%s" y="%s" fill="currentColor" stroke="none" font-size="12" text-anchor="middle">%d</text>`,
+			pflowCoord(float64(x1+x2)/2-uy*8), pflowCoord(float64(y1+y2)/2+ux*8), arc.Weight)
+	}
+}
+
+// pflowCoord formats a coordinate with at most one decimal.
+func pflowCoord(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// PflowOption configures the pflow extension.
+type PflowOption func(e *pflowExtension)
+
+// WithPflowCDN sets the base URL of the pflow viewer assets, `pflow.js`
+// and `pflow.css`, such as a self-hosted copy or a pinned release. Once
+// loaded, the viewer replaces the static image of the nets with an
+// interactive one. An empty URL renders static images only.
+func WithPflowCDN(url string) PflowOption {
+	return func(e *pflowExtension) {
+		e.assets.cdn = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithPflowIntegrity sets the subresource integrity hashes of the viewer
+// assets, such as `sha384-...`, checked by browsers before using them.
+func WithPflowIntegrity(js, css string) PflowOption {
+	return func(e *pflowExtension) {
+		e.assets.jsIntegrity, e.assets.cssIntegrity = js, css
+	}
+}
+
+// WithPflowFallback sets the base URL of a copy of the viewer assets, such
+// as the ones served by gnoweb, loaded when the CDN is unreachable.
+func WithPflowFallback(url string) PflowOption {
+	return func(e *pflowExtension) {
+		e.assets.fallback = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithPflowSimulate sets the path of the simulation endpoint, which fires
+// the transitions clicked on the static image of the nets.
+func WithPflowSimulate(path string) PflowOption {
+	return func(e *pflowExtension) {
+		e.simulate = path
+	}
+}
+
+// WithPflowCache caches the rendered nets in the given cache.
+func WithPflowCache(c *BlockCache) PflowOption {
+	return func(e *pflowExtension) {
+		e.cache = c
+	}
+}
+
+// pflowExtension is a Goldmark extension handling ```pflow Petri net
+// blocks.
+type pflowExtension struct {
+	assets   pflowAssets
+	simulate string
+	cache    *BlockCache
+}
+
+// pflowAssets locates the pflow viewer assets.
+type pflowAssets struct {
+	cdn          string
+	fallback     string
+	jsIntegrity  string
+	cssIntegrity string
+}
+
+// NewPflowExtension returns a new pflow extension. Petri nets are rendered
+// on the server as static inline SVG, requiring no script.
+func NewPflowExtension(opts ...PflowOption) goldmark.Extender {
+	var e pflowExtension
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the pflow transformer and renderer to the provided Goldmark
+// markdown processor.
+func (e *pflowExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(util.Prioritized(&pflowTransformer{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&pflowRenderer{assets: e.assets, simulate: e.simulate, cache: e.cache}, 500),
+	))
+}
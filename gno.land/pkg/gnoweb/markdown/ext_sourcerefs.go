@@ -0,0 +1,157 @@
+package markdown
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	// sourceRefIssuePattern matches `#123`-style issue or pull request references.
+	sourceRefIssuePattern = regexp.MustCompile(`(?:^|[^\w&#/])(#([0-9]{1,9}))\b`)
+	// sourceRefCommitPattern matches 7 to 40 characters long hexadecimal commit hashes.
+	sourceRefCommitPattern = regexp.MustCompile(`\b([0-9a-f]{7,40})\b`)
+)
+
+// sourceRef represents a reference found inside a text segment.
+type sourceRef struct {
+	start, stop int // position relative to the text segment
+	dest        string
+}
+
+// isHashLike reports whether the given token is likely to be a commit hash
+// and not a regular word or number. A hash must contain both digits and letters.
+func isHashLike(token []byte) bool {
+	var hasDigit, hasLetter bool
+	for _, c := range token {
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c >= 'a' && c <= 'f':
+			hasLetter = true
+		}
+	}
+	return hasDigit && hasLetter
+}
+
+// findSourceRefs returns all references found in the given value, ordered by
+// position.
+func findSourceRefs(value []byte, base string) []sourceRef {
+	var refs []sourceRef
+	for _, m := range sourceRefIssuePattern.FindAllSubmatchIndex(value, -1) {
+		refs = append(refs, sourceRef{
+			start: m[2], stop: m[3],
+			dest: base + "/issues/" + string(value[m[4]:m[5]]),
+		})
+	}
+
+	for _, m := range sourceRefCommitPattern.FindAllSubmatchIndex(value, -1) {
+		if !isHashLike(value[m[2]:m[3]]) {
+			continue
+		}
+
+		refs = append(refs, sourceRef{
+			start: m[2], stop: m[3],
+			dest: base + "/commit/" + string(value[m[2]:m[3]]),
+		})
+	}
+
+	// Both patterns cannot overlap, simply sort them by position
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].start < refs[j].start
+	})
+
+	return refs
+}
+
+// sourceRefsTransformer implements ASTTransformer
+type sourceRefsTransformer struct {
+	base string
+}
+
+// Transform splits text nodes containing source references into text and link nodes.
+func (t *sourceRefsTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var texts []*ast.Text
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.CodeSpan, *ast.Link, *ast.AutoLink, *ast.Image, *ast.RawHTML:
+			// Never touch code and existing links
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, node := range texts {
+		refs := findSourceRefs(node.Segment.Value(source), t.base)
+		if len(refs) == 0 {
+			continue
+		}
+
+		parent, seg := node.Parent(), node.Segment
+		pos := 0
+		for _, ref := range refs {
+			if ref.start > pos {
+				before := ast.NewTextSegment(text.NewSegment(seg.Start+pos, seg.Start+ref.start))
+				parent.InsertBefore(parent, node, before)
+			}
+
+			link := ast.NewLink()
+			link.Destination = []byte(ref.dest)
+			link.AppendChild(link, ast.NewTextSegment(text.NewSegment(seg.Start+ref.start, seg.Start+ref.stop)))
+			parent.InsertBefore(parent, node, link)
+
+			pos = ref.stop
+		}
+
+		if seg.Start+pos < seg.Stop {
+			// Keep the original node for the remaining text so line breaks are preserved
+			node.Segment = seg.WithStart(seg.Start + pos)
+		} else {
+			last := ast.NewTextSegment(text.NewSegment(seg.Stop, seg.Stop))
+			last.SetSoftLineBreak(node.SoftLineBreak())
+			last.SetHardLineBreak(node.HardLineBreak())
+			parent.ReplaceChild(parent, node, last)
+		}
+	}
+}
+
+// sourceRefsExtension is a Goldmark extension that links issue references
+// (`#123`) and commit hashes to a source repository.
+type sourceRefsExtension struct {
+	base string
+}
+
+// NewSourceRefsExtension returns an extension linking issue references and
+// commit hashes to the given repository base URL
+// (e.g. `https://github.com/gnolang/gno`). An empty base disables the
+// extension.
+func NewSourceRefsExtension(base string) goldmark.Extender {
+	return &sourceRefsExtension{base: strings.TrimSuffix(base, "/")}
+}
+
+// Extend adds the source references transformer to the provided Goldmark markdown processor.
+func (e *sourceRefsExtension) Extend(m goldmark.Markdown) {
+	if e.base == "" {
+		return
+	}
+
+	// Run before the link transformer so created links get resolved as well.
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&sourceRefsTransformer{base: e.base}, 400),
+	))
+}
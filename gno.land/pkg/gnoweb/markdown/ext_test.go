@@ -2,15 +2,22 @@ package markdown
 
 import (
 	"bytes"
+	"errors"
 	"flag"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/stretchr/testify/require"
 	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
+	"golang.org/x/tools/txtar"
 )
 
 const testdataDir = "golden"
@@ -20,6 +27,40 @@ var (
 	dump   = flag.Bool("dump", false, "dump ast tree after parsing")
 )
 
+// goldenExtensions maps golden directories to the setup of the optional
+// extensions their files are rendered with, on top of the Gno extension.
+// Files of other directories are only rendered with the Gno extension.
+var goldenExtensions = map[string]func(m goldmark.Markdown){
+	"ext_call": func(m goldmark.Markdown) {
+		NewCallLinkExtension().Extend(m)
+	},
+	"ext_codelines": func(m goldmark.Markdown) {
+		NewCodeLineAnchorsExtension(highlighting.WithFormatOptions(
+			chromahtml.WithLineNumbers(true),
+			chromahtml.WithClasses(true),
+			chromahtml.ClassPrefix("chroma-"),
+		)).Extend(m)
+	},
+	"ext_dot": func(m goldmark.Markdown) {
+		NewDotExtension(WithDotRuntimeURL("https://cdn.example.com/viz.js")).Extend(m)
+	},
+	"ext_emoji": func(m goldmark.Markdown) {
+		NewEmojiExtension().Extend(m)
+	},
+	"ext_math": func(m goldmark.Markdown) {
+		NewMathExtension(WithMathServerRender(true)).Extend(m)
+	},
+	"ext_mermaid": func(m goldmark.Markdown) {
+		NewMermaidExtension(WithMermaidRuntimeURL("https://cdn.example.com/mermaid.js")).Extend(m)
+	},
+	"ext_pflow": func(m goldmark.Markdown) {
+		NewPflowExtension(WithPflowCDN("https://cdn.example.com/pflow/")).Extend(m)
+	},
+	"ext_sourcerefs": func(m goldmark.Markdown) {
+		NewSourceRefsExtension("https://github.com/gnolang/gno").Extend(m)
+	},
+}
+
 func testGoldmarkOutput(t *testing.T, nameIn string, input []byte) (string, []byte) {
 	t.Helper()
 
@@ -43,6 +84,14 @@ func testGoldmarkOutput(t *testing.T, nameIn string, input []byte) (string, []by
 	m := goldmark.New()
 	ext.Extend(m)
 
+	// Setup the optional extensions of the golden directory, named by the
+	// test as `TestGnoExtension/<dir>/<file>`
+	if parts := strings.Split(t.Name(), "/"); len(parts) > 2 {
+		if setup, ok := goldenExtensions[parts[1]]; ok {
+			setup(m)
+		}
+	}
+
 	// Parse markdown input with context
 	node := m.Parser().Parse(text.NewReader(input), ctxOpts)
 
@@ -71,3 +120,580 @@ func assertExt(t *testing.T, filename, ext string) {
 	require.Truef(t, strings.HasSuffix(filename, ext),
 		"expected %q extension for filename %q", ext, filename)
 }
+
+func TestSourceRefsExtension_NoBase(t *testing.T) {
+	m := goldmark.New()
+	NewSourceRefsExtension("").Extend(m)
+
+	var html bytes.Buffer
+	require.NoError(t, m.Convert([]byte("Fixed in #123 by a1b2c3d."), &html))
+	require.Equal(t, "<p>Fixed in #123 by a1b2c3d.</p>\n", html.String())
+}
+
+func TestMathExtension_ClientRuntime(t *testing.T) {
+	m := goldmark.New(goldmark.WithExtensions(NewMathExtension()))
+
+	var html bytes.Buffer
+	require.NoError(t, m.Convert([]byte("Energy: $E = mc^2$"), &html))
+	require.Equal(t, `<p>Energy: <span class="gno-math" data-math-display="inline">E = mc^2</span></p>`+"\n", html.String())
+}
+
+func TestMathExtension_OtherGoldenFiles(t *testing.T) {
+	// Enabling math doesn't change the output of pages without math
+	files, err := filepath.Glob(filepath.Join(testdataDir, "*", "*.txtar"))
+	require.NoError(t, err)
+	for _, file := range files {
+		if filepath.Base(filepath.Dir(file)) == "ext_math" {
+			continue
+		}
+
+		archive, err := txtar.ParseFile(file)
+		require.NoError(t, err)
+		input := archive.Files[0].Data
+
+		var without, with bytes.Buffer
+		require.NoError(t, goldmark.New().Convert(input, &without))
+		m := goldmark.New(goldmark.WithExtensions(NewMathExtension(WithMathServerRender(true))))
+		require.NoError(t, m.Convert(input, &with))
+		require.Equal(t, without.String(), with.String(), file)
+	}
+}
+
+func TestMathExtension_KaTeX(t *testing.T) {
+	m := goldmark.New(goldmark.WithExtensions(NewMathExtension(WithMathKaTeX("https://cdn.example.com/katex/"))))
+
+	var html bytes.Buffer
+	require.NoError(t, m.Convert([]byte("```math\nx^2\n```"), &html))
+	require.Equal(t, `<div class="gno-math" data-math-display="block" data-controller="math" data-math-katex-value="https://cdn.example.com/katex">x^2</div>`+"\n", html.String())
+
+	// Server rendering takes precedence
+	m = goldmark.New(goldmark.WithExtensions(NewMathExtension(WithMathServerRender(true), WithMathKaTeX(DefaultKaTeXURL))))
+	html.Reset()
+	require.NoError(t, m.Convert([]byte("$x$"), &html))
+	require.NotContains(t, html.String(), "data-controller")
+}
+
+func TestStripCommentsExtension(t *testing.T) {
+	m := goldmark.New(
+		goldmark.WithExtensions(NewStripCommentsExtension()),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	src := "<!-- debug: block -->\n\n" +
+		"Hello <!-- inline --> world, `<!-- span -->`.\n\n" +
+		"```html\n<!-- inside code -->\n```\n\n" +
+		"    <!-- indented code -->\n\n" +
+		"<div>kept</div>\n"
+
+	var out bytes.Buffer
+	require.NoError(t, m.Convert([]byte(src), &out))
+	require.Equal(t, "<p>Hello  world, <code>&lt;!-- span --&gt;</code>.</p>\n"+
+		"<pre><code class=\"language-html\">&lt;!-- inside code --&gt;\n</code></pre>\n"+
+		"<pre><code>&lt;!-- indented code --&gt;\n</code></pre>\n"+
+		"<div>kept</div>\n", out.String())
+}
+
+func TestAltTextWarningExtension(t *testing.T) {
+	src := "![](/a.png) ![logo](/b.png) ![ ](/c.png)\n"
+
+	var out bytes.Buffer
+	m := goldmark.New(goldmark.WithExtensions(NewAltTextWarningExtension()))
+	require.NoError(t, m.Convert([]byte(src), &out))
+	require.Equal(t, "<p><img src=\"/a.png\" alt=\"\">"+
+		"<span class=\"gno-alt-warning\" role=\"note\" title=\"/a.png\">Image without alt text</span> "+
+		"<img src=\"/b.png\" alt=\"logo\"> "+
+		"<img src=\"/c.png\" alt=\" \">"+
+		"<span class=\"gno-alt-warning\" role=\"note\" title=\"/c.png\">Image without alt text</span></p>\n", out.String())
+
+	out.Reset()
+	require.NoError(t, goldmark.New().Convert([]byte(src), &out))
+	require.NotContains(t, out.String(), "gno-alt-warning")
+}
+
+func TestHTTPToCurl(t *testing.T) {
+	cases := []struct {
+		name     string
+		req      string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "get",
+			req:      "GET https://rpc.gno.land/status HTTP/1.1\nAccept: application/json\n",
+			expected: "curl \\\n  'https://rpc.gno.land/status' \\\n  -H 'Accept: application/json'",
+		},
+		{
+			name: "post with host",
+			req:  "POST /abci_query HTTP/1.1\nHost: rpc.gno.land\nContent-Type: application/json\n\n{\"path\": \"vm/qrender\", \"data\": \"it's\"}\n",
+			expected: "curl \\\n  -X POST \\\n  'https://rpc.gno.land/abci_query' \\\n" +
+				"  -H 'Content-Type: application/json' \\\n" +
+				"  --data-raw '{\"path\": \"vm/qrender\", \"data\": \"it'\\''s\"}'",
+		},
+		{name: "unknown method", req: "FETCH https://gno.land/\n", err: true},
+		{name: "relative without host", req: "GET /status\n", err: true},
+		{name: "malformed header", req: "GET https://gno.land/\nnot a header\n", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := HTTPToCurl(tc.req)
+			if tc.err {
+				require.ErrorIs(t, err, ErrHTTPRequestInvalid)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestHTTPCurlExtension(t *testing.T) {
+	m := goldmark.New(goldmark.WithExtensions(NewHTTPCurlExtension()))
+
+	src := "```http\nGET https://gno.land/r/demo HTTP/1.1\n```\n\n```http\nnot a request\n```\n"
+
+	var out bytes.Buffer
+	require.NoError(t, m.Convert([]byte(src), &out))
+	require.Equal(t, 1, strings.Count(out.String(), `class="gno-http-curl"`))
+	require.Contains(t, out.String(), `data-copy-text-value="curl \
+  &#39;https://gno.land/r/demo&#39;"`)
+	require.Contains(t, out.String(), "<pre><code class=\"language-http\">not a request\n</code></pre>")
+}
+
+func TestBrokenLinksExtension(t *testing.T) {
+	gnourl, err := weburl.Parse("https://gno.land/r/test")
+	require.NoError(t, err)
+
+	var checked []string
+	exists := func(path string) bool {
+		checked = append(checked, path)
+		return path == "/r/demo/boards"
+	}
+
+	m := goldmark.New()
+	NewGnoExtension().Extend(m)
+	NewBrokenLinksExtension(exists).Extend(m)
+
+	src := "[boards](/r/demo/boards) [gone](/r/demo/gone:page) [site](https://example.com/r/x) [user](/u/test) [sub](./sub)\n"
+
+	var out bytes.Buffer
+	require.NoError(t, m.Convert([]byte(src), &out, parser.WithContext(NewGnoParserContext(gnourl))))
+	require.Equal(t, []string{"/r/demo/boards", "/r/demo/gone", "/r/test/sub"}, checked, "relative links are checked once resolved")
+	require.Equal(t, 2, strings.Count(out.String(), `class="gno-link-warning"`))
+	require.Contains(t, out.String(), `</a><span class="gno-link-warning" role="note" title="/r/demo/gone does not exist">Broken link</span>`)
+	require.NotContains(t, out.String(), `title="/r/demo/boards does not exist"`)
+}
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		name, input, expected string
+	}{
+		{"ascii", "Hello World_2", "hello-world-2"},
+		{"punctuation", " What's new? ", "whats-new"},
+		{"emoji", "Launch 🚀 day", "launch--day"},
+		{"nfc", "Café crème", "café-crème"},
+		{"nfd", "Cafe\u0301 cre\u0300me", "café-crème"},
+		{"combining marks", "a\u0323\u0302", "\u1ead"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Slugify(tc.input))
+		})
+	}
+}
+
+func TestSlugHeadingIDs(t *testing.T) {
+	gnourl, err := weburl.Parse("https://gno.land/r/test")
+	require.NoError(t, err)
+
+	m := goldmark.New(goldmark.WithParserOptions(parser.WithAutoHeadingID()))
+	src := "# Café\n\n# Cafe\u0301\n"
+
+	var out bytes.Buffer
+	require.NoError(t, m.Convert([]byte(src), &out, parser.WithContext(NewGnoParserContext(gnourl))))
+	require.Contains(t, out.String(), "<h1 id=\"café\">")
+	require.Contains(t, out.String(), "<h1 id=\"café-1\">")
+}
+
+func TestAnchorsExtension(t *testing.T) {
+	gnourl, err := weburl.Parse("https://gno.land/r/test")
+	require.NoError(t, err)
+
+	m := goldmark.New(
+		goldmark.WithExtensions(NewAnchorsExtension()),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	cases := []struct {
+		name, input, expected string
+	}{
+		{"paragraph", "Some *important* note. {#note}\n", "<p id=\"note\">Some <em>important</em> note.</p>\n"},
+		{"marker only", "Hello\n{#hello}\n", "<p id=\"hello\">Hello</p>\n"},
+		{"escaped", "Literal \\{#not-an-anchor}\n", "<p>Literal {#not-an-anchor}</p>\n"},
+		{"not trailing", "{#start} of text\n", "<p>{#start} of text</p>\n"},
+		{"collision", "# Intro\n\nText {#intro}\n", "<h1 id=\"intro\">Intro</h1>\n<p id=\"intro-1\">Text</p>\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, m.Convert([]byte(tc.input), &out, parser.WithContext(NewGnoParserContext(gnourl))))
+			require.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestHeadingSpamGuardExtension(t *testing.T) {
+	spammy := "# Title\n\nBuy now.\n\n" + strings.Repeat("## Cheap tokens\n\n##\n\n", 5)
+	normal := "# Title\n\n" + strings.Repeat("## Section\n\nSome body text, long enough to be read as a real paragraph.\n\n", 8)
+
+	convert := func(src string, opts ...HeadingSpamOption) string {
+		var out bytes.Buffer
+		m := goldmark.New(goldmark.WithExtensions(NewHeadingSpamGuardExtension(opts...)))
+		require.NoError(t, m.Convert([]byte(src), &out))
+		return out.String()
+	}
+
+	out := convert(spammy)
+	require.True(t, strings.HasPrefix(out, `<div class="gno-heading-warning" role="note">This page has 11 headings for 2 words of text.</div>`), out)
+	require.Contains(t, out, "<h2>Cheap tokens</h2>")
+
+	// Collapsed headings become paragraphs, and empty ones are removed
+	out = convert(spammy, WithHeadingSpamCollapse())
+	require.NotContains(t, out, "gno-heading-warning")
+	require.Equal(t, "<h1>Title</h1>\n<p>Buy now.</p>\n"+strings.Repeat("<p>Cheap tokens</p>\n", 5), out)
+
+	// Normal pages are left untouched
+	for _, opts := range [][]HeadingSpamOption{nil, {WithHeadingSpamCollapse()}} {
+		out = convert(normal, opts...)
+		require.NotContains(t, out, "gno-heading-warning")
+		require.Equal(t, 9, strings.Count(out, "<h"))
+	}
+
+	// The ratio is configurable
+	require.Contains(t, convert(normal, WithHeadingSpamRatio(0.01)), "gno-heading-warning")
+}
+
+func TestConditionalExtension(t *testing.T) {
+	src := "# Network\n\n" +
+		":::if testnet\n## Faucet\n\nGet free tokens.\n:::\n\n" +
+		":::if mainnet\nReal tokens.\n:::\n" +
+		":::if unknown\nNever shown.\n:::\n\n" +
+		"Footer.\n"
+
+	convert := func(flags ...string) string {
+		var out bytes.Buffer
+		m := goldmark.New(goldmark.WithExtensions(NewConditionalExtension(flags...)))
+		require.NoError(t, m.Convert([]byte(src), &out))
+		return out.String()
+	}
+
+	require.Equal(t, "<h1>Network</h1>\n<h2>Faucet</h2>\n<p>Get free tokens.</p>\n<p>Footer.</p>\n", convert("testnet"))
+	require.Equal(t, "<h1>Network</h1>\n<p>Real tokens.</p>\n<p>Footer.</p>\n", convert("mainnet"))
+	require.Equal(t, "<h1>Network</h1>\n<p>Footer.</p>\n", convert())
+
+	// Other `:::` lines are left as is
+	var out bytes.Buffer
+	m := goldmark.New(goldmark.WithExtensions(NewConditionalExtension("testnet")))
+	require.NoError(t, m.Convert([]byte(":::note\ntext\n"), &out))
+	require.Equal(t, "<p>:::note\ntext</p>\n", out.String())
+}
+
+func TestPflowExtension(t *testing.T) {
+	src := "```pflow\n" + `{"places": {"p0": {"initial": 1, "x": 10, "y": 10}}, "transitions": {"t0": {"x": 60, "y": 10}}, "arcs": [{"source": "p0", "target": "t0"}]}` + "\n```\n"
+
+	convert := func(opts ...PflowOption) string {
+		var out bytes.Buffer
+		m := goldmark.New(goldmark.WithExtensions(NewPflowExtension(opts...)))
+		require.NoError(t, m.Convert([]byte(src), &out))
+		return out.String()
+	}
+
+	// Static images require no script
+	out := convert()
+	require.Regexp(t, `^<div class="gno-pflow" id="pflow-[0-9a-f]{12}"><svg `, out)
+	require.NotContains(t, out, "<script")
+
+	// Identical blocks of a page get distinct ids
+	var page bytes.Buffer
+	m := goldmark.New(goldmark.WithExtensions(NewPflowExtension()))
+	require.NoError(t, m.Convert([]byte(src+"\n"+src), &page))
+	ids := regexp.MustCompile(`<div class="gno-pflow" id="([^"]+)"`).FindAllStringSubmatch(page.String(), -1)
+	require.Len(t, ids, 2)
+	require.Equal(t, ids[0][1]+"-2", ids[1][1])
+	require.Contains(t, page.String(), `<marker id="`+ids[1][1]+`-arrow"`)
+	require.Contains(t, page.String(), `marker-end="url(#`+ids[1][1]+`-arrow)"`)
+
+	// The viewer assets are loaded from the configured host
+	out = convert(WithPflowCDN("https://assets.example.com/pflow@v1/"))
+	require.Contains(t, out, `data-controller="pflow" data-pflow-cdn-value="https://assets.example.com/pflow@v1"`)
+	require.Contains(t, out, `<script type="application/json" data-pflow-target="model">`)
+	require.Contains(t, out, "<svg ", "the static image is kept as a fallback")
+
+	// Assets are checked against their hashes, and fall back to a local copy
+	out = convert(
+		WithPflowCDN("https://assets.example.com/pflow@v1"),
+		WithPflowIntegrity("sha384-js", "sha384-css"),
+		WithPflowFallback("/public/pflow/"),
+	)
+	require.Contains(t, out, ` data-pflow-fallback-value="/public/pflow" data-pflow-js-integrity-value="sha384-js" data-pflow-css-integrity-value="sha384-css">`)
+
+	// Simulated nets tag their transitions and token counts
+	out = convert(WithPflowSimulate("/_pflow/simulate"))
+	require.Contains(t, out, `data-controller="pflow" data-pflow-simulate-value="/_pflow/simulate">`)
+	require.Contains(t, out, `data-pflow-transition="t0"/>`)
+	require.Contains(t, out, `data-pflow-place="p0">1</text>`)
+}
+
+func TestPflowFire(t *testing.T) {
+	m, err := ParsePflowModel([]byte(`{
+		"places": {"in": {"initial": 2}, "out": {"capacity": 1}, "stop": {}},
+		"transitions": {"move": {}, "halt": {}},
+		"arcs": [
+			{"source": "in", "target": "move"},
+			{"source": "move", "target": "out"},
+			{"source": "stop", "target": "move", "inhibit": true},
+			{"source": "halt", "target": "stop"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	marking := m.InitialMarking()
+	require.Equal(t, []string{"halt", "move"}, m.EnabledTransitions(marking))
+
+	next, err := m.Fire(marking, "move")
+	require.NoError(t, err)
+	require.Equal(t, PflowMarking{"in": 1, "out": 1, "stop": 0}, next)
+	require.Equal(t, 2, marking["in"], "the given marking is left as is")
+
+	// The output place is full
+	_, err = m.Fire(next, "move")
+	require.ErrorIs(t, err, ErrPflowNotEnabled)
+
+	// Tokens in the inhibitor place disable the transition
+	next, err = m.Fire(marking, "halt")
+	require.NoError(t, err)
+	_, err = m.Fire(next, "move")
+	require.ErrorIs(t, err, ErrPflowNotEnabled)
+	require.Equal(t, []string{"halt"}, m.EnabledTransitions(next))
+
+	_, err = m.Fire(marking, "unknown")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrPflowNotEnabled)
+}
+
+func TestPflowCompose(t *testing.T) {
+	const (
+		producer = "```pflow compose=pc\n" + `{"places": {"buffer": {"x": 100, "y": 10}}, "transitions": {"produce": {"x": 10, "y": 10}}, "arcs": [{"source": "produce", "target": "buffer"}]}` + "\n```\n"
+		consumer = "```pflow compose=pc\n" + `{"places": {"buffer": {"x": 100, "y": 10}, "done": {"x": 300, "y": 10}}, "transitions": {"consume": {"x": 200, "y": 10}}, "arcs": [{"source": "buffer", "target": "consume"}, {"source": "consume", "target": "done"}]}` + "\n```\n"
+	)
+
+	convert := func(src string) string {
+		var out bytes.Buffer
+		m := goldmark.New(goldmark.WithExtensions(NewPflowExtension()))
+		require.NoError(t, m.Convert([]byte(src), &out))
+		return out.String()
+	}
+	widgets := regexp.MustCompile(`<div class="gno-pflow[" ]`)
+
+	// Adjacent blocks of a group are composed into a single net
+	out := convert(producer + "\n" + consumer)
+	require.Len(t, widgets.FindAllString(out, -1), 1)
+	require.Contains(t, out, `aria-label="Petri net with 2 places and 2 transitions"`)
+	require.Equal(t, 1, strings.Count(out, `>buffer</text>`), "the shared place is fused")
+
+	// Blocks separated by other content, or of other groups, are not
+	out = convert(producer + "\nThen:\n\n" + consumer)
+	require.Len(t, widgets.FindAllString(out, -1), 2)
+	out = convert(producer + "\n" + strings.Replace(consumer, "compose=pc", "compose=other", 1))
+	require.Len(t, widgets.FindAllString(out, -1), 2)
+
+	// Incompatible interfaces render an inline error
+	out = convert(producer + "\n" + strings.Replace(consumer, `"buffer": {"x"`, `"buffer": {"initial": 1, "x"`, 1))
+	require.Len(t, widgets.FindAllString(out, -1), 1)
+	require.Contains(t, out, `<div class="gno-pflow gno-pflow-error" role="alert"><p>incompatible pflow interfaces: net 2: place &#34;buffer&#34; has 1 initial tokens`)
+
+	_, err := ComposePflowModels(
+		&PflowModel{Places: map[string]PflowPlace{"p": {}}},
+		&PflowModel{Transitions: map[string]PflowTransition{"p": {}}},
+	)
+	require.ErrorIs(t, err, ErrPflowIncompatible)
+}
+
+func TestTocExtension(t *testing.T) {
+	page := "# Title\n\n[[toc]]\n\n## Install\n\n### From source\n\n## Usage & more\n"
+
+	convert := func(src string, opts ...TocOption) string {
+		var out bytes.Buffer
+		m := goldmark.New(
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithExtensions(NewTocExtension(opts...)),
+		)
+		require.NoError(t, m.Convert([]byte(src), &out))
+		return out.String()
+	}
+
+	out := convert(page)
+	require.Contains(t, out, "<h1 id=\"title\">Title</h1>\n"+
+		"<nav class=\"gno-toc\" aria-label=\"Table of contents\">\n<ul>\n"+
+		"<li><a href=\"#install\">Install</a>\n<ul>\n<li><a href=\"#from-source\">From source</a></li>\n</ul>\n</li>\n"+
+		"<li><a href=\"#usage--more\">Usage &amp; more</a></li>\n"+
+		"</ul>\n</nav>\n")
+	require.NotContains(t, out, TocDirective)
+
+	// The directive must be alone in its paragraph
+	require.Contains(t, convert("See [[toc]] below.\n\n## A\n"), "<p>See [[toc]] below.</p>")
+
+	// Auto mode inserts the table before the first section of long pages
+	auto := "# Title\n\nIntro.\n\n## A\n\n## B\n\n## C\n"
+	require.NotContains(t, convert(auto), "gno-toc")
+	out = convert(auto, WithTocAuto())
+	require.Contains(t, out, "<p>Intro.</p>\n<nav class=\"gno-toc\"")
+	require.NotContains(t, convert("# Title\n\n## A\n\n## B\n", WithTocAuto()), "gno-toc")
+}
+
+func TestFrontmatterExtension(t *testing.T) {
+	convert := func(src string) (string, Frontmatter, bool) {
+		var out bytes.Buffer
+		m := goldmark.New(goldmark.WithExtensions(NewFrontmatterExtension()))
+		pc := parser.NewContext()
+		require.NoError(t, m.Convert([]byte(src), &out, parser.WithContext(pc)))
+		fm, ok := GetFrontmatter(pc)
+		return out.String(), fm, ok
+	}
+
+	out, fm, ok := convert("---\ntitle: Boards\ndescription: \"Discussion boards\"\n---\n# Hello\n")
+	require.True(t, ok)
+	require.Equal(t, Frontmatter{Title: "Boards", Description: "Discussion boards"}, fm)
+	require.Equal(t, "<h1>Hello</h1>\n", out)
+
+	out, fm, ok = convert("+++\ntitle = \"Boards\"\nimage = \"https://example.com/og.png\"\n+++\nHello\n")
+	require.True(t, ok)
+	require.Equal(t, Frontmatter{Title: "Boards", Image: "https://example.com/og.png"}, fm)
+	require.Equal(t, "<p>Hello</p>\n", out)
+
+	// Thematic breaks and setext headings are left untouched
+	for _, src := range []string{
+		"---\nSome text\n---\n",
+		"---\n\nNo closing delimiter\n",
+		"Intro\n\n---\ntitle: Not at the top\n---\n",
+	} {
+		out, _, ok = convert(src)
+		require.False(t, ok, src)
+		require.Contains(t, out, "<hr>", src)
+	}
+}
+
+func TestSourceEmbedExtension(t *testing.T) {
+	var fetched []string
+	fetch := func(path, file string) ([]byte, error) {
+		fetched = append(fetched, path+"/"+file)
+		if file != "avl.gno" {
+			return nil, errors.New("not found")
+		}
+		return []byte("package avl\n\n// Tree is a tree.\ntype Tree struct{}\n"), nil
+	}
+	m := goldmark.New(goldmark.WithExtensions(NewSourceEmbedExtension(fetch)))
+
+	gnourl, err := weburl.Parse("https://gno.land/r/test")
+	require.NoError(t, err)
+
+	convert := func(src string) string {
+		t.Helper()
+		var out bytes.Buffer
+		doc := m.Parser().Parse(text.NewReader([]byte(src)), parser.WithContext(NewGnoParserContext(gnourl)))
+		require.NoError(t, m.Renderer().Render(&out, []byte(src), doc))
+		return out.String()
+	}
+
+	out := convert("```gno-embed{path=gno.land/p/demo/avl file=avl.gno lines=3-4}\n```")
+	require.Equal(t, []string{"/p/demo/avl/avl.gno"}, fetched)
+	require.Contains(t, out, `<figcaption><a href="/p/demo/avl/avl.gno#L3">/p/demo/avl/avl.gno</a> (lines 3-4)</figcaption>`)
+	require.Contains(t, out, "Tree is a tree.")
+	require.NotContains(t, out, "package")
+
+	for src, msg := range map[string]string{
+		"```gno-embed{path=/p/demo/avl file=avl.gno lines=4-9}\n```": "avl.gno has 4 lines",
+		"```gno-embed{path=/p/demo/avl file=../avl.gno}\n```":        "invalid file name",
+		"```gno-embed{path=/p/demo/avl file=avl.gno color=red}\n```": "unknown attribute",
+		"```gno-embed{path=/p/demo/avl file=tree.gno}\n```":          "unable to fetch /p/demo/avl/tree.gno: not found",
+		"```gno-embed path=/p/demo/avl\n```":                         "expected gno-embed{path=... file=...}",
+		"```gno-embed{path=/p/demo/avl file=avl.gno lines=a-b}\n```": "invalid lines",
+	} {
+		out := convert(src)
+		require.Contains(t, out, `<div class="gno-embed gno-embed-error" role="alert">`, src)
+		require.Contains(t, out, msg, src)
+	}
+}
+
+func TestImageProxyExtension(t *testing.T) {
+	m := goldmark.New(goldmark.WithExtensions(NewImageProxyExtension("/_proxy/img")))
+
+	var html bytes.Buffer
+	require.NoError(t, m.Convert([]byte("![logo](https://example.com/logo.png?v=1) ![local](/public/logo.png)"), &html))
+	require.Equal(t, `<p><img src="/_proxy/img?url=https%3A%2F%2Fexample.com%2Flogo.png%3Fv%3D1" alt="logo"> <img src="/public/logo.png" alt="local"></p>`+"\n", html.String())
+
+	// Signed URLs
+	sign := func(url string) string { return "sig:" + url }
+	m = goldmark.New(goldmark.WithExtensions(NewImageProxyExtension("/_proxy/img", WithImageProxySigner(sign))))
+
+	html.Reset()
+	require.NoError(t, m.Convert([]byte("![logo](https://example.com/logo.png)"), &html))
+	require.Equal(t, `<p><img src="/_proxy/img?url=https%3A%2F%2Fexample.com%2Flogo.png&amp;sig=sig%3Ahttps%3A%2F%2Fexample.com%2Flogo.png" alt="logo"></p>`+"\n", html.String())
+}
+
+func TestBlockCache(t *testing.T) {
+	cache, err := NewBlockCache(2)
+	require.NoError(t, err)
+
+	m := goldmark.New(goldmark.WithExtensions(
+		NewPflowExtension(WithPflowCache(cache)),
+		NewMathExtension(WithMathServerRender(true), WithMathCache(cache)),
+	))
+	convert := func(src string) string {
+		t.Helper()
+		var html bytes.Buffer
+		require.NoError(t, m.Convert([]byte(src), &html))
+		return html.String()
+	}
+
+	const net = "```pflow\n{\"places\": {\"p\": {\"x\": 10, \"y\": 10}}}\n```\n"
+	out := convert(net)
+	require.Contains(t, out, "<svg")
+	require.Equal(t, 1, cache.Len())
+	require.Equal(t, out, convert(net))
+	require.Equal(t, 1, cache.Len())
+
+	// Cached blocks are served from the cache
+	source := []byte(`{"places": {"p": {"x": 10, "y": 10}}}`)
+	cid := BlockCID(PflowLanguage, nil, nil, nil, nil, nil, []byte(pflowBlockID(map[string]int{}, source)), source)
+	cache.lru.Add(cid, []byte("<p>cached</p>\n"))
+	require.Equal(t, "<p>cached</p>\n", convert(net))
+
+	// Least recently used blocks are evicted
+	convert("$x^2$")
+	convert("$y^2$")
+	require.Equal(t, 2, cache.Len())
+	require.Contains(t, convert(net), "<svg")
+}
+
+func TestPlainText(t *testing.T) {
+	src := "# Boards\n\nWelcome to **the** [boards](/r/demo/boards), see [below](#rules).\n" +
+		"Second line with `code` and ![logo](/logo.png).\n\n" +
+		"<div>dropped</div>\n\n" +
+		"- one\n- two\n  1. nested\n\n" +
+		"> quoted\n\n" +
+		"```go\nfunc main() {}\n```\n\n" +
+		"| a | b |\n|---|---|\n| 1 | 2 |\n\n" +
+		"---\n\nSee https://gno.land.\n"
+
+	require.Equal(t, "Boards\n\n"+
+		"Welcome to the boards (/r/demo/boards), see below.\n"+
+		"Second line with code and logo.\n\n"+
+		"- one\n- two\n  1. nested\n\n"+
+		"> quoted\n\n"+
+		"    func main() {}\n\n"+
+		"a | b\n1 | 2\n\n"+
+		"---\n\nSee https://gno.land.\n", string(PlainText([]byte(src))))
+}
@@ -0,0 +1,112 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// altText returns the alt text of the given image, made of its text
+// children.
+func altText(img *ast.Image, source []byte) string {
+	var alt bytes.Buffer
+	ast.Walk(img, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Text:
+			alt.Write(n.Segment.Value(source))
+		case *ast.String:
+			alt.Write(n.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(alt.String())
+}
+
+// textModeRenderer implements NodeRenderer, overriding the renderers of the
+// nodes which cannot be displayed as plain text.
+type textModeRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *textModeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindRawHTML, r.renderNothing)
+	reg.Register(ast.KindHTMLBlock, r.renderNothing)
+	reg.Register(KindDotBlock, r.renderDotBlock)
+	reg.Register(KindMermaidBlock, r.renderMermaidBlock)
+	reg.Register(KindPflowBlock, r.renderPflowBlock)
+	reg.Register(KindHTTPCurl, r.renderNothing)
+}
+
+func (r *textModeRenderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if alt := altText(node.(*ast.Image), source); alt != "" {
+			w.WriteString("[Image: " + HTMLEscapeString(alt) + "]")
+		} else {
+			w.WriteString("[Image]")
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *textModeRenderer) renderDotBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		writeDiagramSource(w, "graph", node.(*DotBlock).Source)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *textModeRenderer) renderMermaidBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		writeDiagramSource(w, "mermaid", node.(*MermaidBlock).Source)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// writeDiagramSource writes the source of a diagram, in place of its image.
+func writeDiagramSource(w util.BufWriter, kind string, src []byte) {
+	w.WriteString("<figure>\n<figcaption>Diagram, described by the following " + kind + " source:</figcaption>\n")
+	w.WriteString("<pre><code>" + HTMLEscapeString(string(src)) + "</code></pre>\n</figure>\n")
+}
+
+func (r *textModeRenderer) renderPflowBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if n := node.(*PflowBlock); n.Model != nil {
+			w.WriteString("<p>[" + HTMLEscapeString(PflowDescription(n.Model)) + "]</p>\n")
+		} else {
+			w.WriteString("<p>[Invalid Petri net]</p>\n")
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *textModeRenderer) renderNothing(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+// textModeExtension is a Goldmark extension rendering content as plain
+// semantic HTML.
+type textModeExtension struct{}
+
+// NewTextModeExtension returns an extension rendering images as their alt
+// text and diagrams as their source, and dropping raw HTML and interactive
+// widgets, so the output does not depend on styles, scripts or remote
+// resources.
+func NewTextModeExtension() goldmark.Extender {
+	return &textModeExtension{}
+}
+
+// Extend adds the text mode renderer to the provided Goldmark markdown
+// processor. It takes precedence over the other renderers of those nodes.
+func (e *textModeExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&textModeRenderer{}, 1),
+	))
+}
@@ -0,0 +1,196 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// TocDirective is the paragraph replaced by the table of contents.
+const TocDirective = "[[toc]]"
+
+// minAutoTocItems is the number of headings from which a table of contents
+// is inserted in auto mode.
+const minAutoTocItems = 3
+
+var KindTocBlock = ast.NewNodeKind("TocBlock")
+
+// TocBlock is a table of contents of the page headings.
+type TocBlock struct {
+	ast.BaseBlock
+	Toc Toc
+}
+
+// Dump implements Node.Dump for debug representation.
+func (n *TocBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// Kind implements Node.Kind.
+func (*TocBlock) Kind() ast.NodeKind {
+	return KindTocBlock
+}
+
+// isTocDirective reports whether the given paragraph only holds the table of
+// contents directive.
+func isTocDirective(p *ast.Paragraph, source []byte) bool {
+	lines := p.Lines()
+	if lines.Len() != 1 {
+		return false
+	}
+	seg := lines.At(0)
+	return string(bytes.TrimSpace(seg.Value(source))) == TocDirective
+}
+
+// tocTransformer implements ASTTransformer, replacing `[[toc]]` paragraphs
+// with a TocBlock. In auto mode, pages without directive get one before
+// their first heading listed in the table of contents.
+type tocTransformer struct {
+	auto bool
+}
+
+func (t *tocTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var (
+		directives []*ast.Paragraph
+		first      *ast.Heading
+	)
+	ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Paragraph:
+			if isTocDirective(n, source) {
+				directives = append(directives, n)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			if first == nil && n.Level >= 2 && n.Parent() == doc {
+				first = n
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if len(directives) == 0 && (!t.auto || first == nil) {
+		return
+	}
+
+	// Same headings as the page table of contents
+	toc, err := TocInspect(doc, source, TocOptions{MinDepth: 2, MaxDepth: 6})
+	if err != nil {
+		return
+	}
+
+	for _, p := range directives {
+		parent := p.Parent()
+		parent.ReplaceChild(parent, p, &TocBlock{Toc: toc})
+	}
+
+	if len(directives) == 0 && countTocItems(toc.Items) >= minAutoTocItems {
+		doc.InsertBefore(doc, first, &TocBlock{Toc: toc})
+	}
+}
+
+// countTocItems returns the number of items in the given tree.
+func countTocItems(items []*TocItem) int {
+	n := len(items)
+	for _, item := range items {
+		n += countTocItems(item.Items)
+	}
+	return n
+}
+
+// tocRenderer implements NodeRenderer.
+type tocRenderer struct{}
+
+// RegisterFuncs registers the renderer functions.
+func (r *tocRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindTocBlock, r.renderTocBlock)
+}
+
+func (r *tocRenderer) renderTocBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+
+	n := node.(*TocBlock)
+	if len(n.Toc.Items) == 0 {
+		return ast.WalkSkipChildren, nil
+	}
+
+	w.WriteString(`<nav class="gno-toc" aria-label="Table of contents">` + "\n")
+	writeTocItems(w, n.Toc.Items)
+	w.WriteString("</nav>\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+// writeTocItems writes the given items as nested lists.
+func writeTocItems(w util.BufWriter, items []*TocItem) {
+	w.WriteString("<ul>\n")
+	for _, item := range items {
+		w.WriteString("<li>")
+		title := HTMLEscapeString(string(item.Title))
+		if len(item.ID) > 0 {
+			w.WriteString(`<a href="` + HTMLEscapeString(item.Anchor()) + `">` + title + `</a>`)
+		} else {
+			w.WriteString(title)
+		}
+		if len(item.Items) > 0 {
+			w.WriteString("\n")
+			writeTocItems(w, item.Items)
+		}
+		w.WriteString("</li>\n")
+	}
+	w.WriteString("</ul>\n")
+}
+
+// TocOption configures the table of contents extension.
+type TocOption func(e *tocExtension)
+
+// WithTocAuto inserts a table of contents before the first section of pages
+// with enough headings and no `[[toc]]` directive.
+func WithTocAuto() TocOption {
+	return func(e *tocExtension) {
+		e.auto = true
+	}
+}
+
+// tocExtension is a Goldmark extension rendering tables of contents within
+// the content.
+type tocExtension struct {
+	auto bool
+}
+
+// NewTocExtension returns an extension replacing `[[toc]]` paragraphs with a
+// nested table of contents linking to the page headings. Headings need an
+// id, such as the ones of the parser AutoHeadingID option.
+func NewTocExtension(opts ...TocOption) goldmark.Extender {
+	var e tocExtension
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return &e
+}
+
+// Extend adds the table of contents transformer and renderer to the
+// provided Goldmark markdown processor.
+func (e *tocExtension) Extend(m goldmark.Markdown) {
+	// Run after the other transformers, which may add or remove headings
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(&tocTransformer{auto: e.auto}, 950),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&tocRenderer{}, 500),
+	))
+}
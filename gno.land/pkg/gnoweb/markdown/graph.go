@@ -0,0 +1,215 @@
+package markdown
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// Graph is a directed graph, such as the imports of a package, drawn as a
+// layered diagram by WriteGraphSVG.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// GraphNode is a node of a graph. Muted nodes are drawn dashed.
+type GraphNode struct {
+	ID    string
+	Label string
+	URL   string // optional link of the node
+	Muted bool
+}
+
+// GraphEdge is an edge of a graph, from a node to another.
+type GraphEdge struct {
+	From string
+	To   string
+}
+
+const (
+	graphNodeHeight = 32
+	graphLayerGap   = 56 // vertical space between layers, room for edges
+	graphNodeGap    = 24 // horizontal space between nodes of a layer
+	graphCharWidth  = 7  // approximate width of label characters
+	graphMaxLabel   = 40 // labels are truncated past this number of runes
+	graphMargin     = 8
+)
+
+// graphBox is the laid out box of a node.
+type graphBox struct {
+	node          GraphNode
+	layer         int
+	x, y          int // top left corner
+	width, center int
+}
+
+// WriteGraphSVG writes the given graph as a static SVG image, with nodes
+// laid out in layers so that edges point downwards, and drawn with the
+// current text color like Petri nets. The markers ids are prefixed with
+// the given id. Edges to unknown nodes, and edges closing cycles, are
+// ignored.
+func WriteGraphSVG(out io.Writer, id, description string, g *Graph) error {
+	boxes := layoutGraph(g)
+
+	width, height := 2*graphMargin, 2*graphMargin
+	for _, b := range boxes {
+		width = max(width, b.x+b.width+graphMargin)
+		height = max(height, b.y+graphNodeHeight+graphMargin)
+	}
+
+	w := bufio.NewWriter(out)
+	desc := HTMLEscapeString(description)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="%s">`,
+		width, height, width, height, desc)
+	w.WriteString(`<title>` + desc + `</title>`)
+	fmt.Fprintf(w, `<defs><marker id="%s-arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">`, HTMLEscapeString(id))
+	w.WriteString(`<path d="M0,0 L10,5 L0,10 z" fill="currentColor"/></marker></defs>`)
+
+	// Edges first, so nodes are drawn over them
+	w.WriteString(`<g stroke="currentColor" stroke-width="1.5">`)
+	for _, e := range g.Edges {
+		from, ok1 := boxes[e.From]
+		to, ok2 := boxes[e.To]
+		if !ok1 || !ok2 || from.layer >= to.layer {
+			continue
+		}
+		fmt.Fprintf(w, `<line x1="%d" y1="%d" x2="%d" y2="%d" marker-end="url(#%s-arrow)"/>`,
+			from.center, from.y+graphNodeHeight, to.center, to.y, HTMLEscapeString(id))
+	}
+	w.WriteString(`</g>`)
+
+	w.WriteString(`<g font-family="sans-serif" font-size="12" text-anchor="middle">`)
+	for _, n := range g.Nodes {
+		b, ok := boxes[n.ID]
+		if !ok {
+			continue
+		}
+		if n.URL != "" {
+			w.WriteString(`<a href="` + HTMLEscapeString(n.URL) + `">`)
+		}
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="none" stroke="currentColor" stroke-width="1.5"`,
+			b.x, b.y, b.width, graphNodeHeight)
+		if n.Muted {
+			w.WriteString(` stroke-dasharray="4 3"`)
+		}
+		w.WriteString(`/>`)
+		fmt.Fprintf(w, `<text x="%d" y="%d" dominant-baseline="central" fill="currentColor">%s</text>`,
+			b.center, b.y+graphNodeHeight/2, HTMLEscapeString(graphLabel(n)))
+		if n.URL != "" {
+			w.WriteString(`</a>`)
+		}
+	}
+	w.WriteString(`</g>`)
+
+	w.WriteString(`</svg>`)
+	return w.Flush()
+}
+
+// layoutGraph lays out the nodes in layers, each node being one layer below
+// the lowest node pointing to it. Nodes of a layer are ordered by the mean
+// position of their predecessors, then by id, and layers are centered.
+func layoutGraph(g *Graph) map[string]*graphBox {
+	boxes := make(map[string]*graphBox, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if _, dup := boxes[n.ID]; !dup {
+			boxes[n.ID] = &graphBox{node: n}
+		}
+	}
+
+	preds := map[string][]string{}
+	for _, e := range g.Edges {
+		if boxes[e.From] != nil && boxes[e.To] != nil && e.From != e.To {
+			preds[e.To] = append(preds[e.To], e.From)
+		}
+	}
+
+	// Longest path layering, ignoring the edges closing cycles
+	state := map[string]int{} // 1: visiting, 2: done
+	var visit func(id string) int
+	visit = func(id string) int {
+		b := boxes[id]
+		switch state[id] {
+		case 1:
+			return -1
+		case 2:
+			return b.layer
+		}
+		state[id] = 1
+		for _, p := range preds[id] {
+			if l := visit(p); l >= 0 {
+				b.layer = max(b.layer, l+1)
+			}
+		}
+		state[id] = 2
+		return b.layer
+	}
+
+	var layers [][]*graphBox
+	for _, id := range slices.Sorted(maps.Keys(boxes)) {
+		l := visit(id)
+		for len(layers) <= l {
+			layers = append(layers, nil)
+		}
+		layers[l] = append(layers[l], boxes[id])
+	}
+
+	// Order the layers, then position their nodes
+	maxWidth := 0
+	for l, layer := range layers {
+		if l > 0 {
+			mean := func(b *graphBox) float64 {
+				var sum, n float64
+				for _, p := range preds[b.node.ID] {
+					if pb := boxes[p]; pb.layer < b.layer {
+						sum += float64(pb.center)
+						n++
+					}
+				}
+				if n == 0 {
+					return 0
+				}
+				return sum / n
+			}
+			slices.SortStableFunc(layer, func(a, b *graphBox) int {
+				return cmp.Compare(mean(a), mean(b))
+			})
+		}
+
+		x := graphMargin
+		for _, b := range layer {
+			b.width = max(80, utf8.RuneCountInString(graphLabel(b.node))*graphCharWidth+24)
+			b.x, b.y = x, graphMargin+l*(graphNodeHeight+graphLayerGap)
+			b.center = b.x + b.width/2
+			x += b.width + graphNodeGap
+		}
+		maxWidth = max(maxWidth, x-graphNodeGap)
+	}
+	for _, layer := range layers {
+		if len(layer) == 0 {
+			continue
+		}
+		last := layer[len(layer)-1]
+		shift := (maxWidth - (last.x + last.width)) / 2
+		for _, b := range layer {
+			b.x += shift
+			b.center += shift
+		}
+	}
+
+	return boxes
+}
+
+// graphLabel returns the label of the node, truncated if too long.
+func graphLabel(n GraphNode) string {
+	label := cmp.Or(n.Label, n.ID)
+	if utf8.RuneCountInString(label) > graphMaxLabel {
+		runes := []rune(label)
+		label = "…" + string(runes[len(runes)-graphMaxLabel+1:])
+	}
+	return label
+}
@@ -0,0 +1,298 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// This file implements a minimal TeX to MathML converter supporting the
+// subset of TeX commonly used in documentation: identifiers, numbers,
+// operators, groups, sub/superscripts, fractions, roots, text and the most
+// common symbols.
+
+var ErrMathInvalid = errors.New("invalid math expression")
+
+// mathSymbols maps TeX commands to their unicode symbol and MathML element.
+var mathSymbols = map[string]struct {
+	tag, value string
+}{
+	// Greek letters
+	"alpha": {"mi", "α"}, "beta": {"mi", "β"}, "gamma": {"mi", "γ"}, "delta": {"mi", "δ"},
+	"epsilon": {"mi", "ε"}, "zeta": {"mi", "ζ"}, "eta": {"mi", "η"}, "theta": {"mi", "θ"},
+	"iota": {"mi", "ι"}, "kappa": {"mi", "κ"}, "lambda": {"mi", "λ"}, "mu": {"mi", "μ"},
+	"nu": {"mi", "ν"}, "xi": {"mi", "ξ"}, "pi": {"mi", "π"}, "rho": {"mi", "ρ"},
+	"sigma": {"mi", "σ"}, "tau": {"mi", "τ"}, "upsilon": {"mi", "υ"}, "phi": {"mi", "φ"},
+	"chi": {"mi", "χ"}, "psi": {"mi", "ψ"}, "omega": {"mi", "ω"},
+	"Gamma": {"mi", "Γ"}, "Delta": {"mi", "Δ"}, "Theta": {"mi", "Θ"}, "Lambda": {"mi", "Λ"},
+	"Xi": {"mi", "Ξ"}, "Pi": {"mi", "Π"}, "Sigma": {"mi", "Σ"}, "Phi": {"mi", "Φ"},
+	"Psi": {"mi", "Ψ"}, "Omega": {"mi", "Ω"},
+
+	// Miscellaneous symbols
+	"infty": {"mi", "∞"}, "partial": {"mi", "∂"}, "nabla": {"mi", "∇"},
+
+	// Operators and relations
+	"cdot": {"mo", "⋅"}, "times": {"mo", "×"}, "div": {"mo", "÷"}, "pm": {"mo", "±"},
+	"mp": {"mo", "∓"}, "leq": {"mo", "≤"}, "le": {"mo", "≤"}, "geq": {"mo", "≥"},
+	"ge": {"mo", "≥"}, "neq": {"mo", "≠"}, "ne": {"mo", "≠"}, "approx": {"mo", "≈"},
+	"equiv": {"mo", "≡"}, "sim": {"mo", "∼"}, "in": {"mo", "∈"}, "notin": {"mo", "∉"},
+	"subset": {"mo", "⊂"}, "subseteq": {"mo", "⊆"}, "cup": {"mo", "∪"}, "cap": {"mo", "∩"},
+	"to": {"mo", "→"}, "rightarrow": {"mo", "→"}, "leftarrow": {"mo", "←"},
+	"Rightarrow": {"mo", "⇒"}, "Leftrightarrow": {"mo", "⇔"}, "forall": {"mo", "∀"},
+	"exists": {"mo", "∃"}, "sum": {"mo", "∑"}, "prod": {"mo", "∏"}, "int": {"mo", "∫"},
+	"cdots": {"mo", "⋯"}, "ldots": {"mo", "…"},
+
+	// Functions
+	"log": {"mi", "log"}, "ln": {"mi", "ln"}, "exp": {"mi", "exp"}, "sin": {"mi", "sin"},
+	"cos": {"mi", "cos"}, "tan": {"mi", "tan"}, "min": {"mi", "min"}, "max": {"mi", "max"},
+	"lim": {"mi", "lim"},
+
+	// Spacing and delimiters
+	",": {"mspace", ""}, ";": {"mspace", ""}, "quad": {"mspace", ""},
+	"{": {"mo", "{"}, "}": {"mo", "}"}, "%": {"mo", "%"}, "$": {"mo", "$"},
+	"left": {"", ""}, "right": {"", ""},
+}
+
+// TexToMathML converts the given TeX expression into a MathML element.
+func TexToMathML(tex string, display bool) (string, error) {
+	p := &texParser{src: []rune(tex)}
+	body, err := p.parseExpr(false)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMathInvalid, err)
+	}
+
+	mode := "inline"
+	if display {
+		mode = "block"
+	}
+
+	return `<math xmlns="http://www.w3.org/1998/Math/MathML" display="` + mode + `"><mrow>` + body + `</mrow></math>`, nil
+}
+
+type texParser struct {
+	src []rune
+	pos int
+}
+
+func (p *texParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *texParser) peek() rune { return p.src[p.pos] }
+
+func (p *texParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// parseExpr parses a sequence of atoms until the end of the input, or until
+// the closing brace if `group` is true.
+func (p *texParser) parseExpr(group bool) (string, error) {
+	var sb strings.Builder
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			if group {
+				return "", errors.New("missing closing brace")
+			}
+			return sb.String(), nil
+		}
+
+		if p.peek() == '}' {
+			if !group {
+				return "", errors.New("unexpected closing brace")
+			}
+			p.pos++
+			return sb.String(), nil
+		}
+
+		atom, err := p.parseScripted()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(atom)
+	}
+}
+
+// parseScripted parses an atom followed by optional sub and superscripts.
+func (p *texParser) parseScripted() (string, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return "", err
+	}
+
+	var sub, sup string
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+
+		c := p.peek()
+		if c != '_' && c != '^' {
+			break
+		}
+		p.pos++
+
+		script, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case c == '_' && sub == "":
+			sub = script
+		case c == '^' && sup == "":
+			sup = script
+		default:
+			return "", fmt.Errorf("double %q script", c)
+		}
+	}
+
+	switch {
+	case sub != "" && sup != "":
+		return "<msubsup>" + base + sub + sup + "</msubsup>", nil
+	case sub != "":
+		return "<msub>" + base + sub + "</msub>", nil
+	case sup != "":
+		return "<msup>" + base + sup + "</msup>", nil
+	default:
+		return base, nil
+	}
+}
+
+// parseArg parses a single argument, either a group or an atom, always
+// wrapped in a single element.
+func (p *texParser) parseArg() (string, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return "", errors.New("missing argument")
+	}
+
+	if p.peek() == '{' {
+		p.pos++
+		inner, err := p.parseExpr(true)
+		if err != nil {
+			return "", err
+		}
+		return "<mrow>" + inner + "</mrow>", nil
+	}
+
+	return p.parseAtom()
+}
+
+// parseAtom parses a single atom: a group, a command, a number, an
+// identifier or an operator.
+func (p *texParser) parseAtom() (string, error) {
+	c := p.peek()
+	switch {
+	case c == '{':
+		return p.parseArg()
+	case c == '\\':
+		p.pos++
+		return p.parseCommand()
+	case c == '^' || c == '_':
+		return "", fmt.Errorf("unexpected %q", c)
+	case unicode.IsDigit(c):
+		start := p.pos
+		for !p.eof() && (unicode.IsDigit(p.peek()) || p.peek() == '.') {
+			p.pos++
+		}
+		return "<mn>" + string(p.src[start:p.pos]) + "</mn>", nil
+	case unicode.IsLetter(c):
+		p.pos++
+		return "<mi>" + HTMLEscapeString(string(c)) + "</mi>", nil
+	default:
+		p.pos++
+		return "<mo>" + HTMLEscapeString(string(c)) + "</mo>", nil
+	}
+}
+
+// parseCommand parses a command following a backslash.
+func (p *texParser) parseCommand() (string, error) {
+	if p.eof() {
+		return "", errors.New("trailing backslash")
+	}
+
+	start := p.pos
+	if unicode.IsLetter(p.peek()) {
+		for !p.eof() && unicode.IsLetter(p.peek()) {
+			p.pos++
+		}
+	} else {
+		p.pos++ // single character command, such as `\{`
+	}
+	name := string(p.src[start:p.pos])
+
+	switch name {
+	case "frac":
+		num, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		den, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		return "<mfrac>" + num + den + "</mfrac>", nil
+
+	case "sqrt":
+		p.skipSpaces()
+		var index string
+		if !p.eof() && p.peek() == '[' {
+			end := p.pos
+			for end < len(p.src) && p.src[end] != ']' {
+				end++
+			}
+			if end >= len(p.src) {
+				return "", errors.New("missing closing bracket")
+			}
+			sub := &texParser{src: p.src[p.pos+1 : end]}
+			inner, err := sub.parseExpr(false)
+			if err != nil {
+				return "", err
+			}
+			index = "<mrow>" + inner + "</mrow>"
+			p.pos = end + 1
+		}
+
+		radicand, err := p.parseArg()
+		if err != nil {
+			return "", err
+		}
+		if index != "" {
+			return "<mroot>" + radicand + index + "</mroot>", nil
+		}
+		return "<msqrt>" + radicand + "</msqrt>", nil
+
+	case "text", "mathrm":
+		p.skipSpaces()
+		if p.eof() || p.peek() != '{' {
+			return "", fmt.Errorf(`missing argument for \%s`, name)
+		}
+		end := p.pos + 1
+		for end < len(p.src) && p.src[end] != '}' {
+			end++
+		}
+		if end >= len(p.src) {
+			return "", errors.New("missing closing brace")
+		}
+		text := string(p.src[p.pos+1 : end])
+		p.pos = end + 1
+		return "<mtext>" + HTMLEscapeString(text) + "</mtext>", nil
+	}
+
+	sym, ok := mathSymbols[name]
+	if !ok {
+		return "", fmt.Errorf(`unknown command \%s`, name)
+	}
+
+	switch sym.tag {
+	case "":
+		return "", nil
+	case "mspace":
+		return `<mspace width="0.3em"></mspace>`, nil
+	default:
+		return "<" + sym.tag + ">" + HTMLEscapeString(sym.value) + "</" + sym.tag + ">", nil
+	}
+}
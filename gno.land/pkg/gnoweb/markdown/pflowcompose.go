@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+var ErrPflowIncompatible = errors.New("incompatible pflow interfaces")
+
+// ComposePflowModels composes the given nets into a single one, by gluing
+// them along their interface: places, or transitions, sharing an id across
+// nets are fused into one node, the other nodes being kept side by side.
+// Fused places must agree on their initial tokens and capacity, and a node
+// can't be a place of one net and a transition of another. Fused nodes keep
+// the position of their first occurrence, and places are renumbered in
+// order of appearance. Arcs are concatenated, duplicate arcs being merged
+// if identical.
+func ComposePflowModels(models ...*PflowModel) (*PflowModel, error) {
+	composed := &PflowModel{
+		ModelType:   "petriNet",
+		Places:      map[string]PflowPlace{},
+		Transitions: map[string]PflowTransition{},
+	}
+	type arcKey struct{ source, target string }
+	arcs := map[arcKey]PflowArc{}
+
+	for i, m := range models {
+		if composed.Version == "" {
+			composed.Version = m.Version
+		}
+
+		// Keep the offsets order within each net
+		places := slices.SortedFunc(maps.Keys(m.Places), func(a, b string) int {
+			return m.Places[a].Offset - m.Places[b].Offset
+		})
+		for _, id := range places {
+			p := m.Places[id]
+			if _, ok := composed.Transitions[id]; ok {
+				return nil, fmt.Errorf("%w: net %d: place %q is a transition of a previous net", ErrPflowIncompatible, i+1, id)
+			}
+			prev, ok := composed.Places[id]
+			if !ok {
+				p.Offset = len(composed.Places)
+				composed.Places[id] = p
+				continue
+			}
+			if prev.Initial != p.Initial || prev.Capacity != p.Capacity {
+				return nil, fmt.Errorf("%w: net %d: place %q has %d initial tokens and a capacity of %d, previously %d and %d",
+					ErrPflowIncompatible, i+1, id, p.Initial, p.Capacity, prev.Initial, prev.Capacity)
+			}
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(m.Transitions)) {
+			// Valid nets don't reuse their own place ids for transitions
+			if _, ok := composed.Places[id]; ok {
+				return nil, fmt.Errorf("%w: net %d: transition %q is a place of a previous net", ErrPflowIncompatible, i+1, id)
+			}
+			if _, ok := composed.Transitions[id]; !ok {
+				composed.Transitions[id] = m.Transitions[id]
+			}
+		}
+
+		for _, arc := range m.Arcs {
+			key := arcKey{arc.Source, arc.Target}
+			prev, ok := arcs[key]
+			switch {
+			case !ok:
+				arcs[key] = arc
+				composed.Arcs = append(composed.Arcs, arc)
+			case arcWeight(prev) != arcWeight(arc) || prev.Inhibit != arc.Inhibit:
+				return nil, fmt.Errorf("%w: net %d: arc from %q to %q differs from the one of a previous net",
+					ErrPflowIncompatible, i+1, arc.Source, arc.Target)
+			}
+		}
+	}
+
+	if err := composed.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPflowInvalid, err)
+	}
+	return composed, nil
+}
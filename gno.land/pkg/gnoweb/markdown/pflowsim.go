@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrPflowNotEnabled is returned when firing a transition which is not
+// enabled.
+var ErrPflowNotEnabled = errors.New("transition not enabled")
+
+// PflowMarking holds the number of tokens of each place of a Petri net.
+type PflowMarking map[string]int
+
+// InitialMarking returns the initial tokens of the places of the model.
+func (m *PflowModel) InitialMarking() PflowMarking {
+	marking := make(PflowMarking, len(m.Places))
+	for id, p := range m.Places {
+		marking[id] = p.Initial
+	}
+	return marking
+}
+
+// arcWeight returns the weight of the arc, which defaults to one.
+func arcWeight(arc PflowArc) int {
+	return max(arc.Weight, 1)
+}
+
+// Enabled returns an error wrapping ErrPflowNotEnabled if the transition
+// cannot fire from the given marking. A transition is enabled if its input
+// places hold enough tokens, its output places have room for the produced
+// ones, and its inhibitor arcs allow it: an inhibitor arc from a place
+// disables the transition once the place holds weight tokens, an inhibitor
+// arc to a place disables it until the place holds weight tokens.
+func (m *PflowModel) Enabled(marking PflowMarking, transition string) error {
+	if _, ok := m.Transitions[transition]; !ok {
+		return fmt.Errorf("unknown transition %q", transition)
+	}
+
+	next := maps.Clone(marking)
+	for _, arc := range m.Arcs {
+		w := arcWeight(arc)
+		switch {
+		case arc.Target == transition && arc.Inhibit:
+			if marking[arc.Source] >= w {
+				return fmt.Errorf("%w: %q is inhibited by %q", ErrPflowNotEnabled, transition, arc.Source)
+			}
+		case arc.Source == transition && arc.Inhibit:
+			if marking[arc.Target] < w {
+				return fmt.Errorf("%w: %q is inhibited by %q", ErrPflowNotEnabled, transition, arc.Target)
+			}
+		case arc.Target == transition:
+			if next[arc.Source] -= w; next[arc.Source] < 0 {
+				return fmt.Errorf("%w: %q lacks tokens in %q", ErrPflowNotEnabled, transition, arc.Source)
+			}
+		}
+	}
+
+	for _, arc := range m.Arcs {
+		if arc.Source != transition || arc.Inhibit {
+			continue
+		}
+		next[arc.Target] += arcWeight(arc)
+		if capacity := m.Places[arc.Target].Capacity; capacity > 0 && next[arc.Target] > capacity {
+			return fmt.Errorf("%w: %q would exceed the capacity of %q", ErrPflowNotEnabled, transition, arc.Target)
+		}
+	}
+
+	return nil
+}
+
+// Fire fires the transition from the given marking, and returns the
+// resulting marking.
+func (m *PflowModel) Fire(marking PflowMarking, transition string) (PflowMarking, error) {
+	if err := m.Enabled(marking, transition); err != nil {
+		return marking, err
+	}
+
+	next := maps.Clone(marking)
+	for _, arc := range m.Arcs {
+		switch {
+		case arc.Inhibit:
+		case arc.Target == transition:
+			next[arc.Source] -= arcWeight(arc)
+		case arc.Source == transition:
+			next[arc.Target] += arcWeight(arc)
+		}
+	}
+	return next, nil
+}
+
+// EnabledTransitions returns the sorted transitions enabled from the given
+// marking.
+func (m *PflowModel) EnabledTransitions(marking PflowMarking) []string {
+	enabled := []string{}
+	for _, id := range slices.Sorted(maps.Keys(m.Transitions)) {
+		if m.Enabled(marking, id) == nil {
+			enabled = append(enabled, id)
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,138 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// plainTextParser parses the markdown converted by PlainText.
+var plainTextParser = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+// PlainText converts markdown to plain text, for terminals and tools. Blocks
+// are separated by blank lines, list items keep their markers, code blocks
+// are kept as is, links are followed by their destination and images are
+// replaced by their alternative text. Raw HTML is dropped.
+func PlainText(src []byte) []byte {
+	doc := plainTextParser.Parse(text.NewReader(src))
+
+	var buf bytes.Buffer
+	writePlainBlocks(&buf, src, doc, "")
+	return append(bytes.TrimSpace(buf.Bytes()), '\n')
+}
+
+// writePlainBlocks writes the children blocks of the node, each line
+// prefixed with the given indentation.
+func writePlainBlocks(buf *bytes.Buffer, src []byte, n ast.Node, indent string) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		writePlainBlock(buf, src, c, indent)
+	}
+}
+
+func writePlainBlock(buf *bytes.Buffer, src []byte, n ast.Node, indent string) {
+	switch n := n.(type) {
+	case *ast.Heading, *ast.Paragraph, *ast.TextBlock:
+		writePlainLines(buf, indent, plainInline(src, n))
+		if _, tight := n.(*ast.TextBlock); !tight {
+			buf.WriteByte('\n')
+		}
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		var code bytes.Buffer
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			seg := lines.At(i)
+			code.Write(seg.Value(src))
+		}
+		writePlainLines(buf, indent+"    ", strings.TrimRight(code.String(), "\n"))
+		buf.WriteByte('\n')
+	case *ast.Blockquote:
+		writePlainBlocks(buf, src, n, indent+"> ")
+	case *ast.List:
+		number := n.Start
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			marker := "- "
+			if n.IsOrdered() {
+				marker = strconv.Itoa(number) + ". "
+				number++
+			}
+			var sub bytes.Buffer
+			writePlainBlocks(&sub, src, item, "")
+			lines := strings.Split(strings.TrimRight(sub.String(), "\n"), "\n")
+			for i, line := range lines {
+				prefix := strings.Repeat(" ", len(marker))
+				if i == 0 {
+					prefix = marker
+				}
+				buf.WriteString(strings.TrimRight(indent+prefix+line, " ") + "\n")
+			}
+		}
+		buf.WriteByte('\n')
+	case *ast.ThematicBreak:
+		buf.WriteString(indent + "---\n\n")
+	case *extast.Table:
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				cells = append(cells, plainInline(src, cell))
+			}
+			buf.WriteString(indent + strings.Join(cells, " | ") + "\n")
+		}
+		buf.WriteByte('\n')
+	case *ast.HTMLBlock:
+		// Dropped
+	default:
+		writePlainBlocks(buf, src, n, indent)
+	}
+}
+
+// writePlainLines writes the lines of s, each prefixed with indent.
+func writePlainLines(buf *bytes.Buffer, indent, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		buf.WriteString(strings.TrimRight(indent+line, " ") + "\n")
+	}
+}
+
+// plainInline returns the text of the inline children of the node.
+func plainInline(src []byte, n ast.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		writePlainInline(&b, src, c)
+	}
+	return b.String()
+}
+
+func writePlainInline(b *strings.Builder, src []byte, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		b.Write(n.Segment.Value(src))
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			b.WriteByte('\n')
+		}
+	case *ast.String:
+		b.Write(n.Value)
+	case *ast.CodeSpan:
+		b.Write(nodeText(src, n))
+	case *ast.Image:
+		b.WriteString(altText(n, src))
+	case *ast.Link:
+		label := plainInline(src, n)
+		b.WriteString(label)
+		if dest := string(n.Destination); dest != "" && dest != label && !strings.HasPrefix(dest, "#") {
+			b.WriteString(" (" + dest + ")")
+		}
+	case *ast.AutoLink:
+		b.Write(n.URL(src))
+	case *ast.RawHTML:
+		// Dropped
+	default:
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			writePlainInline(b, src, c)
+		}
+	}
+}
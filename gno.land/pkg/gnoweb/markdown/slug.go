@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slugify returns the anchor slug of the given text. The text is first NFC
+// normalized, so that visually identical texts produce the same slug. Then
+// letters and digits are lowercased, spaces, `-` and `_` are replaced by
+// `-`, and any other rune such as punctuation or emoji is dropped.
+//
+// For ASCII texts, slugs are the same as Goldmark's default heading IDs.
+func Slugify(s string) string {
+	s = strings.TrimSpace(norm.NFC.String(s))
+
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r):
+			sb.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r), r == '-', r == '_':
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// slugIDs implements parser.IDs, generating unique element IDs with
+// Slugify.
+type slugIDs map[string]bool
+
+var _ parser.IDs = slugIDs(nil)
+
+// Generate implements IDs.Generate.
+func (s slugIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := Slugify(string(value))
+	if id == "" {
+		if kind == ast.KindHeading {
+			id = "heading"
+		} else {
+			id = "id"
+		}
+	}
+
+	if !s[id] {
+		s[id] = true
+		return []byte(id)
+	}
+
+	for i := 1; ; i++ {
+		if next := fmt.Sprintf("%s-%d", id, i); !s[next] {
+			s[next] = true
+			return []byte(next)
+		}
+	}
+}
+
+// Put implements IDs.Put.
+func (s slugIDs) Put(value []byte) {
+	s[string(value)] = true
+}
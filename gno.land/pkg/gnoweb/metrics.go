@@ -0,0 +1,208 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath is the path of the Prometheus metrics.
+const MetricsPath = "/metrics"
+
+// metricsNamespace prefixes the names of the gnoweb metrics.
+const metricsNamespace = "gnoweb"
+
+// Route classes of the request metrics.
+const (
+	routeRealm     = "realm"
+	routeSource    = "source"
+	routeHelp      = "help"
+	routePackage   = "package"
+	routeUser      = "user"
+	routeAssets    = "assets"
+	routeWebSocket = "websocket"
+	routeOther     = "other"
+)
+
+// Metrics records the Prometheus metrics of gnoweb: the requests by route
+// class, the RPC calls to the node, and the cache lookups.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	rpcDuration     *prometheus.HistogramVec
+	cacheLookups    *prometheus.CounterVec
+}
+
+// NewMetrics returns metrics registered in a new registry, along with the
+// Go runtime and process metrics.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests, by route class, method and status code.",
+		}, []string{"route", "method", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests, by route class.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_call_duration_seconds",
+			Help:      "Duration of the node RPC calls, by method and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "result"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of cache lookups, by cache and result.",
+		}, []string{"cache", "result"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests, m.requestDuration, m.rpcDuration, m.cacheLookups,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the count and the duration of requests by route
+// class, and the page cache lookups, reported by the `X-Gnoweb-Cache`
+// response header. The duration of WebSocket connections is not recorded.
+func (m *Metrics) Middleware(next http.Handler, assetsBase string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := routeClass(r, assetsBase)
+
+		rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		m.requests.WithLabelValues(route, r.Method, strconv.Itoa(rw.status)).Inc()
+		if route != routeWebSocket {
+			m.requestDuration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+		}
+		if result := w.Header().Get("X-Gnoweb-Cache"); result != "" {
+			m.cacheLookups.WithLabelValues("page", result).Inc()
+		}
+	})
+}
+
+// routeClass returns the route class of the request, for metrics labels of
+// bounded cardinality.
+func routeClass(r *http.Request, assetsBase string) string {
+	switch r.URL.Path {
+	case RealtimePath, LiveReloadPath:
+		return routeWebSocket
+	}
+	if strings.HasPrefix(r.URL.Path, assetsBase) {
+		return routeAssets
+	}
+
+	gnourl, err := weburl.ParseFromURL(r.URL)
+	if err != nil {
+		return routeOther
+	}
+
+	switch {
+	case gnourl.WebQuery.Has("source"), gnourl.IsFile():
+		return routeSource
+	case gnourl.WebQuery.Has("help"):
+		return routeHelp
+	case gnourl.IsRealm():
+		return routeRealm
+	case gnourl.IsPure():
+		return routePackage
+	case gnourl.IsUser():
+		return routeUser
+	default:
+		return routeOther
+	}
+}
+
+// WithMetrics records the duration of each call to the underlying client.
+// It is meant to be the innermost middleware, so that retries are recorded
+// as distinct calls.
+func WithMetrics(m *Metrics) ClientMiddleware {
+	return func(next ClientAdapter) ClientAdapter {
+		if m == nil {
+			return next
+		}
+		return &metricsClient{next: next, m: m}
+	}
+}
+
+// metricsClient records the duration of the calls to the next client.
+type metricsClient struct {
+	next ClientAdapter
+	m    *Metrics
+}
+
+func (c *metricsClient) observe(method string, start time.Time, err error) {
+	result := "ok"
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrClientTimeout):
+		result = "timeout"
+	case errors.Is(err, ErrClientPackageNotFound), errors.Is(err, ErrClientFileNotFound),
+		errors.Is(err, ErrClientRenderNotDeclared):
+		result = "not_found"
+	default:
+		result = "error"
+	}
+	c.m.rpcDuration.WithLabelValues(method, result).Observe(time.Since(start).Seconds())
+}
+
+func (c *metricsClient) Realm(ctx context.Context, path, args string) ([]byte, error) {
+	start := time.Now()
+	out, err := c.next.Realm(ctx, path, args)
+	c.observe("realm", start, err)
+	return out, err
+}
+
+func (c *metricsClient) File(ctx context.Context, path, filename string) ([]byte, FileMeta, error) {
+	start := time.Now()
+	out, meta, err := c.next.File(ctx, path, filename)
+	c.observe("file", start, err)
+	return out, meta, err
+}
+
+func (c *metricsClient) ListFiles(ctx context.Context, path string) ([]string, error) {
+	start := time.Now()
+	files, err := c.next.ListFiles(ctx, path)
+	c.observe("list_files", start, err)
+	return files, err
+}
+
+func (c *metricsClient) ListPaths(ctx context.Context, prefix string, limit int) ([]string, error) {
+	start := time.Now()
+	paths, err := c.next.ListPaths(ctx, prefix, limit)
+	c.observe("list_paths", start, err)
+	return paths, err
+}
+
+func (c *metricsClient) Doc(ctx context.Context, path string) (*doc.JSONDocumentation, error) {
+	start := time.Now()
+	jdoc, err := c.next.Doc(ctx, path)
+	c.observe("doc", start, err)
+	return jdoc, err
+}
@@ -0,0 +1,80 @@
+package gnoweb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteClass(t *testing.T) {
+	t.Parallel()
+
+	for path, expected := range map[string]string{
+		"/r/demo/boards":           routeRealm,
+		"/r/demo/boards:board/1":   routeRealm,
+		"/r/demo/boards$source":    routeSource,
+		"/r/demo/boards/board.gno": routeSource,
+		"/r/demo/boards$help":      routeHelp,
+		"/p/demo/avl":              routePackage,
+		"/u/alice":                 routeUser,
+		"/public/main.css":         routeAssets,
+		RealtimePath:               routeWebSocket,
+		"/":                        routeOther,
+	} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		assert.Equal(t, expected, routeClass(r, "/public/"), path)
+	}
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/r/demo/boards":
+			w.Header().Set("X-Gnoweb-Cache", "hit")
+		case "/r/demo/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}), "/public/")
+
+	for _, path := range []string{"/r/demo/boards", "/r/demo/boards", "/r/demo/missing", "/public/main.css"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues(routeRealm, http.MethodGet, "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(routeRealm, http.MethodGet, "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(routeAssets, http.MethodGet, "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("page", "hit")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.requestDuration))
+}
+
+func TestMetrics_Client(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	cli := ChainClient(&catalogClient{realms: map[string]string{"/r/demo/boards": "# Boards"}}, WithMetrics(m))
+
+	_, err := cli.Realm(context.Background(), "/r/demo/boards", "")
+	require.NoError(t, err)
+	_, err = cli.Realm(context.Background(), "/r/demo/unknown", "")
+	require.Error(t, err)
+	_, err = cli.ListPaths(context.Background(), "gno.land/r/", 10)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `gnoweb_rpc_call_duration_seconds_count{method="realm",result="ok"} 1`)
+	assert.Contains(t, body, `gnoweb_rpc_call_duration_seconds_count{method="realm",result="not_found"} 1`)
+	assert.Contains(t, body, `gnoweb_rpc_call_duration_seconds_count{method="list_paths",result="ok"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
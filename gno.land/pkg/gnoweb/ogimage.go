@@ -0,0 +1,193 @@
+package gnoweb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// OGImagePath is the prefix of the social preview images of realms, such as
+// `/_og/r/demo/boards.svg`.
+const OGImagePath = "/_og/"
+
+const (
+	// ogImageTTL is the duration generated images are cached on disk for.
+	ogImageTTL = time.Hour
+	// ogImageLineLength bounds the number of runes of a title line.
+	ogImageLineLength = 28
+	// ogImageMaxLines bounds the number of title lines.
+	ogImageMaxLines = 3
+)
+
+// ogImageURL returns the absolute URL of the preview image of the realm.
+func ogImageURL(origin string, gnourl *weburl.GnoURL) string {
+	return origin + strings.TrimSuffix(OGImagePath, "/") + gnourl.Path + ".svg"
+}
+
+// handlerOGImage serves 1200x630 SVG social preview images of realms, made
+// of their title and the domain. Images are cached in dir, keyed by realm
+// path, and regenerated once older than ogImageTTL.
+func handlerOGImage(h *HTTPHandler, dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		path, ok := strings.CutSuffix(r.URL.Path, ".svg")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		u := &weburl.GnoURL{Path: strings.TrimPrefix(path, strings.TrimSuffix(OGImagePath, "/"))}
+		if !u.IsRealm() || !u.IsValidPath() || strings.HasSuffix(u.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(u.Path))
+		filename := filepath.Join(dir, hex.EncodeToString(sum[:])+".svg")
+
+		img, err := readFreshFile(filename, ogImageTTL)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				h.Logger.Warn("unable to read cached og image", "file", filename, "error", err)
+			}
+
+			title, err := h.ogImageTitle(r, u)
+			if err != nil {
+				h.Logger.Debug("unable to fetch og image realm", "error", err, "path", u.Path)
+				status, _ := GetClientErrorStatusPage(u, err)
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+
+			img = renderOGImage(title, h.Static.Domain)
+			if err := osm.EnsureDir(dir, 0o755); err != nil {
+				h.Logger.Warn("unable to create og image cache", "dir", dir, "error", err)
+			} else if err := osm.WriteFileAtomic(filename, img, 0o644); err != nil {
+				h.Logger.Warn("unable to cache og image", "file", filename, "error", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(ogImageTTL.Seconds())))
+		w.Header().Set("Content-Security-Policy", "default-src 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if r.Method == http.MethodGet {
+			w.Write(img)
+		}
+	})
+}
+
+// ogImageTitle returns the title of the realm: the one of its frontmatter,
+// its first heading, or its path.
+func (h *HTTPHandler) ogImageTitle(r *http.Request, u *weburl.GnoURL) (string, error) {
+	raw, err := h.Client.Realm(r.Context(), u.Path, "")
+	if err != nil {
+		if errors.Is(err, ErrClientRenderNotDeclared) {
+			return u.Path, nil
+		}
+		return "", err
+	}
+
+	var content bytes.Buffer
+	meta, err := h.Renderer.RenderRealm(&content, u, raw)
+	if err != nil {
+		return "", err
+	}
+
+	title := meta.Frontmatter.Title
+	if title == "" {
+		title = meta.Title
+	}
+	return sanitizeTitle(title, u.Path, h.MaxTitleLength), nil
+}
+
+// readFreshFile returns the content of the file, or fs.ErrNotExist if it was
+// modified more than ttl ago.
+func readFreshFile(filename string, ttl time.Duration) ([]byte, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > ttl {
+		return nil, fs.ErrNotExist
+	}
+	return osm.ReadFile(filename)
+}
+
+// renderOGImage returns the SVG preview image of the given title and domain.
+func renderOGImage(title, domain string) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="1200" height="630" viewBox="0 0 1200 630">`)
+	b.WriteString(`<rect width="1200" height="630" fill="#f0f0f0"/>`)
+	b.WriteString(`<rect x="0" y="590" width="1200" height="40" fill="#226c57"/>`)
+	b.WriteString(`<g font-family="Roboto, Helvetica, Arial, sans-serif" fill="#080809">`)
+
+	lines := wrapOGTitle(title)
+	y := 315 - (len(lines)-1)*45
+	for _, line := range lines {
+		fmt.Fprintf(&b, `<text x="80" y="%d" font-size="76" font-weight="700">%s</text>`, y, html.EscapeString(line))
+		y += 90
+	}
+	fmt.Fprintf(&b, `<text x="80" y="530" font-size="36" fill="#54595d">%s</text>`, html.EscapeString(domain))
+
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
+
+// wrapOGTitle splits the title into lines of at most ogImageLineLength runes,
+// on word boundaries, truncating it past ogImageMaxLines.
+func wrapOGTitle(title string) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(title) {
+		for utf8.RuneCountInString(word) > ogImageLineLength {
+			if line != "" {
+				lines, line = append(lines, line), ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:ogImageLineLength]))
+			word = string(runes[ogImageLineLength:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= ogImageLineLength:
+			line += " " + word
+		default:
+			lines, line = append(lines, line), word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > ogImageMaxLines {
+		lines = lines[:ogImageMaxLines]
+		last := []rune(lines[ogImageMaxLines-1])
+		if len(last) >= ogImageLineLength {
+			last = last[:ogImageLineLength-1]
+		}
+		lines[ogImageMaxLines-1] = string(last) + "…"
+	}
+	return lines
+}
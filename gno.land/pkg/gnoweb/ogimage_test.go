@@ -0,0 +1,102 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOGImage(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{realms: map[string]string{
+		"/r/demo/boards": "# Boards & Forums\n\nA simple forum.\n",
+		"/r/demo/blog":   "---\ntitle: Gno Blog\n---\n\n# Home\n",
+	}}
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land", OGImages: true},
+	})
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "og")
+	handler := handlerOGImage(h, dir)
+
+	t.Run("realm", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_og/r/demo/boards.svg", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "image/svg+xml", rr.Header().Get("Content-Type"))
+
+		body := rr.Body.String()
+		assert.True(t, strings.HasPrefix(body, "<svg "))
+		assert.Contains(t, body, ">Boards &amp; Forums</text>")
+		assert.Contains(t, body, ">gno.land</text>")
+
+		// Served from the disk cache afterwards
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		cached, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+		require.NoError(t, err)
+		assert.Equal(t, body, string(cached))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, entries[0].Name()), []byte("<svg>cached</svg>"), 0o644))
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_og/r/demo/boards.svg", nil))
+		assert.Equal(t, "<svg>cached</svg>", rr.Body.String())
+	})
+
+	t.Run("frontmatter", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_og/r/demo/blog.svg", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), ">Gno Blog</text>")
+	})
+
+	t.Run("without render", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_og/r/demo/norender.svg", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), ">/r/demo/norender</text>")
+	})
+
+	t.Run("invalid paths", func(t *testing.T) {
+		for _, path := range []string{"/_og/r/demo/boards", "/_og/p/demo/avl.svg", "/_og/r/demo/.svg", "/_og/r/demo/../../etc.svg"} {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, rr.Code, path)
+		}
+	})
+
+	t.Run("meta tags", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `<meta property="og:image" content="http://example.com/_og/r/demo/boards.svg" />`)
+	})
+}
+
+func TestWrapOGTitle(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"Hello world"}, wrapOGTitle("Hello world"))
+	assert.Equal(t, []string{"The quick brown fox jumps", "over the lazy dog"},
+		wrapOGTitle("The quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, []string{strings.Repeat("a", 28), "aaaa b"}, wrapOGTitle(strings.Repeat("a", 32)+" b"))
+
+	lines := wrapOGTitle(strings.Repeat("word ", 40))
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[2], "…"))
+}
@@ -0,0 +1,72 @@
+package gnoweb
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// OpenSearchPath is the path of the OpenSearch description document.
+const OpenSearchPath = "/opensearch.xml"
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Method   string `xml:"method,attr"`
+	Template string `xml:"template,attr"`
+}
+
+type openSearchImage struct {
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Type   string `xml:"type,attr"`
+	URL    string `xml:",chardata"`
+}
+
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	Image         openSearchImage `xml:"Image"`
+	URL           openSearchURL   `xml:"Url"`
+}
+
+// handlerOpenSearch serves an OpenSearch description document, allowing
+// browsers to register gnoweb as a search engine. Queries are sent to the
+// full-text search if enabled, or else resolved as paths, such as
+// `r/demo/boards`, like in the header search bar.
+func handlerOpenSearch(siteName, assetsBase string, fullText bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := requestOrigin(r)
+		template := origin + "/{searchTerms}"
+		if fullText {
+			template = origin + SearchPath + "?q={searchTerms}"
+		}
+
+		desc := openSearchDescription{
+			ShortName:     siteName,
+			Description:   "Search " + siteName + " packages and realms",
+			InputEncoding: "UTF-8",
+			Image: openSearchImage{
+				Width:  16,
+				Height: 16,
+				Type:   "image/x-icon",
+				URL:    origin + assetsBase + "favicon.ico",
+			},
+			URL: openSearchURL{
+				Type:     "text/html",
+				Method:   "get",
+				Template: template,
+			},
+		}
+
+		out, err := xml.MarshalIndent(desc, "", "  ")
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/opensearchdescription+xml; charset=utf-8")
+		w.Write([]byte(xml.Header))
+		w.Write(out)
+	})
+}
@@ -0,0 +1,43 @@
+package gnoweb
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOpenSearch(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "http://gno.example.com"+OpenSearchPath, nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rr := httptest.NewRecorder()
+	handlerOpenSearch("gno.land", "/public/", false).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/opensearchdescription+xml; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), `<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">`)
+
+	var desc openSearchDescription
+	require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &desc))
+	assert.Equal(t, "gno.land", desc.ShortName)
+	assert.Equal(t, "UTF-8", desc.InputEncoding)
+	assert.Equal(t, "https://gno.example.com/public/favicon.ico", desc.Image.URL)
+	assert.Equal(t, "text/html", desc.URL.Type)
+	assert.Equal(t, "https://gno.example.com/{searchTerms}", desc.URL.Template)
+}
+
+func TestHandlerOpenSearch_FullText(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	handlerOpenSearch("gno.land", "/public/", true).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, OpenSearchPath, nil))
+
+	var desc openSearchDescription
+	require.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &desc))
+	assert.Equal(t, "http://example.com/search?q={searchTerms}", desc.URL.Template)
+}
@@ -0,0 +1,184 @@
+package gnoweb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// DefaultPageCacheSize is the default number of rendered pages cached.
+	DefaultPageCacheSize = 512
+
+	// pageCacheMaxBody bounds the size of cached pages.
+	pageCacheMaxBody = 1 << 20
+	// pageCacheHeightTTL is the duration the block height is checked for.
+	pageCacheHeightTTL = time.Second
+)
+
+// cachedPage is a rendered page, along with its validators.
+type cachedPage struct {
+	status  int
+	header  http.Header
+	body    []byte
+	etag    string
+	modtime time.Time
+}
+
+// pageCache caches the rendered pages for the current block height.
+type pageCache struct {
+	logger *slog.Logger
+	next   http.Handler
+	height heightFunc
+	pages  *lru.Cache[string, *cachedPage]
+
+	mu        sync.Mutex
+	current   int64
+	checkedAt time.Time
+}
+
+// pageCacheMiddleware caches up to size pages rendered by next, keyed by
+// their origin and URL, and purged as soon as the block height advances.
+// Pages are served with an ETag and a Last-Modified header, and clients are
+// asked to revalidate them so that conditional requests are answered with
+// `304 Not Modified` until the chain state changes. Requests carrying the
+// content warning cookie are not cached, as their page depends on it.
+func pageCacheMiddleware(logger *slog.Logger, next http.Handler, height heightFunc, size int) (http.Handler, error) {
+	pages, err := lru.New[string, *cachedPage](size)
+	if err != nil {
+		return nil, err
+	}
+	return &pageCache{logger: logger, next: next, height: height, pages: pages}, nil
+}
+
+func (c *pageCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || len(r.CookiesNamed(contentWarningCookie)) > 0 {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	height, ok := c.currentHeight(r)
+	if !ok {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	key := requestOrigin(r) + r.URL.RequestURI()
+	if locale := requestLocale(r.Context()); locale != "" {
+		key += "#" + locale
+	}
+	if page, ok := c.pages.Get(key); ok {
+		w.Header().Set("X-Gnoweb-Cache", "hit")
+		servePage(w, r, page)
+		return
+	}
+
+	rec := &pageRecorder{header: make(http.Header), status: http.StatusOK}
+	c.next.ServeHTTP(rec, r)
+
+	page := &cachedPage{
+		status:  rec.status,
+		header:  rec.header,
+		body:    rec.body.Bytes(),
+		modtime: time.Now(),
+	}
+	if rec.cacheable() {
+		sum := sha256.Sum256(page.body)
+		page.etag = `"` + strconv.FormatInt(height, 10) + "-" + hex.EncodeToString(sum[:12]) + `"`
+		if page.header.Get("Cache-Control") == "" {
+			page.header.Set("Cache-Control", "public, no-cache")
+		}
+		c.pages.Add(key, page)
+		w.Header().Set("X-Gnoweb-Cache", "miss")
+	}
+	servePage(w, r, page)
+}
+
+// currentHeight returns the current block height, purging the cache if it
+// advanced. The height is checked at most once per pageCacheHeightTTL.
+func (c *pageCache) currentHeight(r *http.Request) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checkedAt) < pageCacheHeightTTL {
+		return c.current, true
+	}
+
+	height, err := c.height(r.Context())
+	if err != nil {
+		c.logger.Debug("unable to fetch height for page cache", "error", err)
+		return 0, false
+	}
+
+	if height != c.current {
+		c.pages.Purge()
+		c.current = height
+	}
+	c.checkedAt = time.Now()
+	return height, true
+}
+
+// servePage writes the page, answering conditional requests of cacheable
+// pages.
+func servePage(w http.ResponseWriter, r *http.Request, page *cachedPage) {
+	for k, v := range page.header {
+		w.Header()[k] = slices.Clone(v)
+	}
+
+	if page.etag == "" {
+		w.WriteHeader(page.status)
+		if r.Method != http.MethodHead {
+			w.Write(page.body)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", page.etag)
+	http.ServeContent(w, r, "", page.modtime, bytes.NewReader(page.body))
+}
+
+// pageRecorder buffers a response. It doesn't implement http.Flusher, so
+// pages are not rendered progressively.
+type pageRecorder struct {
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+	overflow    bool
+}
+
+func (rec *pageRecorder) Header() http.Header { return rec.header }
+
+func (rec *pageRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = code
+	}
+}
+
+func (rec *pageRecorder) Write(b []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	if rec.body.Len()+len(b) > pageCacheMaxBody {
+		rec.overflow = true
+	}
+	return rec.body.Write(b)
+}
+
+// cacheable reports whether the recorded response may be cached: successful
+// and reasonably sized pages, which neither set cookies nor forbid caching.
+func (rec *pageRecorder) cacheable() bool {
+	if rec.status != http.StatusOK || rec.overflow || rec.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := rec.header.Get("Cache-Control")
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
@@ -0,0 +1,106 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCacheMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var current atomic.Int64
+	current.Store(10)
+	height := func(ctx context.Context) (int64, error) {
+		if current.Load() < 0 {
+			return 0, errors.New("node unavailable")
+		}
+		return current.Load(), nil
+	}
+
+	var renders atomic.Int32
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renders.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/r/demo/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/r/demo/cookie":
+			http.SetCookie(w, &http.Cookie{Name: "a", Value: "b"})
+		}
+		w.Write([]byte("page " + r.URL.RequestURI()))
+	})
+
+	handler, err := pageCacheMiddleware(log.NewNoopLogger(), next, height, 16)
+	require.NoError(t, err)
+	cache := handler.(*pageCache)
+
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("/r/demo/boards")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "miss", rr.Header().Get("X-Gnoweb-Cache"))
+	assert.Equal(t, "public, no-cache", rr.Header().Get("Cache-Control"))
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "page /r/demo/boards", rr.Body.String())
+	etag := rr.Header().Get("ETag")
+	assert.Regexp(t, `^"10-[0-9a-f]{24}"$`, etag)
+	require.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	// Served from the cache, and revalidated with the validators
+	rr = get("/r/demo/boards")
+	assert.Equal(t, "hit", rr.Header().Get("X-Gnoweb-Cache"))
+	assert.Equal(t, "page /r/demo/boards", rr.Body.String())
+	assert.Equal(t, http.StatusNotModified, get("/r/demo/boards", "If-None-Match", etag).Code)
+	assert.Equal(t, http.StatusNotModified, get("/r/demo/boards", "If-Modified-Since", rr.Header().Get("Last-Modified")).Code)
+	assert.EqualValues(t, 1, renders.Load())
+
+	// Pages are keyed by URL
+	assert.Equal(t, "page /r/demo/boards:board", get("/r/demo/boards:board").Body.String())
+	assert.EqualValues(t, 2, renders.Load())
+
+	// Errors, cookies and content warnings are not cached
+	for range 2 {
+		assert.Equal(t, http.StatusNotFound, get("/r/demo/missing").Code)
+		get("/r/demo/cookie")
+		get("/r/demo/boards", "Cookie", contentWarningCookie+"=/r/demo")
+	}
+	assert.EqualValues(t, 8, renders.Load())
+
+	// The cache is purged once the height advances
+	current.Store(11)
+	cache.mu.Lock()
+	cache.checkedAt = time.Time{}
+	cache.mu.Unlock()
+	rr = get("/r/demo/boards", "If-None-Match", etag)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "miss", rr.Header().Get("X-Gnoweb-Cache"))
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+	assert.EqualValues(t, 9, renders.Load())
+
+	// Pages are not cached while the height is unknown
+	current.Store(-1)
+	cache.mu.Lock()
+	cache.checkedAt = time.Time{}
+	cache.mu.Unlock()
+	rr = get("/r/demo/boards")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("X-Gnoweb-Cache"))
+	assert.EqualValues(t, 10, renders.Load())
+}
@@ -0,0 +1,282 @@
+package gnoweb
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// StatsPath is the path of the page views endpoint.
+const StatsPath = "/stats"
+
+const (
+	// pageViewsMaxPaths bounds the number of paths views are counted for,
+	// the views of other paths being counted under pageViewsOtherPath.
+	pageViewsMaxPaths  = 10_000
+	pageViewsOtherPath = "(other)"
+	// pageViewsFlushInterval is the interval between two writes of the
+	// counts to the database.
+	pageViewsFlushInterval = 30 * time.Second
+	// defaultStatsLimit is the default number of paths served by the
+	// stats endpoint.
+	defaultStatsLimit = 100
+)
+
+var (
+	pageViewsBucket   = []byte("pageviews")
+	pageViewsSinceKey = []byte("since")
+)
+
+// PageViews counts the views of each page path, as a privacy-preserving
+// first-party alternative to third-party analytics: neither visitors nor
+// their requests are recorded, only the number of views of each path.
+// Counts are kept in memory, and optionally persisted to a bolt database.
+type PageViews struct {
+	logger *slog.Logger
+	db     *bbolt.DB
+
+	mu      sync.Mutex
+	since   time.Time
+	views   map[string]uint64
+	dirty   map[string]bool
+	flushMu sync.Mutex
+
+	stop chan struct{} // closed by Close
+	done chan struct{} // closed once the flush loop returned
+}
+
+// NewPageViews returns page views counted in memory. If file is set, counts
+// are restored from and written every pageViewsFlushInterval to that bolt
+// database, so at most pageViewsFlushInterval of views may be lost on exit
+// unless Close is called.
+func NewPageViews(logger *slog.Logger, file string) (*PageViews, error) {
+	pv := &PageViews{
+		logger: logger,
+		since:  time.Now(),
+		views:  make(map[string]uint64),
+		dirty:  make(map[string]bool),
+	}
+	if file == "" {
+		return pv, nil
+	}
+
+	db, err := bbolt.Open(file, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open page views database: %w", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(pageViewsBucket)
+		if err != nil {
+			return err
+		}
+		if since := b.Get(pageViewsSinceKey); since != nil {
+			return pv.since.UnmarshalBinary(since)
+		}
+		since, _ := pv.since.MarshalBinary()
+		return b.Put(pageViewsSinceKey, since)
+	})
+	if err == nil {
+		err = db.View(func(tx *bbolt.Tx) error {
+			return tx.Bucket(pageViewsBucket).ForEach(func(k, v []byte) error {
+				if len(v) == 8 && (k[0] == '/' || string(k) == pageViewsOtherPath) {
+					pv.views[string(k)] = binary.BigEndian.Uint64(v)
+				}
+				return nil
+			})
+		})
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to load page views: %w", err)
+	}
+
+	pv.db = db
+	pv.stop, pv.done = make(chan struct{}), make(chan struct{})
+	go pv.flushLoop(pageViewsFlushInterval)
+	return pv, nil
+}
+
+// flushLoop flushes the counts every interval, until Close is called.
+func (pv *PageViews) flushLoop(interval time.Duration) {
+	defer close(pv.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pv.stop:
+			return
+		case <-ticker.C:
+			if err := pv.Flush(); err != nil {
+				pv.logger.Error("unable to write page views", "error", err)
+			}
+		}
+	}
+}
+
+// Record counts a view of the given path.
+func (pv *PageViews) Record(path string) {
+	pv.mu.Lock()
+	if _, ok := pv.views[path]; !ok && len(pv.views) >= pageViewsMaxPaths {
+		path = pageViewsOtherPath
+	}
+	pv.views[path]++
+	pv.dirty[path] = true
+	pv.mu.Unlock()
+}
+
+// Flush writes the counts modified since the last flush to the database.
+func (pv *PageViews) Flush() error {
+	if pv.db == nil {
+		return nil
+	}
+
+	pv.flushMu.Lock()
+	defer pv.flushMu.Unlock()
+
+	pv.mu.Lock()
+	views := make(map[string]uint64, len(pv.dirty))
+	for path := range pv.dirty {
+		views[path] = pv.views[path]
+	}
+	clear(pv.dirty)
+	pv.mu.Unlock()
+
+	err := pv.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(pageViewsBucket)
+		for path, count := range views {
+			if err := b.Put([]byte(path), binary.BigEndian.AppendUint64(nil, count)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Retry on the next flush
+		pv.mu.Lock()
+		for path := range views {
+			pv.dirty[path] = true
+		}
+		pv.mu.Unlock()
+	}
+	return err
+}
+
+// Close stops the periodic flushes, flushes the counts and closes the
+// database. It must be called once, after the last recorded view.
+func (pv *PageViews) Close() error {
+	if pv.db == nil {
+		return nil
+	}
+	close(pv.stop)
+	<-pv.done
+	if err := pv.Flush(); err != nil {
+		return err
+	}
+	return pv.db.Close()
+}
+
+// PathViews is the number of views of a path.
+type PathViews struct {
+	Path  string `json:"path"`
+	Views uint64 `json:"views"`
+}
+
+// PageStats are the page views counted since a given time.
+type PageStats struct {
+	Since time.Time   `json:"since"`
+	Total uint64      `json:"total"`
+	Paths []PathViews `json:"paths"`
+}
+
+// Stats returns the views of the paths starting with prefix, most viewed
+// first, limited to the given number of paths. The total counts the views
+// of all of these paths.
+func (pv *PageViews) Stats(prefix string, limit int) PageStats {
+	pv.mu.Lock()
+	stats := PageStats{Since: pv.since, Paths: []PathViews{}}
+	for path, views := range pv.views {
+		if strings.HasPrefix(path, prefix) {
+			stats.Total += views
+			stats.Paths = append(stats.Paths, PathViews{path, views})
+		}
+	}
+	pv.mu.Unlock()
+
+	slices.SortFunc(stats.Paths, func(a, b PathViews) int {
+		if c := cmp.Compare(b.Views, a.Views); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	if limit > 0 && len(stats.Paths) > limit {
+		stats.Paths = stats.Paths[:limit]
+	}
+	return stats
+}
+
+// isBot reports whether the request comes from a crawler, or is a
+// speculative prefetch, which are not page views.
+func isBot(r *http.Request) bool {
+	if r.Header.Get("Sec-Purpose") != "" || r.Header.Get("Purpose") == "prefetch" {
+		return true
+	}
+	ua := strings.ToLower(r.UserAgent())
+	return ua == "" || strings.Contains(ua, "bot") || strings.Contains(ua, "crawl") || strings.Contains(ua, "spider")
+}
+
+// pageViewsMiddleware counts the successful GET requests of HTML pages.
+func pageViewsMiddleware(next http.Handler, pv *PageViews) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || isBot(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		path := r.URL.Path // before being rewritten, such as by aliases
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		status := cmp.Or(sw.status, http.StatusOK)
+		if status == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+			pv.Record(path)
+		}
+	})
+}
+
+// handlerStats serves the page views as JSON, optionally filtered by the
+// `prefix` query parameter and limited by the `limit` one, zero serving all
+// of the paths.
+func handlerStats(pv *PageViews) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := defaultStatsLimit
+		if s := r.URL.Query().Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid `limit` query parameter", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "private, no-store")
+		json.NewEncoder(w).Encode(pv.Stats(r.URL.Query().Get("prefix"), limit))
+	})
+}
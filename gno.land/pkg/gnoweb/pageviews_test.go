@@ -0,0 +1,132 @@
+package gnoweb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageViewsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	pv, err := NewPageViews(slog.New(slog.NewTextHandler(io.Discard, nil)), "")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if r.URL.Path == "/r/demo/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		r.URL.Path = "/r/gnoland/home" // as aliases do
+		w.Write([]byte("page"))
+	})
+	mux.HandleFunc("/r/demo/boards$source", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("source"))
+	})
+	handler := pageViewsMiddleware(mux, pv)
+
+	view := func(method, path, userAgent string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	const browser = "Mozilla/5.0 (X11; Linux x86_64; rv:130.0) Gecko/20100101 Firefox/130.0"
+	view(http.MethodGet, "/", browser)
+	view(http.MethodGet, "/r/demo/boards:gnolang", browser)
+	view(http.MethodGet, "/r/demo/boards:gnolang?page=2", browser)
+	view(http.MethodGet, "/", browser)
+	view(http.MethodHead, "/", browser)
+	view(http.MethodGet, "/", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	view(http.MethodGet, "/", "")
+	view(http.MethodGet, "/r/demo/missing", browser)
+	view(http.MethodGet, "/r/demo/boards$source", browser)
+
+	stats := pv.Stats("", 0)
+	assert.Equal(t, uint64(4), stats.Total)
+	assert.Equal(t, []PathViews{
+		{"/", 2},
+		{"/r/demo/boards:gnolang", 2},
+	}, stats.Paths)
+
+	stats = pv.Stats("/r/", 0)
+	assert.Equal(t, uint64(2), stats.Total)
+	assert.Len(t, stats.Paths, 1)
+}
+
+func TestPageViewsMaxPaths(t *testing.T) {
+	t.Parallel()
+
+	pv, err := NewPageViews(slog.New(slog.NewTextHandler(io.Discard, nil)), "")
+	require.NoError(t, err)
+
+	for i := range pageViewsMaxPaths {
+		pv.views[fmt.Sprintf("/r/demo/%d", i)] = 1
+	}
+	pv.Record("/r/demo/new")
+	pv.Record("/r/demo/new")
+
+	stats := pv.Stats("", 1)
+	assert.Equal(t, []PathViews{{pageViewsOtherPath, 2}}, stats.Paths)
+	assert.Equal(t, uint64(len(pv.views)+1), stats.Total)
+}
+
+func TestPageViewsPersistence(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	file := filepath.Join(t.TempDir(), "pageviews.db")
+
+	pv, err := NewPageViews(logger, file)
+	require.NoError(t, err)
+	pv.Record("/r/demo/boards")
+	pv.Record("/r/demo/boards")
+	pv.Record("/")
+	since := pv.Stats("", 0).Since
+	require.NoError(t, pv.Close())
+
+	pv, err = NewPageViews(logger, file)
+	require.NoError(t, err)
+	defer pv.Close()
+
+	stats := pv.Stats("", 0)
+	assert.True(t, since.Equal(stats.Since))
+	assert.Equal(t, uint64(3), stats.Total)
+	assert.Equal(t, []PathViews{{"/r/demo/boards", 2}, {"/", 1}}, stats.Paths)
+}
+
+func TestHandlerStats(t *testing.T) {
+	t.Parallel()
+
+	pv, err := NewPageViews(slog.New(slog.NewTextHandler(io.Discard, nil)), "")
+	require.NoError(t, err)
+	for _, path := range []string{"/", "/r/demo/boards", "/r/demo/boards", "/r/gnoland/blog"} {
+		pv.Record(path)
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handlerStats(pv).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, StatsPath+query, nil))
+		return rr
+	}
+
+	rr := get("?prefix=/r/&limit=1")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var stats PageStats
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(t, uint64(3), stats.Total)
+	assert.Equal(t, []PathViews{{"/r/demo/boards", 2}}, stats.Paths)
+
+	assert.Equal(t, http.StatusBadRequest, get("?limit=-1").Code)
+}
@@ -0,0 +1,30 @@
+package gnoweb
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+)
+
+// pflowAssetsDir is the directory of the assets holding a copy of the pflow
+// viewer, populated with `make pflow` before building gnoweb.
+const pflowAssetsDir = "pflow"
+
+// reIntegrity matches a subresource integrity hash, such as `sha384-...`.
+var reIntegrity = regexp.MustCompile(`^sha(256|384|512)-[A-Za-z0-9+/]+={0,2}$`)
+
+// validIntegrity reports whether the given integrity hash, if any, is valid.
+func validIntegrity(hash string) bool {
+	return hash == "" || reIntegrity.MatchString(hash)
+}
+
+// hasPflowAssets reports whether the given assets hold a copy of the pflow
+// viewer.
+func hasPflowAssets(assets fs.FS) bool {
+	for _, name := range []string{"pflow.js", "pflow.css"} {
+		if _, err := fs.Stat(assets, path.Join(pflowAssetsDir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
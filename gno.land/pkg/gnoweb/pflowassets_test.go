@@ -0,0 +1,26 @@
+package gnoweb
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidIntegrity(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, validIntegrity(""))
+	assert.True(t, validIntegrity("sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC"))
+	assert.True(t, validIntegrity("sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="))
+	assert.False(t, validIntegrity("md5-1B2M2Y8AsgTpgAmY7PhCfg=="))
+	assert.False(t, validIntegrity(`sha384-abc" onload="alert(1)`))
+}
+
+func TestHasPflowAssets(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, hasPflowAssets(fstest.MapFS{}))
+	assert.False(t, hasPflowAssets(fstest.MapFS{"pflow/pflow.js": {}}))
+	assert.True(t, hasPflowAssets(fstest.MapFS{"pflow/pflow.js": {}, "pflow/pflow.css": {}}))
+}
@@ -0,0 +1,110 @@
+package gnoweb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
+)
+
+// PflowSimulatePath is the path of the Petri net simulation endpoint.
+const PflowSimulatePath = "/_pflow/simulate"
+
+const (
+	// pflowSimulateMaxBody bounds the size of simulation requests.
+	pflowSimulateMaxBody = 256 << 10
+	// pflowSimulateMaxSteps bounds the length of firing sequences.
+	pflowSimulateMaxSteps = 1000
+)
+
+// pflowSimulateRequest is a model to simulate, in the format of ```pflow
+// blocks, along with the sequence of transitions to fire. The marking, if
+// any, overrides the initial tokens of the given places.
+type pflowSimulateRequest struct {
+	Model    json.RawMessage `json:"model"`
+	Marking  md.PflowMarking `json:"marking,omitempty"`
+	Sequence []string        `json:"sequence"`
+}
+
+// pflowSimulateResponse is the marking reached after firing the sequence,
+// or its prefix up to the first transition which is not enabled.
+type pflowSimulateResponse struct {
+	Marking md.PflowMarking `json:"marking"`
+	Fired   int             `json:"fired"`
+	Enabled []string        `json:"enabled"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// handlerPflowSimulate serves the simulation of Petri nets, firing the
+// requested sequence of transitions. The whole simulation is stateless.
+func handlerPflowSimulate(logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req pflowSimulateRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, pflowSimulateMaxBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writePflowSimulateError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+		if len(req.Sequence) > pflowSimulateMaxSteps {
+			writePflowSimulateError(w, http.StatusBadRequest, fmt.Errorf("sequence longer than %d transitions", pflowSimulateMaxSteps))
+			return
+		}
+
+		model, err := md.ParsePflowModel(req.Model)
+		if err != nil {
+			writePflowSimulateError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		marking := model.InitialMarking()
+		for id, tokens := range req.Marking {
+			if _, ok := model.Places[id]; !ok || tokens < 0 {
+				writePflowSimulateError(w, http.StatusBadRequest, fmt.Errorf("invalid marking of place %q", id))
+				return
+			}
+			marking[id] = tokens
+		}
+
+		res := pflowSimulateResponse{Marking: marking}
+		status := http.StatusOK
+		for _, transition := range req.Sequence {
+			next, err := model.Fire(res.Marking, transition)
+			if err != nil {
+				if !errors.Is(err, md.ErrPflowNotEnabled) {
+					status = http.StatusBadRequest
+				} else {
+					status = http.StatusUnprocessableEntity
+				}
+				res.Error = err.Error()
+				break
+			}
+			res.Marking = next
+			res.Fired++
+		}
+		res.Enabled = model.EnabledTransitions(res.Marking)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			logger.Error("unable to write pflow simulation", "error", err)
+		}
+	})
+}
+
+func writePflowSimulateError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
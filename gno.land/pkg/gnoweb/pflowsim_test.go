@@ -0,0 +1,81 @@
+package gnoweb
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPflowSimulate(t *testing.T) {
+	t.Parallel()
+
+	handler := handlerPflowSimulate(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	const model = `{"places": {"in": {"initial": 1}, "out": {}}, "transitions": {"go": {}, "back": {}},
+		"arcs": [{"source": "in", "target": "go"}, {"source": "go", "target": "out"},
+		{"source": "out", "target": "back"}, {"source": "back", "target": "in"}]}`
+
+	simulate := func(body string) (int, pflowSimulateResponse) {
+		req := httptest.NewRequest(http.MethodPost, PflowSimulatePath, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var res pflowSimulateResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return rec.Code, res
+	}
+
+	t.Run("valid sequence", func(t *testing.T) {
+		t.Parallel()
+		code, res := simulate(`{"model": ` + model + `, "sequence": ["go", "back", "go"]}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, res.Fired)
+		assert.Equal(t, map[string]int{"in": 0, "out": 1}, map[string]int(res.Marking))
+		assert.Equal(t, []string{"back"}, res.Enabled)
+		assert.Empty(t, res.Error)
+	})
+
+	t.Run("explicit marking", func(t *testing.T) {
+		t.Parallel()
+		code, res := simulate(`{"model": ` + model + `, "marking": {"in": 0, "out": 2}, "sequence": []}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"back"}, res.Enabled)
+	})
+
+	t.Run("disabled transition", func(t *testing.T) {
+		t.Parallel()
+		code, res := simulate(`{"model": ` + model + `, "sequence": ["go", "go"]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, 1, res.Fired, "the marking before the failed step is returned")
+		assert.Equal(t, map[string]int{"in": 0, "out": 1}, map[string]int(res.Marking))
+		assert.Contains(t, res.Error, "not enabled")
+	})
+
+	for name, body := range map[string]string{
+		"unknown transition": `{"model": ` + model + `, "sequence": ["jump"]}`,
+		"unknown place":      `{"model": ` + model + `, "marking": {"nowhere": 1}}`,
+		"unknown field":      `{"model": ` + model + `, "steps": 1}`,
+		"invalid model":      `{"model": {"arcs": [{"source": "a", "target": "b"}]}}`,
+		"invalid json":       `{"model"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			code, res := simulate(body)
+			assert.Equal(t, http.StatusBadRequest, code)
+			assert.NotEmpty(t, res.Error)
+		})
+	}
+
+	t.Run("method", func(t *testing.T) {
+		t.Parallel()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, PflowSimulatePath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,31 @@
+package gnoweb
+
+import (
+	"bytes"
+	"net/url"
+)
+
+// analyticsOrigin is the origin of the analytics script.
+const analyticsOrigin = "https://sa.gno.services"
+
+// cdnOrigin returns the origin of the given absolute resource URL, or an
+// empty string if the resource is not served by a third party.
+func cdnOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// usedOrigins returns the origins referenced by the rendered content, in
+// the given order.
+func usedOrigins(content []byte, origins []string) []string {
+	var used []string
+	for _, origin := range origins {
+		if bytes.Contains(content, []byte(origin+"/")) {
+			used = append(used, origin)
+		}
+	}
+	return used
+}
@@ -0,0 +1,231 @@
+package gnoweb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// RateLimit configures a token bucket allowing `Rate` requests per second,
+// with bursts of up to `Burst` requests. A zero rate disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+// tokenBucket implements a thread safe token bucket.
+type tokenBucket struct {
+	limit RateLimit
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst)}
+}
+
+// take consumes a token if available. Otherwise, it returns the delay after
+// which a token will be available.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := (1 - b.tokens) / b.limit.Rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+		b.tokens = math.Min(b.tokens, float64(b.limit.Burst))
+	}
+	b.last = now
+}
+
+// maxRateLimitClients is the number of tracked clients after which the
+// least recently seen ones are evicted.
+const maxRateLimitClients = 10_000
+
+// RateLimitMiddleware limits the requests served by mux, once for all of its
+// routes. Requests are limited per client IP using the limit of the pattern
+// of their route in `perIP`, and per path using `perPath`: each path prefix
+// gets its own bucket shared by all clients, the longest prefix matching
+// the path or one of its parents being used. Routes without a limit in
+// `perIP` are not limited at all. A client has a single budget per distinct
+// limit, shared by all the routes using it, so that spreading requests over
+// several endpoints doesn't multiply its rate. Limited requests get a 429
+// response with a `Retry-After` header, unless they are exempted by
+// RateLimitBypassMiddleware.
+//
+// Clients are identified by the `RemoteAddr` of their requests, forwarding
+// headers such as `X-Forwarded-For` being ignored as clients can forge them.
+// Behind a reverse proxy, all requests share the address of the proxy: it
+// must then either restore the client address, such as with the PROXY
+// protocol, or enforce the per-client limits itself, gnoweb keeping only the
+// per-path ones.
+func RateLimitMiddleware(mux *http.ServeMux, perIP map[string]RateLimit, perPath map[string]RateLimit) http.Handler {
+	paths := make(map[string]*tokenBucket, len(perPath))
+	for prefix, limit := range perPath {
+		if limit.enabled() {
+			paths[prefix] = newTokenBucket(limit)
+		}
+	}
+
+	routes := make(map[string]RateLimit, len(perIP))
+	for pattern, limit := range perIP {
+		if limit.enabled() || len(paths) > 0 {
+			routes[pattern] = limit
+		}
+	}
+	if len(routes) == 0 {
+		return mux
+	}
+
+	type clientKey struct {
+		ip    string
+		limit RateLimit
+	}
+
+	// Bound memory usage, evicting the least recently seen clients
+	clients, err := lru.New[clientKey, *tokenBucket](maxRateLimitClients)
+	if err != nil {
+		panic(err) // only fails with a non-positive size
+	}
+	var mu sync.Mutex
+
+	clientBucket := func(key clientKey) *tokenBucket {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if b, ok := clients.Get(key); ok {
+			return b
+		}
+
+		b := newTokenBucket(key.limit)
+		clients.Add(key, b)
+		return b
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimitBypassed(r.Context()) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		_, pattern := mux.Handler(r)
+		perIP, ok := routes[pattern]
+		if !ok {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		now := time.Now()
+
+		if perIP.enabled() {
+			key := clientKey{ip: remoteIP(r), limit: perIP}
+			if ok, wait := clientBucket(key).take(now); !ok {
+				writeTooManyRequests(w, wait)
+				return
+			}
+		}
+
+		var (
+			matched string
+			bucket  *tokenBucket
+		)
+		for prefix, b := range paths {
+			if hasPathPrefix(r.URL.Path, prefix) && len(prefix) >= len(matched) {
+				matched, bucket = prefix, b
+			}
+		}
+
+		if bucket != nil {
+			if ok, wait := bucket.take(now); !ok {
+				writeTooManyRequests(w, wait)
+				return
+			}
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeTooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+// remoteIP returns the IP address of the client of the request.
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+type rateLimitBypassKey struct{}
+
+// rateLimitBypassed reports whether the request is exempt from rate limits.
+func rateLimitBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(rateLimitBypassKey{}).(bool)
+	return bypass
+}
+
+// RateLimitBypassMiddleware exempts the requests of clients whose IP is in
+// one of the given CIDR ranges, or is one of the given IPs, from the rate
+// limits of RateLimitMiddleware, for instance monitoring or trusted
+// internal clients.
+func RateLimitBypassMiddleware(next http.Handler, cidrs []string) (http.Handler, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(cidr)
+			if aerr != nil {
+				return nil, fmt.Errorf("invalid rate limit bypass range %q: %w", cidr, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, err := netip.ParseAddr(remoteIP(r))
+		if err == nil {
+			addr = addr.Unmap()
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					r = r.WithContext(context.WithValue(r.Context(), rateLimitBypassKey{}, true))
+					break
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	}), nil
+}
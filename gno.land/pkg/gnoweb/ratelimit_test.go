@@ -0,0 +1,144 @@
+package gnoweb_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/", ok)
+	mux.Handle("/embed/", ok)
+	mux.Handle("/rpc", ok)
+	mux.Handle("/public/", ok)
+
+	get := func(h http.Handler, ip, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("per ip", func(t *testing.T) {
+		t.Parallel()
+
+		h := gnoweb.RateLimitMiddleware(mux, map[string]gnoweb.RateLimit{"/": {Rate: 0.01, Burst: 2}}, nil)
+		for range 2 {
+			assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+		}
+
+		rr := get(h, "10.0.0.1", "/r/demo")
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "100", rr.Header().Get("Retry-After"))
+
+		// Other clients are not affected
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.2", "/r/demo").Code)
+	})
+
+	t.Run("per path", func(t *testing.T) {
+		t.Parallel()
+
+		h := gnoweb.RateLimitMiddleware(mux,
+			map[string]gnoweb.RateLimit{"/": {Rate: 100, Burst: 100}}, // generous per-IP limit
+			map[string]gnoweb.RateLimit{
+				"/r/expensive": {Rate: 0.5, Burst: 2},
+			},
+		)
+
+		// The path bucket is shared across clients
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/expensive").Code)
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.2", "/r/expensive:page").Code)
+
+		rr := get(h, "10.0.0.3", "/r/expensive")
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+
+		// Other paths are still served, even from the same clients
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.3", "/r/demo").Code)
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.3", "/r/expensivev2").Code)
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+	})
+
+	t.Run("shared across routes", func(t *testing.T) {
+		t.Parallel()
+
+		h := gnoweb.RateLimitMiddleware(mux, map[string]gnoweb.RateLimit{
+			"/":       {Rate: 0.01, Burst: 2},
+			"/embed/": {Rate: 0.01, Burst: 2},
+			"/rpc":    {Rate: 0.01, Burst: 1},
+		}, nil)
+
+		// Routes with the same limit share the budget of a client
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/embed/r/demo").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.0.0.1", "/embed/r/demo").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.0.0.1", "/r/demo").Code)
+
+		// Other limits have their own budget
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/rpc").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.0.0.1", "/rpc").Code)
+
+		// Routes without a limit are not limited
+		for range 3 {
+			assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/public/main.css").Code)
+		}
+	})
+
+	t.Run("bounded clients", func(t *testing.T) {
+		t.Parallel()
+
+		h := gnoweb.RateLimitMiddleware(mux, map[string]gnoweb.RateLimit{"/": {Rate: 0.01, Burst: 1}}, nil)
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.0.0.1", "/r/demo").Code)
+
+		// Up to 10k clients are tracked, the least recently seen ones
+		// being evicted first
+		for i := range 10_000 {
+			get(h, fmt.Sprintf("10.1.%d.%d", i/256, i%256), "/r/demo")
+		}
+		assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.1.39.15", "/r/demo").Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		h := gnoweb.RateLimitMiddleware(mux, map[string]gnoweb.RateLimit{"/": {}}, map[string]gnoweb.RateLimit{})
+		for range 10 {
+			assert.Equal(t, http.StatusOK, get(h, "10.0.0.1", "/r/demo").Code)
+		}
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		t.Parallel()
+
+		limited := gnoweb.RateLimitMiddleware(mux, map[string]gnoweb.RateLimit{"/": {Rate: 0.01, Burst: 1}}, nil)
+		h, err := gnoweb.RateLimitBypassMiddleware(limited, []string{"10.1.0.0/16", "192.168.1.7", "fd00::/8"})
+		require.NoError(t, err)
+
+		for _, ip := range []string{"10.1.2.3", "192.168.1.7", "[fd00::1]"} {
+			for range 3 {
+				assert.Equal(t, http.StatusOK, get(h, ip, "/r/demo").Code, ip)
+			}
+		}
+
+		// Other clients are still limited
+		assert.Equal(t, http.StatusOK, get(h, "10.2.0.1", "/r/demo").Code)
+		assert.Equal(t, http.StatusTooManyRequests, get(h, "10.2.0.1", "/r/demo").Code)
+
+		_, err = gnoweb.RateLimitBypassMiddleware(limited, []string{"10.0.0.0/33"})
+		assert.Error(t, err)
+	})
+}
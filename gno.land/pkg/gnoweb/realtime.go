@@ -0,0 +1,280 @@
+package gnoweb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// RealtimePath is the WebSocket endpoint notifying realm pages that
+	// their content changed.
+	RealtimePath = "/ws"
+	// RealtimeScriptPath serves the script connecting to RealtimePath.
+	RealtimeScriptPath = "/_realtime.js"
+
+	// DefaultRealtimeInterval is the default interval the block height is
+	// polled at.
+	DefaultRealtimeInterval = 2 * time.Second
+
+	// realtimeMaxConns caps the number of open connections.
+	realtimeMaxConns = 1024
+	// realtimeMaxPages caps the number of distinct pages watched.
+	realtimeMaxPages = 256
+	// realtimeWriteTimeout bounds the duration of a notification write.
+	realtimeWriteTimeout = 10 * time.Second
+)
+
+// realtimeScript connects to the realtime endpoint for the current page,
+// and replaces the main content with the one of a fresh render when
+// notified. Pages are not refreshed while a form control of the content is
+// focused, the refresh is deferred to the next notification.
+const realtimeScript = `(() => {
+  const page = location.pathname + location.search;
+  const url = (location.protocol === "https:" ? "wss://" : "ws://") + location.host + "` + RealtimePath + `?path=" + encodeURIComponent(page);
+  const refresh = async () => {
+    const main = document.getElementById("main-content");
+    if (!main || main.contains(document.activeElement) && document.activeElement.matches("input, textarea, select")) return;
+    const res = await fetch(location.href, { headers: { Accept: "text/html" } });
+    if (!res.ok) return;
+    const next = new DOMParser().parseFromString(await res.text(), "text/html").getElementById("main-content");
+    if (next) main.innerHTML = next.innerHTML;
+  };
+  const connect = (delay) => {
+    const ws = new WebSocket(url);
+    ws.onopen = () => { delay = 1000; };
+    ws.onmessage = (e) => {
+      try { if (JSON.parse(e.data).event === "changed") refresh(); } catch (err) {}
+    };
+    ws.onclose = () => setTimeout(() => connect(Math.min(delay * 2, 60000)), delay);
+  };
+  connect(1000);
+})();
+`
+
+// realtimeEvent is a notification sent to the realtime clients.
+type realtimeEvent struct {
+	Event  string `json:"event"`
+	Path   string `json:"path"`
+	Height int64  `json:"height"`
+}
+
+// realtimePage is a watched realm page, along with the digest of its last
+// render.
+type realtimePage struct {
+	path, args string
+	digest     [sha256.Size]byte
+	subs       map[chan realtimeEvent]struct{}
+}
+
+// realtimeHub watches the realm pages of its clients, polling the block
+// height, and notifies them when a new block changed the render of their
+// page. The height is polled only while clients are connected.
+type realtimeHub struct {
+	logger   *slog.Logger
+	client   ClientAdapter
+	height   heightFunc
+	interval time.Duration
+
+	mu      sync.Mutex
+	pages   map[string]*realtimePage
+	conns   int
+	running bool
+}
+
+func newRealtimeHub(logger *slog.Logger, cli ClientAdapter, height heightFunc, interval time.Duration) *realtimeHub {
+	if interval <= 0 {
+		interval = DefaultRealtimeInterval
+	}
+	return &realtimeHub{
+		logger:   logger,
+		client:   cli,
+		height:   height,
+		interval: interval,
+		pages:    make(map[string]*realtimePage),
+	}
+}
+
+// handlerRealtimeScript serves the realtime client script.
+func handlerRealtimeScript() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Write([]byte(realtimeScript))
+	})
+}
+
+// ServeHTTP upgrades requests to a WebSocket connection notifying the
+// client when the render of the realm page given by the `path` query
+// changes.
+func (hub *realtimeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gnourl, err := weburl.Parse(r.URL.Query().Get("path"))
+	if err != nil || !gnourl.IsRealm() {
+		http.Error(w, "invalid realm path", http.StatusBadRequest)
+		return
+	}
+	key := gnourl.EncodeURL()
+
+	// Render the page once, to detect its next changes
+	raw, err := hub.client.Realm(r.Context(), gnourl.Path, gnourl.EncodeArgs())
+	if err != nil {
+		status, _ := GetClientErrorStatusPage(gnourl, err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	events, ok := hub.subscribe(key, gnourl, sha256.Sum256(raw))
+	if !ok {
+		http.Error(w, "too many realtime connections", http.StatusServiceUnavailable)
+		return
+	}
+	defer hub.unsubscribe(key, events)
+
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		hub.logger.Debug("unable to upgrade realtime connection", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	// Detect the client going away
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			msg, _ := json.Marshal(event)
+			conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe registers a client of the page, starting the height polling if
+// needed. It returns false once the connection or page caps are reached.
+func (hub *realtimeHub) subscribe(key string, gnourl *weburl.GnoURL, digest [sha256.Size]byte) (chan realtimeEvent, bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	page, ok := hub.pages[key]
+	if hub.conns >= realtimeMaxConns || (!ok && len(hub.pages) >= realtimeMaxPages) {
+		return nil, false
+	}
+	if !ok {
+		page = &realtimePage{
+			path:   gnourl.Path,
+			args:   gnourl.EncodeArgs(),
+			digest: digest,
+			subs:   make(map[chan realtimeEvent]struct{}),
+		}
+		hub.pages[key] = page
+	}
+
+	events := make(chan realtimeEvent, 1)
+	page.subs[events] = struct{}{}
+	hub.conns++
+
+	if !hub.running {
+		hub.running = true
+		go hub.run()
+	}
+	return events, true
+}
+
+// unsubscribe removes a client of the page.
+func (hub *realtimeHub) unsubscribe(key string, events chan realtimeEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	page := hub.pages[key]
+	delete(page.subs, events)
+	if len(page.subs) == 0 {
+		delete(hub.pages, key)
+	}
+	hub.conns--
+}
+
+// run polls the block height until no client is left, checking the watched
+// pages on each new block.
+func (hub *realtimeHub) run() {
+	ticker := time.NewTicker(hub.interval)
+	defer ticker.Stop()
+
+	var last int64
+	for range ticker.C {
+		hub.mu.Lock()
+		if hub.conns == 0 {
+			hub.running = false
+			hub.mu.Unlock()
+			return
+		}
+		hub.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), hub.interval)
+		height, err := hub.height(ctx)
+		cancel()
+		if err != nil {
+			hub.logger.Debug("unable to fetch block height", "error", err)
+			continue
+		}
+		if height != last {
+			hub.check(height)
+			last = height
+		}
+	}
+}
+
+// check renders the watched pages, and notifies the clients of those whose
+// render changed.
+func (hub *realtimeHub) check(height int64) {
+	hub.mu.Lock()
+	pages := maps.Clone(hub.pages)
+	hub.mu.Unlock()
+
+	for key, page := range pages {
+		ctx, cancel := context.WithTimeout(context.Background(), hub.interval)
+		raw, err := hub.client.Realm(ctx, page.path, page.args)
+		cancel()
+		if err != nil {
+			hub.logger.Debug("unable to render watched realm", "path", key, "error", err)
+			continue
+		}
+
+		digest := sha256.Sum256(raw)
+		event := realtimeEvent{Event: "changed", Path: key, Height: height}
+
+		hub.mu.Lock()
+		if digest != page.digest {
+			page.digest = digest
+			for events := range page.subs {
+				select {
+				case events <- event:
+				default: // a notification is already pending
+				}
+			}
+		}
+		hub.mu.Unlock()
+	}
+}
@@ -0,0 +1,93 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// realtimeClient is a catalogClient whose realms can be updated.
+type realtimeClient struct {
+	catalogClient
+	mu sync.Mutex
+}
+
+func (c *realtimeClient) Realm(ctx context.Context, path, args string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.catalogClient.Realm(ctx, path, args)
+}
+
+func (c *realtimeClient) set(path, content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.realms[path] = content
+}
+
+func TestRealtimeHub(t *testing.T) {
+	t.Parallel()
+
+	cli := &realtimeClient{catalogClient: catalogClient{realms: map[string]string{
+		"/r/demo/boards": "# Boards",
+		"/r/demo/blog":   "# Blog",
+	}}}
+
+	var current atomic.Int64
+	current.Store(10)
+	height := func(ctx context.Context) (int64, error) {
+		return current.Load(), nil
+	}
+
+	hub := newRealtimeHub(log.NewNoopLogger(), cli, height, 10*time.Millisecond)
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	dial := func(path string) *websocket.Conn {
+		u := "ws" + strings.TrimPrefix(srv.URL, "http") + "?path=" + url.QueryEscape(path)
+		conn, _, err := websocket.DefaultDialer.Dial(u, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	boards := dial("/r/demo/boards")
+	blog := dial("/r/demo/blog")
+
+	// A new block without changes is not notified
+	current.Store(11)
+	blog.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := blog.ReadMessage()
+	require.Error(t, err)
+
+	cli.set("/r/demo/boards", "# Boards\n\nNew post")
+	current.Store(12)
+
+	boards.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := boards.ReadMessage()
+	require.NoError(t, err)
+
+	var event realtimeEvent
+	require.NoError(t, json.Unmarshal(msg, &event))
+	assert.Equal(t, realtimeEvent{Event: "changed", Path: "/r/demo/boards", Height: 12}, event)
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, path := range []string{"/p/demo/avl", "/r/demo/unknown", "not a path"} {
+			u := "ws" + strings.TrimPrefix(srv.URL, "http") + "?path=" + url.QueryEscape(path)
+			_, res, err := websocket.DefaultDialer.Dial(u, nil)
+			require.Error(t, err, path)
+			assert.NotEqual(t, http.StatusSwitchingProtocols, res.StatusCode, path)
+		}
+	})
+}
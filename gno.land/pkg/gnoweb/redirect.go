@@ -1,7 +1,18 @@
 package gnoweb
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
 )
@@ -17,21 +28,228 @@ var Redirects = map[string]string{
 	"/getting-started":         "/start",
 }
 
+// redirectsCheckInterval is the minimum interval between two checks of the
+// modification of a redirects file.
+const redirectsCheckInterval = 5 * time.Second
+
+// RedirectMatch is how the path of a redirect rule is matched.
+type RedirectMatch string
+
+const (
+	// RedirectMatchExact matches the path as is.
+	RedirectMatchExact RedirectMatch = "exact"
+	// RedirectMatchPrefix matches the paths starting with the path, the
+	// rest of the path being appended to the target.
+	RedirectMatchPrefix RedirectMatch = "prefix"
+	// RedirectMatchRegexp matches the paths matching the regular expression
+	// as a whole, the target may refer to its submatches as `$1`.
+	RedirectMatchRegexp RedirectMatch = "regexp"
+)
+
+// RedirectRule redirects the requests whose path matches From to To.
+type RedirectRule struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Match RedirectMatch `json:"match,omitempty"` // exact by default
+	// Permanent rules respond with a 301 status instead of a 302.
+	Permanent bool `json:"permanent,omitempty"`
+	// Alias rules serve the target page under the requested path instead
+	// of redirecting to it.
+	Alias bool `json:"alias,omitempty"`
+}
+
+// DefaultRedirectRules returns the rules of the Redirects map.
+func DefaultRedirectRules() []RedirectRule {
+	rules := make([]RedirectRule, 0, len(Redirects))
+	for _, from := range slices.Sorted(maps.Keys(Redirects)) {
+		rules = append(rules, RedirectRule{From: from, To: Redirects[from]})
+	}
+	return rules
+}
+
+// redirectRules are the compiled rules of a RedirectTable.
+type redirectRules struct {
+	exact    map[string]*RedirectRule
+	prefixes []*RedirectRule // longest first
+	regexps  []*RedirectRule
+	compiled []*regexp.Regexp // of regexps
+}
+
+func compileRedirectRules(rules []RedirectRule) (*redirectRules, error) {
+	rr := &redirectRules{exact: map[string]*RedirectRule{}}
+	for i := range rules {
+		rule := &rules[i]
+		if rule.From == "" || rule.To == "" {
+			return nil, fmt.Errorf("rule %d: empty path or target", i)
+		}
+
+		switch rule.Match {
+		case "", RedirectMatchExact:
+			rr.exact[rule.From] = rule
+		case RedirectMatchPrefix:
+			rr.prefixes = append(rr.prefixes, rule)
+		case RedirectMatchRegexp:
+			re, err := regexp.Compile(`^(?:` + rule.From + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			rr.regexps = append(rr.regexps, rule)
+			rr.compiled = append(rr.compiled, re)
+		default:
+			return nil, fmt.Errorf("rule %d: unknown match %q", i, rule.Match)
+		}
+	}
+	slices.SortStableFunc(rr.prefixes, func(a, b *RedirectRule) int {
+		return len(b.From) - len(a.From)
+	})
+	return rr, nil
+}
+
+// match returns the target of the given path and its rule. Exact rules are
+// matched first, then the longest prefix rule, then the regexp rules in
+// order.
+func (rr *redirectRules) match(path string) (string, *RedirectRule) {
+	if rule, ok := rr.exact[path]; ok {
+		return rule.To, rule
+	}
+	for _, rule := range rr.prefixes {
+		if rest, ok := strings.CutPrefix(path, rule.From); ok {
+			return rule.To + rest, rule
+		}
+	}
+	for i, re := range rr.compiled {
+		if m := re.FindStringSubmatchIndex(path); m != nil {
+			rule := rr.regexps[i]
+			return string(re.ExpandString(nil, rule.To, path, m)), rule
+		}
+	}
+	return "", nil
+}
+
+// RedirectTable holds the redirect rules of RedirectMiddleware, which can be
+// replaced while serving requests, and optionally reloaded from a file when
+// it is modified.
+type RedirectTable struct {
+	rules atomic.Pointer[redirectRules]
+
+	logger *slog.Logger
+	file   string
+
+	mu      sync.Mutex
+	checked time.Time
+	modTime time.Time
+}
+
+// NewRedirectTable returns a table of the given rules.
+func NewRedirectTable(rules []RedirectRule) (*RedirectTable, error) {
+	t := &RedirectTable{}
+	if err := t.Set(rules); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadRedirectTable returns a table of the rules of the given JSON file, a
+// list of RedirectRule, reloaded when the file is modified. If the file
+// becomes invalid, the previous rules are kept.
+func LoadRedirectTable(logger *slog.Logger, file string) (*RedirectTable, error) {
+	t := &RedirectTable{logger: logger, file: file}
+	if err := t.reload(time.Now()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Set replaces the rules of the table.
+func (t *RedirectTable) Set(rules []RedirectRule) error {
+	rr, err := compileRedirectRules(slices.Clone(rules))
+	if err != nil {
+		return fmt.Errorf("invalid redirect rules: %w", err)
+	}
+	t.rules.Store(rr)
+	return nil
+}
+
+// Match returns the target of the given path and its rule, or a nil rule if
+// no rule matches.
+func (t *RedirectTable) Match(path string) (string, *RedirectRule) {
+	if t.file != "" {
+		t.checkFile()
+	}
+	return t.rules.Load().match(path)
+}
+
+// checkFile reloads the rules file if it has been modified since the last
+// check, at most every redirectsCheckInterval.
+func (t *RedirectTable) checkFile() {
+	now := time.Now()
+	if !t.mu.TryLock() {
+		return // being checked
+	}
+	defer t.mu.Unlock()
+	if now.Sub(t.checked) < redirectsCheckInterval {
+		return
+	}
+
+	if err := t.reload(now); err != nil {
+		t.logger.Error("unable to reload redirects", "file", t.file, "error", err)
+	}
+}
+
+// reload loads the rules file if it has been modified.
+func (t *RedirectTable) reload(now time.Time) error {
+	t.checked = now
+	info, err := os.Stat(t.file)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(t.modTime) {
+		return nil
+	}
+	t.modTime = info.ModTime()
+
+	data, err := os.ReadFile(t.file)
+	if err != nil {
+		return err
+	}
+	var rules []RedirectRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("unable to parse redirects: %w", err)
+	}
+	if err := t.Set(rules); err != nil {
+		return err
+	}
+	t.logger.Info("loaded redirects", "file", t.file, "rules", len(rules))
+	return nil
+}
+
 // RedirectMiddleware redirects all incoming requests whose path matches
-// any of the Redirects to the corresponding URL, and renders a redirect view.
-func RedirectMiddleware(next http.Handler, analytics bool) http.Handler {
+// any of the rules of the table to their target, and renders a redirect
+// view. Requests matching an alias rule are served the target page instead.
+func RedirectMiddleware(next http.Handler, table *RedirectTable, analytics bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if the request path matches a redirect
-		if newPath, ok := Redirects[r.URL.Path]; ok {
-			http.Redirect(w, r, newPath, http.StatusFound)
-			components.RedirectView(components.RedirectData{
-				To:            newPath,
-				WithAnalytics: analytics,
-			}).Render(w)
+		newPath, rule := table.Match(r.URL.Path)
+		if rule == nil {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Call the next handler
-		next.ServeHTTP(w, r)
+		if rule.Alias {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path, r2.URL.RawPath = newPath, ""
+			next.ServeHTTP(w, r2)
+			return
+		}
+
+		status := http.StatusFound
+		if rule.Permanent {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, newPath, status)
+		components.RedirectView(components.RedirectData{
+			To:            newPath,
+			WithAnalytics: analytics,
+		}).Render(w)
 	})
 }
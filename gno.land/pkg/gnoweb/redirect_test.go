@@ -0,0 +1,105 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectMiddleware(t *testing.T) {
+	t.Parallel()
+
+	table, err := NewRedirectTable([]RedirectRule{
+		{From: "/blog", To: "/r/gnoland/blog"},
+		{From: "/r/demo/boards/", To: "/r/demo/boards2/", Match: RedirectMatchPrefix, Permanent: true},
+		{From: "/r/demo/", To: "/r/archive/", Match: RedirectMatchPrefix},
+		{From: `/u/(\w+)`, To: "/r/gnoland/users/v1:$1", Match: RedirectMatchRegexp},
+		{From: "/home", To: "/r/gnoland/home", Alias: true},
+	})
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "page "+r.URL.Path)
+	})
+	handler := RedirectMiddleware(next, table, false)
+
+	cases := []struct {
+		path     string
+		status   int
+		location string
+		body     string
+	}{
+		{"/blog", http.StatusFound, "/r/gnoland/blog", ""},
+		{"/blog/post", http.StatusOK, "", "page /blog/post"},
+		{"/r/demo/boards/1", http.StatusMovedPermanently, "/r/demo/boards2/1", ""},
+		{"/r/demo/foo", http.StatusFound, "/r/archive/foo", ""},
+		{"/u/alice", http.StatusFound, "/r/gnoland/users/v1:alice", ""},
+		{"/u/alice/bob", http.StatusOK, "", "page /u/alice/bob"},
+		{"/home", http.StatusOK, "", "page /r/gnoland/home"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, tc.location, rr.Header().Get("Location"))
+			if tc.body != "" {
+				assert.Equal(t, tc.body, rr.Body.String())
+			}
+		})
+	}
+
+	for _, rules := range [][]RedirectRule{
+		{{From: "/a"}},
+		{{From: "/a", To: "/b", Match: "glob"}},
+		{{From: "(", To: "/b", Match: RedirectMatchRegexp}},
+	} {
+		_, err := NewRedirectTable(rules)
+		assert.Error(t, err)
+	}
+}
+
+func TestLoadRedirectTable(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "redirects.json")
+	write := func(content string, mtime time.Time) {
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(file, mtime, mtime))
+	}
+	now := time.Now()
+	write(`[{"from": "/a", "to": "/b"}]`, now.Add(-time.Hour))
+
+	table, err := LoadRedirectTable(slog.New(slog.NewTextHandler(io.Discard, nil)), file)
+	require.NoError(t, err)
+	to, rule := table.Match("/a")
+	require.NotNil(t, rule)
+	assert.Equal(t, "/b", to)
+
+	// Reloaded once modified
+	write(`[{"from": "/a", "to": "/c", "permanent": true}]`, now)
+	table.checked = time.Time{}
+	to, rule = table.Match("/a")
+	require.NotNil(t, rule)
+	assert.Equal(t, "/c", to)
+	assert.True(t, rule.Permanent)
+
+	// Invalid rules are ignored
+	write(`[{"from": "/a"}]`, now.Add(time.Hour))
+	table.checked = time.Time{}
+	to, _ = table.Match("/a")
+	assert.Equal(t, "/c", to)
+
+	_, err = LoadRedirectTable(slog.New(slog.NewTextHandler(io.Discard, nil)), filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -1,10 +1,13 @@
 package gnoweb
 
 import (
+	"bytes"
 	"fmt"
+	"html/template"
 	"io"
 	"log/slog"
 	gopath "path"
+	"slices"
 	"strings"
 
 	"github.com/alecthomas/chroma/v2"
@@ -14,16 +17,25 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/yuin/goldmark"
 	markdown "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	mdhtml "github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 )
 
 // Renderer defines the interface for rendering realms and source files.
 type Renderer interface {
-	RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (md.Toc, error)
+	RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (RealmMeta, error)
 	RenderSource(w io.Writer, name string, src []byte) error
 }
 
+// RealmMeta holds the metadata of a rendered realm.
+type RealmMeta struct {
+	Title       string // text of the first level 1 heading, if any
+	Toc         md.Toc
+	Frontmatter md.Frontmatter
+}
+
 // HTMLRenderer implements the Renderer interface for HTML output.
 type HTMLRenderer struct {
 	logger *slog.Logger
@@ -31,48 +43,211 @@ type HTMLRenderer struct {
 
 	gm goldmark.Markdown
 	ch *chromahtml.Formatter
+	cl *chromahtml.Formatter // formats single lines, without wrapper
 }
 
 func NewHTMLRenderer(logger *slog.Logger, cfg RenderConfig) *HTMLRenderer {
-	gmOpts := append(cfg.GoldmarkOptions, goldmark.WithExtensions(
-		markdown.NewHighlighting(
-			markdown.WithFormatOptions(cfg.ChromaOptions...), // force using chroma config
-		),
-	))
+	highlighting := markdown.NewHighlighting(
+		markdown.WithFormatOptions(cfg.ChromaOptions...), // force using chroma config
+	)
+	if cfg.CodeLineAnchors {
+		highlighting = md.NewCodeLineAnchorsExtension(
+			markdown.WithFormatOptions(cfg.ChromaOptions...),
+		)
+	}
+	gmOpts := append(cfg.GoldmarkOptions, goldmark.WithExtensions(highlighting))
+	if cfg.HardLineBreaks {
+		gmOpts = append(gmOpts, goldmark.WithRendererOptions(mdhtml.WithHardWraps()))
+	}
 	return &HTMLRenderer{
 		logger: logger,
 		cfg:    &cfg,
 		gm:     goldmark.New(gmOpts...),
 		ch:     chromahtml.New(cfg.ChromaOptions...),
+		cl: chromahtml.New(append(slices.Clone(cfg.ChromaOptions),
+			chromahtml.WithLineNumbers(false),
+			chromahtml.PreventSurroundingPre(true),
+		)...),
 	}
 }
 
-// RenderRealm renders a realm to HTML and returns a table of contents.
-func (r *HTMLRenderer) RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (md.Toc, error) {
+// RenderRealm renders a realm to HTML and returns its metadata: a table of
+// contents and its frontmatter, if any.
+func (r *HTMLRenderer) RenderRealm(w io.Writer, u *weburl.GnoURL, src []byte) (RealmMeta, error) {
+	if isJSONContent(src) {
+		return RealmMeta{}, r.renderJSON(w, u, src)
+	}
+
 	ctx := md.NewGnoParserContext(u)
 
+	if r.cfg.NormalizeWhitespace {
+		src = collapseBlankLines(src)
+	}
+
 	// Use Goldmark for Markdown parsing
 	doc := r.gm.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
 	if err := r.gm.Renderer().Render(w, src, doc); err != nil {
-		return md.Toc{}, fmt.Errorf("unable to render markdown at path %q: %w", u.Path, err)
+		return RealmMeta{}, fmt.Errorf("unable to render markdown at path %q: %w", u.Path, err)
 	}
 
+	var meta RealmMeta
+	meta.Title = firstHeadingTitle(doc, src)
+	meta.Frontmatter, _ = md.GetFrontmatter(ctx)
+
 	toc, err := md.TocInspect(doc, src, md.TocOptions{MaxDepth: 6, MinDepth: 2})
 	if err != nil {
 		r.logger.Warn("unable to inspect for TOC elements", "error", err)
 	}
+	meta.Toc = toc
+
+	return meta, nil
+}
+
+// firstHeadingTitle returns the text of the first level 1 heading of the
+// document, if any.
+func firstHeadingTitle(doc ast.Node, src []byte) string {
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		heading, ok := n.(*ast.Heading)
+		if !ok || heading.Level != 1 {
+			continue
+		}
+
+		var title strings.Builder
+		ast.Walk(heading, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+			if entering {
+				switch t := node.(type) {
+				case *ast.Text:
+					title.Write(t.Segment.Value(src))
+				case *ast.String:
+					title.Write(t.Value)
+				}
+			}
+			return ast.WalkContinue, nil
+		})
+		return strings.TrimSpace(title.String())
+	}
+	return ""
+}
+
+// collapseBlankLines collapses interior runs of 3 or more blank lines into a
+// single blank line, except inside fenced code blocks. Leading and trailing
+// runs are kept as is.
+func collapseBlankLines(src []byte) []byte {
+	var (
+		out   bytes.Buffer
+		fence []byte   // opening fence of the current code block, if any
+		blank [][]byte // pending blank lines
+	)
+
+	out.Grow(len(src))
+	for _, line := range bytes.SplitAfter(src, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if fence == nil && len(trimmed) == 0 {
+			blank = append(blank, line)
+			continue
+		}
+
+		// Flush pending blank lines
+		if len(blank) >= 3 && out.Len() > 0 {
+			out.WriteByte('\n')
+		} else {
+			for _, b := range blank {
+				out.Write(b)
+			}
+		}
+		blank = blank[:0]
+
+		// Track fenced code blocks
+		if f := codeFence(line); f != nil {
+			switch {
+			case fence == nil:
+				fence = f
+			case f[0] == fence[0] && len(f) >= len(fence) && len(bytes.TrimLeft(trimmed, string(f[0]))) == 0:
+				fence = nil
+			}
+		}
+
+		out.Write(line)
+	}
+
+	for _, b := range blank {
+		out.Write(b)
+	}
 
-	return toc, nil
+	return out.Bytes()
+}
+
+// codeFence returns the fence marker (e.g. "```") starting the given line, or
+// nil if the line doesn't start with a fence.
+func codeFence(line []byte) []byte {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return nil
+	}
+
+	c := trimmed[0]
+	if c != '`' && c != '~' {
+		return nil
+	}
+
+	n := 0
+	for n < len(trimmed) && trimmed[n] == c {
+		n++
+	}
+	if n < 3 {
+		return nil
+	}
+
+	return trimmed[:n]
 }
 
 // RenderSource renders a source file into HTML with syntax highlighting based on its extension.
 func (r *HTMLRenderer) RenderSource(w io.Writer, name string, src []byte) error {
+	iterator, err := tokeniseSource(name, src)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ch.Format(w, r.cfg.ChromaStyle, iterator); err != nil {
+		return fmt.Errorf("unable to format source file %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// RenderSourceLines renders a source file into highlighted HTML fragments,
+// one per line, without their line break. The file is tokenised as a whole
+// so that tokens spanning several lines are highlighted consistently.
+func (r *HTMLRenderer) RenderSourceLines(name string, src []byte) ([]template.HTML, error) {
+	iterator, err := tokeniseSource(name, src)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := chroma.SplitTokensIntoLines(iterator.Tokens())
+	lines := make([]template.HTML, len(tokens))
+	for i, line := range tokens {
+		var buf strings.Builder
+		if err := r.cl.Format(&buf, r.cfg.ChromaStyle, chroma.Literator(line...)); err != nil {
+			return nil, fmt.Errorf("unable to format source file %q: %w", name, err)
+		}
+		lines[i] = template.HTML(strings.ReplaceAll(buf.String(), "\n", ""))
+	}
+	return lines, nil
+}
+
+// tokeniseSource tokenises a source file with the lexer of its extension.
+func tokeniseSource(name string, src []byte) (chroma.Iterator, error) {
 	var lexer chroma.Lexer
 
 	// Determine the lexer to be used based on the file extension.
 	switch strings.ToLower(gopath.Ext(name)) {
 	case ".gno":
-		lexer = lexers.Get("go")
+		lexer = lexers.Get("gno")
 	case ".md":
 		lexer = lexers.Get("markdown")
 	case ".mod":
@@ -84,19 +259,14 @@ func (r *HTMLRenderer) RenderSource(w io.Writer, name string, src []byte) error
 	}
 
 	if lexer == nil {
-		return fmt.Errorf("unsupported lexer for file %q", name)
+		return nil, fmt.Errorf("unsupported lexer for file %q", name)
 	}
 
 	iterator, err := lexer.Tokenise(nil, string(src))
 	if err != nil {
-		return fmt.Errorf("unable to tokenise %q: %w", name, err)
+		return nil, fmt.Errorf("unable to tokenise %q: %w", name, err)
 	}
-
-	if err := r.ch.Format(w, r.cfg.ChromaStyle, iterator); err != nil {
-		return fmt.Errorf("unable to format source file %q: %w", name, err)
-	}
-
-	return nil
+	return iterator, nil
 }
 
 // WriteChromaCSS writes the CSS for syntax highlighting to the provided writer.
@@ -19,6 +19,22 @@ type RenderConfig struct {
 	ChromaStyle     *chroma.Style
 	ChromaOptions   []chromahtml.Option
 	GoldmarkOptions []goldmark.Option
+
+	// NormalizeWhitespace collapses runs of blank lines in realm markdown
+	// before rendering, leaving fenced code blocks untouched.
+	NormalizeWhitespace bool
+
+	// CodeLineAnchors prefixes code block line number anchors with the
+	// block index, so that they are unique across the page.
+	CodeLineAnchors bool
+
+	// JSONTreeViewer wraps JSON realm output into a collapsible tree
+	// viewer, falling back to the highlighted JSON without JavaScript.
+	JSONTreeViewer bool
+
+	// HardLineBreaks renders the single newlines of paragraphs as line
+	// breaks, instead of collapsing them.
+	HardLineBreaks bool
 }
 
 // NewDefaultRenderConfig returns a RenderConfig with default styles and options.
@@ -43,7 +59,9 @@ func NewDefaultGoldmarkOptions() []goldmark.Option {
 			extension.Strikethrough,
 			extension.Table,
 			extension.Footnote,
+			extension.DefinitionList,
 			extension.TaskList,
+			md.NewFrontmatterExtension(),
 			md.NewGnoExtension(
 				md.WithImageValidator(allowSvgDataImage),
 			),
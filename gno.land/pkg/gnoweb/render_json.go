@@ -0,0 +1,64 @@
+package gnoweb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// jsonTreeCollapse is the number of entries above which an array or object
+// starts collapsed in the JSON tree viewer.
+const jsonTreeCollapse = 20
+
+// isJSONContent reports whether the given realm output is a JSON object or
+// array rather than markdown.
+func isJSONContent(src []byte) bool {
+	src = bytes.TrimSpace(src)
+	if len(src) == 0 || (src[0] != '{' && src[0] != '[') {
+		return false
+	}
+	return json.Valid(src)
+}
+
+// renderJSON renders JSON realm output as a highlighted block. If the tree
+// viewer is enabled, the block is wrapped into a container which the
+// `json-tree` controller replaces with a collapsible tree, keeping the
+// highlighted block as a no-JS fallback and raw view.
+func (r *HTMLRenderer) renderJSON(w io.Writer, u *weburl.GnoURL, src []byte) error {
+	src = bytes.TrimSpace(src)
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, src, "", "  "); err != nil {
+		return fmt.Errorf("unable to indent json at path %q: %w", u.Path, err)
+	}
+
+	iterator, err := lexers.Get("json").Tokenise(nil, pretty.String())
+	if err != nil {
+		return fmt.Errorf("unable to tokenise json at path %q: %w", u.Path, err)
+	}
+
+	if !r.cfg.JSONTreeViewer {
+		if err := r.ch.Format(w, r.cfg.ChromaStyle, iterator); err != nil {
+			return fmt.Errorf("unable to format json at path %q: %w", u.Path, err)
+		}
+		return nil
+	}
+
+	// Escape `<`, `>` and `&` so the payload can't close its script element
+	var data bytes.Buffer
+	json.HTMLEscape(&data, src)
+
+	fmt.Fprintf(w, `<div class="gno-json-tree" data-controller="json-tree" data-json-tree-collapse-value="%d">`+"\n", jsonTreeCollapse)
+	fmt.Fprintf(w, `<script type="application/json" data-json-tree-target="data">%s</script>`+"\n", data.Bytes())
+	io.WriteString(w, `<div class="gno-json-tree-source" data-json-tree-target="source">`+"\n")
+	if err := r.ch.Format(w, r.cfg.ChromaStyle, iterator); err != nil {
+		return fmt.Errorf("unable to format json at path %q: %w", u.Path, err)
+	}
+	io.WriteString(w, "</div>\n</div>\n")
+
+	return nil
+}
@@ -6,6 +6,18 @@ import (
 )
 
 func init() {
+	// Register a lexer for Gno source files, extending the Go one with the
+	// Gno specific types and builtins.
+	lexers.Register(chroma.MustNewLexer(
+		&chroma.Config{
+			Name:      "Gno",
+			Aliases:   []string{"gno"},
+			Filenames: []string{"*.gno"},
+			MimeTypes: []string{"text/x-gno"},
+		},
+		gnoRules,
+	))
+
 	// Register a custom lexer for Go/Gno module files (go.mod, gno.mod) for syntax highlighting.
 	lexers.Register(chroma.MustNewLexer(
 		&chroma.Config{
@@ -40,3 +52,16 @@ func init() {
 		},
 	))
 }
+
+// gnoRules returns the rules of the Go lexer, preceded by the Gno specific
+// ones so that they take precedence.
+func gnoRules() chroma.Rules {
+	rules := lexers.Go.(*chroma.RegexLexer).MustRules().Clone()
+	rules["root"] = append([]chroma.Rule{
+		// Crossing functions and realm builtins
+		{Pattern: `\b(crossing|cross|revive)\b(\()`, Type: chroma.ByGroups(chroma.NameBuiltin, chroma.Punctuation), Mutator: nil},
+		{Pattern: `\bcross\b`, Type: chroma.NameBuiltin, Mutator: nil},
+		{Pattern: `\b(realm|address)\b`, Type: chroma.KeywordType, Mutator: nil},
+	}, rules["root"]...)
+	return rules
+}
@@ -2,9 +2,14 @@ package gnoweb
 
 import (
 	bytes "bytes"
+	"flag"
 	"log/slog"
+	"strings"
 	"testing"
 
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
 	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,11 +24,45 @@ func TestRenderer_RenderRealm_Markdown(t *testing.T) {
 	w := &bytes.Buffer{}
 	u := &weburl.GnoURL{Path: "/r/test"}
 	src := []byte(`# Hello\n\nThis is a **test**.`)
-	toc, err := r.RenderRealm(w, u, src)
+	meta, err := r.RenderRealm(w, u, src)
 	require.NoError(t, err)
 	assert.Regexp(t, "<h1[^>]*>.*Hello.*</h1>", w.String())
 	assert.Contains(t, w.String(), "<strong>test</strong>")
-	assert.NotNil(t, toc)
+	assert.NotNil(t, meta)
+}
+
+func TestRenderer_RenderRealm_Academic(t *testing.T) {
+	r := newTestRenderer()
+	w := &bytes.Buffer{}
+	u := &weburl.GnoURL{Path: "/r/test"}
+	src := []byte("Petri nets[^1] model concurrency.\n\nPlace\n: Holds tokens.\n\n[^1]: Carl Adam Petri, 1962.\n")
+	_, err := r.RenderRealm(w, u, src)
+	require.NoError(t, err)
+	assert.Contains(t, w.String(), `<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>`)
+	assert.Contains(t, w.String(), "<dl>\n<dt>Place</dt>\n<dd>Holds tokens.</dd>\n</dl>")
+}
+
+func TestRenderer_RenderRealm_JSON(t *testing.T) {
+	r := newTestRenderer()
+	u := &weburl.GnoURL{Path: "/r/test"}
+
+	cases := []struct {
+		src  string
+		json bool
+	}{
+		{`{"a": 1}`, true},
+		{"\n  [1, 2]\n", true},
+		{`{not json}`, false},
+		{`"string"`, false},
+		{`# [Title]`, false},
+	}
+
+	for _, tc := range cases {
+		w := &bytes.Buffer{}
+		_, err := r.RenderRealm(w, u, []byte(tc.src))
+		require.NoError(t, err)
+		assert.Equal(t, tc.json, strings.Contains(w.String(), "chroma-chroma"), "source: %q", tc.src)
+	}
 }
 
 func TestRenderer_RenderSource_Gno(t *testing.T) {
@@ -35,6 +74,34 @@ func TestRenderer_RenderSource_Gno(t *testing.T) {
 	assert.Contains(t, w.String(), "chroma-") // chroma CSS classes
 }
 
+func TestGnoLexer(t *testing.T) {
+	lexer := lexers.Get("gno")
+	require.NotNil(t, lexer)
+	assert.Equal(t, lexer, lexers.Match("render.gno"))
+
+	src := "func Transfer(cur realm, to address) {\n\tcrossing()\n\tbar.Do(cross, 1)\n\trevive(f)\n}\n"
+	iterator, err := lexer.Tokenise(nil, src)
+	require.NoError(t, err)
+
+	types := map[string]chroma.TokenType{}
+	for _, token := range iterator.Tokens() {
+		types[token.Value] = token.Type
+	}
+	assert.Equal(t, chroma.KeywordType, types["realm"])
+	assert.Equal(t, chroma.KeywordType, types["address"])
+	assert.Equal(t, chroma.NameBuiltin, types["crossing"])
+	assert.Equal(t, chroma.NameBuiltin, types["cross"])
+	assert.Equal(t, chroma.NameBuiltin, types["revive"])
+	assert.Equal(t, chroma.KeywordDeclaration, types["func"], "go rules still apply")
+
+	// Realm markdown code fences use the same lexer
+	r := newTestRenderer()
+	w := &bytes.Buffer{}
+	_, err = r.RenderRealm(w, &weburl.GnoURL{Path: "/r/test"}, []byte("```gno\nfunc F(cur realm) {}\n```\n"))
+	require.NoError(t, err)
+	assert.Contains(t, w.String(), `<span class="chroma-kt">realm</span>`)
+}
+
 func TestRenderer_RenderSource_Markdown(t *testing.T) {
 	r := newTestRenderer()
 	w := &bytes.Buffer{}
@@ -89,3 +156,99 @@ func TestRenderer_WriteFormatterCSS(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, w.String(), ".chroma-")
 }
+
+func TestCollapseBlankLines(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"single blank line", "a\n\nb\n", "a\n\nb\n"},
+		{"two blank lines", "a\n\n\nb\n", "a\n\n\nb\n"},
+		{"many blank lines", "a\n\n\n\n\n\nb\n", "a\n\nb\n"},
+		{"whitespace only lines", "a\n  \n\t\n \n\nb", "a\n\nb"},
+		{"inside code block", "```\na\n\n\n\n\nb\n```\n\n\n\n\nc\n", "```\na\n\n\n\n\nb\n```\n\nc\n"},
+		{"inside tilde code block", "~~~~go\na\n\n\n\n```\n\n\n\nb\n~~~~\n", "~~~~go\na\n\n\n\n```\n\n\n\nb\n~~~~\n"},
+		{"leading blank lines", "\n\n\n\na\n", "\n\n\n\na\n"},
+		{"trailing blank lines", "a\n\n\n\n  ", "a\n\n\n\n  "},
+		{"trailing blank lines after code block", "```\na\n```\n\n\n\n", "```\na\n```\n\n\n\n"},
+		{"trailing blank lines inside code block", "```\na\n\n\n\n  \n", "```\na\n\n\n\n  \n"},
+		{"few whitespace lines kept", "a\n    \n\t\nb\n", "a\n    \n\t\nb\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(collapseBlankLines([]byte(tc.input))))
+		})
+	}
+}
+
+func TestRenderer_RenderRealm_NormalizeWhitespace(t *testing.T) {
+	cfg := NewDefaultRenderConfig()
+	cfg.NormalizeWhitespace = true
+	r := NewHTMLRenderer(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)), cfg)
+
+	w := &bytes.Buffer{}
+	src := []byte("first\n\n\n\n\nsecond\n\n```\ncode\n\n\n\nblock\n```\n")
+	_, err := r.RenderRealm(w, &weburl.GnoURL{Path: "/r/test"}, src)
+	require.NoError(t, err)
+	assert.Contains(t, w.String(), "<p>first</p>\n<p>second</p>")
+	assert.Contains(t, w.String(), "code\n\n\n\nblock")
+}
+
+var updateGolden = flag.Bool("update-golden-tests", false, "update golden tests")
+
+// TestRenderer_HardLineBreaks compares the soft and hard line breaks output
+// of the same sources.
+func TestRenderer_HardLineBreaks(t *testing.T) {
+	gold := md.NewGoldentTests(func(t *testing.T, nameIn string, input []byte) (string, []byte) {
+		t.Helper()
+
+		var out bytes.Buffer
+		for _, hard := range []bool{false, true} {
+			cfg := NewDefaultRenderConfig()
+			cfg.HardLineBreaks = hard
+			r := NewHTMLRenderer(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)), cfg)
+
+			if hard {
+				out.WriteString("<!-- hard line breaks -->\n")
+			} else {
+				out.WriteString("<!-- soft line breaks -->\n")
+			}
+			_, err := r.RenderRealm(&out, &weburl.GnoURL{Path: "/r/test"}, input)
+			require.NoError(t, err)
+			if !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+				out.WriteString("\n")
+			}
+		}
+
+		return "output.html", out.Bytes()
+	})
+	gold.Update = *updateGolden
+	gold.Run(t, "testdata/linebreaks")
+}
+
+func TestRenderer_RenderRealm_Title(t *testing.T) {
+	r := newTestRenderer()
+	u := &weburl.GnoURL{Path: "/r/test"}
+	src := []byte("Intro\n\n## Section\n\n# Main `title` *here*\n\n# Other\n")
+
+	meta, err := r.RenderRealm(&bytes.Buffer{}, u, src)
+	require.NoError(t, err)
+	assert.Equal(t, "Main title here", meta.Title)
+}
+
+func TestRenderer_RenderSourceLines(t *testing.T) {
+	r := newTestRenderer()
+	src := []byte("package foo\n\n/* a\nb */\nvar x = \"<x>\"\n")
+
+	lines, err := r.RenderSourceLines("foo.gno", src)
+	require.NoError(t, err)
+	require.Len(t, lines, 5)
+	assert.Equal(t, `<span class="chroma-kn">package</span> <span class="chroma-nx">foo</span>`, string(lines[0]))
+	assert.Empty(t, lines[1])
+	// Multi-line tokens are highlighted on each of their lines
+	assert.Equal(t, `<span class="chroma-cm">/* a</span>`, string(lines[2]))
+	assert.Equal(t, `<span class="chroma-cm">b */</span>`, string(lines[3]))
+	assert.Contains(t, string(lines[4]), "&#34;&lt;x&gt;&#34;")
+}
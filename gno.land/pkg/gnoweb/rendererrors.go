@@ -0,0 +1,114 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RenderErrorsPath is the path of the render errors history endpoint.
+const RenderErrorsPath = "/debug/render-errors"
+
+const (
+	// renderErrorsPerPath is the number of errors kept per path.
+	renderErrorsPerPath = 10
+	// renderErrorsMaxPaths bounds the number of paths errors are kept for.
+	renderErrorsMaxPaths = 256
+	// renderErrorHeightTimeout bounds the block height query of a record.
+	renderErrorHeightTimeout = time.Second
+)
+
+// RenderError is a recorded render failure of a path.
+type RenderError struct {
+	Time   time.Time `json:"time"`
+	Height int64     `json:"height,omitempty"`
+	Status int       `json:"status"`
+	Error  string    `json:"error"`
+}
+
+// RenderErrorLog keeps the most recent render errors of each path in
+// memory, for debugging intermittent failures.
+type RenderErrorLog struct {
+	height heightFunc
+
+	mu     sync.Mutex
+	errors map[string][]RenderError
+}
+
+// NewRenderErrorLog returns an empty RenderErrorLog, recording the block
+// height returned by height along with each error.
+func NewRenderErrorLog(height func(ctx context.Context) (int64, error)) *RenderErrorLog {
+	return &RenderErrorLog{height: height, errors: make(map[string][]RenderError)}
+}
+
+// Record records a render error of the given path, with the status of the
+// error page it resulted in.
+func (l *RenderErrorLog) Record(ctx context.Context, path string, status int, err error) {
+	entry := RenderError{Time: time.Now(), Status: status, Error: err.Error()}
+	if l.height != nil {
+		ctx, cancel := context.WithTimeout(ctx, renderErrorHeightTimeout)
+		entry.Height, _ = l.height(ctx) // unknown on failure
+		cancel()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, ok := l.errors[path]
+	if !ok && len(l.errors) >= renderErrorsMaxPaths {
+		l.evictOldest()
+	}
+	if len(entries) >= renderErrorsPerPath {
+		entries = entries[len(entries)-renderErrorsPerPath+1:]
+	}
+	l.errors[path] = append(entries, entry)
+}
+
+// evictOldest forgets the path whose last error is the oldest.
+func (l *RenderErrorLog) evictOldest() {
+	var (
+		oldest string
+		last   time.Time
+	)
+	for path, entries := range l.errors {
+		if t := entries[len(entries)-1].Time; oldest == "" || t.Before(last) {
+			oldest, last = path, t
+		}
+	}
+	delete(l.errors, oldest)
+}
+
+// Errors returns the recorded errors of the given path, most recent first.
+func (l *RenderErrorLog) Errors(path string) []RenderError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := l.errors[path]
+	res := make([]RenderError, len(entries))
+	for i, entry := range entries {
+		res[len(entries)-1-i] = entry
+	}
+	return res
+}
+
+// handlerRenderErrors serves the recorded render errors of the path given
+// by the `path` query parameter, as JSON.
+func handlerRenderErrors(l *RenderErrorLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSpace(r.URL.Query().Get("path"))
+		if path == "" {
+			http.Error(w, "missing `path` query parameter", http.StatusBadRequest)
+			return
+		}
+		path = "/" + strings.Trim(path, "/")
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Path   string        `json:"path"`
+			Errors []RenderError `json:"errors"`
+		}{path, l.Errors(path)})
+	})
+}
@@ -0,0 +1,91 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingClient fails to render any realm with the given error.
+type failingClient struct {
+	catalogClient
+	err error
+}
+
+func (c *failingClient) Realm(ctx context.Context, path, args string) ([]byte, error) {
+	return nil, c.err
+}
+
+func TestRenderErrorLog(t *testing.T) {
+	t.Parallel()
+
+	height := func(ctx context.Context) (int64, error) { return 42, nil }
+	log := NewRenderErrorLog(height)
+
+	client := &failingClient{err: fmt.Errorf("%w: deadline exceeded", ErrClientTimeout)}
+	handler, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: client,
+		Renderer:      NewHTMLRenderer(slog.New(slog.NewTextHandler(io.Discard, nil)), NewDefaultRenderConfig()),
+		Aliases:       map[string]AliasTarget{},
+		RenderErrors:  log,
+	})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/flaky", nil))
+	require.Equal(t, http.StatusRequestTimeout, rr.Code)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handlerRenderErrors(log).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, RenderErrorsPath+"?path="+path, nil))
+		return rr
+	}
+
+	t.Run("recorded errors", func(t *testing.T) {
+		rr := get("/r/demo/flaky")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+		var res struct {
+			Path   string        `json:"path"`
+			Errors []RenderError `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+		assert.Equal(t, "/r/demo/flaky", res.Path)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, int64(42), res.Errors[0].Height)
+		assert.Equal(t, http.StatusRequestTimeout, res.Errors[0].Status)
+		assert.Equal(t, "RPC node request timeout: deadline exceeded", res.Errors[0].Error)
+		assert.False(t, res.Errors[0].Time.IsZero())
+
+		assert.Contains(t, get("r/demo/other/").Body.String(), `"errors":[]`)
+		assert.Equal(t, http.StatusBadRequest, get("").Code)
+	})
+
+	t.Run("bounded history", func(t *testing.T) {
+		log := NewRenderErrorLog(nil)
+		for i := range renderErrorsPerPath + 5 {
+			log.Record(context.Background(), "/r/demo/boards", http.StatusInternalServerError, fmt.Errorf("error %d", i))
+		}
+
+		entries := log.Errors("/r/demo/boards")
+		require.Len(t, entries, renderErrorsPerPath)
+		assert.Equal(t, fmt.Sprintf("error %d", renderErrorsPerPath+4), entries[0].Error, "most recent first")
+		assert.Equal(t, "error 5", entries[len(entries)-1].Error)
+
+		// The number of tracked paths is bounded too
+		for i := range renderErrorsMaxPaths {
+			log.Record(context.Background(), fmt.Sprintf("/r/demo/%d", i), http.StatusInternalServerError, fmt.Errorf("error"))
+		}
+		assert.Len(t, log.errors, renderErrorsMaxPaths)
+		assert.Empty(t, log.Errors("/r/demo/boards"), "oldest path should be evicted")
+	})
+}
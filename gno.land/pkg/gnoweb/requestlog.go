@@ -0,0 +1,72 @@
+package gnoweb
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader carries the ID of a request, in both the request and the
+// response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds the size of the request IDs accepted from clients.
+const maxRequestIDLen = 64
+
+type requestIDKey struct{}
+
+// requestIDFromContext returns the ID of the request, if any.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// isValidRequestID reports whether a client provided request ID is safe to
+// reuse in logs and headers.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogMiddleware attaches an ID to each request, the one given by the
+// `X-Request-ID` header if valid, or a new one, and returns it in the
+// response. Requests are logged with their method, path, status, duration
+// and ID, along with their trace ID when traced.
+func RequestLogMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = randomHex(16)
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+
+		rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rw.status,
+			"took", time.Since(start),
+			"request_id", id,
+		}
+		if traceID := traceIDFromContext(ctx); traceID != "" {
+			attrs = append(attrs, "trace_id", traceID)
+		}
+		logger.Info("request", attrs...)
+	})
+}
@@ -0,0 +1,50 @@
+package gnoweb_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	handler := gnoweb.RequestLogMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	serve := func(requestID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/r/test", nil)
+		if requestID != "" {
+			req.Header.Set(gnoweb.RequestIDHeader, requestID)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		return rr.Header().Get(gnoweb.RequestIDHeader)
+	}
+
+	t.Run("incoming id", func(t *testing.T) {
+		assert.Equal(t, "req-42", serve("req-42"))
+		assert.Contains(t, logs.String(), "msg=request method=GET path=/r/test status=404")
+		assert.Contains(t, logs.String(), "request_id=req-42")
+		assert.NotContains(t, logs.String(), "trace_id=")
+	})
+
+	t.Run("new id", func(t *testing.T) {
+		for _, invalid := range []string{"", "bad id\n", strings.Repeat("a", 65)} {
+			id := serve(invalid)
+			assert.Len(t, id, 16)
+			assert.Contains(t, logs.String(), "request_id="+id)
+		}
+	})
+}
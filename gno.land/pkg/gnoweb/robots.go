@@ -0,0 +1,76 @@
+package gnoweb
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RobotsPath is the path of the robots.txt crawler policy.
+const RobotsPath = "/robots.txt"
+
+// RobotsPolicy is the crawler policy served at `/robots.txt`, applying to
+// all user agents. The zero policy allows crawling everything.
+type RobotsPolicy struct {
+	// Allow and Disallow are the path prefixes crawlers may and may not
+	// fetch, such as `/r/demo/`. The longest matching prefix wins, and
+	// `*` matches any sequence of characters.
+	Allow    []string
+	Disallow []string
+	// CrawlDelay, if set, is the delay crawlers should wait between two
+	// requests, rounded up to the second.
+	CrawlDelay time.Duration
+}
+
+// validate checks the rules of the policy.
+func (p RobotsPolicy) validate() error {
+	for _, rule := range slices.Concat(p.Allow, p.Disallow) {
+		if !strings.HasPrefix(rule, "/") && !strings.HasPrefix(rule, "*") {
+			return fmt.Errorf("invalid robots rule %q: must start with `/` or `*`", rule)
+		}
+		if strings.ContainsAny(rule, "\r\n#") {
+			return fmt.Errorf("invalid robots rule %q", rule)
+		}
+	}
+	if p.CrawlDelay < 0 {
+		return fmt.Errorf("invalid robots crawl delay %s", p.CrawlDelay)
+	}
+	return nil
+}
+
+// text returns the robots.txt document of the policy.
+func (p RobotsPolicy) text() string {
+	var sb strings.Builder
+	sb.WriteString("User-agent: *\n")
+	for _, rule := range p.Allow {
+		sb.WriteString("Allow: " + rule + "\n")
+	}
+	for _, rule := range p.Disallow {
+		sb.WriteString("Disallow: " + rule + "\n")
+	}
+	if len(p.Allow) == 0 && len(p.Disallow) == 0 {
+		sb.WriteString("Disallow:\n") // allow everything
+	}
+	if p.CrawlDelay > 0 {
+		delay := int(math.Ceil(p.CrawlDelay.Seconds()))
+		sb.WriteString("Crawl-delay: " + strconv.Itoa(delay) + "\n")
+	}
+	return sb.String()
+}
+
+// handlerRobots serves the robots.txt document of the given policy,
+// referencing the sitemap if enabled.
+func handlerRobots(policy RobotsPolicy, sitemap bool) http.Handler {
+	text := policy.text()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(text))
+		if sitemap {
+			w.Write([]byte("\nSitemap: " + requestOrigin(r) + SitemapPath + "\n"))
+		}
+	})
+}
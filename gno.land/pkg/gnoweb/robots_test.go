@@ -0,0 +1,60 @@
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRobots(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		policy   RobotsPolicy
+		sitemap  bool
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: "User-agent: *\nDisallow:\n",
+		},
+		{
+			name: "configured",
+			policy: RobotsPolicy{
+				Allow:      []string{"/r/gnoland/"},
+				Disallow:   []string{"/r/", "/*$source"},
+				CrawlDelay: 1500 * time.Millisecond,
+			},
+			sitemap: true,
+			expected: "User-agent: *\nAllow: /r/gnoland/\nDisallow: /r/\nDisallow: /*$source\nCrawl-delay: 2\n" +
+				"\nSitemap: https://gno.land/sitemap.xml\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.NoError(t, tc.policy.validate())
+
+			req := httptest.NewRequest(http.MethodGet, "https://gno.land"+RobotsPath, nil)
+			rr := httptest.NewRecorder()
+			handlerRobots(tc.policy, tc.sitemap).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expected, rr.Body.String())
+		})
+	}
+
+	for _, invalid := range []RobotsPolicy{
+		{Allow: []string{"r/demo"}},
+		{Disallow: []string{"/r/\nSitemap: https://evil.com"}},
+		{CrawlDelay: -time.Second},
+	} {
+		assert.Error(t, invalid.validate())
+	}
+}
@@ -0,0 +1,334 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// RPCGatewayPath is the path of the read-only RPC gateway.
+const RPCGatewayPath = "/rpc"
+
+const (
+	// rpcGatewayMaxBody bounds the size of gateway requests.
+	rpcGatewayMaxBody = 64 << 10
+	// rpcGatewayMaxBatch bounds the number of calls of a batch request.
+	rpcGatewayMaxBatch = 20
+	// rpcGatewayTimeout bounds the duration of a gateway request.
+	rpcGatewayTimeout = 10 * time.Second
+	// rpcGatewayMaxPaths bounds the number of paths listed by `list_paths`.
+	rpcGatewayMaxPaths = 1_000
+	// rpcGatewayCacheSize is the number of call results cached.
+	rpcGatewayCacheSize = 1024
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcCodeParseError     = -32700
+	rpcCodeInvalidRequest = -32600
+	rpcCodeMethodNotFound = -32601
+	rpcCodeInvalidParams  = -32602
+	rpcCodeServerError    = -32000
+)
+
+// rpcGatewayRequest is a JSON-RPC 2.0 call to the gateway.
+type rpcGatewayRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcGatewayResponse is a JSON-RPC 2.0 response of the gateway.
+type rpcGatewayResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      json.RawMessage  `json:"id"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *rpcGatewayError `json:"error,omitempty"`
+}
+
+type rpcGatewayError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcGatewayError) Error() string {
+	return e.Message
+}
+
+// rpcGatewayMethod is a read-only gateway method, decoding its own params.
+type rpcGatewayMethod func(ctx context.Context, params json.RawMessage) (any, error)
+
+// RPCGatewayMethods lists the methods the gateway may expose.
+var RPCGatewayMethods = []string{"status", "render", "list_files", "list_paths"}
+
+// rpcGateway proxies an allowlist of read-only queries to the node.
+type rpcGateway struct {
+	logger  *slog.Logger
+	methods map[string]rpcGatewayMethod
+	height  heightFunc
+	results *lru.Cache[string, rpcCachedResult]
+}
+
+// rpcCachedResult is the result of a gateway call, valid at the block
+// height it was made at.
+type rpcCachedResult struct {
+	height int64
+	result json.RawMessage
+}
+
+// handlerRPCGateway serves a CORS enabled JSON-RPC 2.0 endpoint proxying the
+// given read-only methods, a subset of RPCGatewayMethods, to the node.
+// Batch requests are supported. Notifications, calls without an `id`, get
+// no response: as methods are read-only, they are not even run. Realms
+// behind a content warning, under one of warnPaths, are not rendered.
+// Successful results are cached until the block height, as returned by
+// `height`, advances.
+func handlerRPCGateway(logger *slog.Logger, cli ClientAdapter, status nodeStatusFunc, height heightFunc, allowed, warnPaths []string) (http.Handler, error) {
+	all := map[string]rpcGatewayMethod{
+		"status": func(ctx context.Context, _ json.RawMessage) (any, error) {
+			res, err := status(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"chain_id":    res.NodeInfo.Network,
+				"height":      res.SyncInfo.LatestBlockHeight,
+				"catching_up": res.SyncInfo.CatchingUp,
+			}, nil
+		},
+		"render": func(ctx context.Context, params json.RawMessage) (any, error) {
+			var p struct {
+				Path string `json:"path"`
+				Args string `json:"args"`
+			}
+			if err := decodeRPCParams(params, &p); err != nil || p.Path == "" {
+				return nil, &rpcGatewayError{rpcCodeInvalidParams, "expected a `path`"}
+			}
+			if _, warned := matchWarnPath(warnPaths, p.Path); warned {
+				return nil, &rpcGatewayError{rpcCodeServerError, "flagged content, behind a content warning"}
+			}
+			raw, err := cli.Realm(ctx, p.Path, p.Args)
+			if err != nil {
+				return nil, err
+			}
+			return string(raw), nil
+		},
+		"list_files": func(ctx context.Context, params json.RawMessage) (any, error) {
+			var p struct {
+				Path string `json:"path"`
+			}
+			if err := decodeRPCParams(params, &p); err != nil || p.Path == "" {
+				return nil, &rpcGatewayError{rpcCodeInvalidParams, "expected a `path`"}
+			}
+			return cli.ListFiles(ctx, p.Path)
+		},
+		"list_paths": func(ctx context.Context, params json.RawMessage) (any, error) {
+			var p struct {
+				Prefix string `json:"prefix"`
+				Limit  int    `json:"limit"`
+			}
+			if err := decodeRPCParams(params, &p); err != nil || p.Prefix == "" {
+				return nil, &rpcGatewayError{rpcCodeInvalidParams, "expected a `prefix`"}
+			}
+			if p.Limit <= 0 || p.Limit > rpcGatewayMaxPaths {
+				p.Limit = rpcGatewayMaxPaths
+			}
+			return cli.ListPaths(ctx, p.Prefix, p.Limit)
+		},
+	}
+
+	results, err := lru.New[string, rpcCachedResult](rpcGatewayCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	gw := &rpcGateway{
+		logger:  logger,
+		methods: make(map[string]rpcGatewayMethod, len(allowed)),
+		height:  height,
+		results: results,
+	}
+	for _, name := range allowed {
+		method, ok := all[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown rpc gateway method %q", name)
+		}
+		gw.methods[name] = method
+	}
+
+	return gw, nil
+}
+
+// decodeRPCParams decodes the given named params into v.
+func decodeRPCParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(params, v)
+}
+
+func (gw *rpcGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Max-Age", "86400")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, rpcGatewayMaxBody)).Decode(&body); err != nil {
+		gw.writeResponse(w, rpcGatewayResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &rpcGatewayError{rpcCodeParseError, "parse error"},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), rpcGatewayTimeout)
+	defer cancel()
+
+	// Single call
+	if body = bytes.TrimSpace(body); len(body) == 0 || body[0] != '[' {
+		if res, ok := gw.call(ctx, body); ok {
+			gw.writeResponse(w, res)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 || len(batch) > rpcGatewayMaxBatch {
+		gw.writeResponse(w, rpcGatewayResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &rpcGatewayError{rpcCodeInvalidRequest, fmt.Sprintf("expected a batch of 1 to %d calls", rpcGatewayMaxBatch)},
+		})
+		return
+	}
+
+	var res []rpcGatewayResponse
+	for _, call := range batch {
+		if r, ok := gw.call(ctx, call); ok {
+			res = append(res, r)
+		}
+	}
+	if len(res) == 0 {
+		w.WriteHeader(http.StatusNoContent) // only notifications
+		return
+	}
+	gw.writeResponse(w, res)
+}
+
+// call runs a single JSON-RPC call. It returns false for notifications,
+// which must not be answered.
+func (gw *rpcGateway) call(ctx context.Context, raw json.RawMessage) (rpcGatewayResponse, bool) {
+	var req rpcGatewayRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcGatewayResponse{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &rpcGatewayError{rpcCodeInvalidRequest, "invalid request"},
+		}, true
+	}
+
+	// An explicit `"id": null` is kept as is, only absent ids are notifications
+	if len(req.ID) == 0 {
+		return rpcGatewayResponse{}, false
+	}
+
+	res := rpcGatewayResponse{JSONRPC: "2.0", ID: req.ID}
+	method, ok := gw.methods[req.Method]
+	if !ok {
+		res.Error = &rpcGatewayError{rpcCodeMethodNotFound, "method not allowed"}
+		return res, true
+	}
+
+	result, err := gw.cachedCall(ctx, req.Method, method, req.Params)
+	if err != nil {
+		res.Error = gw.toRPCError(req.Method, err)
+		return res, true
+	}
+
+	res.Result = result
+	return res, true
+}
+
+// cachedCall returns the result of the given method, served from the cache
+// if it was called with the same params at the current block height.
+func (gw *rpcGateway) cachedCall(ctx context.Context, name string, method rpcGatewayMethod, params json.RawMessage) (json.RawMessage, error) {
+	key := name
+	if len(params) > 0 {
+		var compact bytes.Buffer
+		_ = json.Compact(&compact, params) // already decoded, thus valid
+		key += "\x00" + compact.String()
+	}
+
+	height, herr := gw.height(ctx)
+	if herr == nil {
+		if cached, ok := gw.results.Get(key); ok && cached.height == height {
+			return cached.result, nil
+		}
+	}
+
+	result, err := method(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	if herr == nil && len(raw) <= pageCacheMaxBody {
+		gw.results.Add(key, rpcCachedResult{height: height, result: raw})
+	}
+	return raw, nil
+}
+
+// toRPCError converts the given method error, only exposing the messages
+// of known client errors.
+func (gw *rpcGateway) toRPCError(method string, err error) *rpcGatewayError {
+	var rpcErr *rpcGatewayError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	for _, known := range []error{
+		ErrClientPackageNotFound, ErrClientFileNotFound, ErrClientRenderNotDeclared,
+		ErrClientTimeout, ErrClientTransport, ErrClientUnavailable,
+	} {
+		if errors.Is(err, known) {
+			return &rpcGatewayError{rpcCodeServerError, known.Error()}
+		}
+	}
+
+	gw.logger.Error("rpc gateway call failed", "method", method, "error", err)
+	return &rpcGatewayError{rpcCodeServerError, "internal error"}
+}
+
+func (gw *rpcGateway) writeResponse(w http.ResponseWriter, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		gw.logger.Error("unable to write rpc gateway response", "error", err)
+	}
+}
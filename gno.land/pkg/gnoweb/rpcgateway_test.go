@@ -0,0 +1,165 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerRPCGateway(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{realms: map[string]string{"/r/demo/boards": "# Boards"}}
+	status := func(ctx context.Context) (*ctypes.ResultStatus, error) {
+		res := &ctypes.ResultStatus{}
+		res.NodeInfo.Network = "dev"
+		res.SyncInfo.LatestBlockHeight = 42
+		return res, nil
+	}
+
+	height := func(ctx context.Context) (int64, error) { return 42, nil }
+
+	handler, err := handlerRPCGateway(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, status, height, []string{"status", "render", "list_paths"}, []string{"/r/flagged"})
+	require.NoError(t, err)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, RPCGatewayPath, strings.NewReader(body)))
+		return rr
+	}
+
+	t.Run("allowed methods", func(t *testing.T) {
+		t.Parallel()
+
+		rr := post(`[
+			{"jsonrpc": "2.0", "id": 1, "method": "status"},
+			{"jsonrpc": "2.0", "id": 2, "method": "render", "params": {"path": "/r/demo/boards"}},
+			{"jsonrpc": "2.0", "id": 3, "method": "list_paths", "params": {"prefix": "gno.land/r/", "limit": 2}}
+		]`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.JSONEq(t, `[
+			{"jsonrpc": "2.0", "id": 1, "result": {"chain_id": "dev", "height": 42, "catching_up": false}},
+			{"jsonrpc": "2.0", "id": 2, "result": "# Boards"},
+			{"jsonrpc": "2.0", "id": 3, "result": ["/r/demo/boards", "/r/demo/blog", "/r/gnoland/home", "/r/demo/norender"]}
+		]`, rr.Body.String())
+	})
+
+	t.Run("flagged realms", func(t *testing.T) {
+		t.Parallel()
+
+		rr := post(`{"jsonrpc": "2.0", "id": 1, "method": "render", "params": {"path": "/r/flagged/realm"}}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "flagged content, behind a content warning"}}`, rr.Body.String())
+	})
+
+	t.Run("rejected methods", func(t *testing.T) {
+		t.Parallel()
+
+		for _, method := range []string{"list_files", "broadcast_tx_commit", "abci_query"} {
+			rr := post(`{"jsonrpc": "2.0", "id": "a", "method": "` + method + `", "params": {"path": "/r/demo/boards"}}`)
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var res rpcGatewayResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.NotNil(t, res.Error, method)
+			assert.Equal(t, rpcCodeMethodNotFound, res.Error.Code, method)
+			assert.Empty(t, res.Result, method)
+		}
+	})
+
+	t.Run("client errors", func(t *testing.T) {
+		t.Parallel()
+
+		rr := post(`{"jsonrpc": "2.0", "id": 1, "method": "render", "params": {"path": "/r/none"}}`)
+		assert.JSONEq(t, `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "render function not declared"}}`, rr.Body.String())
+
+		rr = post(`{"jsonrpc": "2.0", "id": 1, "method": "render"}`)
+		assert.Contains(t, rr.Body.String(), `"code":-32602`)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Contains(t, post(`{`).Body.String(), `"code":-32700`)
+		assert.Contains(t, post(`{"id": 1, "method": "status"}`).Body.String(), `"code":-32600`)
+		assert.Contains(t, post(`[]`).Body.String(), `"code":-32600`)
+	})
+
+	t.Run("notifications", func(t *testing.T) {
+		t.Parallel()
+
+		rr := post(`{"jsonrpc": "2.0", "method": "status"}`)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Body.String())
+
+		rr = post(`[{"jsonrpc": "2.0", "method": "status"}, {"jsonrpc": "2.0", "method": "broadcast_tx_commit"}]`)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Body.String())
+
+		rr = post(`[{"jsonrpc": "2.0", "method": "status"}, {"jsonrpc": "2.0", "id": null, "method": "status"}]`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[{"jsonrpc": "2.0", "id": null, "result": {"chain_id": "dev", "height": 42, "catching_up": false}}]`, rr.Body.String())
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, RPCGatewayPath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodOptions, RPCGatewayPath, nil))
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, "POST, OPTIONS", rr.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("unknown allowed method", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := handlerRPCGateway(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, status, height, []string{"broadcast_tx_commit"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestHandlerRPCGateway_Cache(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{}
+	var latest int64
+	height := func(ctx context.Context) (int64, error) { return latest, nil }
+
+	handler, err := handlerRPCGateway(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, nil, height, []string{"list_paths"}, nil)
+	require.NoError(t, err)
+
+	post := func(body string) string {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, RPCGatewayPath, strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	// Calls with the same params are cached, whatever their id or formatting
+	first := post(`{"jsonrpc": "2.0", "id": 1, "method": "list_paths", "params": {"prefix": "gno.land/r/"}}`)
+	second := post(`{"jsonrpc": "2.0", "id": 2, "method": "list_paths", "params": {"prefix":"gno.land/r/"}}`)
+	assert.Equal(t, 1, cli.lists)
+	assert.Equal(t, strings.Replace(first, `"id":1`, `"id":2`, 1), second)
+
+	post(`{"jsonrpc": "2.0", "id": 3, "method": "list_paths", "params": {"prefix": "gno.land/p/"}}`)
+	assert.Equal(t, 2, cli.lists)
+
+	// Until the block height advances
+	latest = 1
+	post(`{"jsonrpc": "2.0", "id": 4, "method": "list_paths", "params": {"prefix": "gno.land/r/"}}`)
+	assert.Equal(t, 3, cli.lists)
+}
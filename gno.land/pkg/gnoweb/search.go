@@ -0,0 +1,413 @@
+package gnoweb
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	gopath "path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	md "github.com/gnolang/gno/gno.land/pkg/gnoweb/markdown"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// SearchPath is the path of the search page.
+const SearchPath = "/search"
+
+const (
+	// DefaultSearchTTL is the default duration after which the search index
+	// is rebuilt.
+	DefaultSearchTTL = 30 * time.Minute
+
+	// searchBuildTimeout bounds the duration of an index build.
+	searchBuildTimeout = 10 * time.Minute
+	// searchMaxPackages caps the number of packages indexed per prefix.
+	searchMaxPackages = 10_000
+	// searchMaxSourceSize caps the source bytes indexed per package.
+	searchMaxSourceSize = 256 << 10
+	// searchMaxTextSize caps the rendered text kept per realm for excerpts.
+	searchMaxTextSize = 8 << 10
+	// searchMaxResults caps the number of results of a query.
+	searchMaxResults = 200
+	// searchPerPage is the number of results per page.
+	searchPerPage = 20
+	// searchMaxQueryTerms caps the number of terms of a query.
+	searchMaxQueryTerms = 8
+	// searchExcerptLength is the length, in runes, of result excerpts.
+	searchExcerptLength = 200
+)
+
+// searchPrefixes are the indexed package prefixes.
+var searchPrefixes = []string{"/r/", "/p/"}
+
+// Weights of the term occurrences, by field.
+const (
+	searchWeightTitle  = 8
+	searchWeightPath   = 4
+	searchWeightRender = 2
+	searchWeightSource = 1
+)
+
+// searchDoc is an indexed package.
+type searchDoc struct {
+	path  string
+	title string
+	text  string // start of the rendered text, for excerpts
+}
+
+// searchPosting is the weighted number of occurrences of a term in a doc.
+type searchPosting struct {
+	doc   int
+	score float64
+}
+
+// searchIndexData is an immutable inverted index of packages.
+type searchIndexData struct {
+	docs  []searchDoc
+	terms map[string][]searchPosting
+}
+
+// SearchEngine is a full-text search engine of the packages known by the
+// node, indexing the render of realms and the source of all packages. The
+// in-memory index is built in the background, and rebuilt once older than
+// its ttl.
+type SearchEngine struct {
+	logger *slog.Logger
+	client ClientAdapter
+	domain string
+	ttl    time.Duration
+
+	mu       sync.RWMutex
+	index    *searchIndexData
+	builtAt  time.Time
+	building bool
+}
+
+// NewSearchEngine returns a search engine of the packages known by the
+// client, rebuilding its index once older than ttl.
+func NewSearchEngine(logger *slog.Logger, cli ClientAdapter, domain string, ttl time.Duration) *SearchEngine {
+	if ttl <= 0 {
+		ttl = DefaultSearchTTL
+	}
+	return &SearchEngine{logger: logger, client: cli, domain: domain, ttl: ttl}
+}
+
+// Search returns up to searchMaxResults results of the query, by decreasing
+// relevance. Results include all the query terms. If no index was built
+// yet, ok is false; a build is started in the background if needed.
+func (se *SearchEngine) Search(query string) (results []components.SearchResult, ok bool) {
+	se.mu.RLock()
+	index, builtAt, building := se.index, se.builtAt, se.building
+	se.mu.RUnlock()
+
+	if !building && (index == nil || time.Since(builtAt) >= se.ttl) {
+		se.refresh()
+	}
+	if index == nil {
+		return nil, false
+	}
+
+	terms := searchTerms(query)
+	if len(terms) > searchMaxQueryTerms {
+		terms = terms[:searchMaxQueryTerms]
+	}
+	return index.search(terms), true
+}
+
+// refresh rebuilds the index in the background, unless already building.
+func (se *SearchEngine) refresh() {
+	se.mu.Lock()
+	if se.building {
+		se.mu.Unlock()
+		return
+	}
+	se.building = true
+	se.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), searchBuildTimeout)
+		defer cancel()
+		se.rebuild(ctx)
+	}()
+}
+
+// rebuild builds the index, replacing the current one if successful.
+func (se *SearchEngine) rebuild(ctx context.Context) {
+	start := time.Now()
+	index, err := se.build(ctx)
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	se.building = false
+	if err != nil {
+		se.logger.Warn("unable to build search index", "error", err)
+		return
+	}
+
+	se.index, se.builtAt = index, time.Now()
+	se.logger.Info("search index built", "docs", len(index.docs), "terms", len(index.terms), "took", time.Since(start))
+}
+
+// build lists the packages, and indexes the render of realms along with the
+// source of all packages.
+func (se *SearchEngine) build(ctx context.Context) (*searchIndexData, error) {
+	index := &searchIndexData{terms: make(map[string][]searchPosting)}
+	for _, prefix := range searchPrefixes {
+		paths, err := se.client.ListPaths(ctx, se.domain+prefix, searchMaxPackages)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			if path == "" {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			index.add(se.crawl(ctx, path))
+		}
+	}
+	return index, nil
+}
+
+// searchPackage is the crawled content of a package.
+type searchPackage struct {
+	doc    searchDoc
+	render string
+	source string
+}
+
+// crawl fetches the render and the source of the package. Failures are
+// ignored, the package is still indexed by path.
+func (se *SearchEngine) crawl(ctx context.Context, path string) searchPackage {
+	pkg := searchPackage{doc: searchDoc{path: path, title: gopath.Base(path)}}
+
+	if strings.HasPrefix(path, "/r/") {
+		if raw, err := se.client.Realm(ctx, path, ""); err == nil {
+			if title, _ := extractTitleExcerpt(raw); title != "" {
+				pkg.doc.title = title
+			}
+			pkg.render = string(md.PlainText(raw))
+			pkg.doc.text = truncateRunes(pkg.render, searchMaxTextSize)
+		}
+	}
+
+	files, err := se.client.ListFiles(ctx, path)
+	if err != nil {
+		return pkg
+	}
+
+	var source strings.Builder
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".gno") || strings.HasSuffix(file, "_test.gno") || strings.HasSuffix(file, "_filetest.gno") {
+			continue
+		}
+		if source.Len() >= searchMaxSourceSize {
+			break
+		}
+
+		content, _, err := se.client.File(ctx, path, file)
+		if err != nil {
+			continue
+		}
+		source.Write(content[:min(len(content), searchMaxSourceSize-source.Len())])
+		source.WriteByte('\n')
+	}
+	pkg.source = source.String()
+	return pkg
+}
+
+// add indexes the package.
+func (idx *searchIndexData) add(pkg searchPackage) {
+	id := len(idx.docs)
+	idx.docs = append(idx.docs, pkg.doc)
+
+	scores := make(map[string]float64)
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{pkg.doc.title, searchWeightTitle},
+		{pkg.doc.path, searchWeightPath},
+		{pkg.render, searchWeightRender},
+		{pkg.source, searchWeightSource},
+	} {
+		for _, term := range searchTerms(field.text) {
+			scores[term] += field.weight
+		}
+	}
+
+	for term, score := range scores {
+		idx.terms[term] = append(idx.terms[term], searchPosting{doc: id, score: score})
+	}
+}
+
+// search returns the docs including all the terms, scored by tf-idf.
+func (idx *searchIndexData) search(terms []string) []components.SearchResult {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	var scores map[int]float64
+	for _, term := range terms {
+		postings := idx.terms[term]
+		idf := math.Log(1 + n/float64(max(len(postings), 1)))
+
+		next := make(map[int]float64, len(postings))
+		for _, p := range postings {
+			// Term frequencies are dampened so that long sources do not dominate
+			if prev, ok := scores[p.doc]; ok || scores == nil {
+				next[p.doc] = prev + (1+math.Log(p.score))*idf
+			}
+		}
+		if scores = next; len(scores) == 0 {
+			return nil
+		}
+	}
+
+	ids := slices.Collect(maps.Keys(scores))
+	slices.SortFunc(ids, func(a, b int) int {
+		if scores[a] != scores[b] {
+			if scores[a] > scores[b] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(idx.docs[a].path, idx.docs[b].path)
+	})
+	if len(ids) > searchMaxResults {
+		ids = ids[:searchMaxResults]
+	}
+
+	results := make([]components.SearchResult, len(ids))
+	for i, id := range ids {
+		doc := idx.docs[id]
+		results[i] = components.SearchResult{
+			Path:    doc.path,
+			Title:   sanitizeTitle(doc.title, doc.path, searchExcerptLength),
+			Excerpt: searchExcerpt(doc.text, terms),
+		}
+	}
+	return results
+}
+
+// searchTerms returns the lowercased words of the text, made of letters and
+// digits, of 2 to 40 runes.
+func searchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	for _, word := range words {
+		if n := utf8.RuneCountInString(word); n >= 2 && n <= 40 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// searchExcerpt returns the part of the text around the first occurrence of
+// one of the terms, or its start.
+func searchExcerpt(text string, terms []string) string {
+	start := 0
+	// Lowercasing may change byte offsets, only use matching ones
+	if lower := strings.ToLower(text); len(lower) == len(text) {
+		start = len(text)
+		for _, term := range terms {
+			if i := strings.Index(lower, term); i >= 0 {
+				start = min(start, i)
+			}
+		}
+		if start == len(text) {
+			start = 0
+		}
+	}
+
+	before := truncateRunesEnd(text[:start], searchExcerptLength/4)
+	excerpt := before + text[start:]
+	if truncated := truncateRunes(excerpt, searchExcerptLength); truncated != excerpt {
+		excerpt = truncated + "…"
+	}
+	if len(before) < start {
+		excerpt = "…" + excerpt
+	}
+	return strings.Join(strings.Fields(excerpt), " ")
+}
+
+// truncateRunes returns the first n runes of s.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// truncateRunesEnd returns the last n runes of s.
+func truncateRunesEnd(s string, n int) string {
+	for i := len(s); i > 0; {
+		if n == 0 {
+			return s[i:]
+		}
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+		n--
+	}
+	return s
+}
+
+// GetSearchView returns the view of the results of the `q` query parameter.
+func (h *HTTPHandler) GetSearchView(gnourl *weburl.GnoURL, indexData *components.IndexData) (int, *components.View) {
+	query := strings.TrimSpace(gnourl.Query.Get("q"))
+	indexData.Mode = components.ViewModeHome
+	indexData.HeaderData.Mode = indexData.Mode
+	indexData.HeadData.Title = "Search - " + h.Static.Domain
+	if query != "" {
+		indexData.HeadData.Title = sanitizeTitle(query, "", h.MaxTitleLength) + " - " + indexData.HeadData.Title
+	}
+
+	data := components.SearchData{Query: query}
+	if query == "" {
+		return http.StatusOK, components.SearchView(data)
+	}
+
+	results, ok := h.Search.Search(query)
+	data.Indexing = !ok
+	data.Total = len(results)
+
+	pages := max((len(results)+searchPerPage-1)/searchPerPage, 1)
+	page, err := strconv.Atoi(gnourl.Query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	page = min(page, pages)
+	data.Results = results[(page-1)*searchPerPage : min(page*searchPerPage, len(results))]
+
+	pageURL := func(n int) string {
+		return SearchPath + "?" + url.Values{"q": {query}, "page": {strconv.Itoa(n)}}.Encode()
+	}
+	data.Pagination = &components.Pagination{Page: page, Pages: pages}
+	if page > 1 {
+		data.Pagination.PrevURL = pageURL(page - 1)
+	}
+	if page < pages {
+		data.Pagination.NextURL = pageURL(page + 1)
+	}
+
+	return http.StatusOK, components.SearchView(data)
+}
@@ -0,0 +1,148 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceClient is a catalogClient serving package sources.
+type sourceClient struct {
+	catalogClient
+	files map[string]map[string]string
+}
+
+func (c *sourceClient) ListFiles(ctx context.Context, path string) ([]string, error) {
+	var names []string
+	for name := range c.files[path] {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (c *sourceClient) File(ctx context.Context, path, filename string) ([]byte, FileMeta, error) {
+	if content, ok := c.files[path][filename]; ok {
+		return []byte(content), FileMeta{}, nil
+	}
+	return nil, FileMeta{}, ErrClientFileNotFound
+}
+
+func newTestSearchEngine() *SearchEngine {
+	cli := &sourceClient{
+		catalogClient: catalogClient{realms: map[string]string{
+			"/r/demo/boards":  "# Boards\n\nA simple **forum**, the Gno forum.\n",
+			"/r/demo/blog":    "# Gno Blog\n\nPosts about the forum of the boards realm.\n",
+			"/r/gnoland/home": "# Welcome\n\nWelcome to gno.land\n",
+		}},
+		files: map[string]map[string]string{
+			"/p/demo/avl": {
+				"tree.gno":      "package avl\n\n// Tree is a self-balancing binary tree.\ntype Tree struct{}\n",
+				"tree_test.gno": "package avl\n\n// forum\n",
+			},
+			"/r/demo/blog": {"blog.gno": "package blog\n\nfunc Render(path string) string { return \"\" }\n"},
+		},
+	}
+	return NewSearchEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, "gno.land", time.Hour)
+}
+
+func TestSearchEngine(t *testing.T) {
+	t.Parallel()
+
+	se := newTestSearchEngine()
+	se.rebuild(context.Background())
+
+	paths := func(query string) []string {
+		results, ok := se.Search(query)
+		require.True(t, ok)
+
+		var paths []string
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+		return paths
+	}
+
+	// Title matches rank first
+	assert.Equal(t, []string{"/r/demo/boards", "/r/demo/blog"}, paths("boards"))
+	// Then the most frequent ones
+	assert.Equal(t, []string{"/r/demo/boards", "/r/demo/blog"}, paths("FORUM"))
+
+	// All the terms must match
+	assert.Equal(t, []string{"/r/demo/blog"}, paths("forum posts"))
+	assert.Empty(t, paths("forum welcome"))
+
+	// Package sources are indexed, but not their tests
+	assert.Equal(t, []string{"/p/demo/avl"}, paths("self-balancing"))
+	assert.Equal(t, []string{"/r/demo/blog"}, paths("render"))
+	assert.Empty(t, paths(""))
+
+	results, _ := se.Search("forum")
+	assert.Equal(t, "Boards", results[0].Title)
+	assert.Equal(t, "Boards A simple forum, the Gno forum.", results[0].Excerpt)
+}
+
+func TestSearchEngine_Background(t *testing.T) {
+	t.Parallel()
+
+	se := newTestSearchEngine()
+	results, ok := se.Search("boards")
+	assert.False(t, ok)
+	assert.Empty(t, results)
+
+	require.Eventually(t, func() bool {
+		_, ok := se.Search("boards")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSearchExcerpt(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat("lorem ipsum ", 20) + "the Gno forum " + strings.Repeat("dolor sit ", 30)
+	excerpt := searchExcerpt(text, []string{"forum"})
+	assert.True(t, strings.HasPrefix(excerpt, "…"))
+	assert.True(t, strings.HasSuffix(excerpt, "…"))
+	assert.Contains(t, excerpt, "the Gno forum dolor")
+
+	assert.Equal(t, "short text", searchExcerpt("short\ntext", []string{"missing"}))
+}
+
+func TestHTTPHandler_Search(t *testing.T) {
+	t.Parallel()
+
+	se := newTestSearchEngine()
+	se.rebuild(context.Background())
+
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: se.client,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land"},
+		Search:        se,
+	})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/search?q=forum", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "<title>forum - Search - gno.land</title>")
+	assert.Contains(t, body, `value="forum"`)
+	assert.Contains(t, body, "2 results")
+	assert.Contains(t, body, `<a href="/r/demo/boards">`)
+	assert.Contains(t, body, `<span class="b-search_title">Gno Blog</span>`)
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/search?q=nothing", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "No package matches “nothing”.")
+}
@@ -0,0 +1,166 @@
+package gnoweb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	gopath "path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchIndexPath is the path of the static search index.
+const SearchIndexPath = "/search-index.json"
+
+const (
+	// DefaultSearchIndexTTL is the default duration after which the search
+	// index is rebuilt.
+	DefaultSearchIndexTTL = 10 * time.Minute
+
+	// searchIndexMaxRealms caps the number of realms rendered per build.
+	searchIndexMaxRealms = 10_000
+
+	// searchIndexExcerptLength is the maximum length, in runes, of excerpts.
+	searchIndexExcerptLength = 160
+)
+
+// SearchIndexEntry is a realm entry of the search index.
+type SearchIndexEntry struct {
+	Path    string `json:"path"`
+	Title   string `json:"title"`
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// searchIndex builds and caches the JSON search index of the realms known
+// by the node, rebuilding it once older than its ttl.
+type searchIndex struct {
+	logger *slog.Logger
+	client ClientAdapter
+	domain string
+	ttl    time.Duration
+	warn   []string // content warning prefixes, whose realms are not rendered
+
+	mu      sync.Mutex
+	data    []byte
+	builtAt time.Time
+}
+
+// handlerSearchIndex serves the search index of the realms known by the
+// client, suitable for in-browser search. Realms behind a content warning
+// are only indexed by path.
+func handlerSearchIndex(logger *slog.Logger, cli ClientAdapter, domain string, ttl time.Duration, warnPaths []string) http.Handler {
+	if ttl <= 0 {
+		ttl = DefaultSearchIndexTTL
+	}
+
+	idx := &searchIndex{logger: logger, client: cli, domain: domain, ttl: ttl, warn: warnPaths}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, builtAt, err := idx.get(r.Context())
+		if err != nil {
+			logger.Error("unable to build search index", "error", err)
+			http.Error(w, "search index unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		serveGenerated(w, r, "application/json", data, builtAt, ttl)
+	})
+}
+
+// get returns the cached index and its build time, rebuilding it if
+// expired. If the rebuild fails, the previous index is returned if any.
+func (idx *searchIndex) get(ctx context.Context) ([]byte, time.Time, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.data != nil && time.Since(idx.builtAt) < idx.ttl {
+		return idx.data, idx.builtAt, nil
+	}
+
+	entries, err := idx.build(ctx)
+	if err != nil {
+		if idx.data != nil {
+			idx.logger.Warn("unable to rebuild search index, serving previous one", "error", err)
+			return idx.data, idx.builtAt, nil
+		}
+		return nil, time.Time{}, err
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	idx.data, idx.builtAt = data, time.Now()
+	return data, idx.builtAt, nil
+}
+
+// build lists the realms and renders them to extract their title and excerpt.
+func (idx *searchIndex) build(ctx context.Context) ([]SearchIndexEntry, error) {
+	paths, err := idx.client.ListPaths(ctx, idx.domain+"/r/", searchIndexMaxRealms)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]SearchIndexEntry, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		entry := SearchIndexEntry{Path: path, Title: gopath.Base(path)}
+		if _, warned := matchWarnPath(idx.warn, path); warned {
+			entries = append(entries, entry)
+			continue
+		}
+		if raw, err := idx.client.Realm(ctx, path, ""); err == nil {
+			title, excerpt := extractTitleExcerpt(raw)
+			if title != "" {
+				entry.Title = title
+			}
+			entry.Excerpt = excerpt
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// extractTitleExcerpt returns the first heading and the first paragraph line
+// of the given markdown, out of code blocks.
+func extractTitleExcerpt(src []byte) (title, excerpt string) {
+	var fence string
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() && (title == "" || excerpt == "") {
+		line := strings.TrimSpace(scanner.Text())
+
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "```"), strings.HasPrefix(line, "~~~"):
+			fence = line[:3]
+		case strings.HasPrefix(line, "#"):
+			if title == "" {
+				title = sanitizeTitle(strings.TrimLeft(line, "# "), "", searchIndexExcerptLength)
+			}
+		case strings.HasPrefix(line, "<"), strings.HasPrefix(line, "|"), strings.HasPrefix(line, "!["):
+			// skip markup, tables and images
+		default:
+			if excerpt == "" {
+				excerpt = sanitizeTitle(line, "", searchIndexExcerptLength)
+			}
+		}
+	}
+
+	return title, excerpt
+}
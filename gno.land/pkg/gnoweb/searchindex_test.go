@@ -0,0 +1,94 @@
+package gnoweb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogClient is a fake client serving a fixed set of realms.
+type catalogClient struct {
+	realms map[string]string
+	lists  int
+}
+
+func (c *catalogClient) Realm(ctx context.Context, path, args string) ([]byte, error) {
+	if content, ok := c.realms[path]; ok {
+		return []byte(content), nil
+	}
+	return nil, ErrClientRenderNotDeclared
+}
+
+func (c *catalogClient) File(ctx context.Context, path, filename string) ([]byte, FileMeta, error) {
+	return nil, FileMeta{}, ErrClientFileNotFound
+}
+
+func (c *catalogClient) ListFiles(ctx context.Context, path string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *catalogClient) ListPaths(ctx context.Context, prefix string, limit int) ([]string, error) {
+	c.lists++
+	switch prefix {
+	case "gno.land/r/":
+		return []string{"/r/demo/boards", "/r/demo/blog", "/r/gnoland/home", "/r/demo/norender"}, nil
+	case "gno.land/p/":
+		return []string{"/p/demo/avl"}, nil
+	}
+	return nil, nil
+}
+
+func (c *catalogClient) Doc(ctx context.Context, path string) (*doc.JSONDocumentation, error) {
+	return nil, ErrClientPackageNotFound
+}
+
+func TestHandlerSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{realms: map[string]string{
+		"/r/demo/boards":  "# Boards\n\nA simple <b>forum</b> realm.\n",
+		"/r/demo/blog":    "```\n# not a title\n```\n\n## Gno Blog\n\n" + strings.Repeat("word ", 100),
+		"/r/gnoland/home": "![banner](/img.png)\n\nWelcome to gno.land\n",
+	}}
+
+	handler := handlerSearchIndex(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, "gno.land", time.Hour, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, SearchIndexPath, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	var entries []SearchIndexEntry
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &entries))
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, SearchIndexEntry{Path: "/r/demo/boards", Title: "Boards", Excerpt: "A simple forum realm."}, entries[0])
+	assert.Equal(t, "/r/demo/blog", entries[1].Path)
+	assert.Equal(t, "Gno Blog", entries[1].Title)
+	assert.Equal(t, searchIndexExcerptLength, len([]rune(entries[1].Excerpt)))
+	assert.Equal(t, SearchIndexEntry{Path: "/r/gnoland/home", Title: "home", Excerpt: "Welcome to gno.land"}, entries[2])
+	assert.Equal(t, SearchIndexEntry{Path: "/r/demo/norender", Title: "norender"}, entries[3])
+
+	// The index is cached until its ttl expires
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, SearchIndexPath, nil))
+	assert.Equal(t, 1, cli.lists)
+
+	// Realms behind a content warning are only indexed by path
+	handler = handlerSearchIndex(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, "gno.land", time.Hour, []string{"/r/demo/boards"})
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, SearchIndexPath, nil))
+	entries = nil
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &entries))
+	assert.Equal(t, SearchIndexEntry{Path: "/r/demo/boards", Title: "boards"}, entries[0])
+}
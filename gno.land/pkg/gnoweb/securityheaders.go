@@ -0,0 +1,221 @@
+package gnoweb
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCSPImageHosts are the external image hosts allowed by the default
+// Content-Security-Policy.
+var DefaultCSPImageHosts = []string{
+	// Gno-related hosts
+	"https://gnolang.github.io",
+	"https://assets.gnoteam.com",
+	"https://sa.gno.services",
+
+	// Other providers should respect DMCA guidelines.
+	// NOTE: Feel free to open a PR to add more providers here :)
+
+	// imgur
+	"https://imgur.com",
+	"https://*.imgur.com",
+
+	// GitHub
+	"https://*.github.io",
+	"https://github.com",
+	"https://*.githubusercontent.com",
+
+	// IPFS
+	"https://ipfs.io",
+	"https://cloudflare-ipfs.com",
+}
+
+// SecurityHeaders configures the security headers set on every response by
+// SecurityHeadersMiddleware. Empty headers are not set.
+type SecurityHeaders struct {
+	// CSP, if enabled, sets a Content-Security-Policy restricting resources
+	// to the gateway, the analytics script, ImageHosts, and the CDN hosts
+	// of the enabled markdown extensions.
+	CSP bool
+	// CSPSources are additional "<directive> <source>" entries of the
+	// policy of all pages, such as "connect-src https://api.example.com".
+	CSPSources []string
+	// ImageHosts are the external hosts images may be loaded from.
+	ImageHosts []string
+	// FrameOptions is the X-Frame-Options header, such as "DENY".
+	FrameOptions string
+	// ReferrerPolicy is the Referrer-Policy header, such as "no-referrer".
+	ReferrerPolicy string
+	// HSTSMaxAge, if positive, sets Strict-Transport-Security so that
+	// browsers only access the gateway over https for that duration.
+	HSTSMaxAge time.Duration
+	// HSTSIncludeSubdomains extends HSTS to the subdomains of the gateway.
+	HSTSIncludeSubdomains bool
+}
+
+// DefaultSecurityHeaders returns the default security headers, which neither
+// restrict the sources of resources nor enforce https.
+func DefaultSecurityHeaders() SecurityHeaders {
+	return SecurityHeaders{
+		ImageHosts:     slices.Clone(DefaultCSPImageHosts),
+		FrameOptions:   "DENY",
+		ReferrerPolicy: "no-referrer",
+	}
+}
+
+// StrictSecurityHeaders returns the default security headers along with a
+// Content-Security-Policy and HSTS, suitable for public gateways served
+// over https.
+func StrictSecurityHeaders() SecurityHeaders {
+	s := DefaultSecurityHeaders()
+	s.CSP = true
+	s.HSTSMaxAge = 365 * 24 * time.Hour
+	return s
+}
+
+// policy returns the base Content-Security-Policy, allowing the given CDN
+// origins to serve scripts, styles and fonts.
+func (s SecurityHeaders) policy(cdnOrigins []string) string {
+	imgSrc := append([]string{"img-src 'self' data:"}, s.ImageHosts...)
+	scriptSrc := append([]string{"script-src 'self'", analyticsOrigin}, cdnOrigins...)
+	styleSrc := append([]string{"style-src 'self'"}, cdnOrigins...)
+	fontSrc := append([]string{"font-src 'self'"}, cdnOrigins...)
+
+	csp := mergeCSP("default-src 'self'", []string{
+		strings.Join(scriptSrc, " "),
+		strings.Join(styleSrc, " "),
+		strings.Join(imgSrc, " "),
+		strings.Join(fontSrc, " "),
+	})
+	return mergeCSP(csp, s.CSPSources)
+}
+
+// hsts returns the Strict-Transport-Security header, or an empty string if
+// disabled.
+func (s SecurityHeaders) hsts() string {
+	if s.HSTSMaxAge <= 0 {
+		return ""
+	}
+	hsts := "max-age=" + strconv.FormatInt(int64(s.HSTSMaxAge/time.Second), 10)
+	if s.HSTSIncludeSubdomains {
+		hsts += "; includeSubDomains"
+	}
+	return hsts
+}
+
+// SecurityHeadersMiddleware sets the configured security headers on every
+// response. The CSP of pages matching a `cspByPath` prefix, as a path or
+// one of its sub-paths, is extended with the given additional sources.
+func SecurityHeadersMiddleware(next http.Handler, cfg SecurityHeaders, cdnOrigins []string, cspByPath map[string][]string) http.Handler {
+	var (
+		csp          string
+		cspOverrides map[string]string
+	)
+	if cfg.CSP {
+		csp = cfg.policy(cdnOrigins)
+
+		// Precompute the CSP of each path override
+		cspOverrides = make(map[string]string, len(cspByPath))
+		for prefix, sources := range cspByPath {
+			cspOverrides[prefix] = mergeCSP(csp, sources)
+		}
+	}
+	hsts := cfg.hsts()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Prevent MIME type sniffing by browsers. This ensures that the browser
+		// does not interpret files as a different MIME type than declared.
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		// Prevent the page from being embedded in an iframe, which
+		// mitigates clickjacking attacks.
+		if cfg.FrameOptions != "" {
+			w.Header().Set("X-Frame-Options", cfg.FrameOptions)
+		}
+
+		// Control the amount of referrer information sent in the Referer header.
+		if cfg.ReferrerPolicy != "" {
+			w.Header().Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+
+		// Restrict the sources of scripts, styles, images, and other
+		// resources, which prevents cross-site scripting (XSS) and other code
+		// injection attacks. The override of the longest matching path
+		// prefix is used, if any.
+		if csp != "" {
+			policy, matched := csp, ""
+			for prefix, override := range cspOverrides {
+				if hasPathPrefix(r.URL.Path, prefix) && len(prefix) > len(matched) {
+					policy, matched = override, prefix
+				}
+			}
+			w.Header().Set("Content-Security-Policy", policy)
+		}
+
+		if hsts != "" {
+			w.Header().Set("Strict-Transport-Security", hsts)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hasPathPrefix reports whether path is prefix, or one of its sub-paths,
+// such as `/r/charts/map`, `/r/charts:home` or `/r/charts$source` for the
+// `/r/charts` prefix, but not `/r/chartsfoo`.
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	if len(path) == len(prefix) || strings.HasSuffix(prefix, "/") {
+		return true
+	}
+	switch path[len(prefix)] {
+	case '/', ':', '$':
+		return true
+	}
+	return false
+}
+
+// mergeCSP adds the given "<directive> <source>" entries to the base CSP
+// policy. Sources of an unknown directive create that directive.
+func mergeCSP(base string, sources []string) string {
+	var (
+		names      []string
+		directives = make(map[string][]string)
+	)
+
+	add := func(directive string) {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			return
+		}
+
+		name := fields[0]
+		if _, ok := directives[name]; !ok {
+			names = append(names, name)
+		}
+		for _, src := range fields[1:] {
+			if !slices.Contains(directives[name], src) {
+				directives[name] = append(directives[name], src)
+			}
+		}
+	}
+
+	for _, directive := range strings.Split(base, ";") {
+		add(directive)
+	}
+	for _, src := range sources {
+		add(src)
+	}
+
+	policy := make([]string, len(names))
+	for i, name := range names {
+		policy[i] = strings.Join(append([]string{name}, directives[name]...), " ")
+	}
+
+	return strings.Join(policy, "; ")
+}
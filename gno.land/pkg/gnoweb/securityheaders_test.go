@@ -0,0 +1,162 @@
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := SecurityHeadersMiddleware(http.HandlerFunc(okHandler), StrictSecurityHeaders(), nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", rr.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "max-age=31536000", rr.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "OK", rr.Body.String())
+
+	csp := rr.Header().Get("Content-Security-Policy")
+	assert.True(t, strings.HasPrefix(csp, "default-src 'self'; script-src 'self' https://sa.gno.services; style-src 'self'; img-src 'self' data: "), csp)
+	assert.Contains(t, csp, "https://assets.gnoteam.com")
+
+	// Neither CSP nor HSTS by default
+	handler = SecurityHeadersMiddleware(http.HandlerFunc(okHandler), DefaultSecurityHeaders(), nil, nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, rr.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeadersMiddlewareConfig(t *testing.T) {
+	t.Parallel()
+
+	get := func(cfg SecurityHeaders) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		rr := httptest.NewRecorder()
+		SecurityHeadersMiddleware(http.HandlerFunc(okHandler), cfg, []string{"https://cdn.example.com"}, nil).ServeHTTP(rr, req)
+		return rr.Header()
+	}
+
+	// Without CSP nor HSTS
+	headers := get(SecurityHeaders{FrameOptions: "SAMEORIGIN"})
+	assert.Equal(t, "nosniff", headers.Get("X-Content-Type-Options"))
+	assert.Equal(t, "SAMEORIGIN", headers.Get("X-Frame-Options"))
+	assert.Empty(t, headers.Get("Referrer-Policy"))
+	assert.Empty(t, headers.Get("Content-Security-Policy"))
+	assert.Empty(t, headers.Get("Strict-Transport-Security"))
+
+	// CDN origins and additional sources
+	headers = get(SecurityHeaders{
+		CSP:                   true,
+		CSPSources:            []string{"connect-src https://api.example.com"},
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubdomains: true,
+	})
+	assert.Equal(t, "default-src 'self'; "+
+		"script-src 'self' https://sa.gno.services https://cdn.example.com; "+
+		"style-src 'self' https://cdn.example.com; "+
+		"img-src 'self' data:; "+
+		"font-src 'self' https://cdn.example.com; "+
+		"connect-src https://api.example.com",
+		headers.Get("Content-Security-Policy"))
+	assert.Equal(t, "max-age=3600; includeSubDomains", headers.Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeadersMiddlewareCSPByPath(t *testing.T) {
+	t.Parallel()
+
+	handler := SecurityHeadersMiddleware(http.HandlerFunc(okHandler), StrictSecurityHeaders(), nil, map[string][]string{
+		"/r/charts":     {"script-src https://cdn.example.com"},
+		"/r/charts/map": {"script-src https://cdn.example.com", "connect-src https://tiles.example.com"},
+	})
+
+	get := func(path string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Header().Get("Content-Security-Policy")
+	}
+
+	base := get("/r/other")
+	assert.NotContains(t, base, "https://cdn.example.com")
+	assert.Equal(t, base, get("/"))
+	assert.Equal(t, base, get("/r/chartsfoo")) // not a sub-path
+
+	// Sources are merged into the existing directive
+	csp := get("/r/charts:home")
+	assert.Contains(t, csp, "script-src 'self' https://sa.gno.services https://cdn.example.com;")
+	assert.Equal(t, strings.Count(base, ";"), strings.Count(csp, ";"))
+	assert.Contains(t, csp, "https://assets.gnoteam.com")
+
+	assert.Equal(t, csp, get("/r/charts"))
+	assert.Equal(t, csp, get("/r/charts$source"))
+
+	// The longest prefix wins, and unknown directives are added
+	csp = get("/r/charts/map")
+	assert.Contains(t, csp, "script-src 'self' https://sa.gno.services https://cdn.example.com;")
+	assert.Contains(t, csp, "connect-src https://tiles.example.com")
+}
+
+func TestRouterSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	logger := log.NewTestingLogger(t)
+
+	cfg := NewDefaultAppConfig()
+	cfg.NodeRemote = "127.0.0.1:123456" // invalid port
+	cfg.ChainID = "test"
+	cfg.MermaidDiagrams = true
+	router, err := NewRouter(logger, cfg)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/liveness", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// Neither CSP nor HSTS by default
+	assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, rr.Header().Get("Strict-Transport-Security"))
+
+	// Strict
+	strict := StrictSecurityHeaders()
+	cfg.SecurityHeaders = &strict
+	router, err = NewRouter(logger, cfg)
+	require.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+	assert.Contains(t, rr.Header().Get("Content-Security-Policy"), cdnOrigin(cfg.MermaidRuntimeURL))
+	assert.Equal(t, "max-age=31536000", rr.Header().Get("Strict-Transport-Security"))
+
+	// Disabled
+	cfg = NewDefaultAppConfig()
+	cfg.NodeRemote = "127.0.0.1:123456"
+	cfg.ChainID = "test"
+	cfg.SecurityHeaders = nil
+	router, err = NewRouter(logger, cfg)
+	require.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Empty(t, rr.Header().Get("X-Frame-Options"))
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+}
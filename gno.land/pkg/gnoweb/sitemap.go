@@ -0,0 +1,135 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SitemapPath is the path of the sitemap.
+const SitemapPath = "/sitemap.xml"
+
+const (
+	// DefaultSitemapTTL is the default duration after which the sitemap is
+	// regenerated.
+	DefaultSitemapTTL = 10 * time.Minute
+
+	// sitemapMaxURLs caps the number of listed packages, which is the
+	// maximum number of URLs of a sitemap.
+	sitemapMaxURLs = 50_000
+)
+
+// sitemapPrefixes are the listed package prefixes, in listing order.
+var sitemapPrefixes = []string{"/r/", "/p/"}
+
+// sitemapURL is a path listed by the sitemap, along with the time it was
+// first listed, used as its `lastmod` hint. Package code being immutable
+// once added, this is the time of its last modification, up to the start
+// of gnoweb.
+type sitemapURL struct {
+	path      string
+	firstSeen time.Time
+}
+
+// sitemap caches the package paths listed by the sitemap, listing them again
+// once older than its ttl.
+type sitemap struct {
+	logger *slog.Logger
+	client ClientAdapter
+	domain string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	urls    []sitemapURL
+	seen    map[string]time.Time
+	builtAt time.Time
+}
+
+// handlerSitemap serves the sitemap of the home page, the realms and the pure
+// packages known by the client, with URLs relative to the request origin.
+func handlerSitemap(logger *slog.Logger, cli ClientAdapter, domain string, ttl time.Duration) http.Handler {
+	if ttl <= 0 {
+		ttl = DefaultSitemapTTL
+	}
+
+	sm := &sitemap{logger: logger, client: cli, domain: domain, ttl: ttl}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		urls, builtAt, err := sm.get(r.Context())
+		if err != nil {
+			logger.Error("unable to generate sitemap", "error", err)
+			http.Error(w, "sitemap unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		serveGenerated(w, r, "application/xml; charset=utf-8", renderSitemap(requestOrigin(r), urls), builtAt, ttl)
+	})
+}
+
+// get returns the cached URLs and their listing time, listing them again if
+// expired. If the listing fails, the previous URLs are returned if any.
+func (sm *sitemap) get(ctx context.Context) ([]sitemapURL, time.Time, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.urls != nil && time.Since(sm.builtAt) < sm.ttl {
+		return sm.urls, sm.builtAt, nil
+	}
+
+	// The home page has no meaningful modification time
+	urls := []sitemapURL{{path: "/"}}
+	for _, prefix := range sitemapPrefixes {
+		if len(urls) >= sitemapMaxURLs {
+			break
+		}
+
+		paths, err := sm.client.ListPaths(ctx, sm.domain+prefix, sitemapMaxURLs-len(urls))
+		if err != nil {
+			if sm.urls != nil {
+				sm.logger.Warn("unable to regenerate sitemap, serving previous one", "error", err)
+				return sm.urls, sm.builtAt, nil
+			}
+			return nil, time.Time{}, err
+		}
+
+		for _, path := range paths {
+			if path != "" && len(urls) < sitemapMaxURLs {
+				urls = append(urls, sitemapURL{path: path})
+			}
+		}
+	}
+
+	now := time.Now()
+	seen := make(map[string]time.Time, len(urls))
+	for i := range urls[1:] {
+		u := &urls[i+1]
+		if u.firstSeen = sm.seen[u.path]; u.firstSeen.IsZero() {
+			u.firstSeen = now
+		}
+		seen[u.path] = u.firstSeen
+	}
+
+	sm.urls, sm.seen, sm.builtAt = urls, seen, now
+	return sm.urls, sm.builtAt, nil
+}
+
+// renderSitemap renders the sitemap listing the given URLs under origin.
+func renderSitemap(origin string, urls []sitemapURL) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n")
+	for _, u := range urls {
+		buf.WriteString("  <url><loc>")
+		xml.EscapeText(&buf, []byte(origin+u.path))
+		buf.WriteString("</loc>")
+		if !u.firstSeen.IsZero() {
+			buf.WriteString("<lastmod>" + u.firstSeen.UTC().Format(time.DateOnly) + "</lastmod>")
+		}
+		buf.WriteString("</url>\n")
+	}
+	buf.WriteString("</urlset>\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,80 @@
+package gnoweb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerSitemap(t *testing.T) {
+	t.Parallel()
+
+	cli := &catalogClient{}
+	handler := handlerSitemap(slog.New(slog.NewTextHandler(io.Discard, nil)), cli, "gno.land", time.Hour)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "http://example.com"+SitemapPath, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Cache-Control"), "public, max-age=")
+	lastModified := rr.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	today := time.Now().UTC().Format(time.DateOnly)
+	assert.Contains(t, body, "<url><loc>http://example.com/</loc></url>")
+	assert.Contains(t, body, "<url><loc>http://example.com/r/demo/boards</loc><lastmod>"+today+"</lastmod></url>")
+	assert.Contains(t, body, "<loc>http://example.com/r/gnoland/home</loc>")
+	assert.Contains(t, body, "<url><loc>http://example.com/p/demo/avl</loc><lastmod>"+today+"</lastmod></url>")
+
+	// The sitemap is generated once within its ttl
+	for range 5 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, SitemapPath, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+	assert.Equal(t, 2, cli.lists)
+
+	// Crawlers revalidate with the last modification time
+	req := httptest.NewRequest(http.MethodGet, SitemapPath, nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, 2, cli.lists)
+}
+
+func TestSitemapFirstSeen(t *testing.T) {
+	t.Parallel()
+
+	sm := &sitemap{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		client: &catalogClient{},
+		domain: "gno.land",
+		ttl:    time.Nanosecond,
+	}
+
+	urls, _, err := sm.get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, urls, 6)
+	assert.Equal(t, sitemapURL{path: "/"}, urls[0])
+	assert.Equal(t, "/p/demo/avl", urls[5].path)
+
+	// Paths keep the time they were first listed
+	firstSeen := sm.seen["/r/demo/boards"]
+	require.False(t, firstSeen.IsZero())
+	time.Sleep(time.Millisecond)
+
+	_, _, err = sm.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, firstSeen, sm.seen["/r/demo/boards"])
+	assert.Equal(t, 4, sm.client.(*catalogClient).lists)
+}
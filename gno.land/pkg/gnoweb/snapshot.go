@@ -0,0 +1,88 @@
+package gnoweb
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// snapshotHeightTTL is how long the latest block height is cached for
+// snapshot lookups.
+const snapshotHeightTTL = time.Second
+
+// snapshotMiddleware serves pre-rendered realm pages from dir, keyed by the
+// block height and the realm path, such as
+// `<dir>/<height>/r/demo/boards:board.html`. The height is the one requested
+// with the `?height=<N>` query if historical is set, or else the latest one,
+// cached for snapshotHeightTTL. Requests without a matching snapshot, or for
+// which warned reports a content warning, fall back to `next`.
+func snapshotMiddleware(logger *slog.Logger, next http.Handler, dir string, height heightFunc, historical bool, warned func(*http.Request, *weburl.GnoURL) bool) http.Handler {
+	latest := cachedHeight(height, snapshotHeightTTL)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gnourl, err := weburl.ParseFromURL(r.URL)
+		if err != nil || !gnourl.IsRealm() || len(gnourl.WebQuery) > 0 || warned(r, gnourl) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Only pages pinned to a height may have a query
+		var h int64
+		if historical {
+			if h, gnourl, err = parseHeightQuery(gnourl); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if len(gnourl.Query) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if h == 0 {
+			if h, err = latest(r.Context()); err != nil {
+				logger.Debug("unable to fetch height for snapshot lookup", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		name := strconv.FormatInt(h, 10) + gnourl.Path
+		if gnourl.Args != "" {
+			name += ":" + gnourl.Args
+		}
+
+		filename, err := osm.SafeJoin(dir, name+".html")
+		if err != nil {
+			logger.Warn("rejected snapshot path", "path", name, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content, err := osm.ReadFile(filename)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				logger.Warn("unable to read snapshot", "file", filename, "error", err)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("X-Gnoweb-Snapshot", strconv.FormatInt(h, 10))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(content)
+		}
+	})
+}
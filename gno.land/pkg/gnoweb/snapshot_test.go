@@ -0,0 +1,120 @@
+package gnoweb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotMiddleware(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"42/r/test/realm.html":     "snapshot",
+		"42/r/test/realm:foo.html": "snapshot foo",
+		"41/r/test/other.html":     "old snapshot",
+		"42/r/flagged/realm.html":  "flagged snapshot",
+	} {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+	}
+
+	live := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("live"))
+	})
+	height := func(ctx context.Context) (int64, error) { return 42, nil }
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	warned := func(r *http.Request, gnourl *weburl.GnoURL) bool {
+		return strings.HasPrefix(gnourl.Path, "/r/flagged")
+	}
+	handler := snapshotMiddleware(logger, live, dir, height, true, warned)
+
+	for _, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{"/r/test/realm", "snapshot"},
+		{"/r/test/realm:foo", "snapshot foo"},
+		{"/r/test/realm:bar", "live"},
+		{"/r/test/realm?foo=bar", "live"},
+		{"/r/test/other?height=41", "old snapshot"},
+		{"/r/test/realm?height=42", "snapshot"},
+		{"/r/test/realm?height=41", "live"},
+		{"/r/test/realm?height=42&foo=bar", "live"},
+		{"/r/test/realm?height=latest", "live"},
+		{"/r/test/realm$source", "live"},
+		{"/r/test/other", "live"}, // snapshot at another height
+		{"/r/test/missing", "live"},
+		{"/p/test/realm", "live"},
+		{"/r/flagged/realm", "live"},                           // behind a content warning
+		{"/r/test/realm:../../../../../../etc/passwd", "live"}, // traversal
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tc.expected, rr.Body.String())
+			if tc.expected == "old snapshot" {
+				assert.Equal(t, "41", rr.Header().Get("X-Gnoweb-Snapshot"))
+			} else if tc.expected != "live" {
+				assert.Equal(t, "42", rr.Header().Get("X-Gnoweb-Snapshot"))
+				assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+
+	t.Run("latest height cached", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		height := func(ctx context.Context) (int64, error) {
+			calls.Add(1)
+			return 42, nil
+		}
+		handler := snapshotMiddleware(logger, live, dir, height, true, warned)
+
+		for range 3 {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/realm", nil))
+			assert.Equal(t, "snapshot", rr.Body.String())
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("historical render disabled", func(t *testing.T) {
+		t.Parallel()
+
+		handler := snapshotMiddleware(logger, live, dir, height, false, warned)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/other?height=41", nil))
+		assert.Equal(t, "live", rr.Body.String())
+	})
+
+	t.Run("height unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		failing := func(ctx context.Context) (int64, error) { return 0, errors.New("node down") }
+		handler := snapshotMiddleware(logger, live, dir, failing, true, warned)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/test/realm", nil))
+		assert.Equal(t, "live", rr.Body.String())
+	})
+}
@@ -0,0 +1,61 @@
+package gnoweb
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sourceFetchTimeout bounds the duration of a single source fetch.
+	sourceFetchTimeout = 2 * time.Second
+	// maxFetchedSources bounds the number of cached source files.
+	maxFetchedSources = 256
+)
+
+// sourceFetcher fetches the source files embedded in realm content. As
+// package sources cannot change once deployed, they are cached until the
+// cache is full.
+type sourceFetcher struct {
+	client ClientAdapter
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+func newSourceFetcher(client ClientAdapter) *sourceFetcher {
+	return &sourceFetcher{
+		client: client,
+		cache:  make(map[string][]byte),
+	}
+}
+
+// Fetch returns the given file of the package at path.
+func (f *sourceFetcher) Fetch(path, file string) ([]byte, error) {
+	key := "/" + strings.Trim(path, "/") + "/" + file
+
+	f.mu.Lock()
+	src, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok {
+		return src, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sourceFetchTimeout)
+	defer cancel()
+
+	src, _, err := f.client.File(ctx, path, file)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	if len(f.cache) >= maxFetchedSources {
+		clear(f.cache)
+	}
+	f.cache[key] = src
+	f.mu.Unlock()
+
+	return src, nil
+}
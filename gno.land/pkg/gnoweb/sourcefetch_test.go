@@ -0,0 +1,43 @@
+package gnoweb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filesClient is a client only knowing the given files.
+type filesClient struct {
+	catalogClient
+	files map[string]string
+	calls int
+}
+
+func (c *filesClient) File(ctx context.Context, path, filename string) ([]byte, FileMeta, error) {
+	c.calls++
+	if content, ok := c.files[path+"/"+filename]; ok {
+		return []byte(content), FileMeta{}, nil
+	}
+	return nil, FileMeta{}, ErrClientFileNotFound
+}
+
+func TestSourceFetcher(t *testing.T) {
+	t.Parallel()
+
+	client := &filesClient{files: map[string]string{"/p/demo/avl/avl.gno": "package avl\n"}}
+	fetcher := newSourceFetcher(client)
+
+	src, err := fetcher.Fetch("/p/demo/avl", "avl.gno")
+	require.NoError(t, err)
+	assert.Equal(t, "package avl\n", string(src))
+
+	_, err = fetcher.Fetch("/p/demo/avl", "node.gno")
+	assert.ErrorIs(t, err, ErrClientFileNotFound)
+
+	// Found files are cached, missing ones are not
+	fetcher.Fetch("/p/demo/avl", "avl.gno")
+	fetcher.Fetch("/p/demo/avl", "node.gno")
+	assert.Equal(t, 3, client.calls)
+}
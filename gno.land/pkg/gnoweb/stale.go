@@ -0,0 +1,53 @@
+package gnoweb
+
+import (
+	"errors"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// maxStaleRenders bounds the number of realm renders kept for outages.
+	maxStaleRenders = 1024
+	// maxStaleRenderSize bounds the size of the renders kept for outages.
+	maxStaleRenderSize = 1 << 20
+)
+
+// isOutageError reports whether the given client error means the node
+// cannot be reached.
+func isOutageError(err error) bool {
+	return IsRetryableClientError(err) || errors.Is(err, ErrClientUnavailable)
+}
+
+// staleRenders keeps the last successful render output of realm paths, to
+// be served while the node is unreachable. The least recently used renders
+// are evicted first.
+type staleRenders struct {
+	renders *lru.Cache[string, []byte]
+}
+
+func newStaleRenders() *staleRenders {
+	renders, err := lru.New[string, []byte](maxStaleRenders)
+	if err != nil {
+		panic(err) // only fails with a non-positive size
+	}
+	return &staleRenders{renders: renders}
+}
+
+// Set records the render output of the realm at path with the given args.
+// Renders larger than maxStaleRenderSize are not kept, dropping the
+// previous render of the realm.
+func (s *staleRenders) Set(path, args string, raw []byte) {
+	key := path + ":" + args
+	if len(raw) > maxStaleRenderSize {
+		s.renders.Remove(key)
+		return
+	}
+	s.renders.Add(key, raw)
+}
+
+// Get returns the last render output of the realm at path with the given
+// args, if any.
+func (s *staleRenders) Get(path, args string) ([]byte, bool) {
+	return s.renders.Get(path + ":" + args)
+}
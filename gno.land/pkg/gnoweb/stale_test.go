@@ -0,0 +1,32 @@
+package gnoweb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleRenders(t *testing.T) {
+	t.Parallel()
+
+	s := newStaleRenders()
+	for i := range maxStaleRenders {
+		s.Set(fmt.Sprintf("/r/test/%d", i), "", []byte("render"))
+	}
+
+	// The least recently used render is evicted, not a hot one
+	_, ok := s.Get("/r/test/0", "")
+	require.True(t, ok)
+	s.Set("/r/test/new", "", []byte("render"))
+	_, ok = s.Get("/r/test/0", "")
+	assert.True(t, ok)
+	_, ok = s.Get("/r/test/1", "")
+	assert.False(t, ok)
+
+	// Oversized renders are not kept, nor their previous render
+	s.Set("/r/test/0", "", make([]byte, maxStaleRenderSize+1))
+	_, ok = s.Get("/r/test/0", "")
+	assert.False(t, ok)
+}
@@ -7,30 +7,42 @@ import (
 	"crypto/sha256"
 	"embed"
 	"encoding/hex"
-	"io"
 	"io/fs"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 //go:embed public/*
 var assets embed.FS
 
-// AssetHandler returns an http.Handler to serve static assets from the embedded filesystem.
-// Assets are always served from the embedded /public directory.
-func AssetHandler() http.Handler {
+// assetFS returns the embedded /public directory.
+func assetFS() fs.FS {
 	sub, err := fs.Sub(assets, "public")
 	if err != nil {
 		panic(err) // shouldn't fail if "public" exists
 	}
 
-	return http.FileServer(http.FS(sub))
+	return sub
+}
+
+// AssetHandler returns an http.Handler to serve static assets from the embedded filesystem.
+// Assets are always served from the embedded /public directory.
+//
+// Files are served with `http.ServeContent`, which supports `Range` and
+// `If-Range` requests, validated against the ETag set by `CacheHandler`
+// since embedded files have no modification time.
+func AssetHandler() http.Handler {
+	return http.FileServer(http.FS(assetFS()))
 }
 
 // assetsHash stores a global ETag representing the content of all embedded files for cache validation.
 var assetsHash string
 
+// assetNames maps the embedded assets to their content-hashed names.
+var assetNames = map[string]string{}
+
 var DefaultCacheAssetsHandler = func(next http.Handler) http.Handler {
 	return CacheHandler(assetsHash, next)
 }
@@ -49,12 +61,15 @@ func init() {
 
 	h := sha256.New()
 	for _, p := range paths {
-		f, err := assets.Open(p)
+		data, err := assets.ReadFile(p)
 		if err != nil {
 			panic(err)
 		}
-		defer f.Close()
-		io.Copy(h, f)
+		h.Write(data)
+
+		sum := sha256.Sum256(data)
+		name := strings.TrimPrefix(p, "public/")
+		assetNames[name] = hashedAssetName(name, sum[:])
 	}
 
 	// ETag is quoted per RFC 7232
@@ -4,6 +4,7 @@
 package gnoweb
 
 import (
+	"io/fs"
 	"net/http"
 	"os"
 )
@@ -22,11 +23,19 @@ func getAssetDir() string {
 	return "./public"
 }
 
+// assetFS returns the assets directory.
+func assetFS() fs.FS {
+	return os.DirFS(getAssetDir())
+}
+
 // AssetHandler returns an http.Handler to serve static files from the given assetsPath.
 func AssetHandler() http.Handler {
 	adir := getAssetDir()
 	return http.FileServer(http.Dir(adir))
 }
 
+// assetNames is empty in noembed mode, as assets may change at any time.
+var assetNames map[string]string
+
 // DefaultCacheAssetsHandler in noembed mode always disables cache.
 var DefaultCacheAssetsHandler = NoCacheHandler
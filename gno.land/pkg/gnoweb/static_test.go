@@ -0,0 +1,37 @@
+//go:build !noembed
+
+package gnoweb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashedAssetsHandler(t *testing.T) {
+	t.Parallel()
+
+	hashed, ok := assetNames["main.css"]
+	require.True(t, ok)
+	assert.Regexp(t, `^main\.[0-9a-f]{10}\.css$`, hashed)
+
+	h := http.StripPrefix("/public/", hashedAssetsHandler(AssetHandler(), assetNames, DefaultCacheAssetsHandler(AssetHandler())))
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := get("/public/" + hashed)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=31536000, immutable", rr.Header().Get("Cache-Control"))
+	assert.Contains(t, rr.Header().Get("Content-Type"), "text/css")
+	plain := get("/public/main.css")
+	assert.Equal(t, plain.Body.String(), rr.Body.String())
+
+	// Outdated hashes are not found
+	assert.Equal(t, http.StatusNotFound, get("/public/main.0000000000.css").Code)
+}
@@ -0,0 +1,61 @@
+package gnoweb
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/components"
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+// ServeRealmText serves the content of the realm rendered by the text
+// renderer within the text layout, which references no styles nor scripts.
+// The `view` query parameter is not forwarded to the realm.
+func (h *HTTPHandler) ServeRealmText(ctx context.Context, gnourl *weburl.GnoURL, w http.ResponseWriter) {
+	u := *gnourl
+	u.Query = maps.Clone(gnourl.Query)
+	u.Query.Del("view")
+	gnourl = &u
+
+	raw, err := h.Client.Realm(ctx, gnourl.Path, gnourl.EncodeArgs())
+	if err != nil {
+		h.Logger.Debug("unable to fetch realm text", "error", err, "path", gnourl.EncodeURL())
+		status, _ := GetClientErrorStatusPage(gnourl, err)
+		h.renderText(w, gnourl, status, textMessage(http.StatusText(status)))
+		return
+	}
+
+	var content bytes.Buffer
+	if _, err := h.TextRenderer.RenderRealm(&content, gnourl, raw); err != nil {
+		h.Logger.Error("unable to render realm text", "error", err, "path", gnourl.EncodeURL())
+		h.renderText(w, gnourl, http.StatusInternalServerError, textMessage("internal error"))
+		return
+	}
+
+	// NOTE: `RenderRealm` should ensure that HTML content is sanitized
+	h.renderText(w, gnourl, http.StatusOK, components.NewReaderComponent(&content))
+}
+
+// textMessage returns a component displaying the given message.
+func textMessage(msg string) components.Component {
+	return components.NewReaderComponent(strings.NewReader("<p>" + html.EscapeString(msg) + "</p>\n"))
+}
+
+// renderText writes the given content within the text layout.
+func (h *HTTPHandler) renderText(w http.ResponseWriter, gnourl *weburl.GnoURL, status int, content components.Component) {
+	// Nothing but the document itself is ever loaded
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	err := components.TextLayout(components.TextData{
+		Title:   h.Static.Domain + " - " + sanitizeTitle(gnourl.Path, "/", h.MaxTitleLength),
+		Content: content,
+	}).Render(w)
+	if err != nil {
+		h.Logger.Error("failed to render text layout", "error", err)
+	}
+}
@@ -0,0 +1,151 @@
+package gnoweb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThemeFile is the name of the generated theme stylesheet, served under the
+// assets path.
+const ThemeFile = "theme.css"
+
+// Theme customizes the look of gnoweb pages without forking the embedded
+// assets.
+type Theme struct {
+	// Vars overrides CSS custom properties of the base stylesheet, such as
+	// `--s-color-bg-base`, by name.
+	Vars map[string]string
+	// CSS is appended to the theme stylesheet, after Vars.
+	CSS string
+	// Templates, if set, holds `*.html` templates overriding the component
+	// templates they define, such as `layouts/footer`.
+	Templates fs.FS
+}
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{}
+)
+
+// RegisterTheme makes a theme available by name, to be selected with the
+// Theme option of AppConfig. It is meant to be called from init functions,
+// and panics if the name is empty or already registered.
+func RegisterTheme(name string, theme Theme) {
+	themesMu.Lock()
+	defer themesMu.Unlock()
+
+	if name == "" {
+		panic("gnoweb: invalid theme registration")
+	}
+	if _, dup := themes[name]; dup {
+		panic("gnoweb: theme registered twice: " + name)
+	}
+	themes[name] = theme
+}
+
+// Themes returns the sorted names of the registered themes.
+func Themes() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(themes))
+}
+
+// LoadThemeDir loads the theme of the given directory: its `theme.css`
+// stylesheet, and its `templates` directory of template overrides. Both
+// are optional.
+func LoadThemeDir(dir string) (Theme, error) {
+	var theme Theme
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return theme, fmt.Errorf("unable to load theme: %w", err)
+	}
+	if !info.IsDir() {
+		return theme, fmt.Errorf("unable to load theme: %q is not a directory", dir)
+	}
+
+	css, err := os.ReadFile(filepath.Join(dir, ThemeFile))
+	switch {
+	case err == nil:
+		theme.CSS = string(css)
+	case !errors.Is(err, fs.ErrNotExist):
+		return theme, fmt.Errorf("unable to load theme stylesheet: %w", err)
+	}
+
+	templates := filepath.Join(dir, "templates")
+	if info, err := os.Stat(templates); err == nil && info.IsDir() {
+		theme.Templates = os.DirFS(templates)
+	}
+
+	return theme, nil
+}
+
+// lookupTheme returns the registered theme of the given name, or else the
+// theme of the directory at this path.
+func lookupTheme(nameOrDir string) (Theme, error) {
+	themesMu.RLock()
+	theme, ok := themes[nameOrDir]
+	themesMu.RUnlock()
+	if ok {
+		return theme, nil
+	}
+
+	return LoadThemeDir(nameOrDir)
+}
+
+var (
+	themeVarRe   = regexp.MustCompile(`^(--)?[a-zA-Z0-9_-]+$`)
+	themeValueRe = regexp.MustCompile(`[;{}<>\\]`)
+)
+
+// Stylesheet returns the stylesheet of the theme: its variables, declared
+// on `:root`, followed by its CSS.
+func (t Theme) Stylesheet() ([]byte, error) {
+	var css bytes.Buffer
+	if len(t.Vars) > 0 {
+		vars := make(map[string]string, len(t.Vars))
+		for name, value := range t.Vars {
+			if !themeVarRe.MatchString(name) {
+				return nil, fmt.Errorf("invalid theme variable name %q", name)
+			}
+			if themeValueRe.MatchString(value) {
+				return nil, fmt.Errorf("invalid value of theme variable %q", name)
+			}
+			vars["--"+strings.TrimPrefix(name, "--")] = value
+		}
+
+		css.WriteString(":root {\n")
+		for _, name := range slices.Sorted(maps.Keys(vars)) {
+			fmt.Fprintf(&css, "  %s: %s;\n", name, vars[name])
+		}
+		css.WriteString("}\n")
+	}
+	css.WriteString(t.CSS)
+
+	return css.Bytes(), nil
+}
+
+// newThemeCSSHandler returns a handler serving the given theme stylesheet,
+// and the hash of its content.
+func newThemeCSSHandler(css []byte) (http.Handler, string) {
+	sum := sha256.Sum256(css)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		http.ServeContent(w, r, ThemeFile, time.Time{}, bytes.NewReader(css))
+	}), hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,62 @@
+package gnoweb_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeStylesheet(t *testing.T) {
+	t.Parallel()
+
+	theme := gnoweb.Theme{
+		Vars: map[string]string{
+			"--s-color-bg-base": "#101010",
+			"g-color-light":     "#eee",
+		},
+		CSS: "body { letter-spacing: 0.01em; }\n",
+	}
+	css, err := theme.Stylesheet()
+	require.NoError(t, err)
+	assert.Equal(t, ":root {\n  --g-color-light: #eee;\n  --s-color-bg-base: #101010;\n}\nbody { letter-spacing: 0.01em; }\n", string(css))
+
+	for name, vars := range map[string]map[string]string{
+		"invalid name":  {"color: red; --x": "1"},
+		"invalid value": {"--x": "red; } body { display: none"},
+		"closing style": {"--x": "</style>"},
+	} {
+		_, err := gnoweb.Theme{Vars: vars}.Stylesheet()
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoadThemeDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "theme.css"), []byte(":root { --s-color-bg-base: #000; }"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates", "layouts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "layouts", "footer.html"), []byte(`{{ define "layouts/footer" }}{{ end }}`), 0o644))
+
+	theme, err := gnoweb.LoadThemeDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ":root { --s-color-bg-base: #000; }", theme.CSS)
+	require.NotNil(t, theme.Templates)
+	matches, err := fs.Glob(theme.Templates, "*/*.html")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"layouts/footer.html"}, matches)
+
+	// Both the stylesheet and the templates are optional
+	theme, err = gnoweb.LoadThemeDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, theme.CSS)
+	assert.Nil(t, theme.Templates)
+
+	_, err = gnoweb.LoadThemeDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package gnoweb
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxTitleLength is the default maximum length, in runes, of the
+// realm part of page titles.
+const DefaultMaxTitleLength = 100
+
+// maxDescriptionLength is the maximum length, in runes, of page
+// descriptions.
+const maxDescriptionLength = 300
+
+var reTitleMarkup = regexp.MustCompile(`<[^>]*>`)
+
+// sanitizeTitle makes an untrusted title safe for the page chrome: markup
+// and control characters are stripped, white spaces are collapsed and the
+// result is truncated to max runes. The fallback is returned if nothing is
+// left of the title.
+func sanitizeTitle(title, fallback string, max int) string {
+	if max <= 0 {
+		max = DefaultMaxTitleLength
+	}
+
+	if !utf8.ValidString(title) {
+		title = strings.ToValidUTF8(title, "")
+	}
+
+	title = reTitleMarkup.ReplaceAllString(title, "")
+	title = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), r == '<', r == '>':
+			return -1
+		}
+		return r
+	}, title)
+	title = strings.Join(strings.Fields(title), " ")
+
+	if title == "" {
+		return fallback
+	}
+
+	if utf8.RuneCountInString(title) > max {
+		runes := []rune(title)
+		title = strings.TrimSpace(string(runes[:max-1])) + "…"
+	}
+
+	return title
+}
@@ -0,0 +1,45 @@
+package gnoweb
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeTitle(t *testing.T) {
+	t.Parallel()
+
+	const fallback = "/r/demo/boards"
+
+	cases := []struct {
+		name     string
+		title    string
+		max      int
+		expected string
+	}{
+		{"plain", "My Board", 0, "My Board"},
+		{"markup", `My <b>Board</b><script>alert(1)</script>`, 0, "My Boardalert(1)"},
+		{"unclosed tag", "Board </title", 0, "Board /title"},
+		{"control chars", "My\x00 Bo\x1bard\n\tnews", 0, "My Board news"},
+		{"empty", "", 0, fallback},
+		{"only markup", "  <img src=x onerror=alert(1)>  ", 0, fallback},
+		{"overlong", strings.Repeat("a", 20), 10, strings.Repeat("a", 9) + "…"},
+		{"overlong multibyte", strings.Repeat("é", 20), 10, strings.Repeat("é", 9) + "…"},
+		{"invalid utf8", "Board\xff", 0, "Board"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, sanitizeTitle(tc.title, fallback, tc.max))
+		})
+	}
+
+	t.Run("default max length", func(t *testing.T) {
+		t.Parallel()
+		title := sanitizeTitle(strings.Repeat("a", 1000), fallback, 0)
+		assert.Equal(t, DefaultMaxTitleLength, utf8.RuneCountInString(title))
+	})
+}
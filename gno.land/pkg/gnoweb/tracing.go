@@ -0,0 +1,188 @@
+package gnoweb
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	rpchttp "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client/http"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader is the W3C trace context header.
+const TraceparentHeader = "traceparent"
+
+// traceContext is a parsed W3C `traceparent`.
+type traceContext struct {
+	TraceID string // 32 hex characters
+	SpanID  string // 16 hex characters
+	Flags   string // 2 hex characters
+}
+
+func (tc traceContext) String() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.Flags
+}
+
+// parseTraceparent parses a version 00 (or forward compatible) traceparent.
+func parseTraceparent(s string) (traceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || (parts[0] == "00" && len(parts) != 4) {
+		return traceContext{}, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isLowerHex(version, 2) || version == "ff" ||
+		!isLowerHex(traceID, 32) || traceID == strings.Repeat("0", 32) ||
+		!isLowerHex(spanID, 16) || spanID == strings.Repeat("0", 16) ||
+		!isLowerHex(flags, 2) {
+		return traceContext{}, false
+	}
+
+	return traceContext{TraceID: traceID, SpanID: spanID, Flags: flags}, true
+}
+
+func isLowerHex(s string, size int) bool {
+	if len(s) != size {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHex(size int) string {
+	b := make([]byte, size/2)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type traceKey struct{}
+
+// traceIDFromContext returns the trace ID of the request, if any.
+func traceIDFromContext(ctx context.Context) string {
+	if tc, ok := ctx.Value(traceKey{}).(traceContext); ok {
+		return tc.TraceID
+	}
+	return ""
+}
+
+// TracingMiddleware continues the W3C trace of incoming requests, or starts a
+// new one, and propagates it to the node RPC calls made while serving them.
+// Requests are logged along with their trace ID, see RequestLogMiddleware.
+func TracingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return traceContextMiddleware(RequestLogMiddleware(logger, next))
+}
+
+// traceContextMiddleware attaches the W3C trace context to requests.
+func traceContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc, ok := parseTraceparent(r.Header.Get(TraceparentHeader))
+		if !ok {
+			tc = traceContext{TraceID: randomHex(32), Flags: "01"}
+		}
+		tc.SpanID = randomHex(16) // gnoweb span, parent of the node calls
+
+		ctx := context.WithValue(r.Context(), traceKey{}, tc)
+		ctx = rpchttp.WithHeaders(ctx, http.Header{TraceparentHeader: {tc.String()}})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tracerName is the instrumentation name of the OpenTelemetry spans.
+const tracerName = "github.com/gnolang/gno/gno.land/pkg/gnoweb"
+
+// SpanMiddleware emits an OpenTelemetry server span for each request, child
+// of the incoming trace context if any. The node RPC queries made while
+// serving the request are emitted as its child spans.
+//
+// The span context replaces the `traceparent` header of the request, so that
+// TracingMiddleware logs the trace ID of the span.
+func SpanMiddleware(tp trace.TracerProvider, next http.Handler) http.Handler {
+	tracer := tp.Tracer(tracerName)
+	propagator := propagation.TraceContext{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		r = r.WithContext(ctx)
+		r.Header = r.Header.Clone()
+		propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+		rw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rw.status))
+		if rw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.status))
+		}
+	})
+}
+
+// startQuerySpan starts a client span of the node query, if the context
+// holds a recording span, and propagates its context to the node.
+func startQuerySpan(ctx context.Context, qpath string, height int64) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.IsRecording() {
+		return ctx, parent
+	}
+
+	ctx, span := parent.TracerProvider().Tracer(tracerName).Start(ctx, "abci_query "+qpath,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.method", "abci_query"),
+			attribute.String("gnoweb.query.path", qpath),
+			attribute.Int64("gnoweb.query.height", height),
+		),
+	)
+
+	headers := http.Header{}
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(headers))
+	return rpchttp.WithHeaders(ctx, headers), span
+}
+
+// statusWriter records the status code of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush implements http.Flusher, for progressive rendering.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, for WebSocket upgrades.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,162 @@
+package gnoweb_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// syncBuffer is a buffer safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const traceID = "0af7651916cd43dd8448eb211c80319c"
+
+	// Fake node, recording the trace context of RPC calls
+	var nodeTraceparents []string
+	var mu sync.Mutex
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		nodeTraceparents = append(nodeTraceparents, r.Header.Get(gnoweb.TraceparentHeader))
+		mu.Unlock()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(node.Close)
+
+	traceparents := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(nodeTraceparents)
+	}
+
+	rpcclient, err := client.NewHTTPClient(node.URL)
+	require.NoError(t, err)
+
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	adapter := gnoweb.NewRPCClientAdapter(logger, rpcclient, "gno.land")
+
+	handler := gnoweb.TracingMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adapter.Realm(r.Context(), "/r/test", "")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	t.Run("incoming trace", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/r/test", nil)
+		req.Header.Set(gnoweb.TraceparentHeader, "00-"+traceID+"-b7ad6b7169203331-01")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Len(t, traceparents(), 1)
+		parts := strings.Split(traceparents()[0], "-")
+		require.Len(t, parts, 4)
+		assert.Equal(t, "00", parts[0])
+		assert.Equal(t, traceID, parts[1])
+		assert.NotEqual(t, "b7ad6b7169203331", parts[2]) // gnoweb span
+		assert.Equal(t, "01", parts[3])
+
+		out := logs.String()
+		assert.Contains(t, out, `msg="query request failed"`)
+		assert.Contains(t, out, "trace_id="+traceID)
+		assert.Contains(t, out, "msg=request method=GET path=/r/test status=418")
+	})
+
+	t.Run("new trace", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/r/test", nil)
+		req.Header.Set(gnoweb.TraceparentHeader, "00-"+strings.Repeat("0", 32)+"-b7ad6b7169203331-01") // invalid
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Len(t, traceparents(), 2)
+		parts := strings.Split(traceparents()[1], "-")
+		require.Len(t, parts, 4)
+		assert.NotEqual(t, strings.Repeat("0", 32), parts[1])
+		assert.Len(t, parts[1], 32)
+		assert.Contains(t, logs.String(), "trace_id="+parts[1])
+	})
+}
+
+func TestSpanMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const traceID = "0af7651916cd43dd8448eb211c80319c"
+
+	// Fake node, recording the trace context of RPC calls
+	nodeTraceparent := make(chan string, 1)
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nodeTraceparent <- r.Header.Get(gnoweb.TraceparentHeader)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(node.Close)
+
+	rpcclient, err := client.NewHTTPClient(node.URL)
+	require.NoError(t, err)
+
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	adapter := gnoweb.NewRPCClientAdapter(logger, rpcclient, "gno.land")
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	handler := gnoweb.SpanMiddleware(tp, gnoweb.TracingMiddleware(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adapter.Realm(r.Context(), "/r/test", "")
+		w.WriteHeader(http.StatusBadGateway)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/r/test", nil)
+	req.Header.Set(gnoweb.TraceparentHeader, "00-"+traceID+"-b7ad6b7169203331-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /r/test", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, traceID, server.SpanContext().TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", server.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, server.Status().Code)
+
+	assert.Equal(t, "abci_query vm/qrender", query.Name())
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind())
+	assert.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, codes.Error, query.Status().Code)
+
+	// The node is called with the context of the query span
+	parts := strings.Split(<-nodeTraceparent, "-")
+	require.Len(t, parts, 4)
+	assert.Equal(t, traceID, parts[1])
+	assert.Equal(t, query.SpanContext().SpanID().String(), parts[2])
+
+	assert.Contains(t, logs.String(), "trace_id="+traceID)
+}
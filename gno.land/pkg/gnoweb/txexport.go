@@ -0,0 +1,175 @@
+package gnoweb
+
+import (
+	"fmt"
+	"go/token"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// TxExportPath is the prefix of the unsigned call transactions exported from
+// help pages, such as `/_tx/r/demo/boards?func=CreateBoard&args=x&caller=g1...`.
+const TxExportPath = "/_tx/"
+
+// The gas parameters of exported transactions, as in the help page commands.
+const (
+	txExportGasWanted = 5_000_000
+	txExportGasFee    = "1000000ugnot"
+)
+
+// txExportURL returns the path exporting calls of the package at the given
+// path.
+func txExportURL(pkgPath string) string {
+	return strings.TrimSuffix(TxExportPath, "/") + pkgPath
+}
+
+// helpFuncs returns the functions of the package callable from a
+// transaction: its exported non-method functions, without their leading
+// `realm` parameter if any.
+func helpFuncs(jdoc *doc.JSONDocumentation) []*doc.JSONFunc {
+	fsigs := []*doc.JSONFunc{}
+	for _, fun := range jdoc.Funcs {
+		if !(fun.Type == "" && token.IsExported(fun.Name)) {
+			continue
+		}
+
+		if len(fun.Params) >= 1 && fun.Params[0].Type == "realm" {
+			// Don't make an entry field for "cur realm". The signature will still show it.
+			f := *fun
+			f.Params = f.Params[1:]
+			fun = &f
+		}
+		fsigs = append(fsigs, fun)
+	}
+	return fsigs
+}
+
+// handlerTxExport serves the unsigned transaction calling a function of a
+// realm, given by the `func` query parameter, with the `args`, `caller` and
+// `send` query parameters, as the amino JSON expected by `gnokey sign`.
+// Arguments are checked against the types of the function parameters.
+func handlerTxExport(h *HTTPHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		u := &weburl.GnoURL{Path: strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(TxExportPath, "/"))}
+		if !u.IsRealm() || !u.IsValidPath() {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		caller, err := crypto.AddressFromString(strings.TrimSpace(query.Get("caller")))
+		if err != nil {
+			http.Error(w, "invalid caller address", http.StatusBadRequest)
+			return
+		}
+		send, err := std.ParseCoins(strings.TrimSpace(query.Get("send")))
+		if err != nil {
+			http.Error(w, "invalid send amount", http.StatusBadRequest)
+			return
+		}
+
+		jdoc, err := h.Client.Doc(r.Context(), u.Path)
+		if err != nil {
+			h.Logger.Debug("unable to fetch qdoc", "error", err, "path", u.Path)
+			status, _ := GetClientErrorStatusPage(u, err)
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		name, args := query.Get("func"), query["args"]
+		var fn *doc.JSONFunc
+		for _, f := range helpFuncs(jdoc) {
+			if f.Name == name {
+				fn = f
+				break
+			}
+		}
+		if fn == nil {
+			http.Error(w, "unknown function", http.StatusBadRequest)
+			return
+		}
+		if err := checkCallArgs(fn, args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		gasFee := std.MustParseCoin(txExportGasFee)
+		tx := std.Tx{
+			Msgs: []std.Msg{vm.MsgCall{
+				Caller:  caller,
+				Send:    send,
+				PkgPath: path.Join(h.Static.Domain, u.Path),
+				Func:    fn.Name,
+				Args:    args,
+			}},
+			Fee: std.NewFee(txExportGasWanted, gasFee),
+		}
+		body, err := amino.MarshalJSONIndent(tx, "", "  ")
+		if err != nil {
+			h.Logger.Error("unable to marshal tx", "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="call.tx"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(body)
+	})
+}
+
+// checkCallArgs checks the arguments of a call of the function against the
+// types of its parameters, for the types gnokey parses.
+func checkCallArgs(fn *doc.JSONFunc, args []string) error {
+	if len(args) != len(fn.Params) {
+		return fmt.Errorf("%s expects %d arguments, got %d", fn.Name, len(fn.Params), len(args))
+	}
+
+	for i, param := range fn.Params {
+		var err error
+		switch arg := args[i]; param.Type {
+		case "int", "int64":
+			_, err = strconv.ParseInt(arg, 10, 64)
+		case "int8", "int16", "int32":
+			_, err = strconv.ParseInt(arg, 10, typeBits(param.Type))
+		case "uint", "uint64":
+			_, err = strconv.ParseUint(arg, 10, 64)
+		case "uint8", "uint16", "uint32", "byte":
+			_, err = strconv.ParseUint(arg, 10, typeBits(param.Type))
+		case "float32", "float64":
+			_, err = strconv.ParseFloat(arg, typeBits(param.Type))
+		case "bool":
+			_, err = strconv.ParseBool(arg)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s argument %q", param.Type, param.Name)
+		}
+	}
+
+	return nil
+}
+
+// typeBits returns the bit size of a sized numeric type, such as 16 for
+// `uint16`.
+func typeBits(typ string) int {
+	if typ == "byte" {
+		return 8
+	}
+	bits, _ := strconv.Atoi(strings.TrimLeft(typ, "abcdefghijklmnopqrstuvwxyz"))
+	return bits
+}
@@ -0,0 +1,116 @@
+package gnoweb
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerTxExport(t *testing.T) {
+	t.Parallel()
+
+	cli := NewMockClient(&MockPackage{
+		Path:   "/r/demo/boards",
+		Domain: "gno.land",
+		Files:  map[string]string{"boards.gno": "package boards"},
+		Functions: []*doc.JSONFunc{
+			{Name: "CreateThread", Params: []*doc.JSONField{
+				{Name: "cur", Type: "realm"},
+				{Name: "bid", Type: "uint64"},
+				{Name: "title", Type: "string"},
+			}},
+			{Name: "render", Params: []*doc.JSONField{{Name: "path", Type: "string"}}},
+		},
+	})
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land", TxExport: true},
+	})
+	require.NoError(t, err)
+	handler := handlerTxExport(h)
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	export := func(query url.Values) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_tx/r/demo/boards?"+query.Encode(), nil))
+		return rr
+	}
+
+	rr := export(url.Values{
+		"func":   {"CreateThread"},
+		"args":   {"1", "Hello"},
+		"caller": {caller.String()},
+		"send":   {"100ugnot"},
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "call.tx")
+
+	var tx std.Tx
+	require.NoError(t, amino.UnmarshalJSON(rr.Body.Bytes(), &tx))
+	require.Len(t, tx.Msgs, 1)
+	assert.Equal(t, vm.MsgCall{
+		Caller:  caller,
+		Send:    std.MustParseCoins("100ugnot"),
+		PkgPath: "gno.land/r/demo/boards",
+		Func:    "CreateThread",
+		Args:    []string{"1", "Hello"},
+	}, tx.Msgs[0])
+	assert.Equal(t, int64(txExportGasWanted), tx.Fee.GasWanted)
+	assert.Empty(t, tx.Signatures)
+
+	for name, query := range map[string]url.Values{
+		"missing caller":  {"func": {"CreateThread"}, "args": {"1", "Hello"}},
+		"unknown func":    {"func": {"Missing"}, "caller": {caller.String()}},
+		"unexported func": {"func": {"render"}, "args": {""}, "caller": {caller.String()}},
+		"missing args":    {"func": {"CreateThread"}, "args": {"1"}, "caller": {caller.String()}},
+		"invalid uint":    {"func": {"CreateThread"}, "args": {"-1", "Hello"}, "caller": {caller.String()}},
+		"invalid send":    {"func": {"CreateThread"}, "args": {"1", "Hello"}, "caller": {caller.String()}, "send": {"x"}},
+	} {
+		assert.Equal(t, http.StatusBadRequest, export(query).Code, name)
+	}
+}
+
+func TestHelpViewTxExport(t *testing.T) {
+	t.Parallel()
+
+	cli := NewMockClient(&MockPackage{
+		Path:   "/r/demo/boards",
+		Domain: "gno.land",
+		Files:  map[string]string{"boards.gno": "package boards"},
+		Functions: []*doc.JSONFunc{
+			{Name: "Vote", Params: []*doc.JSONField{{Name: "up", Type: "bool"}, {Name: "weight", Type: "int"}}},
+		},
+	})
+	h, err := NewHTTPHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), &HTTPHandlerConfig{
+		ClientAdapter: cli,
+		Renderer:      newTestRenderer(),
+		Aliases:       map[string]AliasTarget{},
+		Meta:          StaticMetadata{Domain: "gno.land", TxExport: true},
+	})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/demo/boards$help", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `action="/_tx/r/demo/boards"`)
+	assert.Contains(t, body, `name="func" value="Vote"`)
+	assert.Contains(t, body, `type="text" list="help-bool-values" name="args"`)
+	assert.Contains(t, body, `type="number" step="1" name="args"`)
+	assert.Contains(t, body, `name="caller"`)
+}
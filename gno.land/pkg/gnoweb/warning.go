@@ -0,0 +1,112 @@
+package gnoweb
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/gnoweb/weburl"
+)
+
+const (
+	// contentWarningQuery is the web query accepting a content warning.
+	contentWarningQuery = "proceed"
+
+	// contentWarningCookie remembers the content warnings accepted by a
+	// visitor. Its path is the one of the accepted warning prefix.
+	contentWarningCookie = "gnoweb_content_warning"
+)
+
+// warnPrefix returns the longest content warning prefix matching path.
+func (h *HTTPHandler) warnPrefix(path string) (string, bool) {
+	return matchWarnPath(h.WarnPaths, path)
+}
+
+// matchWarnPath returns the longest of the given content warning prefixes
+// matching path.
+func matchWarnPath(prefixes []string, path string) (string, bool) {
+	var matched string
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+			matched = prefix
+		}
+	}
+	return matched, matched != ""
+}
+
+// contentURL returns the url of the content served for the request, whose
+// parsed url is gnourl, resolving the aliases to gnoweb paths.
+func (h *HTTPHandler) contentURL(r *http.Request, gnourl *weburl.GnoURL) *weburl.GnoURL {
+	target, ok := h.Aliases[r.URL.Path]
+	if !ok || target.Kind != GnowebPath {
+		return gnourl
+	}
+
+	u := *r.URL
+	u.Path, u.RawPath = target.Value, ""
+	aliased, err := weburl.ParseFromURL(&u)
+	if err != nil {
+		return gnourl
+	}
+	return aliased
+}
+
+// acceptContentWarning remembers, using a cookie, that the visitor accepted
+// the content warning of the given url.
+func (h *HTTPHandler) acceptContentWarning(w http.ResponseWriter, gnourl *weburl.GnoURL) {
+	prefix, ok := h.warnPrefix(gnourl.Path)
+	if !ok || !gnourl.WebQuery.Has(contentWarningQuery) {
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     contentWarningCookie,
+		Value:    url.QueryEscape(prefix),
+		Path:     prefix,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// contentWarningRequired reports whether the content warning interstitial
+// must be displayed instead of the content at the given url.
+func (h *HTTPHandler) contentWarningRequired(r *http.Request, gnourl *weburl.GnoURL) bool {
+	prefix, ok := h.warnPrefix(gnourl.Path)
+	if !ok || gnourl.WebQuery.Has(contentWarningQuery) {
+		return false
+	}
+
+	for _, cookie := range r.CookiesNamed(contentWarningCookie) {
+		if value, err := url.QueryUnescape(cookie.Value); err == nil && value == prefix {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contentWarningProceedURL returns the url displaying the content after
+// accepting the warning.
+func contentWarningProceedURL(gnourl *weburl.GnoURL) string {
+	u := *gnourl
+	u.WebQuery = maps.Clone(gnourl.WebQuery)
+	if u.WebQuery == nil {
+		u.WebQuery = url.Values{}
+	}
+	u.WebQuery.Set(contentWarningQuery, "")
+	return u.EncodeWebURL()
+}
+
+// onlyContentWarningQuery reports whether gnourl has no web query, other
+// than the one accepting its content warning.
+func onlyContentWarningQuery(gnourl *weburl.GnoURL) bool {
+	switch len(gnourl.WebQuery) {
+	case 0:
+		return true
+	case 1:
+		return gnourl.WebQuery.Has(contentWarningQuery)
+	default:
+		return false
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var ErrURLInvalidPath = errors.New("invalid path")
@@ -212,6 +214,10 @@ func ParseFromURL(u *url.URL) (*GnoURL, error) {
 		return nil, fmt.Errorf("unable to unescape path %q: %w", path, err)
 	}
 
+	// Normalize the path, so that visually identical file names resolve to
+	// the same file
+	upath = norm.NFC.String(upath)
+
 	var file string
 
 	// A file is considered as one that either ends with an extension or
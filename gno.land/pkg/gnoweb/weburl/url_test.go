@@ -27,6 +27,18 @@ func TestParseGnoURL(t *testing.T) {
 			},
 		},
 
+		{
+			Name:  "file nfd",
+			Input: "https://gno.land/r/simple/test/cafe%CC%81.md",
+			Expected: &GnoURL{
+				Domain:   "gno.land",
+				Path:     "/r/simple/test",
+				WebQuery: url.Values{},
+				Query:    url.Values{},
+				File:     "caf\u00e9.md", // NFC
+			},
+		},
+
 		{
 			Name:  "file",
 			Input: "https://gno.land/r/simple/test/encode.gno",
@@ -28,6 +28,15 @@ var (
 	ErrInvalidBatchResponse      = errors.New("invalid http batch response size")
 )
 
+// headersKey is the context key of the additional request headers.
+type headersKey struct{}
+
+// WithHeaders returns a copy of ctx carrying headers to be added to the
+// requests sent with it, such as tracing headers.
+func WithHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
 // Client is an HTTP client implementation
 type Client struct {
 	rpcURL string // the remote RPC URL of the node
@@ -128,7 +137,12 @@ func sendRequestCommon[T requestType, R responseType](
 		return nil, fmt.Errorf("unable to create request, %w", err)
 	}
 
-	// Set the header content type
+	// Set the context headers, if any, and the header content type
+	if headers, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for key, values := range headers {
+			req.Header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
@@ -160,6 +160,49 @@ func TestClient_SendRequest(t *testing.T) {
 		assert.Nil(t, resp.Error)
 	})
 
+	t.Run("context headers", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			request = types.RPCRequest{
+				JSONRPC: "2.0",
+				ID:      types.JSONRPCStringID("id"),
+			}
+
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "application/json", r.Header.Get("content-type"))
+				require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", r.Header.Get("traceparent"))
+
+				marshalledResponse, err := json.Marshal(types.RPCResponse{
+					JSONRPC: "2.0",
+					ID:      request.ID,
+				})
+				require.NoError(t, err)
+
+				_, err = w.Write(marshalledResponse)
+				require.NoError(t, err)
+			})
+
+			server = createTestServer(t, handler)
+		)
+
+		// Create the client
+		c, err := NewClient(server.URL)
+		require.NoError(t, err)
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFn()
+
+		ctx = WithHeaders(ctx, http.Header{
+			"traceparent":  {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			"Content-Type": {"text/plain"}, // can't be overridden
+		})
+
+		// Send the request
+		_, err = c.SendRequest(ctx, request)
+		require.NoError(t, err)
+	})
+
 	t.Run("response ID mismatch", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,58 @@
+package os
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// WriteFileWithBackup atomically replaces filename with data, after backing
+// up its previous content to `filename.bak.1`. Existing backups are rotated
+// (`.bak.1` becomes `.bak.2`, and so on), keeping up to `keep` of them.
+// A zero `keep` disables backups, making it a plain atomic write.
+//
+// The previous content is copied rather than moved, so that filename always
+// exists, even if writing the new content fails.
+func WriteFileWithBackup(filename string, data []byte, perm os.FileMode, keep int) error {
+	if keep > 0 {
+		if err := backupFile(filename, keep); err != nil {
+			return fmt.Errorf("unable to backup %q: %w", filename, err)
+		}
+	}
+
+	return WriteFileAtomic(filename, data, perm)
+}
+
+// backupFile copies filename to its first backup slot after rotating
+// existing backups, dropping the oldest one beyond `keep`.
+func backupFile(filename string, keep int) error {
+	stat, err := os.Stat(filename)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil // nothing to backup
+	case err != nil:
+		return err
+	}
+
+	prev, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Drop the oldest backup, then shift the others
+	if err := os.Remove(backupName(filename, keep)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for n := keep - 1; n > 0; n-- {
+		err := os.Rename(backupName(filename, n), backupName(filename, n+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return WriteFileAtomic(backupName(filename, 1), prev, stat.Mode().Perm())
+}
+
+func backupName(filename string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", filename, n)
+}
@@ -0,0 +1,75 @@
+package os
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileWithBackup(t *testing.T) {
+	t.Parallel()
+
+	readFile := func(t *testing.T, name string) string {
+		t.Helper()
+
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("no backup", func(t *testing.T) {
+		t.Parallel()
+
+		filename := filepath.Join(t.TempDir(), "genesis.json")
+		require.NoError(t, WriteFileWithBackup(filename, []byte("v1"), 0o600, 0))
+		require.NoError(t, WriteFileWithBackup(filename, []byte("v2"), 0o600, 0))
+
+		assert.Equal(t, "v2", readFile(t, filename))
+		assert.NoFileExists(t, filename+".bak.1")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		filename := filepath.Join(t.TempDir(), "genesis.json")
+		require.NoError(t, WriteFileWithBackup(filename, []byte("v1"), 0o600, 2))
+
+		assert.Equal(t, "v1", readFile(t, filename))
+		assert.NoFileExists(t, filename+".bak.1")
+	})
+
+	t.Run("rotate backups", func(t *testing.T) {
+		t.Parallel()
+
+		filename := filepath.Join(t.TempDir(), "genesis.json")
+		for i := 1; i <= 5; i++ {
+			require.NoError(t, WriteFileWithBackup(filename, []byte("v"+strconv.Itoa(i)), 0o600, 3))
+		}
+
+		assert.Equal(t, "v5", readFile(t, filename))
+		assert.Equal(t, "v4", readFile(t, filename+".bak.1"))
+		assert.Equal(t, "v3", readFile(t, filename+".bak.2"))
+		assert.Equal(t, "v2", readFile(t, filename+".bak.3"))
+		assert.NoFileExists(t, filename+".bak.4")
+	})
+
+	t.Run("keep permissions", func(t *testing.T) {
+		t.Parallel()
+
+		filename := filepath.Join(t.TempDir(), "priv_validator_key.json")
+		require.NoError(t, os.WriteFile(filename, []byte("v1"), 0o400))
+		require.NoError(t, WriteFileWithBackup(filename, []byte("v2"), 0o600, 1))
+
+		stat, err := os.Stat(filename + ".bak.1")
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o400), stat.Mode().Perm())
+
+		stat, err = os.Stat(filename)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+	})
+}
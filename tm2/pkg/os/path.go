@@ -1,12 +1,35 @@
 package os
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
+	"strings"
 )
 
+// ErrUnsafePath is returned by SafeJoin when the path would escape its root.
+var ErrUnsafePath = errors.New("path escapes root directory")
+
 func MakeAbs(path string, root string) string {
 	if filepath.IsAbs(path) {
 		return path
 	}
 	return filepath.Join(root, path)
 }
+
+// SafeJoin joins the slash-separated, untrusted path to root, returning an
+// ErrUnsafePath error if the result would lie outside of root. Leading
+// slashes are ignored, so `/a/b` is resolved as `<root>/a/b`.
+// Symbolic links within root are not resolved.
+func SafeJoin(root, path string) (string, error) {
+	rel := filepath.FromSlash(strings.TrimLeft(path, "/"))
+	if rel == "" {
+		return filepath.Clean(root), nil
+	}
+
+	if strings.IndexByte(rel, 0) >= 0 || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, path)
+	}
+
+	return filepath.Join(root, rel), nil
+}
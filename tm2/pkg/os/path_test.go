@@ -0,0 +1,41 @@
+package os
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeJoin(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.FromSlash("/srv/root")
+
+	for _, tc := range []struct {
+		path     string
+		expected string
+	}{
+		{"", "/srv/root"},
+		{"/", "/srv/root"},
+		{"a/b.html", "/srv/root/a/b.html"},
+		{"/a/b.html", "/srv/root/a/b.html"},
+		{"a/../b", "/srv/root/b"},
+		{"a/./b//c", "/srv/root/a/b/c"},
+	} {
+		res, err := SafeJoin(root, tc.path)
+		require.NoError(t, err, "path: %q", tc.path)
+		assert.Equal(t, filepath.FromSlash(tc.expected), res, "path: %q", tc.path)
+	}
+
+	for _, path := range []string{
+		"..",
+		"../etc/passwd",
+		"/a/../../etc",
+		"a/\x00b",
+	} {
+		_, err := SafeJoin(root, path)
+		require.ErrorIs(t, err, ErrUnsafePath, "path: %q", path)
+	}
+}